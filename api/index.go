@@ -4,16 +4,23 @@ import (
 	"context"
 	"log"
 	"net/http"
-	"os"
 	"sync"
+	_ "time/tzdata"
 
 	"github.com/clerk/clerk-sdk-go/v2"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
-	"russ-rentals/internal/database"
+	"russ-rentals/internal/config"
 	"russ-rentals/internal/handlers"
+	"russ-rentals/internal/jobs"
+	"russ-rentals/internal/mailer"
 	authMiddleware "russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/payment"
+	"russ-rentals/internal/repository"
+	"russ-rentals/internal/storage"
+	"russ-rentals/internal/token"
 )
 
 var (
@@ -21,25 +28,95 @@ var (
 	once sync.Once
 )
 
+// serverlessStorage opens the storage driver for feature. Serverless
+// instances have a read-only filesystem and share nothing, so anything but
+// the s3 driver disables feature rather than the whole app.
+func serverlessStorage(feature, driver string, opts storage.Options) storage.Storage {
+	if driver != storage.DriverS3 {
+		log.Printf("%s are disabled: set the s3 driver to store them on a serverless deploy", feature)
+		return storage.Unavailable{}
+	}
+	s, err := storage.New(driver, opts)
+	if err != nil {
+		log.Printf("%s are disabled: %v", feature, err)
+		return storage.Unavailable{}
+	}
+	return s
+}
+
 func init() {
 	once.Do(func() {
+		cfg := config.Load()
+
 		// Initialize Clerk
-		if key := os.Getenv("CLERK_SECRET_KEY"); key != "" {
-			clerk.SetKey(key)
+		if cfg.ClerkSecretKey != "" {
+			clerk.SetKey(cfg.ClerkSecretKey)
+		}
+
+		// Initialize storage
+		if err := cfg.CheckStorage(); err != nil {
+			log.Fatal(err)
+		}
+		loc, err := cfg.Location()
+		if err != nil {
+			log.Fatal(err)
+		}
+		store, err := repository.Open(context.Background(), cfg.StorageDriver, cfg.DatabaseURL, handlers.GetSampleProperties())
+		if err != nil {
+			log.Fatalf("Failed to open %s storage: %v", cfg.StorageDriver, err)
+		}
+		if cfg.StorageDriver == repository.DriverMemory {
+			log.Printf("Warning: using in-memory storage; data is lost on restart")
+		}
+
+		// Initialize mail and signed links
+		mail, err := mailer.New(cfg.MailDriver, cfg.MailOptions())
+		if err != nil {
+			log.Fatalf("Failed to configure mailer: %v", err)
+		}
+		// Each instance would otherwise pick its own random key and reject
+		// links signed by the others. Emailed links need the public origin,
+		// as there is no localhost to fall back to.
+		if cfg.AppSecret == "" {
+			log.Fatal("APP_SECRET is required: serverless instances must share a signing key")
+		}
+		if cfg.BaseURL == "" {
+			log.Fatal("BASE_URL is required for links in emails")
+		}
+		secret, err := cfg.SigningSecret()
+		if err != nil {
+			log.Fatal(err)
 		}
 
-		// Initialize database
-		var db *database.DB
-		if dbURL := os.Getenv("DATABASE_URL"); dbURL != "" {
-			var err error
-			db, err = database.Connect(context.Background(), dbURL)
-			if err != nil {
-				log.Printf("Warning: Database not connected: %v", err)
-			}
+		// Initialize photo uploads. The filesystem is read-only here, so
+		// they need the s3 driver.
+		uploads := serverlessStorage("Photo uploads", cfg.UploadDriver, cfg.StorageOptions())
+
+		// Lease documents are kept out of the public uploads and only served
+		// through signed links. Like uploads they need the s3 driver here.
+		documents := serverlessStorage("Lease documents", cfg.DocumentDriver, cfg.DocumentStorageOptions())
+
+		// Initialize payments
+		paymentOpts, err := cfg.PaymentOptions()
+		if err != nil {
+			log.Fatal(err)
+		}
+		payments, err := payment.New(cfg.PaymentDriver, paymentOpts)
+		if err != nil {
+			log.Fatalf("Failed to configure payments: %v", err)
 		}
 
 		// Create handler with dependencies
-		h := handlers.NewHandler(db)
+		h := handlers.NewHandler(store, mail, token.NewSigner(secret), uploads, documents, payments, cfg.BaseURL, loc)
+		h.AdminIDs = cfg.AdminUserIDs
+
+		// Instances don't live long enough to run the scheduler, so the
+		// host's cron runs each job through /cron/jobs/:name
+		tasks := &jobs.Tasks{Store: store, Mailer: mail, Payments: payments, BaseURL: cfg.BaseURL}
+		h.Jobs = jobs.NewScheduler(store.Jobs, tasks.Jobs(), loc)
+		if cfg.CronSecret == "" {
+			log.Printf("Background jobs are disabled: set CRON_SECRET for the cron to run them")
+		}
 
 		// Create Echo instance
 		e = echo.New()
@@ -50,9 +127,15 @@ func init() {
 		e.Use(middleware.Recover())
 		e.Use(middleware.Gzip())
 		e.Use(authMiddleware.OptionalClerkAuth())
+		e.Use(authMiddleware.Roles(store.Users, store.Leases, cfg.AdminUserIDs))
+		e.Use(authMiddleware.Location(loc))
 
 		// Static files
 		e.Static("/static", "static")
+		// Local uploads are served by the app unless UPLOAD_PUBLIC_URL points elsewhere
+		if local, ok := uploads.(*storage.LocalStorage); ok && cfg.UploadPublicURL == "" {
+			e.Static("/uploads", local.Dir())
+		}
 
 		// Public routes
 		e.GET("/", h.Home)
@@ -62,8 +145,24 @@ func init() {
 		e.GET("/properties/:slug/gallery", h.PropertyGallery)
 		e.GET("/contact", h.Contact)
 		e.POST("/contact", h.SubmitContact)
+		e.GET("/contact/slots", h.ShowingSlots)
+		e.GET("/showings/confirm", h.ConfirmShowing)
+		e.GET("/showings/cancel", h.CancelShowingPage)
+		e.POST("/showings/cancel", h.CancelShowing)
+		e.GET("/calendar/agent.ics", h.AgentCalendar)
+		e.GET("/calendar/property.ics", h.PropertyCalendar)
+		e.GET("/documents/open", h.OpenDocument)
+		e.GET("/maintenance/photos", h.OpenMaintenancePhoto)
+		e.GET("/sign", h.SignaturePage)
+		e.POST("/sign", h.SubmitSignature)
+		e.GET("/sign/document", h.SignatureDocument)
 		e.GET("/about", h.About)
 		e.POST("/api/newsletter", h.Newsletter)
+		e.GET("/newsletter/confirm", h.ConfirmNewsletter)
+		e.GET("/newsletter/unsubscribe", h.UnsubscribeNewsletterPage)
+		e.POST("/newsletter/unsubscribe", h.UnsubscribeNewsletter)
+		e.POST("/webhooks/payments", h.PaymentWebhook)
+		e.GET("/cron/jobs/:name", h.RunJob, authMiddleware.CronAuth(cfg.CronSecret))
 
 		// Auth routes
 		e.GET("/sign-in", h.SignIn)
@@ -75,8 +174,124 @@ func init() {
 
 		// Protected routes
 		dashboard := e.Group("/dashboard")
-		dashboard.Use(authMiddleware.ClerkAuth())
+		dashboard.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireTenant())
 		dashboard.GET("", h.Dashboard)
+		dashboard.GET("/ledger", h.Ledger)
+		dashboard.GET("/ledger.csv", h.LedgerCSV)
+		dashboard.GET("/pay", h.PayRent)
+		dashboard.POST("/pay", h.SubmitRentPayment)
+		dashboard.POST("/autopay", h.EnrollAutopay)
+		dashboard.DELETE("/autopay", h.CancelAutopay)
+		dashboard.GET("/maintenance", h.Maintenance)
+		dashboard.GET("/maintenance/new", h.NewMaintenanceRequest)
+		dashboard.POST("/maintenance", h.SubmitMaintenanceRequest)
+		dashboard.GET("/maintenance/:id", h.MaintenanceRequestDetail)
+		dashboard.GET("/maintenance/:id/photos/:photo", h.MaintenancePhoto)
+		dashboard.GET("/documents", h.TenantDocuments)
+		dashboard.GET("/documents/:id", h.TenantOpenDocument)
+		dashboard.GET("/signatures/:id", h.TenantSignDocument)
+
+		e.GET("/apply/:slug", h.StartApplication, authMiddleware.ClerkAuth())
+		applications := e.Group("/applications")
+		applications.Use(authMiddleware.ClerkAuth())
+		applications.GET("", h.Applications)
+		applications.GET("/:id", h.ApplicationDetail)
+		applications.POST("/:id/withdraw", h.WithdrawApplication)
+		applications.GET("/:id/review", h.ApplicationReview)
+		applications.POST("/:id/submit", h.SubmitApplication)
+		applications.GET("/:id/pay", h.ApplicationPayment)
+		applications.POST("/:id/pay", h.PayApplication)
+		applications.GET("/:id/:step", h.ApplicationStep)
+		applications.POST("/:id/:step", h.SaveApplicationStep)
+
+		// Admin routes
+		admin := e.Group("/admin")
+		admin.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireRole(models.RoleAdmin))
+		admin.GET("", h.AdminProperties)
+		admin.GET("/properties/new", h.AdminNewProperty)
+		admin.POST("/properties", h.AdminCreateProperty)
+		admin.GET("/properties/:id/edit", h.AdminEditProperty)
+		admin.PUT("/properties/:id", h.AdminUpdateProperty)
+		admin.DELETE("/properties/:id", h.AdminDeleteProperty)
+		admin.GET("/properties/:id/images", h.AdminPropertyImages)
+		admin.POST("/properties/:id/images", h.AdminUploadImage)
+		admin.PUT("/properties/:id/images", h.AdminUpdateImages)
+		admin.GET("/applications", h.AdminApplications)
+		admin.GET("/applications/:id", h.AdminApplication)
+		admin.PUT("/applications/:id/status", h.AdminSetApplicationStatus)
+		admin.POST("/applications/:id/refund", h.AdminRefundApplicationFee)
+		admin.GET("/leases", h.AdminLeases)
+		admin.GET("/leases/new", h.AdminNewLease)
+		admin.POST("/leases", h.AdminCreateLease)
+		admin.GET("/leases/:id", h.AdminLease)
+		admin.GET("/leases/:id/edit", h.AdminEditLease)
+		admin.PUT("/leases/:id", h.AdminUpdateLease)
+		admin.PUT("/leases/:id/status", h.AdminSetLeaseStatus)
+		admin.GET("/leases/:id/ledger", h.AdminLeaseLedger)
+		admin.POST("/leases/:id/ledger", h.AdminPostLedgerEntry)
+		admin.GET("/leases/:id/ledger.csv", h.AdminLeaseLedgerCSV)
+		admin.GET("/leases/:id/documents", h.AdminLeaseDocuments)
+		admin.POST("/leases/:id/documents", h.AdminUploadDocument)
+		admin.GET("/leases/:id/documents/generate", h.AdminDocumentTemplateForm)
+		admin.POST("/leases/:id/documents/generate", h.AdminGenerateDocument)
+		admin.GET("/documents/:id", h.AdminOpenDocument)
+		admin.POST("/documents/:id/signatures", h.AdminRequestSignatures)
+		admin.POST("/documents/:id/signatures/void", h.AdminVoidSignatures)
+		admin.POST("/documents/:id/signatures/finish", h.AdminFinishSigning)
+		admin.GET("/signatures/:id/sign", h.AdminSignDocument)
+		admin.GET("/late-fees", h.AdminLateFees)
+		admin.GET("/late-fees/new", h.AdminNewLateFee)
+		admin.POST("/late-fees", h.AdminCreateLateFee)
+		admin.GET("/late-fees/report", h.AdminLateFeeReport)
+		admin.GET("/late-fees/:id/edit", h.AdminEditLateFee)
+		admin.PUT("/late-fees/:id", h.AdminUpdateLateFee)
+		admin.DELETE("/late-fees/:id", h.AdminDeleteLateFee)
+
+		// Staff routes
+		inquiries := e.Group("/admin/inquiries")
+		inquiries.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireRole(models.RoleStaff))
+		inquiries.GET("", h.AdminInquiries)
+		inquiries.GET("/:id", h.AdminInquiry)
+		inquiries.PUT("/:id/status", h.AdminSetInquiryStatus)
+		inquiries.PUT("/:id/assignee", h.AdminAssignInquiry)
+		inquiries.POST("/:id/notes", h.AdminAddInquiryNote)
+
+		maintenance := e.Group("/admin/maintenance")
+		maintenance.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireRole(models.RoleStaff))
+		maintenance.GET("", h.AdminMaintenance)
+		maintenance.GET("/:id", h.AdminMaintenanceRequest)
+		maintenance.GET("/:id/photos/:photo", h.AdminMaintenancePhoto)
+		maintenance.PUT("/:id/status", h.AdminUpdateMaintenance)
+		maintenance.PUT("/:id/assignee", h.AdminAssignMaintenance)
+		maintenance.POST("/:id/notes", h.AdminAddMaintenanceNote)
+		maintenance.POST("/:id/work-orders", h.AdminCreateWorkOrder)
+
+		vendors := e.Group("/admin/vendors")
+		vendors.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireRole(models.RoleStaff))
+		vendors.GET("", h.AdminVendors)
+		vendors.GET("/new", h.AdminNewVendor)
+		vendors.POST("", h.AdminCreateVendor)
+		vendors.GET("/:id/edit", h.AdminEditVendor)
+		vendors.PUT("/:id", h.AdminUpdateVendor)
+
+		workOrders := e.Group("/admin/work-orders")
+		workOrders.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireRole(models.RoleStaff))
+		workOrders.GET("", h.AdminWorkOrders)
+		workOrders.GET("/export.csv", h.AdminWorkOrdersCSV)
+		workOrders.GET("/:id", h.AdminWorkOrder)
+		workOrders.PUT("/:id", h.AdminUpdateWorkOrder)
+		workOrders.POST("/:id/complete", h.AdminCompleteWorkOrder)
+		workOrders.POST("/:id/cancel", h.AdminCancelWorkOrder)
+		workOrders.PUT("/:id/invoice", h.AdminRecordWorkOrderInvoice)
+		workOrders.POST("/:id/charge", h.AdminChargeWorkOrder)
+
+		showings := e.Group("/admin/showings")
+		showings.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireRole(models.RoleStaff))
+		showings.GET("", h.AdminShowings)
+		showings.POST("/windows", h.AdminCreateShowingWindow)
+		showings.DELETE("/windows/:id", h.AdminDeleteShowingWindow)
+		showings.POST("/:id/cancel", h.AdminCancelShowing)
+		showings.POST("/calendar-key", h.AdminResetCalendarFeeds)
 	})
 }
 
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"russ-rentals/internal/config"
+	"russ-rentals/internal/handlers"
+	"russ-rentals/internal/jobs"
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/payment"
+	"russ-rentals/internal/repository"
+)
+
+const jobsUsage = "usage: server jobs list | run <job> | runs [job] [limit]"
+
+// newScheduler creates the scheduler for the server's recurring tasks
+func newScheduler(store *repository.Store, mail mailer.Mailer, payments payment.Gateway, baseURL string, loc *time.Location) *jobs.Scheduler {
+	tasks := &jobs.Tasks{Store: store, Mailer: mail, Payments: payments, BaseURL: baseURL}
+	return jobs.NewScheduler(store.Jobs, tasks.Jobs(), loc)
+}
+
+// jobsCommand lists the background jobs, runs one on demand, or shows
+// recent runs
+func jobsCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(jobsUsage)
+	}
+
+	if err := cfg.CheckStorage(); err != nil {
+		return err
+	}
+	loc, err := cfg.Location()
+	if err != nil {
+		return err
+	}
+	baseURL, err := cfg.PublicURL()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	store, err := repository.Open(ctx, cfg.StorageDriver, cfg.DatabaseURL, handlers.GetSampleProperties())
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	mail, err := mailer.New(cfg.MailDriver, cfg.MailOptions())
+	if err != nil {
+		return err
+	}
+	paymentOpts, err := cfg.PaymentOptions()
+	if err != nil {
+		return err
+	}
+	payments, err := payment.New(cfg.PaymentDriver, paymentOpts)
+	if err != nil {
+		return err
+	}
+	sched := newScheduler(store, mail, payments, baseURL, loc)
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "JOB\tSCHEDULE\tNEXT RUN\tDESCRIPTION")
+		now := time.Now().In(loc)
+		for _, job := range sched.Jobs() {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", job.Name, job.Schedule, job.Schedule.Next(now).Format("Jan 2 15:04 MST"), job.Description)
+		}
+		return w.Flush()
+	case args[0] == "run" && len(args) == 2:
+		run, err := sched.RunNow(ctx, args[1])
+		if errors.Is(err, repository.ErrLocked) {
+			return fmt.Errorf("%s is already running", args[1])
+		}
+		if run == nil {
+			return err
+		}
+		fmt.Printf("%s run %d %s in %s: %s\n", run.Job, run.ID, run.Status, run.Duration(time.Now()).Round(time.Millisecond), run.Summary)
+		return err
+	case args[0] == "runs" && len(args) <= 3:
+		job, limit := "", 20
+		if len(args) > 1 {
+			job = args[1]
+		}
+		if len(args) > 2 {
+			if limit, err = strconv.Atoi(args[2]); err != nil || limit <= 0 {
+				return errors.New(jobsUsage)
+			}
+		}
+		runs, err := store.Jobs.ListRuns(ctx, job, limit)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "ID\tJOB\tTRIGGER\tSTARTED\tDURATION\tSTATUS\tSUMMARY")
+		now := time.Now()
+		for _, r := range runs {
+			summary := r.Summary
+			if r.Error != "" {
+				summary = r.Error
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", r.ID, r.Job, r.Trigger, r.StartedAt.In(loc).Format("Jan 2 15:04:05"),
+				r.Duration(now).Round(time.Millisecond), r.Status, summary)
+		}
+		return w.Flush()
+	default:
+		return errors.New(jobsUsage)
+	}
+}
@@ -2,37 +2,140 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
 	"russ-rentals/internal/config"
-	"russ-rentals/internal/database"
 	"russ-rentals/internal/handlers"
+	"russ-rentals/internal/mailer"
 	authMiddleware "russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/payment"
+	"russ-rentals/internal/repository"
+	"russ-rentals/internal/storage"
+	"russ-rentals/internal/token"
 )
 
+const usage = `Usage: server [command]
+
+Commands:
+  serve                     Start the HTTP server (default)
+  migrate up|down|status    Apply, roll back, or list database migrations
+  seed                      Upsert the sample properties and images
+  role list|set|remove      Manage roles for users without Clerk role claims
+  jobs list|run|runs        List background jobs, run one now, or show recent runs
+`
+
 func main() {
 	cfg := config.Load()
 
-	// Initialize database
+	command := "serve"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	switch command {
+	case "serve":
+		serve(cfg)
+	case "migrate":
+		if err := migrateCommand(cfg, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+	case "seed":
+		if err := seedCommand(cfg); err != nil {
+			log.Fatal(err)
+		}
+	case "role":
+		if err := roleCommand(cfg, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+	case "jobs":
+		if err := jobsCommand(cfg, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+}
+
+func serve(cfg *config.Config) {
 	ctx := context.Background()
-	db, err := database.Connect(ctx, cfg.DatabaseURL)
+	if err := cfg.CheckStorage(); err != nil {
+		log.Fatal(err)
+	}
+	loc, err := cfg.Location()
+	if err != nil {
+		log.Fatal(err)
+	}
+	baseURL, err := cfg.PublicURL()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Bring the schema up to date before serving, if enabled
+	if cfg.AutoMigrate && cfg.StorageDriver == repository.DriverPostgres {
+		if err := migrateUp(ctx, cfg); err != nil {
+			log.Fatalf("Auto-migrate failed: %v", err)
+		}
+	}
+
+	// Initialize storage
+	store, err := repository.Open(ctx, cfg.StorageDriver, cfg.DatabaseURL, handlers.GetSampleProperties())
+	if err != nil {
+		log.Fatalf("Failed to open %s storage: %v", cfg.StorageDriver, err)
+	}
+	if cfg.StorageDriver == repository.DriverMemory {
+		log.Printf("Warning: using in-memory storage; data is lost on restart")
+	}
+	defer store.Close()
+
+	// Initialize mail and signed links
+	mail, err := mailer.New(cfg.MailDriver, cfg.MailOptions())
+	if err != nil {
+		log.Fatalf("Failed to configure mailer: %v", err)
+	}
+	secret, err := cfg.SigningSecret()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Initialize photo uploads
+	uploads, err := storage.New(cfg.UploadDriver, cfg.StorageOptions())
+	if err != nil {
+		log.Fatalf("Failed to configure uploads: %v", err)
+	}
+
+	// Lease documents are kept out of the public uploads and only served
+	// through signed links
+	documents, err := storage.New(cfg.DocumentDriver, cfg.DocumentStorageOptions())
+	if err != nil {
+		log.Fatalf("Failed to configure documents: %v", err)
+	}
+
+	// Initialize payments
+	paymentOpts, err := cfg.PaymentOptions()
 	if err != nil {
-		log.Printf("Warning: Database not connected: %v", err)
-		db = nil
+		log.Fatal(err)
 	}
-	if db != nil {
-		defer db.Close()
+	payments, err := payment.New(cfg.PaymentDriver, paymentOpts)
+	if err != nil {
+		log.Fatalf("Failed to configure payments: %v", err)
 	}
 
 	// Create handler with dependencies
-	h := handlers.NewHandler(db)
+	h := handlers.NewHandler(store, mail, token.NewSigner(secret), uploads, documents, payments, baseURL, loc)
+	h.AdminIDs = cfg.AdminUserIDs
 
 	// Create Echo instance
 	e := echo.New()
@@ -43,13 +146,33 @@ func main() {
 	e.Use(middleware.Recover())
 	e.Use(middleware.Gzip())
 	e.Use(authMiddleware.OptionalClerkAuth())
+	e.Use(authMiddleware.Roles(store.Users, store.Leases, cfg.AdminUserIDs))
+	e.Use(authMiddleware.Location(loc))
 
 	// Static files
 	e.Static("/static", "static")
+	// Local uploads are served by the app unless UPLOAD_PUBLIC_URL points elsewhere
+	if local, ok := uploads.(*storage.LocalStorage); ok && cfg.UploadPublicURL == "" {
+		e.Static("/uploads", local.Dir())
+	}
 
 	// Setup routes
 	setupRoutes(e, h)
 
+	// Start background jobs. JOBS_ENABLED=false leaves them to other
+	// servers or to `server jobs run`.
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobsDone := make(chan struct{})
+	if cfg.JobsEnabled {
+		sched := newScheduler(store, mail, payments, baseURL, loc)
+		go func() {
+			defer close(jobsDone)
+			sched.Run(jobsCtx)
+		}()
+	} else {
+		close(jobsDone)
+	}
+
 	// Start server
 	go func() {
 		if err := e.Start(":" + cfg.Port); err != nil {
@@ -68,6 +191,12 @@ func main() {
 	if err := e.Shutdown(ctx); err != nil {
 		log.Fatal(err)
 	}
+	stopJobs()
+	select {
+	case <-jobsDone:
+	case <-ctx.Done():
+		log.Printf("Background jobs did not stop in time")
+	}
 }
 
 func setupRoutes(e *echo.Echo, h *handlers.Handler) {
@@ -79,8 +208,23 @@ func setupRoutes(e *echo.Echo, h *handlers.Handler) {
 	e.GET("/properties/:slug/gallery", h.PropertyGallery)
 	e.GET("/contact", h.Contact)
 	e.POST("/contact", h.SubmitContact)
+	e.GET("/contact/slots", h.ShowingSlots)
+	e.GET("/showings/confirm", h.ConfirmShowing)
+	e.GET("/showings/cancel", h.CancelShowingPage)
+	e.POST("/showings/cancel", h.CancelShowing)
+	e.GET("/calendar/agent.ics", h.AgentCalendar)
+	e.GET("/calendar/property.ics", h.PropertyCalendar)
+	e.GET("/documents/open", h.OpenDocument)
+	e.GET("/maintenance/photos", h.OpenMaintenancePhoto)
+	e.GET("/sign", h.SignaturePage)
+	e.POST("/sign", h.SubmitSignature)
+	e.GET("/sign/document", h.SignatureDocument)
 	e.GET("/about", h.About)
 	e.POST("/api/newsletter", h.Newsletter)
+	e.GET("/newsletter/confirm", h.ConfirmNewsletter)
+	e.GET("/newsletter/unsubscribe", h.UnsubscribeNewsletterPage)
+	e.POST("/newsletter/unsubscribe", h.UnsubscribeNewsletter)
+	e.POST("/webhooks/payments", h.PaymentWebhook)
 
 	// Auth routes
 	e.GET("/sign-in", h.SignIn)
@@ -92,6 +236,122 @@ func setupRoutes(e *echo.Echo, h *handlers.Handler) {
 
 	// Protected routes
 	dashboard := e.Group("/dashboard")
-	dashboard.Use(authMiddleware.ClerkAuth())
+	dashboard.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireTenant())
 	dashboard.GET("", h.Dashboard)
+	dashboard.GET("/ledger", h.Ledger)
+	dashboard.GET("/ledger.csv", h.LedgerCSV)
+	dashboard.GET("/pay", h.PayRent)
+	dashboard.POST("/pay", h.SubmitRentPayment)
+	dashboard.POST("/autopay", h.EnrollAutopay)
+	dashboard.DELETE("/autopay", h.CancelAutopay)
+	dashboard.GET("/maintenance", h.Maintenance)
+	dashboard.GET("/maintenance/new", h.NewMaintenanceRequest)
+	dashboard.POST("/maintenance", h.SubmitMaintenanceRequest)
+	dashboard.GET("/maintenance/:id", h.MaintenanceRequestDetail)
+	dashboard.GET("/maintenance/:id/photos/:photo", h.MaintenancePhoto)
+	dashboard.GET("/documents", h.TenantDocuments)
+	dashboard.GET("/documents/:id", h.TenantOpenDocument)
+	dashboard.GET("/signatures/:id", h.TenantSignDocument)
+
+	e.GET("/apply/:slug", h.StartApplication, authMiddleware.ClerkAuth())
+	applications := e.Group("/applications")
+	applications.Use(authMiddleware.ClerkAuth())
+	applications.GET("", h.Applications)
+	applications.GET("/:id", h.ApplicationDetail)
+	applications.POST("/:id/withdraw", h.WithdrawApplication)
+	applications.GET("/:id/review", h.ApplicationReview)
+	applications.POST("/:id/submit", h.SubmitApplication)
+	applications.GET("/:id/pay", h.ApplicationPayment)
+	applications.POST("/:id/pay", h.PayApplication)
+	applications.GET("/:id/:step", h.ApplicationStep)
+	applications.POST("/:id/:step", h.SaveApplicationStep)
+
+	// Admin routes
+	admin := e.Group("/admin")
+	admin.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireRole(models.RoleAdmin))
+	admin.GET("", h.AdminProperties)
+	admin.GET("/properties/new", h.AdminNewProperty)
+	admin.POST("/properties", h.AdminCreateProperty)
+	admin.GET("/properties/:id/edit", h.AdminEditProperty)
+	admin.PUT("/properties/:id", h.AdminUpdateProperty)
+	admin.DELETE("/properties/:id", h.AdminDeleteProperty)
+	admin.GET("/properties/:id/images", h.AdminPropertyImages)
+	admin.POST("/properties/:id/images", h.AdminUploadImage)
+	admin.PUT("/properties/:id/images", h.AdminUpdateImages)
+	admin.GET("/applications", h.AdminApplications)
+	admin.GET("/applications/:id", h.AdminApplication)
+	admin.PUT("/applications/:id/status", h.AdminSetApplicationStatus)
+	admin.POST("/applications/:id/refund", h.AdminRefundApplicationFee)
+	admin.GET("/leases", h.AdminLeases)
+	admin.GET("/leases/new", h.AdminNewLease)
+	admin.POST("/leases", h.AdminCreateLease)
+	admin.GET("/leases/:id", h.AdminLease)
+	admin.GET("/leases/:id/edit", h.AdminEditLease)
+	admin.PUT("/leases/:id", h.AdminUpdateLease)
+	admin.PUT("/leases/:id/status", h.AdminSetLeaseStatus)
+	admin.GET("/leases/:id/ledger", h.AdminLeaseLedger)
+	admin.POST("/leases/:id/ledger", h.AdminPostLedgerEntry)
+	admin.GET("/leases/:id/ledger.csv", h.AdminLeaseLedgerCSV)
+	admin.GET("/leases/:id/documents", h.AdminLeaseDocuments)
+	admin.POST("/leases/:id/documents", h.AdminUploadDocument)
+	admin.GET("/leases/:id/documents/generate", h.AdminDocumentTemplateForm)
+	admin.POST("/leases/:id/documents/generate", h.AdminGenerateDocument)
+	admin.GET("/documents/:id", h.AdminOpenDocument)
+	admin.POST("/documents/:id/signatures", h.AdminRequestSignatures)
+	admin.POST("/documents/:id/signatures/void", h.AdminVoidSignatures)
+	admin.POST("/documents/:id/signatures/finish", h.AdminFinishSigning)
+	admin.GET("/signatures/:id/sign", h.AdminSignDocument)
+	admin.GET("/late-fees", h.AdminLateFees)
+	admin.GET("/late-fees/new", h.AdminNewLateFee)
+	admin.POST("/late-fees", h.AdminCreateLateFee)
+	admin.GET("/late-fees/report", h.AdminLateFeeReport)
+	admin.GET("/late-fees/:id/edit", h.AdminEditLateFee)
+	admin.PUT("/late-fees/:id", h.AdminUpdateLateFee)
+	admin.DELETE("/late-fees/:id", h.AdminDeleteLateFee)
+
+	// Staff routes
+	inquiries := e.Group("/admin/inquiries")
+	inquiries.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireRole(models.RoleStaff))
+	inquiries.GET("", h.AdminInquiries)
+	inquiries.GET("/:id", h.AdminInquiry)
+	inquiries.PUT("/:id/status", h.AdminSetInquiryStatus)
+	inquiries.PUT("/:id/assignee", h.AdminAssignInquiry)
+	inquiries.POST("/:id/notes", h.AdminAddInquiryNote)
+
+	maintenance := e.Group("/admin/maintenance")
+	maintenance.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireRole(models.RoleStaff))
+	maintenance.GET("", h.AdminMaintenance)
+	maintenance.GET("/:id", h.AdminMaintenanceRequest)
+	maintenance.GET("/:id/photos/:photo", h.AdminMaintenancePhoto)
+	maintenance.PUT("/:id/status", h.AdminUpdateMaintenance)
+	maintenance.PUT("/:id/assignee", h.AdminAssignMaintenance)
+	maintenance.POST("/:id/notes", h.AdminAddMaintenanceNote)
+	maintenance.POST("/:id/work-orders", h.AdminCreateWorkOrder)
+
+	vendors := e.Group("/admin/vendors")
+	vendors.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireRole(models.RoleStaff))
+	vendors.GET("", h.AdminVendors)
+	vendors.GET("/new", h.AdminNewVendor)
+	vendors.POST("", h.AdminCreateVendor)
+	vendors.GET("/:id/edit", h.AdminEditVendor)
+	vendors.PUT("/:id", h.AdminUpdateVendor)
+
+	workOrders := e.Group("/admin/work-orders")
+	workOrders.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireRole(models.RoleStaff))
+	workOrders.GET("", h.AdminWorkOrders)
+	workOrders.GET("/export.csv", h.AdminWorkOrdersCSV)
+	workOrders.GET("/:id", h.AdminWorkOrder)
+	workOrders.PUT("/:id", h.AdminUpdateWorkOrder)
+	workOrders.POST("/:id/complete", h.AdminCompleteWorkOrder)
+	workOrders.POST("/:id/cancel", h.AdminCancelWorkOrder)
+	workOrders.PUT("/:id/invoice", h.AdminRecordWorkOrderInvoice)
+	workOrders.POST("/:id/charge", h.AdminChargeWorkOrder)
+
+	showings := e.Group("/admin/showings")
+	showings.Use(authMiddleware.ClerkAuth(), authMiddleware.RequireRole(models.RoleStaff))
+	showings.GET("", h.AdminShowings)
+	showings.POST("/windows", h.AdminCreateShowingWindow)
+	showings.DELETE("/windows/:id", h.AdminDeleteShowingWindow)
+	showings.POST("/:id/cancel", h.AdminCancelShowing)
+	showings.POST("/calendar-key", h.AdminResetCalendarFeeds)
 }
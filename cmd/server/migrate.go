@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"russ-rentals/internal/config"
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/migrate"
+	"russ-rentals/migrations"
+)
+
+func migrateCommand(cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: server migrate up|down|status")
+	}
+
+	ctx := context.Background()
+	migrator, closeDB, err := openMigrator(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			log.Printf("Applied %s", m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Printf("No pending migrations")
+		}
+	case "down":
+		m, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			log.Printf("No migrations to roll back")
+		} else {
+			log.Printf("Rolled back %s", m.Name)
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "APPLIED AT\tMIGRATION")
+		for _, s := range statuses {
+			appliedAt := "Pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%s\t%s\n", appliedAt, s.Name)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q: want up, down, or status", args[0])
+	}
+	return nil
+}
+
+// migrateUp applies pending migrations, logging each one
+func migrateUp(ctx context.Context, cfg *config.Config) error {
+	migrator, closeDB, err := openMigrator(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	applied, err := migrator.Up(ctx)
+	for _, m := range applied {
+		log.Printf("Applied %s", m.Name)
+	}
+	return err
+}
+
+func openMigrator(ctx context.Context, cfg *config.Config) (*migrate.Migrator, func(), error) {
+	all, err := migrate.Load(migrations.FS)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := database.Connect(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return migrate.New(db, all), db.Close, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"russ-rentals/internal/config"
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+)
+
+const roleUsage = "usage: server role list | set <clerk-user-id> <role> [email] | remove <clerk-user-id>"
+
+// roleCommand manages the local users table used when Clerk session claims
+// carry no role
+func roleCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(roleUsage)
+	}
+
+	ctx := context.Background()
+	db, err := database.Connect(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	users := repository.NewPostgresUserRepository(db)
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		all, err := users.List(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "CLERK USER ID\tEMAIL\tROLE")
+		for _, u := range all {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", u.ClerkUserID, u.Email, u.Role)
+		}
+		return w.Flush()
+	case args[0] == "set" && (len(args) == 3 || len(args) == 4):
+		role := models.Role(args[2])
+		if !role.IsValid() {
+			return fmt.Errorf("unknown role %q: want admin, staff, landlord, or tenant", args[2])
+		}
+		email := ""
+		if len(args) == 4 {
+			email = args[3]
+		}
+		user, err := users.SetRole(ctx, args[1], email, role)
+		if err != nil {
+			return err
+		}
+		log.Printf("%s is now %s", user.ClerkUserID, user.Role.Label())
+	case args[0] == "remove" && len(args) == 2:
+		if err := users.Delete(ctx, args[1]); err != nil {
+			return err
+		}
+		log.Printf("Removed role for %s", args[1])
+	default:
+		return errors.New(roleUsage)
+	}
+	return nil
+}
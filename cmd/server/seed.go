@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"russ-rentals/internal/config"
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/handlers"
+	"russ-rentals/internal/repository"
+)
+
+// seedCommand upserts the sample listings into Postgres by slug
+func seedCommand(cfg *config.Config) error {
+	ctx := context.Background()
+
+	db, err := database.Connect(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	result, err := repository.Seed(ctx, db, handlers.GetSampleProperties())
+	if err != nil {
+		return err
+	}
+	log.Printf("Seeded properties: %d created, %d updated, %d images", result.Created, result.Updated, result.Images)
+	return nil
+}
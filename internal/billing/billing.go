@@ -0,0 +1,26 @@
+// Package billing posts the charges leases run up to their ledgers, and
+// the payments tenants make against them.
+package billing
+
+import (
+	"context"
+	"time"
+
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+)
+
+// PostRent posts the rent and pet rent lease has fallen due for by today.
+// Only active and ended leases are billed, and a month is never billed
+// twice, so it's safe to call whenever the ledger is about to be read.
+// It returns how many charges it posted.
+func PostRent(ctx context.Context, ledger repository.LedgerRepository, lease models.Lease, today time.Time) (int, error) {
+	if lease.Status != models.LeaseStatusActive && lease.Status != models.LeaseStatusEnded {
+		return 0, nil
+	}
+	charges := lease.ScheduledCharges(today)
+	if len(charges) == 0 {
+		return 0, nil
+	}
+	return ledger.PostScheduled(ctx, charges)
+}
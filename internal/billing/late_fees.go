@@ -0,0 +1,195 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+)
+
+// LateFeeAssessment is the late fees due on one lease on a given day
+type LateFeeAssessment struct {
+	Lease    models.Lease
+	Property models.Property
+	Policy   models.LateFeePolicy
+	Fees     []models.LedgerEntry
+}
+
+// TotalCents adds up the assessment's fees
+func (a LateFeeAssessment) TotalCents() int64 {
+	var total int64
+	for _, fee := range a.Fees {
+		total += fee.AmountCents
+	}
+	return total
+}
+
+// AssessLateFees works out the late fees due today on every active lease
+// with a late fee policy and posts them, or only reports them if dryRun is
+// set. Rent that has fallen due is posted first, except in a dry run, which
+// never writes. Fees are topped up rather than reposted, so running it
+// more than once a day is harmless. Leases that fail are skipped, and
+// their errors returned with the assessments of the rest.
+func AssessLateFees(ctx context.Context, store *repository.Store, today time.Time, dryRun bool) ([]LateFeeAssessment, error) {
+	leases, err := store.Leases.Filter(ctx, repository.LeaseFilter{Status: models.LeaseStatusActive})
+	if err != nil {
+		return nil, err
+	}
+	policies, err := store.LateFees.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var assessments []LateFeeAssessment
+	var errs []error
+	for _, lease := range leases {
+		policy, ok := LateFeePolicyFor(lease, policies)
+		if !ok {
+			continue
+		}
+
+		a, err := assessLease(ctx, store, lease, policy, today, dryRun)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("lease %d: %w", lease.ID, err))
+			continue
+		}
+		if len(a.Fees) > 0 {
+			assessments = append(assessments, a)
+		}
+	}
+	return assessments, errors.Join(errs...)
+}
+
+// LateFeePolicyFor picks lease's late fee policy from policies: its own
+// if it has one, else its property's. It reports false if neither exists.
+func LateFeePolicyFor(lease models.Lease, policies []models.LateFeePolicy) (models.LateFeePolicy, bool) {
+	if lease.LateFeePolicyID != nil {
+		for _, p := range policies {
+			if p.ID == *lease.LateFeePolicyID {
+				return p, true
+			}
+		}
+	}
+	for _, p := range policies {
+		if slices.Contains(p.PropertyIDs, lease.PropertyID) {
+			return p, true
+		}
+	}
+	return models.LateFeePolicy{}, false
+}
+
+func assessLease(ctx context.Context, store *repository.Store, lease models.Lease, policy models.LateFeePolicy, today time.Time, dryRun bool) (LateFeeAssessment, error) {
+	a := LateFeeAssessment{Lease: lease, Policy: policy}
+	property, err := store.Properties.GetByID(ctx, lease.PropertyID)
+	if err != nil {
+		return a, err
+	}
+	a.Property = *property
+
+	if !dryRun {
+		if _, err := PostRent(ctx, store.Ledger, lease, today); err != nil {
+			return a, err
+		}
+	}
+	ledger, err := store.Ledger.ListByLease(ctx, lease.ID)
+	if err != nil {
+		return a, err
+	}
+	if dryRun {
+		ledger = withScheduledCharges(ledger, lease, today)
+	}
+
+	a.Fees = LateFees(lease, ledger, policy, property.State, today)
+	if !dryRun && len(a.Fees) > 0 {
+		if _, err := store.Ledger.PostScheduled(ctx, a.Fees); err != nil {
+			return a, err
+		}
+	}
+	return a, nil
+}
+
+// LateFees works out the late fees to post to lease's ledger today under
+// policy, for a property in state. Each month's rent still unpaid after
+// the grace period runs up fees until it's paid or the fees reach their
+// cap. Fees already on the ledger count towards the total, so the result
+// only ever tops them up.
+func LateFees(lease models.Lease, ledger models.Ledger, policy models.LateFeePolicy, state string, today time.Time) []models.LedgerEntry {
+	type month struct {
+		period, due          time.Time
+		rent, unpaid, posted int64
+	}
+	months := make(map[time.Time]*month)
+	outstanding := ledger.Outstanding()
+	for i, e := range ledger {
+		if e.Period == nil {
+			continue
+		}
+		m := months[*e.Period]
+		if m == nil {
+			m = &month{period: *e.Period}
+			months[*e.Period] = m
+		}
+		switch e.Kind {
+		case models.LedgerKindRent, models.LedgerKindPetRent:
+			if m.due.IsZero() || e.PostedOn.Before(m.due) {
+				m.due = e.PostedOn
+			}
+			m.rent += e.AmountCents
+			m.unpaid += outstanding[i]
+		case models.LedgerKindLateFee:
+			m.posted += e.AmountCents
+		}
+	}
+
+	periods := make([]time.Time, 0, len(months))
+	for period := range months {
+		periods = append(periods, period)
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Before(periods[j]) })
+
+	today = models.Date(today)
+	var fees []models.LedgerEntry
+	for _, period := range periods {
+		m := months[period]
+		if m.unpaid == 0 {
+			continue
+		}
+		total := policy.FeeCents(m.rent, m.due, today, state)
+		if total <= m.posted {
+			continue
+		}
+		p := period
+		fees = append(fees, models.LedgerEntry{
+			LeaseID:     lease.ID,
+			Kind:        models.LedgerKindLateFee,
+			Description: fmt.Sprintf("Late fee on %s rent, due %s", period.Format("January 2006"), m.due.Format("Jan 2")),
+			AmountCents: total - m.posted,
+			PostedOn:    today,
+			Period:      &p,
+		})
+	}
+	return fees
+}
+
+// withScheduledCharges adds the rent lease has fallen due for by today
+// that isn't on ledger yet, as PostRent would
+func withScheduledCharges(ledger models.Ledger, lease models.Lease, today time.Time) models.Ledger {
+	posted := make(map[string]bool)
+	for _, e := range ledger {
+		if e.Period != nil {
+			posted[string(e.Kind)+e.Period.Format("2006-01")] = true
+		}
+	}
+	for _, e := range lease.ScheduledCharges(today) {
+		if !posted[string(e.Kind)+e.Period.Format("2006-01")] {
+			ledger = append(ledger, e)
+		}
+	}
+	ledger.Sort()
+	return ledger
+}
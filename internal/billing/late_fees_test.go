@@ -0,0 +1,190 @@
+package billing
+
+import (
+	"testing"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// fee is a late fee already on the ledger for period
+func fee(period, posted time.Time, cents int64) models.LedgerEntry {
+	return models.LedgerEntry{Kind: models.LedgerKindLateFee, AmountCents: cents, PostedOn: posted, Period: &period}
+}
+
+func paid(posted time.Time, cents int64) models.LedgerEntry {
+	return models.LedgerEntry{Kind: models.LedgerKindPayment, AmountCents: cents, PostedOn: posted}
+}
+
+func TestLateFees(t *testing.T) {
+	lease := models.Lease{
+		ID:               1,
+		StartDate:        date(2026, time.January, 1),
+		EndDate:          date(2026, time.December, 31),
+		MonthlyRentCents: 150000,
+	}
+	withPet := lease
+	withPet.PetRentCents = 5000
+	policy := models.LateFeePolicy{GraceDays: 5, Type: models.LateFeeTypeFlat, FlatCents: 5000, DailyCents: 1000}
+	capped := policy
+	capped.MaxCents = 6000
+	jan, feb, mar := date(2026, time.January, 1), date(2026, time.February, 1), date(2026, time.March, 1)
+
+	// paidTo is lease's rent through March with January and February paid
+	paidTo := func(lease models.Lease, entries ...models.LedgerEntry) models.Ledger {
+		ledger := models.Ledger(lease.ScheduledCharges(mar))
+		ledger = append(ledger, paid(date(2026, time.January, 1), lease.MonthlyTotalCents()))
+		ledger = append(ledger, paid(date(2026, time.February, 1), lease.MonthlyTotalCents()))
+		ledger = append(ledger, entries...)
+		ledger.Sort()
+		return ledger
+	}
+
+	type want struct {
+		period time.Time
+		cents  int64
+	}
+	tests := []struct {
+		name   string
+		lease  models.Lease
+		ledger models.Ledger
+		policy models.LateFeePolicy
+		state  string
+		today  time.Time
+		want   []want
+	}{
+		{
+			name:   "within grace",
+			ledger: paidTo(lease),
+			policy: policy,
+			today:  date(2026, time.March, 6),
+		},
+		{
+			name:   "first fee after grace",
+			ledger: paidTo(lease),
+			policy: policy,
+			today:  date(2026, time.March, 7),
+			want:   []want{{mar, 5000}},
+		},
+		{
+			name:   "daily accrual tops up",
+			ledger: paidTo(lease, fee(mar, date(2026, time.March, 7), 5000)),
+			policy: policy,
+			today:  date(2026, time.March, 9),
+			want:   []want{{mar, 2000}},
+		},
+		{
+			name:   "repeated on the same day",
+			ledger: paidTo(lease, fee(mar, date(2026, time.March, 7), 5000), fee(mar, date(2026, time.March, 9), 2000)),
+			policy: policy,
+			today:  date(2026, time.March, 9),
+		},
+		{
+			name:   "missed days caught up",
+			ledger: paidTo(lease, fee(mar, date(2026, time.March, 7), 5000)),
+			policy: policy,
+			today:  date(2026, time.March, 12),
+			want:   []want{{mar, 5000}},
+		},
+		{
+			name:   "policy cap",
+			ledger: paidTo(lease, fee(mar, date(2026, time.March, 7), 5000)),
+			policy: capped,
+			today:  date(2026, time.March, 20),
+			want:   []want{{mar, 1000}},
+		},
+		{
+			name:   "policy cap reached",
+			ledger: paidTo(lease, fee(mar, date(2026, time.March, 7), 5000), fee(mar, date(2026, time.March, 8), 1000)),
+			policy: capped,
+			today:  date(2026, time.March, 20),
+		},
+		{
+			name:   "state cap",
+			ledger: paidTo(lease, fee(mar, date(2026, time.March, 7), 3000)),
+			policy: policy,
+			state:  "NY",
+			today:  date(2026, time.March, 20),
+			want:   []want{{mar, 2000}},
+		},
+		{
+			name:   "state grace",
+			ledger: paidTo(lease),
+			policy: models.LateFeePolicy{GraceDays: 1, Type: models.LateFeeTypeFlat, FlatCents: 5000},
+			state:  "ME",
+			today:  date(2026, time.March, 10),
+		},
+		{
+			name:   "percent of rent and pet rent",
+			lease:  withPet,
+			ledger: paidTo(withPet),
+			policy: models.LateFeePolicy{GraceDays: 5, Type: models.LateFeeTypePercent, PercentBps: 1000},
+			today:  date(2026, time.March, 7),
+			want:   []want{{mar, 15500}},
+		},
+		{
+			name:   "paid rent",
+			ledger: paidTo(lease, paid(date(2026, time.March, 3), 150000)),
+			policy: policy,
+			today:  date(2026, time.March, 20),
+		},
+		{
+			name:   "partly paid rent",
+			ledger: paidTo(lease, paid(date(2026, time.March, 3), 100000)),
+			policy: policy,
+			today:  date(2026, time.March, 7),
+			want:   []want{{mar, 5000}},
+		},
+		{
+			name:   "fees stop once rent is paid",
+			ledger: paidTo(lease, fee(mar, date(2026, time.March, 7), 5000), paid(date(2026, time.March, 8), 150000)),
+			policy: policy,
+			today:  date(2026, time.March, 20),
+		},
+		{
+			name: "payments clear rent before an older fee",
+			ledger: paidTo(lease,
+				fee(feb, date(2026, time.February, 7), 5000),
+				paid(date(2026, time.March, 2), 150000),
+			),
+			policy: policy,
+			today:  date(2026, time.March, 20),
+		},
+		{
+			name: "every unpaid month",
+			ledger: func() models.Ledger {
+				ledger := models.Ledger(lease.ScheduledCharges(mar))
+				ledger = append(ledger, paid(jan, 150000))
+				ledger.Sort()
+				return ledger
+			}(),
+			policy: models.LateFeePolicy{GraceDays: 5, Type: models.LateFeeTypeFlat, FlatCents: 5000},
+			today:  date(2026, time.March, 7),
+			want:   []want{{feb, 5000}, {mar, 5000}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := tt.lease
+			if l.ID == 0 {
+				l = lease
+			}
+			fees := LateFees(l, tt.ledger, tt.policy, tt.state, tt.today)
+			if len(fees) != len(tt.want) {
+				t.Fatalf("got %d fees, want %d: %+v", len(fees), len(tt.want), fees)
+			}
+			for i, f := range fees {
+				if !f.Period.Equal(tt.want[i].period) || f.AmountCents != tt.want[i].cents {
+					t.Errorf("fee %d is %d for %s, want %d for %s", i, f.AmountCents, f.Period.Format("2006-01"), tt.want[i].cents, tt.want[i].period.Format("2006-01"))
+				}
+				if f.Kind != models.LedgerKindLateFee || f.LeaseID != l.ID || !f.PostedOn.Equal(tt.today) {
+					t.Errorf("fee %d = %+v, want a late fee on lease %d posted %s", i, f, l.ID, tt.today.Format(time.DateOnly))
+				}
+			}
+		})
+	}
+}
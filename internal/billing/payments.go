@@ -0,0 +1,240 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/payment"
+	"russ-rentals/internal/repository"
+)
+
+// PostPayment posts a rent payment that went through to its lease's
+// ledger, on the day it was paid in loc. Each payment is posted once, so
+// it's safe to call for every notice of it. It reports whether it posted
+// it.
+func PostPayment(ctx context.Context, ledger repository.LedgerRepository, p models.Payment, loc *time.Location) (bool, error) {
+	if p.Purpose != models.PaymentPurposeRent || p.LeaseID == nil || !p.Paid() {
+		return false, nil
+	}
+	paid := time.Now()
+	if p.PaidAt != nil {
+		paid = *p.PaidAt
+	}
+	id := p.ID
+	return ledger.PostPayment(ctx, &models.LedgerEntry{
+		LeaseID:     *p.LeaseID,
+		Kind:        models.LedgerKindPayment,
+		Description: "Online payment, ref " + p.IntentID,
+		AmountCents: p.AmountCents,
+		PostedOn:    models.Date(paid.In(loc)),
+		PaymentID:   &id,
+	})
+}
+
+// RentIdempotencyKey keys the gateway intent of one attempt at paying rent
+// on a lease, so a retried request can't charge twice
+func RentIdempotencyKey(leaseID int64, attempt string) string {
+	return fmt.Sprintf("lease-%d-rent-%s", leaseID, attempt)
+}
+
+// AutopayCharge is how charging one autopay enrollment went
+type AutopayCharge struct {
+	// Autopay is the enrollment as it was left
+	Autopay models.Autopay
+	Lease   models.Lease
+	// Payment is nil when nothing was owed
+	Payment *models.Payment
+}
+
+// ChargeAutopay charges every autopay enrollment due today its lease's
+// balance through gateway. today is in the business's time zone, which
+// payments are posted in. A declined charge is retried
+// models.AutopayRetryDays later, then left for the tenant to pay.
+// Enrollments whose lease isn't active are skipped. Failures don't stop
+// the rest, and are returned with the charges that were made.
+func ChargeAutopay(ctx context.Context, store *repository.Store, gateway payment.Gateway, today time.Time) ([]AutopayCharge, error) {
+	enrollments, err := store.Autopay.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var charges []AutopayCharge
+	var errs []error
+	for _, a := range enrollments {
+		period, due := a.Due(today)
+		if !due {
+			continue
+		}
+		lease, err := store.Leases.Get(ctx, a.LeaseID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("lease %d: %w", a.LeaseID, err))
+			continue
+		}
+		if lease.Status != models.LeaseStatusActive {
+			continue
+		}
+		if a.Gateway != gateway.Name() {
+			errs = append(errs, fmt.Errorf("lease %d: autopay card was saved with %s", a.LeaseID, a.Gateway))
+			continue
+		}
+
+		c, err := chargeAutopay(ctx, store, gateway, a, *lease, period, today)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("lease %d: %w", a.LeaseID, err))
+			continue
+		}
+		charges = append(charges, c)
+	}
+	return charges, errors.Join(errs...)
+}
+
+// chargeAutopay charges a's card lease's balance for period, less any
+// payments still in flight, and saves how it went
+func chargeAutopay(ctx context.Context, store *repository.Store, gateway payment.Gateway, a models.Autopay, lease models.Lease, period, today time.Time) (AutopayCharge, error) {
+	c := AutopayCharge{Lease: lease}
+	if _, err := PostRent(ctx, store.Ledger, lease, today); err != nil {
+		return c, err
+	}
+	inFlight, err := settlePending(ctx, store, gateway, lease.ID, today.Location())
+	if err != nil {
+		return c, err
+	}
+	ledger, err := store.Ledger.ListByLease(ctx, lease.ID)
+	if err != nil {
+		return c, err
+	}
+	if a.Period == nil || !a.Period.Equal(period) {
+		a.Attempts = 0
+	}
+	a.Period = &period
+	a.RetryOn = nil
+	a.LastError = ""
+
+	if owed := ledger.BalanceCents() - inFlight; owed > 0 {
+		if c.Payment, err = chargeSaved(ctx, store, gateway, a, lease, owed, today.Location()); err != nil {
+			return c, err
+		}
+		if c.Payment.Status == models.PaymentStatusFailed {
+			a.Attempts++
+			a.LastError = c.Payment.FailureMessage
+			if a.Attempts <= len(models.AutopayRetryDays) {
+				retry := models.Date(today).AddDate(0, 0, models.AutopayRetryDays[a.Attempts-1])
+				a.RetryOn = &retry
+			}
+		} else {
+			a.Attempts = 0
+		}
+	}
+
+	if err := store.Autopay.SaveAttempt(ctx, &a); err != nil {
+		return c, err
+	}
+	c.Autopay = a
+	return c, nil
+}
+
+// settlePending asks gateway how a lease's pending rent payments are
+// going before autopay charges its balance. Payments that went through
+// are posted to the ledger, so they aren't charged again, and those
+// declined are marked failed. It returns the total of the payments still
+// processing, such as bank debits, which the ledger doesn't show yet.
+func settlePending(ctx context.Context, store *repository.Store, gateway payment.Gateway, leaseID int64, loc *time.Location) (int64, error) {
+	payments, err := store.Payments.ListByLease(ctx, leaseID)
+	if err != nil {
+		return 0, err
+	}
+	var processing int64
+	for _, p := range payments {
+		if p.Purpose != models.PaymentPurposeRent || p.Status != models.PaymentStatusPending || p.Gateway != gateway.Name() {
+			continue
+		}
+		intent, err := gateway.Confirm(ctx, p.IntentID, "")
+		if errors.Is(err, payment.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("check payment %s: %w", p.IntentID, err)
+		}
+		switch intent.Status {
+		case payment.StatusProcessing:
+			processing += p.AmountCents
+		case payment.StatusSucceeded:
+			paid, err := store.Payments.SetStatus(ctx, p.ID, models.PaymentStatusSucceeded, "")
+			if err != nil {
+				return 0, err
+			}
+			if _, err := PostPayment(ctx, store.Ledger, *paid, loc); err != nil {
+				return 0, err
+			}
+		case payment.StatusFailed, payment.StatusCanceled:
+			message := intent.FailureMessage
+			if message == "" {
+				message = "Your payment didn't go through."
+			}
+			if _, err := store.Payments.SetStatus(ctx, p.ID, models.PaymentStatusFailed, message); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return processing, nil
+}
+
+// chargeSaved charges amount to a's saved card, records the payment and,
+// if it went through, posts it to the ledger. Payments still processing
+// are posted by the payment webhook once they clear. Each attempt for a's
+// period is keyed on its number, so a run repeated after a charge that
+// wasn't saved finds the charge rather than making another.
+func chargeSaved(ctx context.Context, store *repository.Store, gateway payment.Gateway, a models.Autopay, lease models.Lease, amount int64, loc *time.Location) (*models.Payment, error) {
+	attempt := fmt.Sprintf("autopay-%s-%d", a.Period.Format(time.DateOnly), a.Attempts+1)
+	intent, err := gateway.ChargeSaved(ctx, payment.IntentRequest{
+		Amount:      amount,
+		Description: fmt.Sprintf("Autopay for lease %d", lease.ID),
+		Metadata: map[string]string{
+			"lease_id": fmt.Sprint(lease.ID),
+			"purpose":  string(models.PaymentPurposeRent),
+		},
+		IdempotencyKey: RentIdempotencyKey(lease.ID, attempt),
+	}, payment.SavedMethod{ID: a.MethodID, CustomerID: a.CustomerID, Label: a.MethodLabel})
+	if err != nil {
+		return nil, err
+	}
+	if p, err := store.Payments.GetByIntent(ctx, intent.ID); !errors.Is(err, repository.ErrNotFound) {
+		return p, err
+	}
+
+	p := &models.Payment{
+		Gateway:     gateway.Name(),
+		IntentID:    intent.ID,
+		Purpose:     models.PaymentPurposeRent,
+		LeaseID:     &lease.ID,
+		PayerID:     a.PayerID,
+		AmountCents: amount,
+		Currency:    intent.Currency,
+	}
+	if err := store.Payments.Create(ctx, p); err != nil {
+		return nil, err
+	}
+
+	switch intent.Status {
+	case payment.StatusSucceeded:
+		if p, err = store.Payments.SetStatus(ctx, p.ID, models.PaymentStatusSucceeded, ""); err != nil {
+			return nil, err
+		}
+		_, err = PostPayment(ctx, store.Ledger, *p, loc)
+		return p, err
+	case payment.StatusFailed, payment.StatusCanceled, payment.StatusRequiresAction:
+		message := intent.FailureMessage
+		switch {
+		case intent.Status == payment.StatusRequiresAction:
+			message = "Your bank asked to confirm this payment, which autopay can't do."
+		case message == "":
+			message = "Your card was declined."
+		}
+		return store.Payments.SetStatus(ctx, p.ID, models.PaymentStatusFailed, message)
+	default:
+		return p, nil
+	}
+}
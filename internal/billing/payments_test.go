@@ -0,0 +1,240 @@
+package billing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/payment"
+	"russ-rentals/internal/repository"
+)
+
+// decliningGateway is a FakeGateway whose saved cards are declined while
+// decline is set
+type decliningGateway struct {
+	*payment.FakeGateway
+	decline bool
+}
+
+func (g *decliningGateway) ChargeSaved(ctx context.Context, req payment.IntentRequest, method payment.SavedMethod) (*payment.Intent, error) {
+	if g.decline {
+		method.ID = "pm_fake_" + payment.FakeCardDeclined
+	}
+	return g.FakeGateway.ChargeSaved(ctx, req, method)
+}
+
+// autopayLease creates an active lease starting March 1, 2026 with an
+// autopay enrollment charging gateway's card on the 1st
+func autopayLease(t *testing.T, store *repository.Store, gateway payment.Gateway) models.Lease {
+	t.Helper()
+	ctx := context.Background()
+	lease := models.Lease{
+		PropertyID:       1,
+		Status:           models.LeaseStatusDraft,
+		StartDate:        date(2026, time.March, 1),
+		EndDate:          date(2027, time.February, 28),
+		MonthlyRentCents: 150000,
+		Tenants:          []models.LeaseTenant{{UserID: "ten1", Name: "Tess", Email: "tess@example.com"}},
+	}
+	if err := store.Leases.Create(ctx, &lease); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Leases.SetStatus(ctx, lease.ID, models.LeaseStatusDraft, models.LeaseStatusActive); err != nil {
+		t.Fatal(err)
+	}
+	lease.Status = models.LeaseStatusActive
+
+	method, err := gateway.SaveMethod(ctx, payment.SaveMethodRequest{PaymentMethod: payment.FakeCardSuccess})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := models.Autopay{
+		LeaseID:     lease.ID,
+		PayerID:     "ten1",
+		Gateway:     gateway.Name(),
+		CustomerID:  method.CustomerID,
+		MethodID:    method.ID,
+		MethodLabel: method.Label,
+		ChargeDay:   1,
+	}
+	if err := store.Autopay.Enroll(ctx, &a); err != nil {
+		t.Fatal(err)
+	}
+	return lease
+}
+
+func balance(t *testing.T, store *repository.Store, leaseID int64) int64 {
+	t.Helper()
+	ledger, err := store.Ledger.ListByLease(context.Background(), leaseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ledger.BalanceCents()
+}
+
+func TestChargeAutopayRetriesDeclines(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore([]models.Property{{ID: 1, Title: "Maple House"}})
+	gateway := &decliningGateway{FakeGateway: payment.NewFakeGateway("usd", "whsec"), decline: true}
+	lease := autopayLease(t, store, gateway)
+
+	runs := []struct {
+		day      time.Time
+		decline  bool
+		status   models.PaymentStatus // empty when nothing is charged
+		attempts int
+		retryOn  *time.Time
+		balance  int64
+	}{
+		{date(2026, time.March, 1), true, models.PaymentStatusFailed, 1, ptr(date(2026, time.March, 2)), 150000},
+		{date(2026, time.March, 2), true, models.PaymentStatusFailed, 2, ptr(date(2026, time.March, 5)), 150000},
+		{date(2026, time.March, 3), false, "", 2, ptr(date(2026, time.March, 5)), 150000},
+		{date(2026, time.March, 5), false, models.PaymentStatusSucceeded, 0, nil, 0},
+		{date(2026, time.March, 6), false, "", 0, nil, 0},
+	}
+	for _, run := range runs {
+		gateway.decline = run.decline
+		charges, err := ChargeAutopay(ctx, store, gateway, run.day)
+		if err != nil {
+			t.Fatalf("%s: ChargeAutopay() error = %v", run.day.Format(time.DateOnly), err)
+		}
+
+		var status models.PaymentStatus
+		if len(charges) == 1 && charges[0].Payment != nil {
+			status = charges[0].Payment.Status
+		}
+		if status != run.status || len(charges) > 1 {
+			t.Errorf("%s: charged %d times with status %q, want status %q", run.day.Format(time.DateOnly), len(charges), status, run.status)
+		}
+		a, err := store.Autopay.GetByLease(ctx, lease.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Attempts != run.attempts || !equalDates(a.RetryOn, run.retryOn) {
+			t.Errorf("%s: attempts %d retry %v, want %d retry %v", run.day.Format(time.DateOnly), a.Attempts, a.RetryOn, run.attempts, run.retryOn)
+		}
+		if got := balance(t, store, lease.ID); got != run.balance {
+			t.Errorf("%s: balance = %d, want %d", run.day.Format(time.DateOnly), got, run.balance)
+		}
+	}
+
+	// One failed payment per declined attempt, then the one that went
+	// through, each under its own intent
+	payments, err := store.Payments.ListByLease(ctx, lease.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intents := make(map[string]bool)
+	for _, p := range payments {
+		intents[p.IntentID] = true
+	}
+	if len(payments) != 3 || len(intents) != 3 {
+		t.Errorf("recorded %d payments under %d intents, want 3 of each", len(payments), len(intents))
+	}
+}
+
+func TestChargeAutopayReusesIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore([]models.Property{{ID: 1, Title: "Maple House"}})
+	gateway := payment.NewFakeGateway("usd", "whsec")
+	lease := autopayLease(t, store, gateway)
+	a, err := store.Autopay.GetByLease(ctx, lease.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An earlier run charged the card but stopped before recording it
+	earlier, err := gateway.ChargeSaved(ctx, payment.IntentRequest{
+		Amount:         150000,
+		IdempotencyKey: RentIdempotencyKey(lease.ID, "autopay-2026-03-01-1"),
+	}, payment.SavedMethod{ID: a.MethodID, CustomerID: a.CustomerID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	charges, err := ChargeAutopay(ctx, store, gateway, date(2026, time.March, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(charges) != 1 || charges[0].Payment == nil || charges[0].Payment.IntentID != earlier.ID {
+		t.Fatalf("ChargeAutopay() = %+v, want the earlier intent %s recorded", charges, earlier.ID)
+	}
+
+	// Charging the same attempt again finds the payment already recorded
+	period := date(2026, time.March, 1)
+	a.Period = &period
+	again, err := chargeSaved(ctx, store, gateway, *a, lease, 150000, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != charges[0].Payment.ID {
+		t.Errorf("chargeSaved() again = payment %d, want the recorded payment %d", again.ID, charges[0].Payment.ID)
+	}
+
+	payments, err := store.Payments.ListByLease(ctx, lease.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payments) != 1 {
+		t.Errorf("recorded %d payments, want 1", len(payments))
+	}
+	if got := balance(t, store, lease.ID); got != 0 {
+		t.Errorf("balance = %d, want 0", got)
+	}
+}
+
+func TestChargeAutopaySettlesPending(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore([]models.Property{{ID: 1, Title: "Maple House"}})
+	gateway := payment.NewFakeGateway("usd", "whsec")
+	lease := autopayLease(t, store, gateway)
+
+	// The tenant paid by hand, but the webhook saying so never came
+	intent, err := gateway.CreateIntent(ctx, payment.IntentRequest{Amount: 150000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := models.Payment{
+		Gateway:     gateway.Name(),
+		IntentID:    intent.ID,
+		Purpose:     models.PaymentPurposeRent,
+		LeaseID:     &lease.ID,
+		PayerID:     "ten1",
+		AmountCents: 150000,
+		Currency:    "usd",
+	}
+	if err := store.Payments.Create(ctx, &p); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gateway.Confirm(ctx, intent.ID, payment.FakeCardSuccess); err != nil {
+		t.Fatal(err)
+	}
+
+	charges, err := ChargeAutopay(ctx, store, gateway, date(2026, time.March, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(charges) != 1 || charges[0].Payment != nil {
+		t.Fatalf("ChargeAutopay() = %+v, want the enrollment visited without a charge", charges)
+	}
+	settled, err := store.Payments.Get(ctx, p.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settled.Status != models.PaymentStatusSucceeded {
+		t.Errorf("pending payment status = %q, want succeeded", settled.Status)
+	}
+	if got := balance(t, store, lease.ID); got != 0 {
+		t.Errorf("balance = %d, want 0", got)
+	}
+}
+
+func ptr(t time.Time) *time.Time { return &t }
+
+func equalDates(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
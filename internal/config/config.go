@@ -1,24 +1,96 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strings"
+	"time"
+
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/payment"
+	"russ-rentals/internal/storage"
+	"russ-rentals/internal/token"
 )
 
 type Config struct {
 	DatabaseURL          string
+	StorageDriver        string
+	AutoMigrate          bool
 	ClerkSecretKey       string
 	ClerkPublishableKey  string
+	AdminUserIDs         []string
 	Port                 string
 	Environment          string
+	BaseURL              string
+	AppSecret            string
+	AppTimezone          string
+	MailDriver           string
+	MailFrom             string
+	MailDir              string
+	SMTPAddr             string
+	SMTPUsername         string
+	SMTPPassword         string
+	UploadDriver         string
+	UploadDir            string
+	UploadPublicURL      string
+	DocumentDriver       string
+	DocumentDir          string
+	DocumentBucket       string
+	S3Endpoint           string
+	S3Region             string
+	S3Bucket             string
+	S3AccessKeyID        string
+	S3SecretAccessKey    string
+	PaymentDriver        string
+	PaymentCurrency      string
+	PaymentWebhookSecret string
+	StripeSecretKey      string
+	StripePublishableKey string
+	StripeWebhookSecret  string
+	StripeAPIURL         string
+	JobsEnabled          bool
+	CronSecret           string
 }
 
 func Load() *Config {
 	return &Config{
 		DatabaseURL:          getEnv("DATABASE_URL", ""),
+		StorageDriver:        getEnv("STORAGE_DRIVER", defaultStorageDriver()),
+		AutoMigrate:          getEnv("AUTO_MIGRATE", "false") == "true",
 		ClerkSecretKey:       getEnv("CLERK_SECRET_KEY", ""),
 		ClerkPublishableKey:  getEnv("CLERK_PUBLISHABLE_KEY", ""),
+		AdminUserIDs:         getEnvList("ADMIN_USER_IDS"),
 		Port:                 getEnv("PORT", "3000"),
 		Environment:          getEnv("ENVIRONMENT", "development"),
+		BaseURL:              getEnv("BASE_URL", ""),
+		AppSecret:            getEnv("APP_SECRET", ""),
+		AppTimezone:          getEnv("APP_TIMEZONE", "America/Chicago"),
+		MailDriver:           getEnv("MAIL_DRIVER", "log"),
+		MailFrom:             getEnv("MAIL_FROM", "Russ Rentals <info@russrentals.com>"),
+		MailDir:              getEnv("MAIL_DIR", "tmp/mail"),
+		SMTPAddr:             getEnv("SMTP_ADDR", ""),
+		SMTPUsername:         getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:         getEnv("SMTP_PASSWORD", ""),
+		UploadDriver:         getEnv("UPLOAD_DRIVER", "local"),
+		UploadDir:            getEnv("UPLOAD_DIR", "uploads"),
+		UploadPublicURL:      getEnv("UPLOAD_PUBLIC_URL", ""),
+		DocumentDriver:       getEnv("DOCUMENT_DRIVER", "local"),
+		DocumentDir:          getEnv("DOCUMENT_DIR", "documents"),
+		DocumentBucket:       getEnv("DOCUMENT_S3_BUCKET", ""),
+		S3Endpoint:           getEnv("S3_ENDPOINT", ""),
+		S3Region:             getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:             getEnv("S3_BUCKET", ""),
+		S3AccessKeyID:        getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey:    getEnv("S3_SECRET_ACCESS_KEY", ""),
+		PaymentDriver:        getEnv("PAYMENT_DRIVER", "fake"),
+		PaymentCurrency:      getEnv("PAYMENT_CURRENCY", "usd"),
+		PaymentWebhookSecret: getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+		StripeSecretKey:      getEnv("STRIPE_SECRET_KEY", ""),
+		StripePublishableKey: getEnv("STRIPE_PUBLISHABLE_KEY", ""),
+		StripeWebhookSecret:  getEnv("STRIPE_WEBHOOK_SECRET", ""),
+		StripeAPIURL:         getEnv("STRIPE_API_URL", ""),
+		JobsEnabled:          getEnv("JOBS_ENABLED", "true") == "true",
+		CronSecret:           getEnv("CRON_SECRET", ""),
 	}
 }
 
@@ -29,6 +101,27 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// defaultStorageDriver picks postgres when a database is configured. Only
+// local development falls back to in-memory storage without one; elsewhere,
+// Vercel included, data would be lost on every restart or cold start.
+func defaultStorageDriver() string {
+	if os.Getenv("DATABASE_URL") == "" && getEnv("ENVIRONMENT", "development") == "development" && os.Getenv("VERCEL") == "" {
+		return "memory"
+	}
+	return "postgres"
+}
+
+// getEnvList splits a comma-separated variable, dropping blank entries
+func getEnvList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
 }
@@ -36,3 +129,114 @@ func (c *Config) IsDevelopment() bool {
 func (c *Config) IsProduction() bool {
 	return c.Environment == "production"
 }
+
+// MailOptions returns the settings for mailer.New
+func (c *Config) MailOptions() mailer.Options {
+	return mailer.Options{
+		From:         c.MailFrom,
+		Dir:          c.MailDir,
+		SMTPAddr:     c.SMTPAddr,
+		SMTPUsername: c.SMTPUsername,
+		SMTPPassword: c.SMTPPassword,
+	}
+}
+
+// StorageOptions returns the settings for storage.New
+func (c *Config) StorageOptions() storage.Options {
+	return storage.Options{
+		Dir:             c.UploadDir,
+		PublicURL:       c.UploadPublicURL,
+		Endpoint:        c.S3Endpoint,
+		Region:          c.S3Region,
+		Bucket:          c.S3Bucket,
+		AccessKeyID:     c.S3AccessKeyID,
+		SecretAccessKey: c.S3SecretAccessKey,
+	}
+}
+
+// DocumentStorageOptions returns the settings for storage.New for lease
+// documents. They share the S3 credentials of uploads but need a bucket of
+// their own, since the uploads bucket is public.
+func (c *Config) DocumentStorageOptions() storage.Options {
+	return storage.Options{
+		Dir:             c.DocumentDir,
+		Endpoint:        c.S3Endpoint,
+		Region:          c.S3Region,
+		Bucket:          c.DocumentBucket,
+		AccessKeyID:     c.S3AccessKeyID,
+		SecretAccessKey: c.S3SecretAccessKey,
+	}
+}
+
+// PaymentOptions returns the settings for payment.New. The fake gateway
+// accepts any card, so production must use a real one.
+func (c *Config) PaymentOptions() (payment.Options, error) {
+	if c.IsProduction() && c.PaymentDriver == payment.DriverFake {
+		return payment.Options{}, fmt.Errorf("PAYMENT_DRIVER=fake is not allowed in production")
+	}
+	webhookSecret := c.PaymentWebhookSecret
+	if c.PaymentDriver == payment.DriverStripe {
+		webhookSecret = c.StripeWebhookSecret
+	}
+	return payment.Options{
+		Currency:       c.PaymentCurrency,
+		WebhookSecret:  webhookSecret,
+		SecretKey:      c.StripeSecretKey,
+		PublishableKey: c.StripePublishableKey,
+		APIURL:         c.StripeAPIURL,
+	}, nil
+}
+
+// PublicURL returns the site's origin for links in emails. Production
+// must set BASE_URL; elsewhere the server's own port on localhost is used.
+func (c *Config) PublicURL() (string, error) {
+	if c.BaseURL != "" {
+		return c.BaseURL, nil
+	}
+	if c.IsProduction() {
+		return "", fmt.Errorf("BASE_URL is required in production")
+	}
+	return "http://localhost:" + c.Port, nil
+}
+
+// CheckStorage reports storage settings that can't work. Production
+// refuses in-memory storage, and postgres needs DATABASE_URL.
+func (c *Config) CheckStorage() error {
+	switch {
+	case c.StorageDriver == "memory" && c.IsProduction():
+		return fmt.Errorf("STORAGE_DRIVER=memory is not allowed in production")
+	case c.StorageDriver == "postgres" && c.DatabaseURL == "" && c.IsProduction():
+		return fmt.Errorf("DATABASE_URL is required in production")
+	case c.StorageDriver == "postgres" && c.DatabaseURL == "":
+		return fmt.Errorf("DATABASE_URL is required; set STORAGE_DRIVER=memory to run without a database")
+	}
+	return nil
+}
+
+// Location returns the business's time zone, which showings, visits and
+// job schedules are read in and dates are shown in. The server's own zone
+// isn't used, as it is UTC on serverless hosts.
+func (c *Config) Location() (*time.Location, error) {
+	loc, err := time.LoadLocation(c.AppTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("APP_TIMEZONE: %w", err)
+	}
+	return loc, nil
+}
+
+// SigningSecret returns the key for signed links. APP_SECRET is required in
+// production and whenever BASE_URL is set, since links sent out by email must
+// outlive the process; elsewhere a random key is used so links only last
+// until restart.
+func (c *Config) SigningSecret() (string, error) {
+	if c.AppSecret != "" {
+		return c.AppSecret, nil
+	}
+	if c.IsProduction() {
+		return "", fmt.Errorf("APP_SECRET is required in production")
+	}
+	if c.BaseURL != "" {
+		return "", fmt.Errorf("APP_SECRET is required when BASE_URL is set")
+	}
+	return token.RandomSecret(), nil
+}
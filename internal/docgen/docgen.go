@@ -0,0 +1,465 @@
+// Package docgen renders lease agreements and notices to tenants as PDFs.
+// Each document is a text/template filled in with the lease and its
+// property. Templates write a small line-based markup:
+//
+//	# Title
+//	## Section heading
+//	- Bullet point
+//
+// Other lines are paragraph text, and blank lines end paragraphs. The
+// signature blocks of the document's parties are added after the text.
+// Once the parties have signed, the document is rendered again with their
+// signatures in the blocks and a certificate of the signing at the end.
+package docgen
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"image/png"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/pdf"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Landlord is who leases and notices are issued by
+const Landlord = "Russ Rentals"
+
+// Template names
+const (
+	TemplateLease          = "lease"
+	TemplateRenewalOffer   = "renewal-offer"
+	TemplateRentIncrease   = "rent-increase"
+	TemplateNoticeToVacate = "notice-to-vacate"
+)
+
+// Names of the notice details templates ask for. They double as the
+// admin form's field names.
+const (
+	FieldEffectiveDate = "effectiveDate"
+	FieldNewRent       = "newRent"
+	FieldNewEndDate    = "newEndDate"
+	FieldRespondBy     = "respondBy"
+	FieldMessage       = "message"
+)
+
+// Template is a kind of document that can be generated
+type Template struct {
+	Name  string
+	Title string
+	Kind  models.DocumentKind
+	// Fields are the notice details staff fill in, in form order
+	Fields []Field
+	// TenantsSign is whether the tenants sign as well as the landlord
+	TenantsSign bool
+}
+
+// Field is a notice detail a template asks for
+type Field struct {
+	Name  string
+	Label string
+}
+
+// Templates lists every template in the order staff are offered them
+var Templates = []Template{
+	{
+		Name:        TemplateLease,
+		Title:       "Residential Lease Agreement",
+		Kind:        models.DocumentKindLease,
+		TenantsSign: true,
+	},
+	{
+		Name:  TemplateRenewalOffer,
+		Title: "Lease Renewal Offer",
+		Kind:  models.DocumentKindNotice,
+		Fields: []Field{
+			{FieldNewEndDate, "Renew until"},
+			{FieldNewRent, "Monthly rent ($)"},
+			{FieldRespondBy, "Respond by"},
+			{FieldMessage, "Note to tenants"},
+		},
+		TenantsSign: true,
+	},
+	{
+		Name:  TemplateRentIncrease,
+		Title: "Notice of Rent Increase",
+		Kind:  models.DocumentKindNotice,
+		Fields: []Field{
+			{FieldNewRent, "New monthly rent ($)"},
+			{FieldEffectiveDate, "Effective date"},
+			{FieldMessage, "Note to tenants"},
+		},
+	},
+	{
+		Name:  TemplateNoticeToVacate,
+		Title: "Notice to Vacate",
+		Kind:  models.DocumentKindNotice,
+		Fields: []Field{
+			{FieldEffectiveDate, "Vacate by"},
+			{FieldMessage, "Reason"},
+		},
+	},
+}
+
+// Lookup finds the template named name
+func Lookup(name string) (Template, bool) {
+	i := slices.IndexFunc(Templates, func(t Template) bool { return t.Name == name })
+	if i < 0 {
+		return Template{}, false
+	}
+	return Templates[i], true
+}
+
+// Uses reports whether the template asks for the field named name
+func (t Template) Uses(name string) bool {
+	return slices.ContainsFunc(t.Fields, func(f Field) bool { return f.Name == name })
+}
+
+// Data is what a template is filled in with. It's saved with each
+// generated document, so the same file can be rendered again later even if
+// the lease or property has changed since.
+type Data struct {
+	Template string `json:"template"`
+	// Date is when the document was issued
+	Date     time.Time       `json:"date"`
+	Landlord string          `json:"landlord"`
+	Lease    models.Lease    `json:"lease"`
+	Property models.Property `json:"property"`
+	// LateFees sums up the lease's late fee policy, or is blank if it has
+	// none
+	LateFees string  `json:"lateFees,omitempty"`
+	Notice   Notice  `json:"notice"`
+	Parties  []Party `json:"parties"`
+}
+
+// Notice holds the details staff fill in for a notice. Each template uses
+// only some of them.
+type Notice struct {
+	EffectiveDate time.Time `json:"effectiveDate"`
+	NewRentCents  int64     `json:"newRentCents"`
+	NewEndDate    time.Time `json:"newEndDate"`
+	RespondBy     time.Time `json:"respondBy"`
+	Message       string    `json:"message,omitempty"`
+}
+
+// Party is someone who signs a document. UserID is the Clerk user ID of a
+// tenant; the landlord has none.
+type Party struct {
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	UserID string `json:"userId,omitempty"`
+	Email  string `json:"email,omitempty"`
+}
+
+// Party roles
+const (
+	RoleTenant   = "Tenant"
+	RoleLandlord = "Landlord"
+)
+
+// NewData fills in t's data for lease. policy is the lease's late fee
+// policy, if it has one.
+func NewData(t Template, lease models.Lease, property models.Property, policy *models.LateFeePolicy, notice Notice, date time.Time) Data {
+	property.Images = nil
+	d := Data{
+		Template: t.Name,
+		Date:     models.Date(date),
+		Landlord: Landlord,
+		Lease:    lease,
+		Property: property,
+		Notice:   notice,
+	}
+	if policy != nil {
+		d.LateFees = policy.Terms()
+	}
+	if t.TenantsSign {
+		for _, tenant := range lease.Tenants {
+			d.Parties = append(d.Parties, Party{Name: tenant.Name, Role: RoleTenant, UserID: tenant.UserID, Email: tenant.Email})
+		}
+	}
+	d.Parties = append(d.Parties, Party{Name: Landlord, Role: RoleLandlord})
+	return d
+}
+
+// Filename is a name for the rendered file, e.g.
+// "rent-increase-lease-12-2026-03-01.pdf"
+func (d Data) Filename() string {
+	return fmt.Sprintf("%s-lease-%d-%s.pdf", d.Template, d.Lease.ID, d.Date.Format("2006-01-02"))
+}
+
+// RenewalStart is the first day of a renewed lease
+func (d Data) RenewalStart() time.Time {
+	return d.Lease.EndDate.AddDate(0, 0, 1)
+}
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"money": models.FormatCents,
+	"dollars": func(d *int) string {
+		if d == nil {
+			return ""
+		}
+		return models.FormatCents(int64(*d) * 100)
+	},
+	"date": func(t time.Time) string {
+		return t.Format("January 2, 2006")
+	},
+	"names": func(tenants []models.LeaseTenant) string {
+		names := make([]string, len(tenants))
+		for i, t := range tenants {
+			names[i] = t.Name
+		}
+		if len(names) < 2 {
+			return strings.Join(names, "")
+		}
+		return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+	},
+	"lower": strings.ToLower,
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// Certificate records how a document was signed
+type Certificate struct {
+	// DocumentID is the vault document that was signed, and SHA256 the hex
+	// SHA-256 of its file
+	DocumentID int64
+	SHA256     string
+	// Requests are the document's signature requests, voided ones
+	// included, and Events its audit trail, oldest first
+	Requests    []models.SignatureRequest
+	Events      []models.SignatureEvent
+	CompletedAt time.Time
+}
+
+// Render renders d to a PDF
+func Render(d Data) ([]byte, error) {
+	return render(d, nil)
+}
+
+// RenderSigned renders d with the signatures of cert's signed requests in
+// the signature blocks, stamps each page as signed and prints cert on a
+// last page
+func RenderSigned(d Data, cert Certificate) ([]byte, error) {
+	return render(d, &cert)
+}
+
+func render(d Data, cert *Certificate) ([]byte, error) {
+	t, ok := Lookup(d.Template)
+	if !ok {
+		return nil, fmt.Errorf("unknown document template %q", d.Template)
+	}
+	var text bytes.Buffer
+	if err := templates.ExecuteTemplate(&text, t.Name+".tmpl", &d); err != nil {
+		return nil, fmt.Errorf("fill in %s: %w", t.Name, err)
+	}
+
+	doc := pdf.New(t.Title, d.Date)
+	layout(doc, text.String())
+	if cert == nil {
+		signatures(doc, d.Parties, nil)
+		return doc.Bytes(), nil
+	}
+
+	signed := make(map[int]models.SignatureRequest)
+	for _, r := range cert.Requests {
+		if r.Status == models.SignatureStatusSigned {
+			signed[r.Position] = r
+		}
+	}
+	if err := signatures(doc, d.Parties, signed); err != nil {
+		return nil, err
+	}
+	doc.Stamp = fmt.Sprintf("Signed electronically  |  Document #%d", cert.DocumentID)
+	certificate(doc, t, *cert)
+	return doc.Bytes(), nil
+}
+
+// Type sizes, in points
+const (
+	titleSize    = 16.0
+	headingSize  = 11.5
+	bodySize     = 10.5
+	smallSize    = 9.0
+	bulletIndent = 14.0
+)
+
+// layout draws the markup in text
+func layout(doc *pdf.Document, text string) {
+	var para []string
+	bullet := false
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		if bullet {
+			doc.Indented(pdf.Regular, bodySize, bulletIndent, "•", strings.Join(para, " "))
+			doc.Space(2)
+		} else {
+			doc.Text(pdf.Regular, bodySize, strings.Join(para, " "))
+			doc.Space(6)
+		}
+		para, bullet = nil, false
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "## "):
+			flush()
+			doc.Need(60)
+			doc.Space(6)
+			doc.Text(pdf.Bold, headingSize, strings.TrimPrefix(line, "## "))
+			doc.Space(2)
+		case strings.HasPrefix(line, "# "):
+			flush()
+			doc.Text(pdf.Bold, titleSize, strings.TrimPrefix(line, "# "))
+			doc.Space(10)
+		case strings.HasPrefix(line, "- "):
+			flush()
+			bullet = true
+			para = append(para, strings.TrimPrefix(line, "- "))
+		default:
+			para = append(para, line)
+		}
+	}
+	flush()
+}
+
+// signatures draws a block for each party to sign, holding the signature
+// of those in signed, by their position among parties
+func signatures(doc *pdf.Document, parties []Party, signed map[int]models.SignatureRequest) error {
+	if len(parties) == 0 {
+		return nil
+	}
+	doc.Need(110)
+	doc.Space(12)
+	doc.Text(pdf.Bold, headingSize, "Signatures")
+	doc.Space(4)
+	for i, p := range parties {
+		doc.Need(80)
+		r, ok := signed[i]
+		date := "Date: ____________________"
+		switch {
+		case !ok || r.SignedAt == nil:
+			doc.Space(30)
+		case r.Method == models.SignatureMethodDrawn:
+			img, err := png.Decode(bytes.NewReader(r.Drawing))
+			if err != nil {
+				return fmt.Errorf("decode signature of %s: %w", r.Party(), err)
+			}
+			doc.Image(img, 0, 220, 40)
+		default:
+			doc.Space(12)
+			doc.Columns(pdf.Italic, 20, []float64{4}, r.TypedName)
+		}
+		if ok && r.SignedAt != nil {
+			date = "Date: " + r.SignedAt.UTC().Format("January 2, 2006")
+		}
+		doc.Line(0, 260)
+		doc.Columns(pdf.Regular, smallSize, []float64{0, 300}, p.Name+", "+p.Role, date)
+		if ok && r.SignedAt != nil {
+			doc.Columns(pdf.Italic, 7.5, []float64{0}, "Signed electronically at "+certificateTime(*r.SignedAt))
+		}
+		doc.Space(8)
+	}
+	return nil
+}
+
+// certificateLabelWidth is how far the values of the certificate's rows
+// are indented past their labels
+const certificateLabelWidth = 120.0
+
+// certificate prints the record of how the document was signed on a page
+// of its own
+func certificate(doc *pdf.Document, t Template, cert Certificate) {
+	row := func(label, value string) {
+		doc.Indented(pdf.Regular, smallSize, certificateLabelWidth, label, value)
+	}
+
+	doc.NewPage()
+	doc.Text(pdf.Bold, titleSize, "Signature Certificate")
+	doc.Space(6)
+	doc.Text(pdf.Regular, bodySize, "This certificate records who signed the "+t.Title+" electronically, "+
+		"when and from where. The fingerprint identifies the document exactly as it was sent to be signed.")
+	doc.Space(8)
+	row("Document", fmt.Sprintf("%s (#%d)", t.Title, cert.DocumentID))
+	row("Fingerprint", "SHA-256 "+cert.SHA256)
+	row("Completed", certificateTime(cert.CompletedAt))
+
+	current := models.CurrentSignatureRequests(cert.Requests)
+	slices.SortFunc(current, func(a, b models.SignatureRequest) int { return a.Position - b.Position })
+	doc.Need(60)
+	doc.Space(12)
+	doc.Text(pdf.Bold, headingSize, "Signers")
+	for _, r := range current {
+		doc.Need(80)
+		doc.Space(8)
+		doc.Text(pdf.Bold, bodySize, r.Party())
+		doc.Space(2)
+		if r.Email != "" {
+			row("Email", r.Email)
+		}
+		if r.Method == models.SignatureMethodDrawn {
+			row("Signature", "Drawn")
+		} else {
+			row("Signature", fmt.Sprintf("Typed name %q", r.TypedName))
+		}
+		if r.SignedAt != nil {
+			row("Signed", certificateTime(*r.SignedAt))
+		}
+		row("IP address", r.IPAddress)
+		row("Device", r.UserAgent)
+	}
+
+	doc.Need(60)
+	doc.Space(12)
+	doc.Text(pdf.Bold, headingSize, "Audit Trail")
+	doc.Space(4)
+	for _, e := range cert.Events {
+		row(certificateTime(e.CreatedAt), eventDescription(e, cert.Requests))
+	}
+}
+
+// eventDescription describes e in the audit trail, naming the party its
+// request was for
+func eventDescription(e models.SignatureEvent, requests []models.SignatureRequest) string {
+	party := "a party"
+	if e.RequestID != nil {
+		if i := slices.IndexFunc(requests, func(r models.SignatureRequest) bool { return r.ID == *e.RequestID }); i >= 0 {
+			party = requests[i].Party()
+		}
+	}
+	var s string
+	switch e.Kind {
+	case models.SignatureEventSent:
+		s = "Sent to " + party
+	case models.SignatureEventViewed:
+		s = "Opened by " + party
+	case models.SignatureEventSigned:
+		s = "Signed by " + party
+	case models.SignatureEventVoided:
+		s = "Request to " + party + " voided"
+	case models.SignatureEventCompleted:
+		s = "Signed by all parties"
+	default:
+		s = e.Kind.Label()
+	}
+	if e.IPAddress != "" {
+		s += " from " + e.IPAddress
+	}
+	return s
+}
+
+// certificateTime formats t for the certificate, in UTC
+func certificateTime(t time.Time) string {
+	return t.UTC().Format("Jan 2, 2006 15:04:05 UTC")
+}
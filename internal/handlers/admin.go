@@ -0,0 +1,378 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/templates/pages"
+)
+
+// maxSlugLength matches properties.slug VARCHAR(100)
+const maxSlugLength = 100
+
+func (h *Handler) AdminProperties(c echo.Context) error {
+	properties, err := h.Store.Properties.List(c.Request().Context())
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load properties")
+	}
+	return Render(c, http.StatusOK, pages.AdminProperties(properties))
+}
+
+func (h *Handler) AdminNewProperty(c echo.Context) error {
+	p := models.Property{Type: models.PropertyTypeHouse, Available: true}
+	return Render(c, http.StatusOK, pages.AdminPropertyEditor(p, nil))
+}
+
+func (h *Handler) AdminCreateProperty(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	p, errs := parsePropertyForm(c)
+	if p.Slug == "" {
+		p.Slug = slugify(p.Title)
+	}
+	if p.Slug == "" && p.Title != "" {
+		errs["slug"] = "Enter a slug or a title with letters or numbers"
+	}
+	if len(errs) > 0 {
+		return Render(c, http.StatusUnprocessableEntity, pages.AdminPropertyForm(p, errs))
+	}
+
+	// Only generated slugs get a numeric suffix; a typed slug that is
+	// already taken is reported back to the admin
+	if c.FormValue("slug") == "" {
+		slug, err := h.uniqueSlug(ctx, p.Slug)
+		if err != nil {
+			c.Logger().Errorf("check slug %q: %v", p.Slug, err)
+			return c.String(http.StatusInternalServerError, "Failed to save property")
+		}
+		p.Slug = slug
+	}
+
+	err := h.Store.Properties.Create(ctx, &p)
+	if errors.Is(err, repository.ErrSlugTaken) {
+		errs["slug"] = "Another property already uses this slug"
+		return Render(c, http.StatusUnprocessableEntity, pages.AdminPropertyForm(p, errs))
+	}
+	if err != nil {
+		c.Logger().Errorf("create property: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to save property")
+	}
+
+	c.Response().Header().Set("HX-Redirect", "/admin")
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) AdminEditProperty(c echo.Context) error {
+	p, err := h.adminProperty(c)
+	if err != nil {
+		return adminPropertyError(c, err)
+	}
+	return Render(c, http.StatusOK, pages.AdminPropertyEditor(*p, nil))
+}
+
+func (h *Handler) AdminUpdateProperty(c echo.Context) error {
+	existing, err := h.adminProperty(c)
+	if err != nil {
+		return adminPropertyError(c, err)
+	}
+
+	p, errs := parsePropertyForm(c)
+	p.ID = existing.ID
+	p.Slug = existing.Slug
+	if len(errs) > 0 {
+		return Render(c, http.StatusUnprocessableEntity, pages.AdminPropertyForm(p, errs))
+	}
+
+	if err := h.Store.Properties.Update(c.Request().Context(), &p); err != nil {
+		c.Logger().Errorf("update property %d: %v", p.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to save property")
+	}
+
+	c.Response().Header().Set("HX-Redirect", "/admin")
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) AdminDeleteProperty(c echo.Context) error {
+	p, err := h.adminProperty(c)
+	if err != nil {
+		return adminPropertyError(c, err)
+	}
+
+	ctx := c.Request().Context()
+	inUse, err := h.propertyInUse(ctx, p.ID)
+	if err != nil {
+		c.Logger().Errorf("check property %d is unused: %v", p.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to delete property")
+	}
+	if inUse {
+		return propertyInUseError(c, p)
+	}
+
+	err = h.Store.Properties.Delete(ctx, p.ID)
+	if errors.Is(err, repository.ErrInUse) {
+		return propertyInUseError(c, p)
+	}
+	if err != nil {
+		c.Logger().Errorf("delete property %d: %v", p.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to delete property")
+	}
+
+	// An empty body removes the table row that triggered the request
+	return c.HTML(http.StatusOK, "")
+}
+
+// propertyInUse reports whether a property has leases, applications or
+// upcoming showings, which deleting it would lose
+func (h *Handler) propertyInUse(ctx context.Context, id int64) (bool, error) {
+	leases, err := h.Store.Leases.Filter(ctx, repository.LeaseFilter{PropertyID: id})
+	if err != nil || len(leases) > 0 {
+		return len(leases) > 0, err
+	}
+	apps, err := h.Store.Applications.Filter(ctx, repository.ApplicationFilter{PropertyID: id})
+	if err != nil || len(apps) > 0 {
+		return len(apps) > 0, err
+	}
+	showings, err := h.Store.Showings.Feed(ctx, id, "", time.Now())
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(showings, models.Showing.Active), nil
+}
+
+// propertyInUseError reports a property that can't be deleted above the
+// properties table, leaving its row in place
+func propertyInUseError(c echo.Context, p *models.Property) error {
+	c.Response().Header().Set("HX-Retarget", "#property-error")
+	c.Response().Header().Set("HX-Reswap", "innerHTML")
+	return c.String(http.StatusConflict, p.Title+" has leases, applications or upcoming showings, so it can't be deleted. Mark it as leased to take it off the site instead.")
+}
+
+// adminProperty loads the property named by the :id path parameter
+func (h *Handler) adminProperty(c echo.Context) (*models.Property, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, repository.ErrNotFound
+	}
+	return h.Store.Properties.GetByID(c.Request().Context(), id)
+}
+
+func adminPropertyError(c echo.Context, err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Property not found")
+	}
+	c.Logger().Errorf("get property %s: %v", c.Param("id"), err)
+	return c.String(http.StatusInternalServerError, "Failed to load property")
+}
+
+// parsePropertyForm reads the admin property form. Field errors are keyed by
+// form field name; fields that fail to parse are left at their zero value.
+func parsePropertyForm(c echo.Context) (models.Property, map[string]string) {
+	errs := make(map[string]string)
+	f := propertyForm{c: c, errs: errs}
+
+	p := models.Property{
+		Slug:        f.text("slug", "Slug", maxSlugLength, false),
+		Title:       f.text("title", "Title", 255, true),
+		Type:        models.PropertyType(c.FormValue("type")),
+		Address:     f.text("address", "Address", 255, true),
+		City:        f.text("city", "City", 100, true),
+		State:       f.text("state", "State", 50, true),
+		ZipCode:     f.text("zipCode", "ZIP code", 20, true),
+		Description: f.text("description", "Description", 0, true),
+		Parking:     f.text("parking", "Parking", 255, false),
+		Laundry:     f.text("laundry", "Laundry", 255, false),
+
+		Price:          f.integer("price", "Rent", 1, 1_000_000),
+		Deposit:        f.integer("deposit", "Deposit", 0, 1_000_000),
+		ApplicationFee: f.integer("applicationFee", "Application fee", 0, 10_000),
+		Bedrooms:       f.integer("bedrooms", "Bedrooms", 0, 50),
+		SquareFeet:     f.integer("squareFeet", "Square feet", 1, 1_000_000),
+
+		PetDeposit: f.optionalInteger("petDeposit", "Pet deposit", 0, 100_000),
+		PetRent:    f.optionalInteger("petRent", "Pet rent", 0, 10_000),
+		YearBuilt:  f.optionalInteger("yearBuilt", "Year built", 1800, time.Now().Year()+1),
+
+		Features:   f.lines("features"),
+		Utilities:  f.lines("utilities"),
+		LeaseTerms: f.lines("leaseTerms"),
+
+		Available:   c.FormValue("available") == "on",
+		PetFriendly: c.FormValue("petFriendly") == "on",
+		Featured:    c.FormValue("featured") == "on",
+	}
+
+	if !p.Type.IsValid() {
+		errs["type"] = "Choose a property type"
+	}
+
+	if p.Slug != "" && p.Slug != slugify(p.Slug) {
+		errs["slug"] = "Use lowercase letters, numbers and dashes only"
+	}
+
+	// bathrooms is DECIMAL(3,1) and listings only use whole or half baths
+	if v := strings.TrimSpace(c.FormValue("bathrooms")); v != "" {
+		baths, err := strconv.ParseFloat(v, 64)
+		if err != nil || baths < 0 || baths > 20 || baths*2 != float64(int(baths*2)) {
+			errs["bathrooms"] = "Bathrooms must be a whole or half number"
+		} else {
+			p.Bathrooms = baths
+		}
+	} else {
+		errs["bathrooms"] = "Bathrooms is required"
+	}
+
+	if v := c.FormValue("availableDate"); v != "" {
+		date, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			errs["availableDate"] = "Enter a valid date"
+		} else {
+			p.AvailableDate = &date
+		}
+	}
+
+	if !p.PetFriendly && (p.PetDeposit != nil || p.PetRent != nil) {
+		errs["petFriendly"] = "Pet fees only apply to pet friendly properties"
+	}
+
+	return p, errs
+}
+
+// propertyForm collects per-field errors while reading form values
+type propertyForm struct {
+	c    echo.Context
+	errs map[string]string
+}
+
+func (f propertyForm) text(name, label string, maxLen int, required bool) string {
+	v := strings.TrimSpace(f.c.FormValue(name))
+	switch {
+	case required && v == "":
+		f.errs[name] = label + " is required"
+	case maxLen > 0 && len(v) > maxLen:
+		f.errs[name] = fmt.Sprintf("%s must be at most %d characters", label, maxLen)
+	}
+	return v
+}
+
+func (f propertyForm) integer(name, label string, min, max int) int {
+	v := strings.TrimSpace(f.c.FormValue(name))
+	if v == "" {
+		f.errs[name] = label + " is required"
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min || n > max {
+		f.errs[name] = fmt.Sprintf("%s must be a whole number from %d to %d", label, min, max)
+		return 0
+	}
+	return n
+}
+
+func (f propertyForm) optionalInteger(name, label string, min, max int) *int {
+	if strings.TrimSpace(f.c.FormValue(name)) == "" {
+		return nil
+	}
+	n := f.integer(name, label, min, max)
+	if _, failed := f.errs[name]; failed {
+		return nil
+	}
+	return &n
+}
+
+// cents reads a dollar amount such as "1,200.50" as cents
+func (f propertyForm) cents(name, label string, required bool) int64 {
+	v := strings.ReplaceAll(strings.TrimPrefix(strings.TrimSpace(f.c.FormValue(name)), "$"), ",", "")
+	if v == "" {
+		if required {
+			f.errs[name] = label + " is required"
+		}
+		return 0
+	}
+	dollars, fraction, _ := strings.Cut(v, ".")
+	d, err := strconv.ParseInt(dollars, 10, 64)
+	cents, ferr := strconv.ParseInt((fraction + "00")[:2], 10, 64)
+	if err != nil || ferr != nil || len(fraction) > 2 || d < 0 || d > 1_000_000 || (required && d == 0 && cents == 0) {
+		f.errs[name] = label + " must be a dollar amount up to $1,000,000"
+		return 0
+	}
+	return d*100 + cents
+}
+
+// date reads a required YYYY-MM-DD date
+func (f propertyForm) date(name, label string) time.Time {
+	v := strings.TrimSpace(f.c.FormValue(name))
+	if v == "" {
+		f.errs[name] = label + " is required"
+		return time.Time{}
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		f.errs[name] = "Enter a valid date"
+	}
+	return t
+}
+
+// lines splits a textarea into its non-blank lines
+func (f propertyForm) lines(name string) []string {
+	items := []string{}
+	for _, line := range strings.Split(f.c.FormValue(name), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			items = append(items, line)
+		}
+	}
+	return items
+}
+
+// slugify lowercases s and joins its letters and digits with single dashes
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
+
+// uniqueSlug returns base, or base with the first free numeric suffix
+func (h *Handler) uniqueSlug(ctx context.Context, base string) (string, error) {
+	for n := 1; ; n++ {
+		slug := base
+		if n > 1 {
+			suffix := "-" + strconv.Itoa(n)
+			slug = strings.TrimRight(base[:min(len(base), maxSlugLength-len(suffix))], "-") + suffix
+		}
+
+		_, err := h.Store.Properties.GetBySlug(ctx, slug)
+		if errors.Is(err, repository.ErrNotFound) {
+			return slug, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
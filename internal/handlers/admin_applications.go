@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/templates/pages"
+)
+
+// AdminApplications lists submitted applications. Requests from the filter
+// form only get the list back.
+func (h *Handler) AdminApplications(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	filters := pages.ApplicationFilters{
+		Property: c.QueryParam("property"),
+		Status:   c.QueryParam("status"),
+	}
+	var filter repository.ApplicationFilter
+	if id, err := strconv.ParseInt(filters.Property, 10, 64); err == nil {
+		filter.PropertyID = id
+	}
+	if s := models.ApplicationStatus(filters.Status); slices.Contains(models.ApplicationStatuses, s) {
+		filter.Status = s
+	}
+
+	apps, err := h.Store.Applications.Filter(ctx, filter)
+	if err != nil {
+		c.Logger().Errorf("filter applications: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load applications")
+	}
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load applications")
+	}
+
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return Render(c, http.StatusOK, pages.AdminApplicationList(apps, properties))
+	}
+	return Render(c, http.StatusOK, pages.AdminApplications(apps, properties, filters))
+}
+
+func (h *Handler) AdminApplication(c echo.Context) error {
+	app, property, err := h.adminApplication(c)
+	if err != nil {
+		return adminApplicationError(c, err)
+	}
+	return h.renderApplicationWorkflow(c, http.StatusOK, app, property, nil, true)
+}
+
+// AdminSetApplicationStatus moves an application along its review and
+// emails the applicant, including the note, about decisions
+func (h *Handler) AdminSetApplicationStatus(c echo.Context) error {
+	app, property, err := h.adminApplication(c)
+	if err != nil {
+		return adminApplicationError(c, err)
+	}
+
+	status := models.ApplicationStatus(c.FormValue("status"))
+	note := strings.TrimSpace(c.FormValue("note"))
+	errs := make(map[string]string)
+	if status == models.ApplicationStatusWithdrawn || !app.Status.CanBecome(status) {
+		errs["status"] = "Choose a status this application can move to"
+	}
+	if len(note) > maxNoteLength {
+		errs["note"] = "Notes can be up to 5000 characters"
+	}
+	if len(errs) > 0 {
+		return h.renderApplicationWorkflow(c, http.StatusUnprocessableEntity, app, property, errs, false)
+	}
+
+	app, err = h.Store.Applications.SetStatus(c.Request().Context(), app.ID, app.Status, status, middleware.GetUserID(c), note)
+	if errors.Is(err, repository.ErrStatusChanged) {
+		return c.String(http.StatusConflict, "This application was just updated. Please reload the page.")
+	}
+	if err != nil {
+		return adminApplicationError(c, err)
+	}
+	if status == models.ApplicationStatusApproved || status == models.ApplicationStatusDenied {
+		h.sendApplicationStatus(c, app, property, note)
+	}
+	return h.renderApplicationWorkflow(c, http.StatusOK, app, property, nil, false)
+}
+
+// renderApplicationWorkflow renders an application's page, or just its
+// status and timeline panel unless page is set
+func (h *Handler) renderApplicationWorkflow(c echo.Context, status int, app *models.Application, property *models.Property, errs map[string]string, page bool) error {
+	ctx := c.Request().Context()
+	events, err := h.Store.Applications.Timeline(ctx, app.ID)
+	if err != nil {
+		c.Logger().Errorf("application %d timeline: %v", app.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load application")
+	}
+	staff, err := h.staffDirectory(ctx, middleware.GetUserID(c))
+	if err != nil {
+		c.Logger().Errorf("list staff: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load application")
+	}
+	fee, err := h.applicationFeePayment(ctx, app, property)
+	if err != nil {
+		c.Logger().Errorf("application %d fee: %v", app.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load application")
+	}
+	if page {
+		return Render(c, status, pages.AdminApplication(*app, *property, events, staff, paidFee(fee)))
+	}
+	return Render(c, status, pages.AdminApplicationWorkflow(*app, events, staff, paidFee(fee), errs))
+}
+
+func (h *Handler) adminApplication(c echo.Context) (*models.Application, *models.Property, error) {
+	ctx := c.Request().Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, nil, repository.ErrNotFound
+	}
+	app, err := h.Store.Applications.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Drafts stay private to the applicant until they submit
+	if app.Status == models.ApplicationStatusDraft {
+		return nil, nil, repository.ErrNotFound
+	}
+	property, err := h.Store.Properties.GetByID(ctx, app.PropertyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return app, property, nil
+}
+
+func adminApplicationError(c echo.Context, err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Application not found")
+	}
+	c.Logger().Errorf("application %s: %v", c.Param("id"), err)
+	return c.String(http.StatusInternalServerError, "Failed to load application")
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/imaging"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/internal/storage"
+	"russ-rentals/templates/pages"
+)
+
+// maxImageUpload is the largest photo file accepted, in bytes
+const maxImageUpload = 15 << 20
+
+func (h *Handler) AdminPropertyImages(c echo.Context) error {
+	p, err := h.adminProperty(c)
+	if err != nil {
+		return adminPropertyError(c, err)
+	}
+	return Render(c, http.StatusOK, pages.AdminPropertyImages(*p))
+}
+
+func (h *Handler) AdminUploadImage(c echo.Context) error {
+	p, err := h.adminProperty(c)
+	if err != nil {
+		return adminPropertyError(c, err)
+	}
+
+	// Leave room for the other multipart fields
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxImageUpload+1<<20)
+
+	room := models.RoomType(c.FormValue("room"))
+	caption := strings.TrimSpace(c.FormValue("caption"))
+	if !room.IsValid() {
+		return uploadError(c, http.StatusBadRequest, "Choose which room the photo shows")
+	}
+	if caption == "" || len(caption) > 255 {
+		return uploadError(c, http.StatusBadRequest, "Enter a caption of up to 255 characters")
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		return uploadError(c, http.StatusBadRequest, "Choose a photo to upload")
+	}
+	if file.Size > maxImageUpload {
+		return uploadError(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("Photos must be %d MB or smaller", maxImageUpload>>20))
+	}
+	src, err := file.Open()
+	if err != nil {
+		return uploadError(c, http.StatusBadRequest, "Could not read the uploaded file")
+	}
+	defer src.Close()
+	data, err := io.ReadAll(io.LimitReader(src, maxImageUpload+1))
+	if err != nil || len(data) > maxImageUpload {
+		return uploadError(c, http.StatusBadRequest, "Could not read the uploaded file")
+	}
+
+	img := models.PropertyImage{
+		PropertyID:   p.ID,
+		Caption:      caption,
+		Room:         room,
+		DisplayOrder: nextDisplayOrder(p.Images),
+	}
+	err = h.storePhoto(c, &img, data)
+	switch {
+	case errors.Is(err, imaging.ErrUnsupported):
+		return uploadError(c, http.StatusUnsupportedMediaType, "Photos must be JPEG, PNG or WebP images")
+	case errors.Is(err, imaging.ErrTooLarge):
+		return uploadError(c, http.StatusRequestEntityTooLarge, "Photo dimensions are too large")
+	case errors.Is(err, storage.ErrUnavailable):
+		return uploadError(c, http.StatusServiceUnavailable, "Photo uploads aren't set up on this server")
+	case err != nil:
+		c.Logger().Errorf("store photo for property %d: %v", p.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to save photo")
+	}
+
+	if err := h.Store.Properties.AddImage(c.Request().Context(), &img); err != nil {
+		c.Logger().Errorf("add image to property %d: %v", p.ID, err)
+		h.deletePhoto(c, h.Uploads, img.StorageKey)
+		return c.String(http.StatusInternalServerError, "Failed to save photo")
+	}
+
+	return Render(c, http.StatusOK, pages.AdminImageCard(img, nil))
+}
+
+// staleImagesMessage is shown when the submitted photo list no longer matches
+// the property's photos, e.g. another admin uploaded one in the meantime
+const staleImagesMessage = "The photos changed since this page was loaded. Review them and save again."
+
+func (h *Handler) AdminUpdateImages(c echo.Context) error {
+	ctx := c.Request().Context()
+	p, err := h.adminProperty(c)
+	if err != nil {
+		return adminPropertyError(c, err)
+	}
+
+	form, err := c.FormParams()
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid form")
+	}
+
+	images, errs := parseImagesForm(form, p.Images)
+	if _, stale := errs["images"]; stale {
+		return Render(c, http.StatusConflict, pages.AdminImagesForm(*p, errs, false))
+	}
+	if len(errs) > 0 {
+		p.Images = images
+		return Render(c, http.StatusUnprocessableEntity, pages.AdminImagesForm(*p, errs, false))
+	}
+
+	err = h.Store.Properties.UpdateImages(ctx, p.ID, images)
+	if errors.Is(err, repository.ErrNotFound) {
+		errs["images"] = staleImagesMessage
+		return h.renderCurrentImages(c, p.ID, http.StatusConflict, errs)
+	}
+	if err != nil {
+		c.Logger().Errorf("update images for property %d: %v", p.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to save photos")
+	}
+
+	p.Images = images
+	return Render(c, http.StatusOK, pages.AdminImagesForm(*p, nil, true))
+}
+
+// renderCurrentImages re-renders the photo list from storage with errs
+func (h *Handler) renderCurrentImages(c echo.Context, propertyID int64, status int, errs map[string]string) error {
+	p, err := h.Store.Properties.GetByID(c.Request().Context(), propertyID)
+	if err != nil {
+		return adminPropertyError(c, err)
+	}
+	return Render(c, status, pages.AdminImagesForm(*p, errs, false))
+}
+
+// parseImagesForm reads the photo list form: one "image" ID per photo in its
+// new gallery order, plus "room-<id>" and "caption-<id>" for each. Every
+// current photo must be listed exactly once; otherwise errs["images"] is set.
+func parseImagesForm(form url.Values, current []models.PropertyImage) ([]models.PropertyImage, map[string]string) {
+	errs := make(map[string]string)
+
+	byID := make(map[int64]models.PropertyImage, len(current))
+	for _, img := range current {
+		byID[img.ID] = img
+	}
+
+	ids := form["image"]
+	images := make([]models.PropertyImage, 0, len(ids))
+	for i, raw := range ids {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		img, ok := byID[id]
+		if err != nil || !ok {
+			errs["images"] = staleImagesMessage
+			return nil, errs
+		}
+		delete(byID, id)
+
+		roomField := fmt.Sprintf("room-%d", id)
+		captionField := fmt.Sprintf("caption-%d", id)
+		img.Room = models.RoomType(form.Get(roomField))
+		img.Caption = strings.TrimSpace(form.Get(captionField))
+		img.DisplayOrder = i
+		if !img.Room.IsValid() {
+			errs[roomField] = "Choose a room"
+		}
+		if img.Caption == "" || len(img.Caption) > 255 {
+			errs[captionField] = "Enter a caption of up to 255 characters"
+		}
+		images = append(images, img)
+	}
+	if len(byID) > 0 {
+		errs["images"] = staleImagesMessage
+		return nil, errs
+	}
+	return images, errs
+}
+
+// storePhoto saves data as a property photo in each of
+// imaging.PhotoVariants and fills in img's URLs and StorageKey
+func (h *Handler) storePhoto(c echo.Context, img *models.PropertyImage, data []byte) error {
+	key, urls, err := h.putPhoto(c, h.Uploads, fmt.Sprintf("properties/%d", img.PropertyID), data, imaging.PhotoVariants)
+	if err != nil {
+		return err
+	}
+	img.StorageKey = key
+	img.ThumbnailURL = urls["thumbnail"]
+	img.CardURL = urls["card"]
+	img.URL = urls["full"]
+	return nil
+}
+
+// putPhoto decodes data, which also drops its EXIF metadata, and saves a
+// JPEG for each of variants under a new key in dir of store. It returns
+// the key and each variant's URL by name.
+func (h *Handler) putPhoto(c echo.Context, store storage.Storage, dir string, data []byte, variants []imaging.Variant) (string, map[string]string, error) {
+	ctx := c.Request().Context()
+	decoded, err := imaging.Decode(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, err
+	}
+	storageKey := dir + "/" + hex.EncodeToString(id)
+
+	urls := make(map[string]string, len(variants))
+	for _, v := range variants {
+		var buf bytes.Buffer
+		if err := imaging.EncodeJPEG(&buf, imaging.Fit(decoded, v.MaxWidth, v.MaxHeight)); err != nil {
+			h.deletePhoto(c, store, storageKey)
+			return "", nil, err
+		}
+
+		key := photoKey(storageKey, v.Name)
+		if err := store.Put(ctx, key, &buf, "image/jpeg"); err != nil {
+			h.deletePhoto(c, store, storageKey)
+			return "", nil, err
+		}
+		urls[v.Name] = store.URL(key)
+	}
+	return storageKey, urls, nil
+}
+
+// deletePhoto removes every variant stored under storageKey in store.
+// Failures are only logged; a stray file is better than failing the
+// request.
+func (h *Handler) deletePhoto(c echo.Context, store storage.Storage, storageKey string) {
+	if storageKey == "" {
+		return
+	}
+	for _, v := range imaging.PhotoVariants {
+		key := photoKey(storageKey, v.Name)
+		if err := store.Delete(c.Request().Context(), key); err != nil {
+			c.Logger().Warnf("delete photo %s: %v", key, err)
+		}
+	}
+}
+
+func photoKey(storageKey, variant string) string {
+	return storageKey + "-" + variant + ".jpg"
+}
+
+func nextDisplayOrder(images []models.PropertyImage) int {
+	next := 0
+	for _, img := range images {
+		if img.DisplayOrder >= next {
+			next = img.DisplayOrder + 1
+		}
+	}
+	return next
+}
+
+func uploadError(c echo.Context, status int, msg string) error {
+	c.Response().Header().Set("HX-Retarget", "#upload-error")
+	c.Response().Header().Set("HX-Reswap", "innerHTML")
+	return Render(c, status, pages.AdminUploadError(msg))
+}
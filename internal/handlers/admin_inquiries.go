@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/templates/pages"
+)
+
+// maxNoteLength caps internal notes on an inquiry
+const maxNoteLength = 5000
+
+// AdminInquiries is the staff inbox. Requests from the filter form only get
+// the list back.
+func (h *Handler) AdminInquiries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	filters := pages.InquiryFilters{
+		Property: c.QueryParam("property"),
+		Type:     c.QueryParam("type"),
+		Status:   c.QueryParam("status"),
+		Assignee: c.QueryParam("assignee"),
+		From:     c.QueryParam("from"),
+		To:       c.QueryParam("to"),
+	}
+	inquiries, err := h.Store.Contacts.Filter(ctx, inquiryFilter(filters, h.Location))
+	if err != nil {
+		c.Logger().Errorf("filter inquiries: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load inquiries")
+	}
+
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load inquiries")
+	}
+	staff, err := h.staffDirectory(ctx, middleware.GetUserID(c))
+	if err != nil {
+		c.Logger().Errorf("list staff: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load inquiries")
+	}
+
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return Render(c, http.StatusOK, pages.AdminInquiryList(inquiries, properties, staff))
+	}
+	return Render(c, http.StatusOK, pages.AdminInquiries(inquiries, properties, staff, filters))
+}
+
+func (h *Handler) AdminInquiry(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	sub, err := h.adminInquiry(c)
+	if err != nil {
+		return adminInquiryError(c, err)
+	}
+
+	var property *models.Property
+	if sub.PropertyID != nil {
+		property, err = h.Store.Properties.GetByID(ctx, *sub.PropertyID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			c.Logger().Errorf("get property %d: %v", *sub.PropertyID, err)
+			return c.String(http.StatusInternalServerError, "Failed to load inquiry")
+		}
+	}
+
+	events, staff, err := h.inquiryActivity(c, sub)
+	if err != nil {
+		c.Logger().Errorf("load inquiry %d: %v", sub.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load inquiry")
+	}
+	return Render(c, http.StatusOK, pages.AdminInquiry(*sub, property, events, staff, middleware.GetUserID(c)))
+}
+
+func (h *Handler) AdminSetInquiryStatus(c echo.Context) error {
+	sub, err := h.adminInquiry(c)
+	if err != nil {
+		return adminInquiryError(c, err)
+	}
+
+	status := models.InquiryStatus(c.FormValue("status"))
+	if !status.IsValid() {
+		return h.renderInquiryWorkflow(c, http.StatusUnprocessableEntity, sub, map[string]string{"status": "Choose a valid status"})
+	}
+	if status != sub.Status {
+		sub, err = h.Store.Contacts.SetStatus(c.Request().Context(), sub.ID, status, middleware.GetUserID(c))
+		if err != nil {
+			return adminInquiryError(c, err)
+		}
+	}
+	return h.renderInquiryWorkflow(c, http.StatusOK, sub, nil)
+}
+
+func (h *Handler) AdminAssignInquiry(c echo.Context) error {
+	ctx := c.Request().Context()
+	sub, err := h.adminInquiry(c)
+	if err != nil {
+		return adminInquiryError(c, err)
+	}
+
+	assignee := c.FormValue("assignee")
+	if assignee != "" {
+		staff, err := h.staffDirectory(ctx, middleware.GetUserID(c))
+		if err != nil {
+			c.Logger().Errorf("list staff: %v", err)
+			return c.String(http.StatusInternalServerError, "Failed to assign inquiry")
+		}
+		if !slices.ContainsFunc(staff, func(u models.User) bool { return u.ClerkUserID == assignee }) {
+			return h.renderInquiryWorkflow(c, http.StatusUnprocessableEntity, sub, map[string]string{"assignee": "Choose a staff member"})
+		}
+	}
+	if assignee != sub.AssigneeID {
+		sub, err = h.Store.Contacts.Assign(ctx, sub.ID, assignee, middleware.GetUserID(c))
+		if err != nil {
+			return adminInquiryError(c, err)
+		}
+	}
+	return h.renderInquiryWorkflow(c, http.StatusOK, sub, nil)
+}
+
+func (h *Handler) AdminAddInquiryNote(c echo.Context) error {
+	sub, err := h.adminInquiry(c)
+	if err != nil {
+		return adminInquiryError(c, err)
+	}
+
+	note := strings.TrimSpace(c.FormValue("note"))
+	if note == "" || len(note) > maxNoteLength {
+		return h.renderInquiryWorkflow(c, http.StatusUnprocessableEntity, sub, map[string]string{"note": "Enter a note of up to 5000 characters"})
+	}
+	if _, err := h.Store.Contacts.AddNote(c.Request().Context(), sub.ID, note, middleware.GetUserID(c)); err != nil {
+		return adminInquiryError(c, err)
+	}
+	return h.renderInquiryWorkflow(c, http.StatusOK, sub, nil)
+}
+
+// renderInquiryWorkflow renders the status, assignee, notes and timeline
+// panel of an inquiry's page
+func (h *Handler) renderInquiryWorkflow(c echo.Context, status int, sub *models.ContactSubmission, errs map[string]string) error {
+	events, staff, err := h.inquiryActivity(c, sub)
+	if err != nil {
+		c.Logger().Errorf("load inquiry %d: %v", sub.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load inquiry")
+	}
+	return Render(c, status, pages.AdminInquiryWorkflow(*sub, events, staff, middleware.GetUserID(c), errs))
+}
+
+// inquiryActivity loads sub's timeline and the staff it can be assigned to
+func (h *Handler) inquiryActivity(c echo.Context, sub *models.ContactSubmission) ([]models.InquiryEvent, []models.User, error) {
+	ctx := c.Request().Context()
+	events, err := h.Store.Contacts.Timeline(ctx, sub.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	staff, err := h.staffDirectory(ctx, middleware.GetUserID(c))
+	if err != nil {
+		return nil, nil, err
+	}
+	return events, staff, nil
+}
+
+// staffDirectory lists the users inquiries can be assigned to: admins and
+// staff with a local role record, plus the current user, whose role may
+// come from their session claims instead
+func (h *Handler) staffDirectory(ctx context.Context, currentUserID string) ([]models.User, error) {
+	users, err := h.Store.Users.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var staff []models.User
+	for _, u := range users {
+		if u.Role == models.RoleAdmin || u.Role == models.RoleStaff {
+			staff = append(staff, u)
+		}
+	}
+	if currentUserID != "" && !slices.ContainsFunc(staff, func(u models.User) bool { return u.ClerkUserID == currentUserID }) {
+		staff = append(staff, models.User{ClerkUserID: currentUserID})
+	}
+	return staff, nil
+}
+
+func (h *Handler) adminInquiry(c echo.Context) (*models.ContactSubmission, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, repository.ErrNotFound
+	}
+	return h.Store.Contacts.Get(c.Request().Context(), id)
+}
+
+func adminInquiryError(c echo.Context, err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Inquiry not found")
+	}
+	c.Logger().Errorf("inquiry %s: %v", c.Param("id"), err)
+	return c.String(http.StatusInternalServerError, "Failed to load inquiry")
+}
+
+// inquiryFilter converts the inbox filter form to a repository filter.
+// Values that do not parse are ignored. Dates are whole days in loc.
+func inquiryFilter(f pages.InquiryFilters, loc *time.Location) repository.InquiryFilter {
+	var filter repository.InquiryFilter
+	if id, err := strconv.ParseInt(f.Property, 10, 64); err == nil {
+		filter.PropertyID = id
+	}
+	if t := models.InquiryType(f.Type); t.IsValid() {
+		filter.Type = t
+	}
+	if s := models.InquiryStatus(f.Status); s.IsValid() {
+		filter.Status = s
+	}
+	filter.AssigneeID = f.Assignee
+	if from, err := time.ParseInLocation("2006-01-02", f.From, loc); err == nil {
+		filter.ReceivedFrom = from
+	}
+	if to, err := time.ParseInLocation("2006-01-02", f.To, loc); err == nil {
+		filter.ReceivedBefore = to.AddDate(0, 0, 1)
+	}
+	return filter
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/billing"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/templates/pages"
+)
+
+// AdminLateFees lists late fee policies and the state limits applied on
+// top of them
+func (h *Handler) AdminLateFees(c echo.Context) error {
+	ctx := c.Request().Context()
+	policies, err := h.Store.LateFees.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list late fee policies: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load late fee policies")
+	}
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load late fee policies")
+	}
+	return Render(c, http.StatusOK, pages.AdminLateFees(policies, properties))
+}
+
+func (h *Handler) AdminNewLateFee(c echo.Context) error {
+	policy := models.LateFeePolicy{GraceDays: 5, Type: models.LateFeeTypeFlat}
+	return h.renderLateFeeEditor(c, http.StatusOK, policy, nil, true)
+}
+
+func (h *Handler) AdminCreateLateFee(c echo.Context) error {
+	policy, errs := parseLateFeeForm(c)
+	if len(errs) > 0 {
+		return h.renderLateFeeEditor(c, http.StatusUnprocessableEntity, policy, errs, false)
+	}
+
+	err := h.Store.LateFees.Create(c.Request().Context(), &policy)
+	if errors.Is(err, repository.ErrNotFound) {
+		errs["propertyIds"] = "One of these properties no longer exists"
+		return h.renderLateFeeEditor(c, http.StatusUnprocessableEntity, policy, errs, false)
+	}
+	if err != nil {
+		c.Logger().Errorf("create late fee policy: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to save late fee policy")
+	}
+
+	c.Response().Header().Set("HX-Redirect", "/admin/late-fees")
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) AdminEditLateFee(c echo.Context) error {
+	policy, err := h.adminLateFee(c)
+	if err != nil {
+		return adminLateFeeError(c, err)
+	}
+	return h.renderLateFeeEditor(c, http.StatusOK, *policy, nil, true)
+}
+
+func (h *Handler) AdminUpdateLateFee(c echo.Context) error {
+	existing, err := h.adminLateFee(c)
+	if err != nil {
+		return adminLateFeeError(c, err)
+	}
+
+	policy, errs := parseLateFeeForm(c)
+	policy.ID = existing.ID
+	if len(errs) > 0 {
+		return h.renderLateFeeEditor(c, http.StatusUnprocessableEntity, policy, errs, false)
+	}
+
+	err = h.Store.LateFees.Update(c.Request().Context(), &policy)
+	if errors.Is(err, repository.ErrNotFound) {
+		errs["propertyIds"] = "This policy or one of these properties no longer exists"
+		return h.renderLateFeeEditor(c, http.StatusUnprocessableEntity, policy, errs, false)
+	}
+	if err != nil {
+		c.Logger().Errorf("update late fee policy %d: %v", policy.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to save late fee policy")
+	}
+
+	c.Response().Header().Set("HX-Redirect", "/admin/late-fees")
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) AdminDeleteLateFee(c echo.Context) error {
+	policy, err := h.adminLateFee(c)
+	if err != nil {
+		return adminLateFeeError(c, err)
+	}
+	if err := h.Store.LateFees.Delete(c.Request().Context(), policy.ID); err != nil {
+		return adminLateFeeError(c, err)
+	}
+
+	// An empty body removes the table row that triggered the request
+	return c.HTML(http.StatusOK, "")
+}
+
+// AdminLateFeeReport is a dry run of the late fee job: the fees it would
+// post on the day given by the date query parameter, today by default.
+// Nothing is written.
+func (h *Handler) AdminLateFeeReport(c echo.Context) error {
+	day := models.Date(h.now())
+	if d, err := time.Parse("2006-01-02", c.QueryParam("date")); err == nil {
+		day = d
+	}
+
+	assessments, err := billing.AssessLateFees(c.Request().Context(), h.Store, day, true)
+	if err != nil && assessments == nil {
+		c.Logger().Errorf("assess late fees: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to assess late fees")
+	}
+	if err != nil {
+		c.Logger().Warnf("assess late fees: %v", err)
+	}
+	return Render(c, http.StatusOK, pages.AdminLateFeeReport(assessments, day, err != nil))
+}
+
+// renderLateFeeEditor renders the policy form, as a whole page when page
+// is set
+func (h *Handler) renderLateFeeEditor(c echo.Context, status int, policy models.LateFeePolicy, errs map[string]string, page bool) error {
+	ctx := c.Request().Context()
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load late fee policy")
+	}
+	policies, err := h.Store.LateFees.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list late fee policies: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load late fee policy")
+	}
+	if page {
+		return Render(c, status, pages.AdminLateFeeEditor(policy, policies, properties, errs))
+	}
+	return Render(c, status, pages.AdminLateFeeForm(policy, policies, properties, errs))
+}
+
+// adminLateFee loads the policy named by the :id path parameter
+func (h *Handler) adminLateFee(c echo.Context) (*models.LateFeePolicy, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, repository.ErrNotFound
+	}
+	return h.Store.LateFees.Get(c.Request().Context(), id)
+}
+
+func adminLateFeeError(c echo.Context, err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Late fee policy not found")
+	}
+	c.Logger().Errorf("late fee policy %s: %v", c.Param("id"), err)
+	return c.String(http.StatusInternalServerError, "Failed to load late fee policy")
+}
+
+// parseLateFeeForm reads the admin late fee policy form
+func parseLateFeeForm(c echo.Context) (models.LateFeePolicy, map[string]string) {
+	errs := make(map[string]string)
+	f := propertyForm{c: c, errs: errs}
+
+	policy := models.LateFeePolicy{
+		Name:       f.text("name", "Name", 100, true),
+		GraceDays:  f.integer("graceDays", "Grace period", 0, 60),
+		Type:       models.LateFeeType(c.FormValue("type")),
+		DailyCents: f.cents("daily", "Daily fee", false),
+		MaxCents:   f.cents("max", "Cap", false),
+	}
+	switch policy.Type {
+	case models.LateFeeTypeFlat:
+		policy.FlatCents = f.cents("flat", "Late fee", true)
+	case models.LateFeeTypePercent:
+		policy.PercentBps = f.percent("percent", "Late fee")
+	default:
+		errs["type"] = "Choose how the late fee is charged"
+	}
+
+	form, _ := c.FormParams()
+	for _, v := range form["propertyIds"] {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+			policy.PropertyIDs = append(policy.PropertyIDs, id)
+		}
+	}
+	return policy, errs
+}
+
+// percent reads a required percentage such as "5" or "7.5%" as basis
+// points
+func (f propertyForm) percent(name, label string) int {
+	v := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(f.c.FormValue(name)), "%"))
+	if v == "" {
+		f.errs[name] = label + " is required"
+		return 0
+	}
+	whole, fraction, _ := strings.Cut(v, ".")
+	w, err := strconv.Atoi(whole)
+	frac, ferr := strconv.Atoi((fraction + "00")[:2])
+	bps := w*100 + frac
+	if err != nil || ferr != nil || len(fraction) > 2 || w < 0 || bps <= 0 || bps > 10000 {
+		f.errs[name] = label + " must be a percentage from 0.01 to 100"
+		return 0
+	}
+	return bps
+}
@@ -0,0 +1,415 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/billing"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/templates/pages"
+)
+
+// AdminLeases lists leases. Requests from the filter form only get the list
+// back.
+func (h *Handler) AdminLeases(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	filters := pages.LeaseFilters{
+		Property: c.QueryParam("property"),
+		Status:   c.QueryParam("status"),
+	}
+	var filter repository.LeaseFilter
+	if id, err := strconv.ParseInt(filters.Property, 10, 64); err == nil {
+		filter.PropertyID = id
+	}
+	if s := models.LeaseStatus(filters.Status); slices.Contains(models.LeaseStatuses, s) {
+		filter.Status = s
+	}
+
+	leases, err := h.Store.Leases.Filter(ctx, filter)
+	if err != nil {
+		c.Logger().Errorf("filter leases: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load leases")
+	}
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load leases")
+	}
+
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return Render(c, http.StatusOK, pages.AdminLeaseList(leases, properties))
+	}
+	return Render(c, http.StatusOK, pages.AdminLeases(leases, properties, filters))
+}
+
+// AdminNewLease shows a blank lease form, or one filled in from the
+// approved application named by the application query parameter
+func (h *Handler) AdminNewLease(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	lease := models.Lease{Status: models.LeaseStatusDraft, Tenants: []models.LeaseTenant{{}}}
+	if id, err := strconv.ParseInt(c.QueryParam("application"), 10, 64); err == nil {
+		app, err := h.Store.Applications.Get(ctx, id)
+		if errors.Is(err, repository.ErrNotFound) || (err == nil && app.Status != models.ApplicationStatusApproved) {
+			return c.String(http.StatusNotFound, "Approved application not found")
+		}
+		if err != nil {
+			return adminApplicationError(c, err)
+		}
+		property, err := h.Store.Properties.GetByID(ctx, app.PropertyID)
+		if err != nil {
+			return adminApplicationError(c, err)
+		}
+		lease = leaseFromApplication(app, property)
+	}
+	return h.renderLeaseEditor(c, http.StatusOK, lease, nil, true)
+}
+
+func (h *Handler) AdminCreateLease(c echo.Context) error {
+	lease, errs, edited := parseLeaseForm(c)
+	lease.Status = models.LeaseStatusDraft
+	if edited || len(errs) > 0 {
+		return h.renderLeaseEditor(c, leaseFormStatus(edited), lease, errs, false)
+	}
+
+	err := h.Store.Leases.Create(c.Request().Context(), &lease)
+	if errors.Is(err, repository.ErrNotFound) {
+		errs["propertyId"] = "Choose a property"
+		return h.renderLeaseEditor(c, http.StatusUnprocessableEntity, lease, errs, false)
+	}
+	if err != nil {
+		c.Logger().Errorf("create lease: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to save lease")
+	}
+	h.grantTenantRoles(c, lease)
+
+	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/admin/leases/%d", lease.ID))
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) AdminLease(c echo.Context) error {
+	lease, err := h.adminLease(c)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	property, err := h.Store.Properties.GetByID(c.Request().Context(), lease.PropertyID)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	policies, err := h.Store.LateFees.List(c.Request().Context())
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	var lateFees *models.LateFeePolicy
+	if policy, ok := billing.LateFeePolicyFor(*lease, policies); ok {
+		lateFees = &policy
+	}
+	return Render(c, http.StatusOK, pages.AdminLease(*lease, *property, lateFees))
+}
+
+// AdminEditLease shows a draft's form. Leases past draft can't be edited.
+func (h *Handler) AdminEditLease(c echo.Context) error {
+	lease, err := h.adminLease(c)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	if lease.Status != models.LeaseStatusDraft {
+		return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/admin/leases/%d", lease.ID))
+	}
+	return h.renderLeaseEditor(c, http.StatusOK, *lease, nil, true)
+}
+
+func (h *Handler) AdminUpdateLease(c echo.Context) error {
+	existing, err := h.adminLease(c)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+
+	lease, errs, edited := parseLeaseForm(c)
+	lease.ID = existing.ID
+	lease.Status = existing.Status
+	if edited || len(errs) > 0 {
+		return h.renderLeaseEditor(c, leaseFormStatus(edited), lease, errs, false)
+	}
+
+	err = h.Store.Leases.Update(c.Request().Context(), &lease)
+	switch {
+	case errors.Is(err, repository.ErrStatusChanged):
+		return c.String(http.StatusConflict, "Only draft leases can be edited. Move this lease back to draft first.")
+	case errors.Is(err, repository.ErrNotFound):
+		errs["propertyId"] = "Choose a property"
+		return h.renderLeaseEditor(c, http.StatusUnprocessableEntity, lease, errs, false)
+	case err != nil:
+		c.Logger().Errorf("update lease %d: %v", lease.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to save lease")
+	}
+	h.grantTenantRoles(c, lease)
+
+	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/admin/leases/%d", lease.ID))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AdminSetLeaseStatus moves a lease along its lifecycle. A lease can only
+// become active if no other active lease of the property overlaps it.
+func (h *Handler) AdminSetLeaseStatus(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	lease, err := h.adminLease(c)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+
+	status := models.LeaseStatus(c.FormValue("status"))
+	errs := make(map[string]string)
+	if !lease.Status.CanBecome(status) {
+		errs["status"] = "Choose a status this lease can move to"
+	}
+	if status == models.LeaseStatusActive && len(errs) == 0 {
+		other, err := h.overlappingLease(ctx, lease)
+		if err != nil {
+			return adminLeaseError(c, err)
+		}
+		if other != nil {
+			errs["status"] = fmt.Sprintf("Lease #%d is already active for this property from %s to %s",
+				other.ID, other.StartDate.Format("Jan 2, 2006"), other.EndDate.Format("Jan 2, 2006"))
+		}
+	}
+	if len(errs) > 0 {
+		return Render(c, http.StatusUnprocessableEntity, pages.AdminLeaseStatus(*lease, errs))
+	}
+
+	lease, err = h.Store.Leases.SetStatus(ctx, lease.ID, lease.Status, status)
+	if errors.Is(err, repository.ErrStatusChanged) {
+		return c.String(http.StatusConflict, "This lease was just updated. Please reload the page.")
+	}
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	return Render(c, http.StatusOK, pages.AdminLeaseStatus(*lease, nil))
+}
+
+// grantTenantRoles gives the lease's tenants the tenant role, so those
+// added from an application aren't left as applicants. Tenants who already
+// hold a role keep it.
+func (h *Handler) grantTenantRoles(c echo.Context, lease models.Lease) {
+	ctx := c.Request().Context()
+	for _, t := range lease.Tenants {
+		user, err := h.Store.Users.GetByClerkID(ctx, t.UserID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			c.Logger().Errorf("load user %s: %v", t.UserID, err)
+			continue
+		}
+		if user != nil && user.Role != "" {
+			continue
+		}
+		if _, err := h.Store.Users.SetRole(ctx, t.UserID, t.Email, models.RoleTenant); err != nil {
+			c.Logger().Errorf("grant tenant role to %s: %v", t.UserID, err)
+		}
+	}
+}
+
+// renderLeaseEditor renders the lease form, as a whole page when page is
+// set. Known users are offered as tenants, alongside the late fee
+// policies.
+func (h *Handler) renderLeaseEditor(c echo.Context, status int, lease models.Lease, errs map[string]string, page bool) error {
+	ctx := c.Request().Context()
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load lease")
+	}
+	users, err := h.Store.Users.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list users: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load lease")
+	}
+	policies, err := h.Store.LateFees.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list late fee policies: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load lease")
+	}
+	if page {
+		return Render(c, status, pages.AdminLeaseEditor(lease, properties, users, policies, errs))
+	}
+	return Render(c, status, pages.AdminLeaseForm(lease, properties, users, policies, errs))
+}
+
+// overlappingLease finds another active lease of lease's property whose
+// dates overlap it, which would keep lease from becoming active. It returns
+// nil if there's none.
+func (h *Handler) overlappingLease(ctx context.Context, lease *models.Lease) (*models.Lease, error) {
+	active, err := h.Store.Leases.Filter(ctx, repository.LeaseFilter{PropertyID: lease.PropertyID, Status: models.LeaseStatusActive})
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range active {
+		if other.ID != lease.ID && other.Overlaps(*lease) {
+			return &other, nil
+		}
+	}
+	return nil, nil
+}
+
+// adminLease loads the lease named by the :id path parameter
+func (h *Handler) adminLease(c echo.Context) (*models.Lease, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, repository.ErrNotFound
+	}
+	return h.Store.Leases.Get(c.Request().Context(), id)
+}
+
+func adminLeaseError(c echo.Context, err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Lease not found")
+	}
+	c.Logger().Errorf("lease %s: %v", c.Param("id"), err)
+	return c.String(http.StatusInternalServerError, "Failed to load lease")
+}
+
+// leaseFormStatus is the status of a re-rendered lease form: 200 after
+// adding or removing a tenant row, 422 for errors
+func leaseFormStatus(edited bool) int {
+	if edited {
+		return http.StatusOK
+	}
+	return http.StatusUnprocessableEntity
+}
+
+// parseLeaseForm reads the admin lease form. Field errors are keyed by form
+// field name, and tenant fields by name and row, e.g. "tenantEmail-1". The
+// form's "add-tenant" and "remove-tenant-<row>" actions edit the tenant
+// rows instead of saving, which edited reports.
+func parseLeaseForm(c echo.Context) (lease models.Lease, errs map[string]string, edited bool) {
+	errs = make(map[string]string)
+	f := propertyForm{c: c, errs: errs}
+
+	lease = models.Lease{
+		Term:             f.text("term", "Lease term", 100, false),
+		Notes:            f.text("notes", "Notes", 5000, false),
+		MonthlyRentCents: f.cents("monthlyRent", "Monthly rent", true),
+		DepositCents:     f.cents("deposit", "Deposit", false),
+		PetRentCents:     f.cents("petRent", "Pet rent", false),
+	}
+	if id, err := strconv.ParseInt(c.FormValue("propertyId"), 10, 64); err == nil {
+		lease.PropertyID = id
+	} else {
+		errs["propertyId"] = "Choose a property"
+	}
+	if id, err := strconv.ParseInt(c.FormValue("applicationId"), 10, 64); err == nil {
+		lease.ApplicationID = &id
+	}
+	if id, err := strconv.ParseInt(c.FormValue("lateFeePolicyId"), 10, 64); err == nil {
+		lease.LateFeePolicyID = &id
+	}
+	lease.StartDate = f.date("startDate", "Start date")
+	lease.EndDate = f.date("endDate", "End date")
+	if errs["startDate"] == "" && errs["endDate"] == "" && !lease.EndDate.After(lease.StartDate) {
+		errs["endDate"] = "End date must be after the start date"
+	}
+
+	form, _ := c.FormParams()
+	names, emails, userIDs := form["tenantName"], form["tenantEmail"], form["tenantUserId"]
+	for i := range names {
+		t := models.LeaseTenant{Name: strings.TrimSpace(names[i])}
+		if i < len(emails) {
+			t.Email = strings.TrimSpace(emails[i])
+		}
+		if i < len(userIDs) {
+			t.UserID = strings.TrimSpace(userIDs[i])
+		}
+		lease.Tenants = append(lease.Tenants, t)
+	}
+
+	switch action := c.FormValue("action"); {
+	case action == "add-tenant":
+		lease.Tenants = append(lease.Tenants, models.LeaseTenant{})
+		return lease, nil, true
+	case strings.HasPrefix(action, "remove-tenant-"):
+		if i, err := strconv.Atoi(strings.TrimPrefix(action, "remove-tenant-")); err == nil && i >= 0 && i < len(lease.Tenants) {
+			lease.Tenants = slices.Delete(lease.Tenants, i, i+1)
+		}
+		return lease, nil, true
+	}
+
+	// Blank rows are ignored, but every lease needs a tenant
+	lease.Tenants = slices.DeleteFunc(lease.Tenants, func(t models.LeaseTenant) bool { return t == models.LeaseTenant{} })
+	if len(lease.Tenants) == 0 {
+		errs["tenants"] = "Add at least one tenant"
+		lease.Tenants = []models.LeaseTenant{{}}
+	}
+	seen := make(map[string]bool)
+	for i, t := range lease.Tenants {
+		key := func(field string) string { return fmt.Sprintf("%s-%d", field, i) }
+		switch {
+		case t.Name == "":
+			errs[key("tenantName")] = "Name is required"
+		case len(t.Name) > 255:
+			errs[key("tenantName")] = "Name must be at most 255 characters"
+		}
+		if !strings.Contains(t.Email, "@") || len(t.Email) > 255 {
+			errs[key("tenantEmail")] = "Enter a valid email address"
+		}
+		switch {
+		case t.UserID == "":
+			errs[key("tenantUserId")] = "Enter the tenant's user ID so they can see the lease"
+		case seen[t.UserID]:
+			errs[key("tenantUserId")] = "This user is already on the lease"
+		}
+		seen[t.UserID] = true
+	}
+	return lease, errs, false
+}
+
+// leaseFromApplication drafts a lease from an approved application, with
+// the property's current rent and the applicant as tenant. Co-applicants
+// are added without user IDs for staff to fill in.
+func leaseFromApplication(app *models.Application, property *models.Property) models.Lease {
+	applicant := app.Data.Applicant
+	lease := models.Lease{
+		PropertyID:       property.ID,
+		ApplicationID:    &app.ID,
+		Status:           models.LeaseStatusDraft,
+		Term:             applicant.LeaseTerm,
+		MonthlyRentCents: int64(property.Price) * 100,
+		DepositCents:     int64(property.Deposit) * 100,
+		Tenants:          []models.LeaseTenant{{UserID: app.ApplicantID, Name: applicant.Name(), Email: applicant.Email}},
+	}
+	for _, co := range app.Data.CoApplicants {
+		lease.Tenants = append(lease.Tenants, models.LeaseTenant{Name: co.Name, Email: co.Email})
+	}
+	if property.PetRent != nil && len(app.Data.Pets) > 0 {
+		lease.PetRentCents = int64(*property.PetRent) * 100
+	}
+	if start, err := time.Parse("2006-01-02", applicant.MoveInDate); err == nil {
+		lease.StartDate = start
+		if months := leaseTermMonths(applicant.LeaseTerm); months > 0 {
+			lease.EndDate = start.AddDate(0, months, -1)
+		}
+	}
+	return lease
+}
+
+var leaseTermPattern = regexp.MustCompile(`(?i)(\d+)[ -]*month`)
+
+// leaseTermMonths reads the length of terms like "12-month lease", or
+// returns 0
+func leaseTermMonths(term string) int {
+	m := leaseTermPattern.FindStringSubmatch(term)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
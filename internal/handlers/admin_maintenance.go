@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/templates/pages"
+)
+
+// AdminMaintenance is the staff maintenance queue. Requests from the filter
+// form only get the list back.
+func (h *Handler) AdminMaintenance(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	filters := pages.MaintenanceFilters{
+		Property: c.QueryParam("property"),
+		Status:   c.QueryParam("status"),
+		Urgency:  c.QueryParam("urgency"),
+		Assignee: c.QueryParam("assignee"),
+	}
+	var filter repository.MaintenanceFilter
+	if id, err := strconv.ParseInt(filters.Property, 10, 64); err == nil {
+		filter.PropertyID = id
+	}
+	if s := models.MaintenanceStatus(filters.Status); s.IsValid() {
+		filter.Status = s
+	}
+	if u := models.MaintenanceUrgency(filters.Urgency); u.IsValid() {
+		filter.Urgency = u
+	}
+	filter.AssigneeID = filters.Assignee
+
+	requests, err := h.Store.Maintenance.Filter(ctx, filter)
+	if err != nil {
+		c.Logger().Errorf("filter maintenance requests: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance requests")
+	}
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance requests")
+	}
+	staff, err := h.staffDirectory(ctx, middleware.GetUserID(c))
+	if err != nil {
+		c.Logger().Errorf("list staff: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance requests")
+	}
+
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return Render(c, http.StatusOK, pages.AdminMaintenanceList(requests, properties, staff))
+	}
+	return Render(c, http.StatusOK, pages.AdminMaintenance(requests, properties, staff, filters))
+}
+
+func (h *Handler) AdminMaintenanceRequest(c echo.Context) error {
+	ctx := c.Request().Context()
+	req, err := h.adminMaintenanceRequest(c)
+	if err != nil {
+		return adminMaintenanceError(c, err)
+	}
+	property, err := h.Store.Properties.GetByID(ctx, req.PropertyID)
+	if err != nil {
+		return adminMaintenanceError(c, err)
+	}
+	events, staff, err := h.maintenanceActivity(c, req)
+	if err != nil {
+		c.Logger().Errorf("load maintenance request %d: %v", req.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance request")
+	}
+	orders, err := h.Store.WorkOrders.ListByRequest(ctx, req.ID)
+	if err != nil {
+		c.Logger().Errorf("list work orders for maintenance request %d: %v", req.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance request")
+	}
+	vendors, err := h.Store.Vendors.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list vendors: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance request")
+	}
+	return Render(c, http.StatusOK, pages.AdminMaintenanceRequest(*req, *property, events, staff, middleware.GetUserID(c), orders, vendors))
+}
+
+// AdminUpdateMaintenance changes a request's status and visit time, and
+// can send the tenant a message with it. The tenant is emailed about
+// whatever changed.
+func (h *Handler) AdminUpdateMaintenance(c echo.Context) error {
+	ctx := c.Request().Context()
+	req, err := h.adminMaintenanceRequest(c)
+	if err != nil {
+		return adminMaintenanceError(c, err)
+	}
+
+	errs := make(map[string]string)
+	status := models.MaintenanceStatus(c.FormValue("status"))
+	if !status.IsValid() {
+		errs["status"] = "Choose a valid status"
+	}
+	visit, visitErr := parseVisitTime(c.FormValue("visitDate"), c.FormValue("visitTime"), h.Location)
+	switch {
+	case visitErr != "":
+		errs["visitDate"] = visitErr
+	case visit == nil && status == models.MaintenanceStatusScheduled:
+		errs["visitDate"] = "Enter when the visit is to mark the request scheduled"
+	}
+	message := strings.TrimSpace(c.FormValue("message"))
+	if len(message) > maxNoteLength {
+		errs["message"] = "Messages can be up to 5000 characters"
+	}
+	if len(errs) > 0 {
+		return h.renderMaintenanceWorkflow(c, http.StatusUnprocessableEntity, req, errs)
+	}
+
+	actorID := middleware.GetUserID(c)
+	var changes []string
+	if status != req.Status {
+		if req, err = h.Store.Maintenance.SetStatus(ctx, req.ID, status, actorID); err != nil {
+			return adminMaintenanceError(c, err)
+		}
+		changes = append(changes, "Status: "+status.Label())
+	}
+	if !sameVisit(visit, req.ScheduledFor) {
+		if req, err = h.Store.Maintenance.Schedule(ctx, req.ID, visit, actorID); err != nil {
+			return adminMaintenanceError(c, err)
+		}
+		changes = append(changes, "Visit: "+maintenanceVisit(visit, h.Location))
+	}
+	if message != "" {
+		if _, err := h.Store.Maintenance.AddEvent(ctx, req.ID, models.MaintenanceEventMessage, message, actorID); err != nil {
+			return adminMaintenanceError(c, err)
+		}
+		changes = append(changes, "\n"+message)
+	}
+	if len(changes) > 0 {
+		h.sendMaintenanceUpdate(c, req, changes)
+	}
+	return h.renderMaintenanceWorkflow(c, http.StatusOK, req, nil)
+}
+
+// AdminAssignMaintenance hands a request to a staff member and records who
+// is doing the work. Only the vendor is shared with the tenant.
+func (h *Handler) AdminAssignMaintenance(c echo.Context) error {
+	ctx := c.Request().Context()
+	req, err := h.adminMaintenanceRequest(c)
+	if err != nil {
+		return adminMaintenanceError(c, err)
+	}
+
+	errs := make(map[string]string)
+	assignee := c.FormValue("assignee")
+	if assignee != "" {
+		staff, err := h.staffDirectory(ctx, middleware.GetUserID(c))
+		if err != nil {
+			c.Logger().Errorf("list staff: %v", err)
+			return c.String(http.StatusInternalServerError, "Failed to assign maintenance request")
+		}
+		if !slices.ContainsFunc(staff, func(u models.User) bool { return u.ClerkUserID == assignee }) {
+			errs["assignee"] = "Choose a staff member"
+		}
+	}
+	vendor := propertyForm{c, errs}.text("vendor", "Vendor", 255, false)
+	if len(errs) > 0 {
+		return h.renderMaintenanceWorkflow(c, http.StatusUnprocessableEntity, req, errs)
+	}
+
+	actorID := middleware.GetUserID(c)
+	if assignee != req.AssigneeID {
+		if req, err = h.Store.Maintenance.Assign(ctx, req.ID, assignee, actorID); err != nil {
+			return adminMaintenanceError(c, err)
+		}
+	}
+	if vendor != req.Vendor {
+		if req, err = h.Store.Maintenance.SetVendor(ctx, req.ID, vendor, actorID); err != nil {
+			return adminMaintenanceError(c, err)
+		}
+		if vendor != "" {
+			h.sendMaintenanceUpdate(c, req, []string{"Vendor: " + vendor})
+		}
+	}
+	return h.renderMaintenanceWorkflow(c, http.StatusOK, req, nil)
+}
+
+// AdminAddMaintenanceNote adds an internal note, which the tenant doesn't
+// see
+func (h *Handler) AdminAddMaintenanceNote(c echo.Context) error {
+	req, err := h.adminMaintenanceRequest(c)
+	if err != nil {
+		return adminMaintenanceError(c, err)
+	}
+
+	note := strings.TrimSpace(c.FormValue("note"))
+	if note == "" || len(note) > maxNoteLength {
+		return h.renderMaintenanceWorkflow(c, http.StatusUnprocessableEntity, req, map[string]string{"note": "Enter a note of up to 5000 characters"})
+	}
+	if _, err := h.Store.Maintenance.AddEvent(c.Request().Context(), req.ID, models.MaintenanceEventNote, note, middleware.GetUserID(c)); err != nil {
+		return adminMaintenanceError(c, err)
+	}
+	return h.renderMaintenanceWorkflow(c, http.StatusOK, req, nil)
+}
+
+// renderMaintenanceWorkflow renders the status, assignment, notes and
+// timeline panel of a request's page
+func (h *Handler) renderMaintenanceWorkflow(c echo.Context, status int, req *models.MaintenanceRequest, errs map[string]string) error {
+	events, staff, err := h.maintenanceActivity(c, req)
+	if err != nil {
+		c.Logger().Errorf("load maintenance request %d: %v", req.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance request")
+	}
+	return Render(c, status, pages.AdminMaintenanceWorkflow(*req, events, staff, middleware.GetUserID(c), errs))
+}
+
+// maintenanceActivity loads req's timeline and the staff it can be assigned
+// to
+func (h *Handler) maintenanceActivity(c echo.Context, req *models.MaintenanceRequest) ([]models.MaintenanceEvent, []models.User, error) {
+	ctx := c.Request().Context()
+	events, err := h.Store.Maintenance.Timeline(ctx, req.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	staff, err := h.staffDirectory(ctx, middleware.GetUserID(c))
+	if err != nil {
+		return nil, nil, err
+	}
+	return events, staff, nil
+}
+
+// AdminMaintenancePhoto sends staff to a signed link for a request's photo
+func (h *Handler) AdminMaintenancePhoto(c echo.Context) error {
+	req, err := h.adminMaintenanceRequest(c)
+	if err != nil {
+		return adminMaintenanceError(c, err)
+	}
+	return h.redirectToMaintenancePhoto(c, req)
+}
+
+func (h *Handler) adminMaintenanceRequest(c echo.Context) (*models.MaintenanceRequest, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, repository.ErrNotFound
+	}
+	return h.Store.Maintenance.Get(c.Request().Context(), id)
+}
+
+func adminMaintenanceError(c echo.Context, err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Maintenance request not found")
+	}
+	c.Logger().Errorf("maintenance request %s: %v", c.Param("id"), err)
+	return c.String(http.StatusInternalServerError, "Failed to load maintenance request")
+}
+
+// parseVisitTime reads a visit's date and time of day in loc. Both blank
+// means no visit; otherwise it returns a message for the form.
+func parseVisitTime(date, clock string, loc *time.Location) (*time.Time, string) {
+	date, clock = strings.TrimSpace(date), strings.TrimSpace(clock)
+	if date == "" && clock == "" {
+		return nil, ""
+	}
+	if date == "" || clock == "" {
+		return nil, "Enter both the date and time of the visit"
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", date+" "+clock, loc)
+	if err != nil {
+		return nil, "Enter a valid date and time"
+	}
+	return &t, ""
+}
+
+// sameVisit reports whether a and b are the same visit time, or both none
+func sameVisit(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// maintenanceVisit describes a visit time for tenants, in loc
+func maintenanceVisit(at *time.Time, loc *time.Location) string {
+	if at == nil {
+		return "not scheduled"
+	}
+	return at.In(loc).Format("Monday, January 2 at 3:04 PM")
+}
+
+// sendMaintenanceUpdate emails the tenant who filed req what changed.
+// Failures are only logged.
+func (h *Handler) sendMaintenanceUpdate(c echo.Context, req *models.MaintenanceRequest, changes []string) {
+	body := fmt.Sprintf(`Hi %s,
+
+There's an update on your maintenance request "%s":
+
+%s
+
+View your request: %s
+`, req.TenantName, req.Title, strings.Join(changes, "\n"),
+		h.absoluteURL(fmt.Sprintf("/dashboard/maintenance/%d", req.ID)))
+
+	err := h.Mailer.Send(c.Request().Context(), mailer.Message{
+		To:      req.TenantEmail,
+		Subject: fmt.Sprintf("Update on maintenance request #%d: %s", req.ID, req.Title),
+		Body:    body,
+	})
+	if err != nil {
+		c.Logger().Warnf("email tenant of maintenance request %d: %v", req.ID, err)
+	}
+}
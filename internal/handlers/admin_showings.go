@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/templates/pages"
+)
+
+// AdminShowings lists open availability windows and upcoming showings
+func (h *Handler) AdminShowings(c echo.Context) error {
+	ctx := c.Request().Context()
+	now := h.now()
+
+	windows, err := h.Store.Showings.Windows(ctx, 0, now)
+	if err != nil {
+		c.Logger().Errorf("list showing windows: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load showings")
+	}
+	showings, err := h.Store.Showings.Upcoming(ctx, now)
+	if err != nil {
+		c.Logger().Errorf("list showings: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load showings")
+	}
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load showings")
+	}
+	staff, err := h.staffDirectory(ctx, middleware.GetUserID(c))
+	if err != nil {
+		c.Logger().Errorf("list staff: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load showings")
+	}
+
+	form := pages.ShowingWindowForm{SlotMinutes: "30"}
+	feeds, err := h.calendarFeeds(c, middleware.GetUserID(c), properties)
+	if err != nil {
+		c.Logger().Errorf("list calendar feeds: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load showings")
+	}
+	return Render(c, http.StatusOK, pages.AdminShowings(windows, showings, properties, staff, form, feeds))
+}
+
+func (h *Handler) AdminCreateShowingWindow(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	form := pages.ShowingWindowForm{
+		Property:    c.FormValue("property"),
+		Agent:       c.FormValue("agent"),
+		Date:        c.FormValue("date"),
+		Start:       c.FormValue("start"),
+		End:         c.FormValue("end"),
+		SlotMinutes: c.FormValue("slotMinutes"),
+	}
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to save availability")
+	}
+	staff, err := h.staffDirectory(ctx, middleware.GetUserID(c))
+	if err != nil {
+		c.Logger().Errorf("list staff: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to save availability")
+	}
+
+	w, errs := parseShowingWindowForm(form, properties, staff, h.now())
+	if len(errs) > 0 {
+		return Render(c, http.StatusUnprocessableEntity, pages.AdminShowingWindowForm(form, properties, staff, errs))
+	}
+	if err := h.Store.Showings.CreateWindow(ctx, &w); err != nil {
+		c.Logger().Errorf("create showing window: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to save availability")
+	}
+
+	c.Response().Header().Set("HX-Redirect", "/admin/showings")
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AdminDeleteShowingWindow stops offering a window's slots. Showings already
+// booked in it are kept.
+func (h *Handler) AdminDeleteShowingWindow(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Availability not found")
+	}
+	if err := h.Store.Showings.DeleteWindow(c.Request().Context(), id); err != nil {
+		c.Logger().Errorf("delete showing window %d: %v", id, err)
+		return c.String(http.StatusInternalServerError, "Failed to delete availability")
+	}
+
+	// An empty body removes the table row that triggered the request
+	return c.HTML(http.StatusOK, "")
+}
+
+// AdminCancelShowing cancels a showing on the visitor's behalf and lets them
+// know by email
+func (h *Handler) AdminCancelShowing(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Showing not found")
+	}
+	showing, err := h.Store.Showings.Get(ctx, id)
+	if err != nil {
+		return adminShowingError(c, err)
+	}
+	property, err := h.Store.Properties.GetByID(ctx, showing.PropertyID)
+	if err != nil {
+		return adminShowingError(c, err)
+	}
+
+	if showing.Active() {
+		showing, err = h.Store.Showings.SetStatus(ctx, showing.ID, models.ShowingStatusCancelled)
+		if err != nil {
+			return adminShowingError(c, err)
+		}
+		h.sendShowingCancelled(c, showing, property)
+	}
+
+	staff, err := h.staffDirectory(ctx, middleware.GetUserID(c))
+	if err != nil {
+		c.Logger().Errorf("list staff: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load showing")
+	}
+	return Render(c, http.StatusOK, pages.AdminShowingRow(*showing, []models.Property{*property}, staff))
+}
+
+// sendShowingCancelled tells the visitor staff cancelled their showing.
+// Failures are only logged.
+func (h *Handler) sendShowingCancelled(c echo.Context, showing *models.Showing, property *models.Property) {
+	body := fmt.Sprintf(`Hi %s,
+
+Unfortunately we've had to cancel your showing of %s %s.
+
+Please pick another time on our contact page:
+
+%s
+
+Sorry for the trouble.
+`, showing.Name, property.Title, h.showingWhen(showing), h.absoluteURL("/contact?property="+property.Slug))
+
+	err := h.Mailer.Send(c.Request().Context(), mailer.Message{
+		To:          showing.Email,
+		Subject:     "Your showing of " + property.Title + " was cancelled",
+		Body:        body,
+		Attachments: []mailer.Attachment{h.showingInvite(c, showing, property, false)},
+	})
+	if err != nil {
+		c.Logger().Warnf("notify visitor of cancelled showing %d: %v", showing.ID, err)
+	}
+}
+
+func adminShowingError(c echo.Context, err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Showing not found")
+	}
+	c.Logger().Errorf("showing %s: %v", c.Param("id"), err)
+	return c.String(http.StatusInternalServerError, "Failed to update showing")
+}
+
+// parseShowingWindowForm validates a new availability window. Times are
+// entered in now's time zone, the business's, and the window must fit at
+// least one slot.
+func parseShowingWindowForm(f pages.ShowingWindowForm, properties []models.Property, staff []models.User, now time.Time) (models.ShowingWindow, map[string]string) {
+	errs := map[string]string{}
+	var w models.ShowingWindow
+
+	id, err := strconv.ParseInt(f.Property, 10, 64)
+	if err != nil || !slices.ContainsFunc(properties, func(p models.Property) bool { return p.ID == id }) {
+		errs["property"] = "Choose a property"
+	}
+	w.PropertyID = id
+
+	if !slices.ContainsFunc(staff, func(u models.User) bool { return u.ClerkUserID == f.Agent }) {
+		errs["agent"] = "Choose who will show the property"
+	}
+	w.AgentID = f.Agent
+
+	minutes, err := strconv.Atoi(f.SlotMinutes)
+	if err != nil || !slices.Contains(pages.ShowingSlotMinutes, minutes) {
+		errs["slotMinutes"] = "Choose a showing length"
+	}
+	w.SlotMinutes = minutes
+
+	start, startErr := time.ParseInLocation("2006-01-02 15:04", f.Date+" "+f.Start, now.Location())
+	end, endErr := time.ParseInLocation("2006-01-02 15:04", f.Date+" "+f.End, now.Location())
+	switch {
+	case startErr != nil:
+		errs["start"] = "Enter a date and start time"
+	case endErr != nil:
+		errs["end"] = "Enter an end time"
+	case !start.After(now):
+		errs["start"] = "Start time must be in the future"
+	case !end.After(start):
+		errs["end"] = "End time must be after the start time"
+	case errs["slotMinutes"] == "" && end.Sub(start) < w.SlotLength():
+		errs["end"] = "Window is too short for one showing"
+	}
+	w.StartsAt = start
+	w.EndsAt = end
+
+	return w, errs
+}
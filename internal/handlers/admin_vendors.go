@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/mail"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/templates/pages"
+)
+
+// AdminVendors lists the vendor directory
+func (h *Handler) AdminVendors(c echo.Context) error {
+	vendors, err := h.Store.Vendors.List(c.Request().Context())
+	if err != nil {
+		c.Logger().Errorf("list vendors: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load vendors")
+	}
+	return Render(c, http.StatusOK, pages.AdminVendors(vendors))
+}
+
+func (h *Handler) AdminNewVendor(c echo.Context) error {
+	return Render(c, http.StatusOK, pages.AdminVendorEditor(models.Vendor{Active: true}, nil))
+}
+
+func (h *Handler) AdminCreateVendor(c echo.Context) error {
+	vendor, errs := parseVendorForm(c)
+	if len(errs) > 0 {
+		return Render(c, http.StatusUnprocessableEntity, pages.AdminVendorForm(vendor, errs))
+	}
+
+	if err := h.Store.Vendors.Create(c.Request().Context(), &vendor); err != nil {
+		c.Logger().Errorf("create vendor: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to save vendor")
+	}
+
+	c.Response().Header().Set("HX-Redirect", "/admin/vendors")
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) AdminEditVendor(c echo.Context) error {
+	vendor, err := h.adminVendor(c)
+	if err != nil {
+		return adminVendorError(c, err)
+	}
+	return Render(c, http.StatusOK, pages.AdminVendorEditor(*vendor, nil))
+}
+
+func (h *Handler) AdminUpdateVendor(c echo.Context) error {
+	existing, err := h.adminVendor(c)
+	if err != nil {
+		return adminVendorError(c, err)
+	}
+
+	vendor, errs := parseVendorForm(c)
+	vendor.ID = existing.ID
+	if len(errs) > 0 {
+		return Render(c, http.StatusUnprocessableEntity, pages.AdminVendorForm(vendor, errs))
+	}
+
+	if err := h.Store.Vendors.Update(c.Request().Context(), &vendor); err != nil {
+		return adminVendorError(c, err)
+	}
+
+	c.Response().Header().Set("HX-Redirect", "/admin/vendors")
+	return c.NoContent(http.StatusNoContent)
+}
+
+// adminVendor loads the vendor named by the :id path parameter
+func (h *Handler) adminVendor(c echo.Context) (*models.Vendor, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, repository.ErrNotFound
+	}
+	return h.Store.Vendors.Get(c.Request().Context(), id)
+}
+
+func adminVendorError(c echo.Context, err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Vendor not found")
+	}
+	c.Logger().Errorf("vendor %s: %v", c.Param("id"), err)
+	return c.String(http.StatusInternalServerError, "Failed to load vendor")
+}
+
+// parseVendorForm reads the admin vendor form
+func parseVendorForm(c echo.Context) (models.Vendor, map[string]string) {
+	errs := make(map[string]string)
+	f := propertyForm{c: c, errs: errs}
+
+	vendor := models.Vendor{
+		Name:            f.text("name", "Name", 255, true),
+		ContactName:     f.text("contactName", "Contact", 255, false),
+		Email:           f.text("email", "Email", 255, false),
+		Phone:           f.text("phone", "Phone", 50, false),
+		HourlyRateCents: f.cents("hourlyRate", "Hourly rate", false),
+		CalloutFeeCents: f.cents("calloutFee", "Call-out fee", false),
+		Notes:           f.text("notes", "Notes", maxNoteLength, false),
+		Active:          c.FormValue("active") == "on",
+	}
+	if _, failed := errs["email"]; !failed && vendor.Email != "" {
+		if addr, err := mail.ParseAddress(vendor.Email); err != nil || addr.Address != vendor.Email {
+			errs["email"] = "Enter a valid email address"
+		}
+	}
+
+	form, _ := c.FormParams()
+	for _, v := range form["trades"] {
+		if t := models.MaintenanceCategory(v); t.IsValid() && !vendor.Covers(t) {
+			vendor.Trades = append(vendor.Trades, t)
+		}
+	}
+	if len(vendor.Trades) == 0 {
+		errs["trades"] = "Choose at least one trade"
+	}
+	return vendor, errs
+}
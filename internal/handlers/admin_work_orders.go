@@ -0,0 +1,476 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/templates/pages"
+)
+
+// AdminWorkOrders lists work orders with what they cost and who pays.
+// Requests from the filter form only get the list back.
+func (h *Handler) AdminWorkOrders(c echo.Context) error {
+	ctx := c.Request().Context()
+	filters, filter := workOrderFilters(c)
+
+	orders, err := h.Store.WorkOrders.Filter(ctx, filter)
+	if err != nil {
+		c.Logger().Errorf("filter work orders: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load work orders")
+	}
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load work orders")
+	}
+	vendors, err := h.Store.Vendors.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list vendors: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load work orders")
+	}
+
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return Render(c, http.StatusOK, pages.AdminWorkOrderList(orders, properties, vendors, filters))
+	}
+	return Render(c, http.StatusOK, pages.AdminWorkOrders(orders, properties, vendors, filters))
+}
+
+// AdminWorkOrdersCSV downloads the filtered work orders, for billing costs
+// back to owners
+func (h *Handler) AdminWorkOrdersCSV(c echo.Context) error {
+	ctx := c.Request().Context()
+	_, filter := workOrderFilters(c)
+
+	orders, err := h.Store.WorkOrders.Filter(ctx, filter)
+	if err != nil {
+		c.Logger().Errorf("filter work orders: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to export work orders")
+	}
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to export work orders")
+	}
+	vendors, err := h.Store.Vendors.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list vendors: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to export work orders")
+	}
+	propertyTitles := make(map[int64]string, len(properties))
+	for _, p := range properties {
+		propertyTitles[p.ID] = p.Title
+	}
+	vendorNames := make(map[int64]string, len(vendors))
+	for _, v := range vendors {
+		vendorNames[v.ID] = v.Name
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"Work order", "Request", "Property", "Vendor", "Status", "Scheduled", "Completed", "Bill to", "Estimate", "Invoice number", "Invoiced on", "Invoice amount", "Charged to tenant"})
+	for _, o := range orders {
+		completed, charged := "", ""
+		if o.CompletedAt != nil {
+			completed = o.CompletedAt.In(h.Location).Format("2006-01-02")
+		}
+		if o.LedgerEntryID != nil {
+			charged = "yes"
+		}
+		w.Write([]string{
+			strconv.FormatInt(o.ID, 10),
+			strconv.FormatInt(o.RequestID, 10),
+			csvText(propertyTitles[o.PropertyID]),
+			csvText(vendorNames[o.VendorID]),
+			o.Status.Label(),
+			csvDate(o.ScheduledOn),
+			completed,
+			o.BillTo.Label(),
+			csvCents(o.EstimateCents),
+			csvText(o.InvoiceNumber),
+			csvDate(o.InvoicedOn),
+			csvCents(o.InvoiceCents),
+			charged,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		c.Logger().Errorf("write work orders csv: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to export work orders")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="work-orders-%s.csv"`, h.now().Format("2006-01-02")))
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// AdminCreateWorkOrder issues a work order for a maintenance request and
+// emails it to the vendor. The vendor becomes the request's vendor, which
+// the tenant is told about.
+func (h *Handler) AdminCreateWorkOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+	req, err := h.adminMaintenanceRequest(c)
+	if err != nil {
+		return adminMaintenanceError(c, err)
+	}
+
+	errs := make(map[string]string)
+	f := propertyForm{c, errs}
+	order := models.WorkOrder{
+		RequestID:     req.ID,
+		PropertyID:    req.PropertyID,
+		Scope:         f.text("scope", "Scope of work", maxNoteLength, true),
+		ScheduledOn:   f.optionalDate("scheduledOn"),
+		EstimateCents: f.cents("estimate", "Estimate", false),
+		BillTo:        models.WorkOrderBillTo(c.FormValue("billTo")),
+		CreatedBy:     middleware.GetUserID(c),
+	}
+	if !order.BillTo.IsValid() {
+		errs["billTo"] = "Choose who pays for the work"
+	}
+	var vendor *models.Vendor
+	if id, err := strconv.ParseInt(c.FormValue("vendorId"), 10, 64); err == nil {
+		vendor, err = h.Store.Vendors.Get(ctx, id)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return adminVendorError(c, err)
+		}
+	}
+	if vendor == nil || !vendor.Active {
+		errs["vendorId"] = "Choose an active vendor"
+	} else {
+		order.VendorID = vendor.ID
+	}
+	if len(errs) > 0 {
+		return h.renderMaintenanceWorkOrders(c, http.StatusUnprocessableEntity, req, order, errs)
+	}
+
+	if err := h.Store.WorkOrders.Create(ctx, &order); err != nil {
+		return adminMaintenanceError(c, err)
+	}
+	if vendor.Name != req.Vendor {
+		if req, err = h.Store.Maintenance.SetVendor(ctx, req.ID, vendor.Name, order.CreatedBy); err != nil {
+			return adminMaintenanceError(c, err)
+		}
+		h.sendMaintenanceUpdate(c, req, []string{"Vendor: " + vendor.Name})
+	}
+	h.sendWorkOrder(c, &order, req, vendor)
+
+	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/admin/work-orders/%d", order.ID))
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) AdminWorkOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+	order, err := h.adminWorkOrder(c)
+	if err != nil {
+		return adminWorkOrderError(c, err)
+	}
+	req, vendor, err := h.workOrderDetails(c, order)
+	if err != nil {
+		return adminWorkOrderError(c, err)
+	}
+	property, err := h.Store.Properties.GetByID(ctx, order.PropertyID)
+	if err != nil {
+		return adminWorkOrderError(c, err)
+	}
+	staff, err := h.staffDirectory(ctx, middleware.GetUserID(c))
+	if err != nil {
+		c.Logger().Errorf("list staff: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load work order")
+	}
+	return Render(c, http.StatusOK, pages.AdminWorkOrder(*order, *req, *property, *vendor, staff))
+}
+
+// AdminUpdateWorkOrder changes an open order's scope, date, estimate and
+// who pays
+func (h *Handler) AdminUpdateWorkOrder(c echo.Context) error {
+	order, err := h.adminWorkOrder(c)
+	if err != nil {
+		return adminWorkOrderError(c, err)
+	}
+
+	errs := make(map[string]string)
+	f := propertyForm{c, errs}
+	order.Scope = f.text("scope", "Scope of work", maxNoteLength, true)
+	order.ScheduledOn = f.optionalDate("scheduledOn")
+	order.EstimateCents = f.cents("estimate", "Estimate", false)
+	order.BillTo = models.WorkOrderBillTo(c.FormValue("billTo"))
+	if !order.BillTo.IsValid() {
+		errs["billTo"] = "Choose who pays for the work"
+	}
+	if len(errs) > 0 {
+		return h.renderWorkOrderPanel(c, http.StatusUnprocessableEntity, order, errs)
+	}
+
+	if err := h.Store.WorkOrders.Update(c.Request().Context(), order); err != nil {
+		return adminWorkOrderError(c, err)
+	}
+	return h.renderWorkOrderPanel(c, http.StatusOK, order, nil)
+}
+
+// AdminCompleteWorkOrder records that the vendor finished the work and
+// what they did
+func (h *Handler) AdminCompleteWorkOrder(c echo.Context) error {
+	order, err := h.adminWorkOrder(c)
+	if err != nil {
+		return adminWorkOrderError(c, err)
+	}
+
+	notes := strings.TrimSpace(c.FormValue("completionNotes"))
+	if notes == "" || len(notes) > maxNoteLength {
+		return h.renderWorkOrderPanel(c, http.StatusUnprocessableEntity, order, map[string]string{"completionNotes": "Describe the work done in up to 5000 characters"})
+	}
+	if order, err = h.Store.WorkOrders.Complete(c.Request().Context(), order.ID, notes); err != nil {
+		return adminWorkOrderError(c, err)
+	}
+	return h.renderWorkOrderPanel(c, http.StatusOK, order, nil)
+}
+
+func (h *Handler) AdminCancelWorkOrder(c echo.Context) error {
+	order, err := h.adminWorkOrder(c)
+	if err != nil {
+		return adminWorkOrderError(c, err)
+	}
+	if order, err = h.Store.WorkOrders.Cancel(c.Request().Context(), order.ID); err != nil {
+		return adminWorkOrderError(c, err)
+	}
+	return h.renderWorkOrderPanel(c, http.StatusOK, order, nil)
+}
+
+// AdminRecordWorkOrderInvoice records the vendor's invoice for a completed
+// order. It can be corrected until the cost is charged to the tenant.
+func (h *Handler) AdminRecordWorkOrderInvoice(c echo.Context) error {
+	order, err := h.adminWorkOrder(c)
+	if err != nil {
+		return adminWorkOrderError(c, err)
+	}
+
+	errs := make(map[string]string)
+	f := propertyForm{c, errs}
+	order.InvoiceNumber = f.text("invoiceNumber", "Invoice number", 100, true)
+	order.InvoiceCents = f.cents("invoiceAmount", "Invoice amount", true)
+	invoicedOn := f.date("invoicedOn", "Invoice date")
+	order.InvoicedOn = &invoicedOn
+	order.BillTo = models.WorkOrderBillTo(c.FormValue("billTo"))
+	if !order.BillTo.IsValid() {
+		errs["billTo"] = "Choose who pays for the work"
+	}
+	if len(errs) > 0 {
+		return h.renderWorkOrderPanel(c, http.StatusUnprocessableEntity, order, errs)
+	}
+
+	if err := h.Store.WorkOrders.RecordInvoice(c.Request().Context(), order); err != nil {
+		return adminWorkOrderError(c, err)
+	}
+	return h.renderWorkOrderPanel(c, http.StatusOK, order, nil)
+}
+
+// AdminChargeWorkOrder posts an invoiced order billed to the tenant as a
+// charge on their lease's ledger, once, and emails the tenant
+func (h *Handler) AdminChargeWorkOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+	order, err := h.adminWorkOrder(c)
+	if err != nil {
+		return adminWorkOrderError(c, err)
+	}
+	if !order.Chargeable() {
+		return adminWorkOrderError(c, repository.ErrStatusChanged)
+	}
+	req, err := h.Store.Maintenance.Get(ctx, order.RequestID)
+	if err != nil {
+		return adminWorkOrderError(c, err)
+	}
+
+	entry := models.LedgerEntry{
+		LeaseID:     req.LeaseID,
+		Kind:        models.LedgerKindCharge,
+		Description: fmt.Sprintf("Repair: %s (work order #%d)", req.Title, order.ID),
+		AmountCents: order.InvoiceCents,
+		PostedOn:    models.Date(h.now()),
+		CreatedBy:   middleware.GetUserID(c),
+	}
+	if order, err = h.Store.WorkOrders.ChargeTenant(ctx, order.ID, &entry); err != nil {
+		return adminWorkOrderError(c, err)
+	}
+	h.sendMaintenanceUpdate(c, req, []string{fmt.Sprintf("The repair cost of %s has been charged to your account.", models.FormatCents(entry.AmountCents))})
+	return h.renderWorkOrderPanel(c, http.StatusOK, order, nil)
+}
+
+// renderMaintenanceWorkOrders renders the work orders panel of a request's
+// page with the new order form holding order
+func (h *Handler) renderMaintenanceWorkOrders(c echo.Context, status int, req *models.MaintenanceRequest, order models.WorkOrder, errs map[string]string) error {
+	ctx := c.Request().Context()
+	orders, err := h.Store.WorkOrders.ListByRequest(ctx, req.ID)
+	if err != nil {
+		c.Logger().Errorf("list work orders for maintenance request %d: %v", req.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load work orders")
+	}
+	vendors, err := h.Store.Vendors.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list vendors: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load work orders")
+	}
+	return Render(c, status, pages.AdminMaintenanceWorkOrders(*req, orders, vendors, order, errs))
+}
+
+// renderWorkOrderPanel renders the status, invoice and charge panel of an
+// order's page
+func (h *Handler) renderWorkOrderPanel(c echo.Context, status int, order *models.WorkOrder, errs map[string]string) error {
+	req, vendor, err := h.workOrderDetails(c, order)
+	if err != nil {
+		return adminWorkOrderError(c, err)
+	}
+	return Render(c, status, pages.AdminWorkOrderPanel(*order, *req, *vendor, errs))
+}
+
+// workOrderDetails loads the request order was issued for and its vendor
+func (h *Handler) workOrderDetails(c echo.Context, order *models.WorkOrder) (*models.MaintenanceRequest, *models.Vendor, error) {
+	ctx := c.Request().Context()
+	req, err := h.Store.Maintenance.Get(ctx, order.RequestID)
+	if err != nil {
+		return nil, nil, err
+	}
+	vendor, err := h.Store.Vendors.Get(ctx, order.VendorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return req, vendor, nil
+}
+
+func (h *Handler) adminWorkOrder(c echo.Context) (*models.WorkOrder, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, repository.ErrNotFound
+	}
+	return h.Store.WorkOrders.Get(c.Request().Context(), id)
+}
+
+func adminWorkOrderError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return c.String(http.StatusNotFound, "Work order not found")
+	case errors.Is(err, repository.ErrStatusChanged):
+		return c.String(http.StatusConflict, "This work order was just updated. Please reload the page.")
+	}
+	c.Logger().Errorf("work order %s: %v", c.Param("id"), err)
+	return c.String(http.StatusInternalServerError, "Failed to load work order")
+}
+
+// workOrderFilters reads the work order list's filter query parameters
+func workOrderFilters(c echo.Context) (pages.WorkOrderFilters, repository.WorkOrderFilter) {
+	filters := pages.WorkOrderFilters{
+		Property: c.QueryParam("property"),
+		Vendor:   c.QueryParam("vendor"),
+		Status:   c.QueryParam("status"),
+		BillTo:   c.QueryParam("billTo"),
+	}
+	var filter repository.WorkOrderFilter
+	if id, err := strconv.ParseInt(filters.Property, 10, 64); err == nil {
+		filter.PropertyID = id
+	}
+	if id, err := strconv.ParseInt(filters.Vendor, 10, 64); err == nil {
+		filter.VendorID = id
+	}
+	if s := models.WorkOrderStatus(filters.Status); s.IsValid() {
+		filter.Status = s
+	}
+	if b := models.WorkOrderBillTo(filters.BillTo); b.IsValid() {
+		filter.BillTo = b
+	}
+	return filters, filter
+}
+
+// sendWorkOrder emails vendor the work order with what they need to do the
+// job. Vendors without an email address are sent nothing; failures are only
+// logged.
+func (h *Handler) sendWorkOrder(c echo.Context, order *models.WorkOrder, req *models.MaintenanceRequest, vendor *models.Vendor) {
+	if vendor.Email == "" {
+		return
+	}
+	property, err := h.Store.Properties.GetByID(c.Request().Context(), order.PropertyID)
+	if err != nil {
+		c.Logger().Warnf("email work order %d: %v", order.ID, err)
+		return
+	}
+
+	name := vendor.ContactName
+	if name == "" {
+		name = vendor.Name
+	}
+	scheduled := "To be arranged"
+	if order.ScheduledOn != nil {
+		scheduled = order.ScheduledOn.Format("Monday, January 2, 2006")
+	}
+	estimate := "None given"
+	if order.EstimateCents > 0 {
+		estimate = models.FormatCents(order.EstimateCents)
+	}
+	entry := "Please arrange access with us first. The tenant hasn't agreed to entry when no one is home."
+	if req.EntryPermitted {
+		entry = "You may let yourself in if no one is home."
+	}
+	if req.EntryNotes != "" {
+		entry += "\n" + req.EntryNotes
+	}
+
+	body := fmt.Sprintf(`Hi %s,
+
+Here is work order #%d from Russ Rentals.
+
+Property: %s, %s, %s %s
+Problem: %s (%s)
+Urgency: %s
+Date: %s
+Estimate: %s
+
+Scope of work:
+%s
+
+Access:
+%s
+
+Please reply to this email with any questions and send your invoice quoting work order #%d.
+`, name, order.ID,
+		property.Address, property.City, property.State, property.ZipCode,
+		req.Title, req.Category.Label(), req.Urgency.Label(), scheduled, estimate,
+		order.Scope, entry, order.ID)
+
+	err = h.Mailer.Send(c.Request().Context(), mailer.Message{
+		To:      vendor.Email,
+		Subject: fmt.Sprintf("Work order #%d: %s", order.ID, req.Title),
+		Body:    body,
+	})
+	if err != nil {
+		c.Logger().Warnf("email work order %d to vendor %d: %v", order.ID, vendor.ID, err)
+	}
+}
+
+// optionalDate reads a YYYY-MM-DD date that may be left blank
+func (f propertyForm) optionalDate(name string) *time.Time {
+	if strings.TrimSpace(f.c.FormValue(name)) == "" {
+		return nil
+	}
+	t := f.date(name, "")
+	if _, failed := f.errs[name]; failed {
+		return nil
+	}
+	return &t
+}
+
+// csvDate writes a date as YYYY-MM-DD, blank for none
+func csvDate(d *time.Time) string {
+	if d == nil {
+		return ""
+	}
+	return d.Format("2006-01-02")
+}
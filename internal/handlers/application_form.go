@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// Limits on repeated sections of the application form
+const (
+	maxApplicationRows = 10
+	minApplicantAge    = 18
+)
+
+// parseApplicationStep reads one step of the application form into data and
+// returns per-field errors. Fields of repeated rows are submitted as
+// parallel lists and their errors are keyed "<field>-<row>"; errors about
+// the rows as a whole are keyed "rows".
+func parseApplicationStep(step models.ApplicationStep, form url.Values, data *models.ApplicationData, property *models.Property, now time.Time) map[string]string {
+	errs := make(map[string]string)
+	f := applicationForm{form: form, errs: errs}
+
+	switch step {
+	case models.ApplicationStepApplicant:
+		a := models.Applicant{
+			FirstName:   f.text("firstName", -1, "First name", 100, true),
+			LastName:    f.text("lastName", -1, "Last name", 100, true),
+			Email:       f.email("email", -1, "Email", true),
+			Phone:       f.text("phone", -1, "Phone", 50, true),
+			DateOfBirth: f.date("dateOfBirth", -1, "Date of birth"),
+			MoveInDate:  f.date("moveInDate", -1, "Move-in date"),
+			LeaseTerm:   f.text("leaseTerm", -1, "Lease term", 100, len(property.LeaseTerms) > 0),
+			Occupants:   f.integer("occupants", -1, "Occupants", 1, 20),
+		}
+		if dob, err := time.Parse("2006-01-02", a.DateOfBirth); err == nil && dob.AddDate(minApplicantAge, 0, 0).After(now) {
+			errs["dateOfBirth"] = fmt.Sprintf("Applicants must be at least %d", minApplicantAge)
+		}
+		if moveIn, err := time.Parse("2006-01-02", a.MoveInDate); err == nil && moveIn.Before(now.Truncate(24*time.Hour)) {
+			errs["moveInDate"] = "Move-in date can't be in the past"
+		}
+		if a.LeaseTerm != "" && len(property.LeaseTerms) > 0 && !slices.Contains(property.LeaseTerms, a.LeaseTerm) {
+			errs["leaseTerm"] = "Choose one of the offered lease terms"
+		}
+		data.Applicant = a
+
+	case models.ApplicationStepResidences:
+		data.Residences = make([]models.Residence, f.rows("address"))
+		for i := range data.Residences {
+			r := models.Residence{
+				Address:       f.text("address", i, "Address", 255, true),
+				City:          f.text("city", i, "City", 100, true),
+				State:         f.text("state", i, "State", 50, true),
+				ZipCode:       f.text("zipCode", i, "ZIP code", 20, true),
+				From:          f.month("from", i, "Moved in", true),
+				To:            f.month("to", i, "Moved out", false),
+				MonthlyRent:   f.integer("monthlyRent", i, "Monthly rent", 0, 100_000),
+				LandlordName:  f.text("landlordName", i, "Landlord name", 255, false),
+				LandlordPhone: f.text("landlordPhone", i, "Landlord phone", 50, false),
+				Reason:        f.text("reason", i, "Reason for leaving", 500, false),
+			}
+			if r.To != "" && r.From != "" && r.To < r.From {
+				errs[rowKey("to", i)] = "Moved out must be after moved in"
+			}
+			data.Residences[i] = r
+		}
+		if len(data.Residences) == 0 {
+			errs["rows"] = "Add your current residence"
+		}
+
+	case models.ApplicationStepEmployment:
+		data.Employment = make([]models.Employment, f.rows("employer"))
+		for i := range data.Employment {
+			data.Employment[i] = models.Employment{
+				Employer:      f.text("employer", i, "Employer or income source", 255, true),
+				Position:      f.text("position", i, "Position", 255, false),
+				Phone:         f.text("phone", i, "Phone", 50, false),
+				From:          f.month("from", i, "Start", false),
+				MonthlyIncome: f.integer("monthlyIncome", i, "Monthly income", 0, 1_000_000),
+			}
+		}
+		if len(data.Employment) == 0 {
+			errs["rows"] = "Add at least one source of income"
+		}
+
+	case models.ApplicationStepReferences:
+		data.References = make([]models.Reference, f.rows("name"))
+		for i := range data.References {
+			data.References[i] = models.Reference{
+				Name:         f.text("name", i, "Name", 255, true),
+				Relationship: f.text("relationship", i, "Relationship", 100, true),
+				Phone:        f.text("phone", i, "Phone", 50, true),
+				Email:        f.email("email", i, "Email", false),
+			}
+		}
+		if len(data.References) == 0 {
+			errs["rows"] = "Add at least one reference"
+		}
+
+	case models.ApplicationStepPets:
+		data.Pets = make([]models.Pet, f.rows("kind"))
+		for i := range data.Pets {
+			data.Pets[i] = models.Pet{
+				Kind:   f.text("kind", i, "Type of pet", 100, true),
+				Breed:  f.text("breed", i, "Breed", 100, false),
+				Weight: f.integer("weight", i, "Weight", 0, 300),
+			}
+		}
+		if len(data.Pets) > 0 && !property.PetFriendly {
+			errs["rows"] = "This property doesn't allow pets"
+		}
+
+	case models.ApplicationStepCoApplicants:
+		data.CoApplicants = make([]models.CoApplicant, f.rows("name"))
+		for i := range data.CoApplicants {
+			data.CoApplicants[i] = models.CoApplicant{
+				Name:         f.text("name", i, "Name", 255, true),
+				Email:        f.email("email", i, "Email", true),
+				Phone:        f.text("phone", i, "Phone", 50, false),
+				Relationship: f.text("relationship", i, "Relationship", 100, false),
+			}
+		}
+	}
+
+	if len(f.rowCounts) > 0 && slices.Max(f.rowCounts) > maxApplicationRows {
+		errs["rows"] = fmt.Sprintf("Add at most %d entries", maxApplicationRows)
+	}
+	return errs
+}
+
+// editApplicationRows adds or removes a row of a repeated step, for the
+// form's "Add another" and "Remove" buttons. It reports whether action was
+// one of those.
+func editApplicationRows(step models.ApplicationStep, action string, data *models.ApplicationData) bool {
+	if action == "add" {
+		switch step {
+		case models.ApplicationStepResidences:
+			data.Residences = append(data.Residences, models.Residence{})
+		case models.ApplicationStepEmployment:
+			data.Employment = append(data.Employment, models.Employment{})
+		case models.ApplicationStepReferences:
+			data.References = append(data.References, models.Reference{})
+		case models.ApplicationStepPets:
+			data.Pets = append(data.Pets, models.Pet{})
+		case models.ApplicationStepCoApplicants:
+			data.CoApplicants = append(data.CoApplicants, models.CoApplicant{})
+		default:
+			return false
+		}
+		return true
+	}
+
+	v, ok := strings.CutPrefix(action, "remove-")
+	if !ok {
+		return false
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil || i < 0 {
+		return false
+	}
+	switch step {
+	case models.ApplicationStepResidences:
+		data.Residences = removeRow(data.Residences, i)
+	case models.ApplicationStepEmployment:
+		data.Employment = removeRow(data.Employment, i)
+	case models.ApplicationStepReferences:
+		data.References = removeRow(data.References, i)
+	case models.ApplicationStepPets:
+		data.Pets = removeRow(data.Pets, i)
+	case models.ApplicationStepCoApplicants:
+		data.CoApplicants = removeRow(data.CoApplicants, i)
+	default:
+		return false
+	}
+	return true
+}
+
+// addRequiredRow starts the applicant off with an empty row on steps that
+// need at least one
+func addRequiredRow(step models.ApplicationStep, data *models.ApplicationData) {
+	switch {
+	case step == models.ApplicationStepResidences && len(data.Residences) == 0,
+		step == models.ApplicationStepEmployment && len(data.Employment) == 0,
+		step == models.ApplicationStepReferences && len(data.References) == 0:
+		editApplicationRows(step, "add", data)
+	}
+}
+
+func removeRow[T any](rows []T, i int) []T {
+	if i >= len(rows) {
+		return rows
+	}
+	return slices.Delete(rows, i, i+1)
+}
+
+// applicationForm collects per-field errors while reading form values. A
+// row of -1 reads a single field rather than one of a repeated row.
+type applicationForm struct {
+	form      url.Values
+	errs      map[string]string
+	rowCounts []int
+}
+
+func (f *applicationForm) rows(name string) int {
+	n := len(f.form[name])
+	f.rowCounts = append(f.rowCounts, n)
+	return n
+}
+
+func (f *applicationForm) value(name string, row int) string {
+	values := f.form[name]
+	if row < 0 {
+		row = 0
+	}
+	if row >= len(values) {
+		return ""
+	}
+	return strings.TrimSpace(values[row])
+}
+
+func (f *applicationForm) text(name string, row int, label string, maxLen int, required bool) string {
+	v := f.value(name, row)
+	switch {
+	case required && v == "":
+		f.errs[rowKey(name, row)] = label + " is required"
+	case len(v) > maxLen:
+		f.errs[rowKey(name, row)] = fmt.Sprintf("%s must be at most %d characters", label, maxLen)
+	}
+	return v
+}
+
+func (f *applicationForm) email(name string, row int, label string, required bool) string {
+	v := f.text(name, row, label, 255, required)
+	if v == "" {
+		return v
+	}
+	if addr, err := mail.ParseAddress(v); err != nil || addr.Address != v {
+		f.errs[rowKey(name, row)] = "Enter a valid email address"
+	}
+	return v
+}
+
+func (f *applicationForm) integer(name string, row int, label string, min, max int) int {
+	v := f.value(name, row)
+	if v == "" {
+		f.errs[rowKey(name, row)] = label + " is required"
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min || n > max {
+		f.errs[rowKey(name, row)] = fmt.Sprintf("%s must be a whole number from %d to %d", label, min, max)
+		return 0
+	}
+	return n
+}
+
+func (f *applicationForm) date(name string, row int, label string) string {
+	v := f.value(name, row)
+	if _, err := time.Parse("2006-01-02", v); err != nil {
+		f.errs[rowKey(name, row)] = "Enter a valid " + strings.ToLower(label)
+	}
+	return v
+}
+
+func (f *applicationForm) month(name string, row int, label string, required bool) string {
+	v := f.value(name, row)
+	if v == "" && !required {
+		return v
+	}
+	if _, err := time.Parse("2006-01", v); err != nil {
+		f.errs[rowKey(name, row)] = label + " must be a month, like 2024-06"
+	}
+	return v
+}
+
+// rowKey is the error key of name in row, or of name alone for row -1
+func rowKey(name string, row int) string {
+	if row < 0 {
+		return name
+	}
+	return name + "-" + strconv.Itoa(row)
+}
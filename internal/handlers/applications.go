@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/templates/pages"
+)
+
+// Applications lists the signed-in user's rental applications
+func (h *Handler) Applications(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	apps, err := h.Store.Applications.ListByApplicant(ctx, middleware.GetUserID(c))
+	if err != nil {
+		c.Logger().Errorf("list applications: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load applications")
+	}
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load applications")
+	}
+	return Render(c, http.StatusOK, pages.Applications(apps, properties))
+}
+
+// StartApplication is where a listing's Apply Now button leads. It resumes
+// the user's draft or open application for the property, or starts a draft.
+func (h *Handler) StartApplication(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+
+	property, err := h.Store.Properties.GetBySlug(ctx, c.Param("slug"))
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Property not found")
+	}
+	if err != nil {
+		c.Logger().Errorf("get property %s: %v", c.Param("slug"), err)
+		return c.String(http.StatusInternalServerError, "Failed to start application")
+	}
+
+	apps, err := h.Store.Applications.ListByApplicant(ctx, userID)
+	if err != nil {
+		c.Logger().Errorf("list applications: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to start application")
+	}
+	for _, a := range apps {
+		if a.PropertyID == property.ID && a.Status.Open() {
+			return c.Redirect(http.StatusSeeOther, applicationURL(&a))
+		}
+	}
+	if !property.Available {
+		return c.Redirect(http.StatusSeeOther, "/properties/"+property.Slug)
+	}
+
+	data := models.ApplicationData{Applicant: models.Applicant{Occupants: 1}}
+	if u, err := h.Store.Users.GetByClerkID(ctx, userID); err == nil {
+		data.Applicant.Email = u.Email
+	}
+	if len(property.LeaseTerms) > 0 {
+		data.Applicant.LeaseTerm = property.LeaseTerms[0]
+	}
+	app, err := h.Store.Applications.StartDraft(ctx, property.ID, userID, data)
+	if err != nil {
+		c.Logger().Errorf("start application: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to start application")
+	}
+	return c.Redirect(http.StatusSeeOther, applicationURL(app))
+}
+
+func (h *Handler) ApplicationStep(c echo.Context) error {
+	app, property, err := h.applicantApplication(c)
+	if err != nil {
+		return applicationError(c, err)
+	}
+	step := models.ApplicationStep(c.Param("step"))
+	if !step.IsValid() {
+		return c.String(http.StatusNotFound, "Page not found")
+	}
+	if app.Status != models.ApplicationStatusDraft {
+		return c.Redirect(http.StatusSeeOther, applicationURL(app))
+	}
+	addRequiredRow(step, &app.Data)
+	return Render(c, http.StatusOK, pages.ApplicationStepPage(*app, *property, step))
+}
+
+// SaveApplicationStep saves one step of a draft. The form's buttons send an
+// action: "add" and "remove-<row>" edit repeated rows, "save" keeps the
+// answers without checking them, and anything else checks the step and
+// moves on to the next one.
+func (h *Handler) SaveApplicationStep(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	app, property, err := h.applicantApplication(c)
+	if err != nil {
+		return applicationError(c, err)
+	}
+	step := models.ApplicationStep(c.Param("step"))
+	if !step.IsValid() {
+		return c.String(http.StatusNotFound, "Page not found")
+	}
+	if app.Status != models.ApplicationStatusDraft {
+		return applicationError(c, repository.ErrStatusChanged)
+	}
+	form, err := c.FormParams()
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid form")
+	}
+
+	action := c.FormValue("action")
+	errs := parseApplicationStep(step, form, &app.Data, property, h.now())
+	edited := editApplicationRows(step, action, &app.Data)
+	done := len(errs) == 0 && !edited && action != "save"
+	setStepCompleted(&app.Data, step, done)
+
+	app, err = h.Store.Applications.SaveDraft(ctx, app.ID, app.Data)
+	if err != nil {
+		return applicationError(c, err)
+	}
+
+	switch {
+	case edited:
+		return Render(c, http.StatusOK, pages.ApplicationStepForm(*app, *property, step, nil, ""))
+	case action == "save":
+		return Render(c, http.StatusOK, pages.ApplicationStepForm(*app, *property, step, nil, "Draft saved"))
+	case !done:
+		return Render(c, http.StatusUnprocessableEntity, pages.ApplicationStepForm(*app, *property, step, errs, ""))
+	}
+	c.Response().Header().Set("HX-Redirect", applicationStepURL(app, nextApplicationStep(step)))
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) ApplicationReview(c echo.Context) error {
+	app, property, err := h.applicantApplication(c)
+	if err != nil {
+		return applicationError(c, err)
+	}
+	if app.Status != models.ApplicationStatusDraft {
+		return c.Redirect(http.StatusSeeOther, applicationURL(app))
+	}
+	fee, err := h.applicationFeePayment(c.Request().Context(), app, property)
+	if err != nil {
+		return applicationError(c, err)
+	}
+	return Render(c, http.StatusOK, pages.ApplicationReview(*app, *property, fee, ""))
+}
+
+// SubmitApplication finalizes a finished draft. Applications with a fee go
+// to the payment page first unless it's already paid.
+func (h *Handler) SubmitApplication(c echo.Context) error {
+	app, property, err := h.applicantApplication(c)
+	if err != nil {
+		return applicationError(c, err)
+	}
+	if app.Status != models.ApplicationStatusDraft {
+		return applicationError(c, repository.ErrStatusChanged)
+	}
+	fee, err := h.applicationFeePayment(c.Request().Context(), app, property)
+	if err != nil {
+		return applicationError(c, err)
+	}
+	switch {
+	case !app.Data.Complete():
+		return Render(c, http.StatusUnprocessableEntity, pages.ApplicationReview(*app, *property, fee, "Finish every step before submitting."))
+	case !property.Available:
+		return Render(c, http.StatusUnprocessableEntity, pages.ApplicationReview(*app, *property, fee, "This property is no longer available."))
+	}
+	if applicationFeeCents(property) > 0 && (fee == nil || !fee.Paid()) {
+		c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/applications/%d/pay", app.ID))
+		return c.NoContent(http.StatusNoContent)
+	}
+	if fee != nil && !fee.Paid() {
+		fee = nil
+	}
+
+	app, err = h.submitApplication(c, app, property, fee)
+	if err != nil {
+		return applicationError(c, err)
+	}
+	c.Response().Header().Set("HX-Redirect", applicationURL(app))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ApplicationDetail shows a submitted application and its progress. Drafts
+// go back to the form.
+func (h *Handler) ApplicationDetail(c echo.Context) error {
+	app, property, err := h.applicantApplication(c)
+	if err != nil {
+		return applicationError(c, err)
+	}
+	if app.Status == models.ApplicationStatusDraft {
+		return c.Redirect(http.StatusSeeOther, applicationURL(app))
+	}
+	events, err := h.Store.Applications.Timeline(c.Request().Context(), app.ID)
+	if err != nil {
+		c.Logger().Errorf("application %d timeline: %v", app.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load application")
+	}
+	fee, err := h.applicationFeePayment(c.Request().Context(), app, property)
+	if err != nil {
+		return applicationError(c, err)
+	}
+	return Render(c, http.StatusOK, pages.ApplicationView(*app, *property, events, paidFee(fee)))
+}
+
+func (h *Handler) WithdrawApplication(c echo.Context) error {
+	app, property, err := h.applicantApplication(c)
+	if err != nil {
+		return applicationError(c, err)
+	}
+	if !app.Status.CanBecome(models.ApplicationStatusWithdrawn) {
+		return applicationError(c, repository.ErrStatusChanged)
+	}
+
+	app, err = h.Store.Applications.SetStatus(c.Request().Context(), app.ID, app.Status, models.ApplicationStatusWithdrawn, middleware.GetUserID(c), "")
+	if err != nil {
+		return applicationError(c, err)
+	}
+	h.sendApplicationStatus(c, app, property, "")
+
+	c.Response().Header().Set("HX-Redirect", applicationURL(app))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// applicantApplication loads the application named in the URL and its
+// property. Other users' applications are reported as not found.
+func (h *Handler) applicantApplication(c echo.Context) (*models.Application, *models.Property, error) {
+	ctx := c.Request().Context()
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, nil, repository.ErrNotFound
+	}
+	app, err := h.Store.Applications.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if app.ApplicantID != middleware.GetUserID(c) {
+		return nil, nil, repository.ErrNotFound
+	}
+	property, err := h.Store.Properties.GetByID(ctx, app.PropertyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return app, property, nil
+}
+
+func applicationError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return c.String(http.StatusNotFound, "Application not found")
+	case errors.Is(err, repository.ErrStatusChanged):
+		return c.String(http.StatusConflict, "This application's status has changed. Please reload the page.")
+	default:
+		c.Logger().Errorf("application %s: %v", c.Param("id"), err)
+		return c.String(http.StatusInternalServerError, "Failed to load application")
+	}
+}
+
+// sendApplicationStatus tells the applicant their application's status
+// changed, quoting note if staff left one. Failures are only logged.
+func (h *Handler) sendApplicationStatus(c echo.Context, app *models.Application, property *models.Property, note string) {
+	var message string
+	switch app.Status {
+	case models.ApplicationStatusSubmitted:
+		message = "We've received your application and will be in touch once we've reviewed it."
+	case models.ApplicationStatusUnderReview:
+		message = "We're reviewing your application now. We may contact your references and employers."
+	case models.ApplicationStatusApproved:
+		message = "Good news: your application has been approved! We'll contact you shortly about next steps."
+	case models.ApplicationStatusDenied:
+		message = "After careful review, we're unable to approve your application at this time."
+	case models.ApplicationStatusWithdrawn:
+		message = "Your application has been withdrawn. You're welcome to apply again while the property is available."
+	}
+	if note != "" {
+		message += "\n\n" + note
+	}
+
+	body := fmt.Sprintf(`Hi %s,
+
+%s
+
+Property: %s
+Status: %s
+
+View your application: %s
+`, app.Data.Applicant.FirstName, message, property.Title, app.Status.Label(),
+		h.absoluteURL(applicationURL(app)))
+
+	err := h.Mailer.Send(c.Request().Context(), mailer.Message{
+		To:      app.Data.Applicant.Email,
+		Subject: "Your application for " + property.Title + ": " + app.Status.Label(),
+		Body:    body,
+	})
+	if err != nil {
+		c.Logger().Warnf("email applicant of application %d: %v", app.ID, err)
+	}
+}
+
+// setStepCompleted marks step as finished or not in data
+func setStepCompleted(data *models.ApplicationData, step models.ApplicationStep, done bool) {
+	data.Completed = slices.DeleteFunc(data.Completed, func(s models.ApplicationStep) bool { return s == step })
+	if done {
+		data.Completed = append(data.Completed, step)
+	}
+}
+
+// nextApplicationStep is the step after step, or "" after the last one
+func nextApplicationStep(step models.ApplicationStep) models.ApplicationStep {
+	i := slices.Index(models.ApplicationSteps, step)
+	if i < 0 || i+1 == len(models.ApplicationSteps) {
+		return ""
+	}
+	return models.ApplicationSteps[i+1]
+}
+
+// applicationURL is where the applicant picks up app: its first unfinished
+// step or the review page for drafts, and its status page otherwise
+func applicationURL(app *models.Application) string {
+	if app.Status != models.ApplicationStatusDraft {
+		return fmt.Sprintf("/applications/%d", app.ID)
+	}
+	for _, s := range models.ApplicationSteps {
+		if !slices.Contains(app.Data.Completed, s) {
+			return applicationStepURL(app, s)
+		}
+	}
+	return applicationStepURL(app, "")
+}
+
+// applicationStepURL is the form page of step, or the review page for ""
+func applicationStepURL(app *models.Application, step models.ApplicationStep) string {
+	if step == "" {
+		return fmt.Sprintf("/applications/%d/review", app.ID)
+	}
+	return fmt.Sprintf("/applications/%d/%s", app.ID, step)
+}
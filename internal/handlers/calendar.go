@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/ical"
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/templates/pages"
+)
+
+const (
+	agentCalendarPurpose    = "calendar-agent"
+	propertyCalendarPurpose = "calendar-property"
+	// calendarLookback is how far back feeds include past showings
+	calendarLookback = 30 * 24 * time.Hour
+	calendarProdID   = "-//Russ Rentals//Showings//EN"
+)
+
+// AgentCalendar is an iCal feed of one agent's showings. Calendar apps can't
+// sign in, so the feed is authorized by the signed token in its URL.
+func (h *Handler) AgentCalendar(c echo.Context) error {
+	agentID, _, err := h.verifyCalendarToken(c, agentCalendarPurpose)
+	if errors.Is(err, errCalendarRevoked) {
+		return c.String(http.StatusNotFound, "Calendar not found")
+	}
+	if err != nil {
+		c.Logger().Errorf("verify calendar link: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load calendar")
+	}
+	return h.renderCalendar(c, "Showings", 0, agentID)
+}
+
+// PropertyCalendar is an iCal feed of one property's showings
+func (h *Handler) PropertyCalendar(c echo.Context) error {
+	_, subject, err := h.verifyCalendarToken(c, propertyCalendarPurpose)
+	if errors.Is(err, errCalendarRevoked) {
+		return c.String(http.StatusNotFound, "Calendar not found")
+	}
+	if err != nil {
+		c.Logger().Errorf("verify calendar link: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load calendar")
+	}
+	id, err := strconv.ParseInt(subject, 10, 64)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Calendar not found")
+	}
+
+	property, err := h.Store.Properties.GetByID(c.Request().Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Calendar not found")
+	}
+	if err != nil {
+		c.Logger().Errorf("get property %d: %v", id, err)
+		return c.String(http.StatusInternalServerError, "Failed to load calendar")
+	}
+	return h.renderCalendar(c, "Showings: "+property.Title, property.ID, "")
+}
+
+// AdminResetCalendarFeeds gives the current user a new calendar key, so the
+// feed links they were given before stop working, and shows the new links
+func (h *Handler) AdminResetCalendarFeeds(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return c.String(http.StatusForbidden, "Sign in to reset your calendar links")
+	}
+	if err := h.Store.Users.SetCalendarKey(ctx, userID, newCalendarKey()); err != nil {
+		c.Logger().Errorf("reset calendar key: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to reset calendar links")
+	}
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to reset calendar links")
+	}
+	feeds, err := h.calendarFeeds(c, userID, properties)
+	if err != nil {
+		c.Logger().Errorf("list calendar feeds: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to reset calendar links")
+	}
+	return Render(c, http.StatusOK, pages.CalendarFeeds(feeds))
+}
+
+// renderCalendar writes the feed of showings matching propertyID and
+// agentID. Cancelled showings stay in the feed so subscribers drop them.
+func (h *Handler) renderCalendar(c echo.Context, name string, propertyID int64, agentID string) error {
+	ctx := c.Request().Context()
+
+	showings, err := h.Store.Showings.Feed(ctx, propertyID, agentID, time.Now().Add(-calendarLookback))
+	if err != nil {
+		c.Logger().Errorf("list showing feed: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load calendar")
+	}
+	properties, err := h.Store.Properties.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load calendar")
+	}
+	byID := make(map[int64]*models.Property, len(properties))
+	for i := range properties {
+		byID[properties[i].ID] = &properties[i]
+	}
+
+	cal := ical.Calendar{ProdID: calendarProdID, Name: name}
+	for i := range showings {
+		if p, ok := byID[showings[i].PropertyID]; ok {
+			cal.Events = append(cal.Events, h.showingEvent(c, &showings[i], p, true))
+		}
+	}
+	return c.Blob(http.StatusOK, ical.ContentType, cal.Encode())
+}
+
+// calendarFeeds lists the feeds offered to userID on the showings page:
+// their own showings, then each property's. The links are signed with the
+// user's calendar key, so resetting it revokes them all.
+func (h *Handler) calendarFeeds(c echo.Context, userID string, properties []models.Property) ([]pages.CalendarFeed, error) {
+	if userID == "" {
+		return nil, nil
+	}
+	key, err := h.Store.Users.EnsureCalendarKey(c.Request().Context(), userID, newCalendarKey())
+	if err != nil {
+		return nil, err
+	}
+	feed := func(name, path, purpose, subject string) pages.CalendarFeed {
+		token := h.Tokens.Sign(purpose, userID+":"+key+":"+subject, 0)
+		return pages.CalendarFeed{
+			Name: name,
+			URL:  h.absoluteURL(path + "?token=" + url.QueryEscape(token)),
+		}
+	}
+
+	feeds := []pages.CalendarFeed{feed("My showings", "/calendar/agent.ics", agentCalendarPurpose, "")}
+	for _, p := range properties {
+		feeds = append(feeds, feed(p.Title, "/calendar/property.ics", propertyCalendarPurpose, strconv.FormatInt(p.ID, 10)))
+	}
+	return feeds, nil
+}
+
+// errCalendarRevoked is returned for feed links that are invalid, were
+// signed with a calendar key since reset, or belong to a user who is no
+// longer staff
+var errCalendarRevoked = errors.New("calendar link revoked")
+
+// verifyCalendarToken checks the token of a feed link for purpose, and
+// returns the user it was made for and the rest of its subject. The links
+// never expire, so the user must still be staff each time.
+func (h *Handler) verifyCalendarToken(c echo.Context, purpose string) (string, string, error) {
+	subject, err := h.Tokens.Verify(c.QueryParam("token"), purpose)
+	if err != nil {
+		return "", "", errCalendarRevoked
+	}
+	parts := strings.SplitN(subject, ":", 3)
+	if len(parts) != 3 || parts[0] == "" {
+		return "", "", errCalendarRevoked
+	}
+	userID, key, rest := parts[0], parts[1], parts[2]
+	ctx := c.Request().Context()
+	if err := h.checkCalendarKey(ctx, userID, key); err != nil {
+		return "", "", err
+	}
+	if err := h.checkCalendarStaff(ctx, userID); err != nil {
+		return "", "", err
+	}
+	return userID, rest, nil
+}
+
+// checkCalendarStaff checks userID is still staff or an admin. Feeds have
+// no session to read Clerk's claims from, so the role comes from AdminIDs
+// and the local users table.
+func (h *Handler) checkCalendarStaff(ctx context.Context, userID string) error {
+	if slices.Contains(h.AdminIDs, userID) {
+		return nil
+	}
+	user, err := h.Store.Users.GetByClerkID(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return errCalendarRevoked
+	}
+	if err != nil {
+		return err
+	}
+	if user.Role != models.RoleStaff && user.Role != models.RoleAdmin {
+		return errCalendarRevoked
+	}
+	return nil
+}
+
+// checkCalendarKey checks key is userID's current calendar key
+func (h *Handler) checkCalendarKey(ctx context.Context, userID, key string) error {
+	current, err := h.Store.Users.CalendarKey(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return errCalendarRevoked
+	}
+	if err != nil {
+		return err
+	}
+	if current != key {
+		return errCalendarRevoked
+	}
+	return nil
+}
+
+// newCalendarKey returns a random calendar key
+func newCalendarKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// showingInvite is an .ics attachment for a showing email. Staff's invites
+// add who is visiting and their contact details, which feeds leave out:
+// anyone holding a feed's link can read it.
+func (h *Handler) showingInvite(c echo.Context, showing *models.Showing, property *models.Property, forStaff bool) mailer.Attachment {
+	e := h.showingEvent(c, showing, property, forStaff)
+	if forStaff {
+		e.Summary += " with " + showing.Name
+		e.Description += fmt.Sprintf("\nVisitor: %s\nEmail: %s\nPhone: %s", showing.Name, showing.Email, showing.Phone)
+	}
+	cal := ical.Calendar{ProdID: calendarProdID, Events: []ical.Event{e}}
+	return mailer.Attachment{Filename: "showing.ics", ContentType: ical.ContentType, Data: cal.Encode()}
+}
+
+// showingEvent describes a showing for a calendar. Staff see its status;
+// visitors see where to go. Neither names the visitor.
+func (h *Handler) showingEvent(c echo.Context, showing *models.Showing, property *models.Property, forStaff bool) ical.Event {
+	e := ical.Event{
+		UID:      fmt.Sprintf("showing-%d@%s", showing.ID, h.calendarHost()),
+		Sequence: showing.Sequence,
+		Status:   showingCalendarStatus(showing.Status),
+		Start:    showing.StartsAt,
+		End:      showing.EndsAt,
+		Stamp:    showing.UpdatedAt,
+		Location: fmt.Sprintf("%s, %s, %s %s", property.Address, property.City, property.State, property.ZipCode),
+	}
+	if forStaff {
+		e.Summary = "Showing: " + property.Title
+		e.Description = "Status: " + showing.Status.Label()
+		e.URL = h.absoluteURL("/admin/showings")
+	} else {
+		e.Summary = "Showing of " + property.Title
+		e.Description = "Your Russ Rentals showing. Use the links in your email to confirm or cancel."
+		e.URL = h.absoluteURL("/properties/" + property.Slug)
+	}
+	return e
+}
+
+// calendarHost qualifies event UIDs so they stay unique across calendars
+func (h *Handler) calendarHost() string {
+	u, err := url.Parse(h.absoluteURL("/"))
+	if err != nil || u.Hostname() == "" {
+		return "russrentals.com"
+	}
+	return u.Hostname()
+}
+
+func showingCalendarStatus(s models.ShowingStatus) string {
+	switch s {
+	case models.ShowingStatusConfirmed:
+		return ical.StatusConfirmed
+	case models.ShowingStatusCancelled:
+		return ical.StatusCancelled
+	default:
+		return ical.StatusTentative
+	}
+}
@@ -14,7 +14,11 @@ import (
 var carouselIndex = 0
 
 func (h *Handler) CarouselNext(c echo.Context) error {
-	featured := h.getFeaturedProperties()
+	featured, err := h.getFeaturedProperties(c.Request().Context())
+	if err != nil {
+		c.Logger().Errorf("featured properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load featured properties")
+	}
 	if len(featured) == 0 {
 		return c.String(http.StatusNotFound, "No featured properties")
 	}
@@ -31,7 +35,11 @@ func (h *Handler) CarouselNext(c echo.Context) error {
 }
 
 func (h *Handler) CarouselPrev(c echo.Context) error {
-	featured := h.getFeaturedProperties()
+	featured, err := h.getFeaturedProperties(c.Request().Context())
+	if err != nil {
+		c.Logger().Errorf("featured properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load featured properties")
+	}
 	if len(featured) == 0 {
 		return c.String(http.StatusNotFound, "No featured properties")
 	}
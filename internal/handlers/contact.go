@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"net/mail"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"russ-rentals/internal/middleware"
 	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/internal/schedule"
 	"russ-rentals/templates/components"
 	"russ-rentals/templates/pages"
 )
@@ -15,49 +21,153 @@ func (h *Handler) Contact(c echo.Context) error {
 	isAuth := middleware.IsAuthenticated(c)
 	propertySlug := c.QueryParam("property")
 
-	properties := GetSampleProperties()
-	var selectedProperty *models.Property
+	properties, err := h.Store.Properties.List(c.Request().Context())
+	if err != nil {
+		c.Logger().Errorf("list properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load properties")
+	}
 
+	var selectedProperty *models.Property
 	if propertySlug != "" {
-		for _, p := range properties {
-			if p.Slug == propertySlug {
-				selectedProperty = &p
+		for i := range properties {
+			if properties[i].Slug == propertySlug {
+				selectedProperty = &properties[i]
 				break
 			}
 		}
 	}
 
+	// "Send Another Message" only needs a fresh form, not the whole page
+	if c.QueryParam("reset") == "true" && c.Request().Header.Get("HX-Request") == "true" {
+		return Render(c, http.StatusOK, components.ContactForm(properties, selectedProperty))
+	}
+
 	return Render(c, http.StatusOK, pages.Contact(properties, selectedProperty, isAuth))
 }
 
 func (h *Handler) SubmitContact(c echo.Context) error {
+	ctx := c.Request().Context()
+
 	// Parse form data
-	name := c.FormValue("name")
-	email := c.FormValue("email")
-	phone := c.FormValue("phone")
+	name := strings.TrimSpace(c.FormValue("name"))
+	email := strings.TrimSpace(c.FormValue("email"))
+	phone := strings.TrimSpace(c.FormValue("phone"))
 	propertySlug := c.FormValue("property")
 	inquiryType := c.FormValue("inquiryType")
 	preferredDate := c.FormValue("preferredDate")
 	preferredTime := c.FormValue("preferredTime")
-	message := c.FormValue("message")
+	message := strings.TrimSpace(c.FormValue("message"))
 
 	// Validate required fields
 	if name == "" || email == "" || phone == "" || message == "" {
-		return Render(c, http.StatusBadRequest, components.ContactFormError("Please fill in all required fields"))
+		return contactFormError(c, http.StatusBadRequest, "Please fill in all required fields")
+	}
+	if len(name) > 255 || len(email) > 255 || len(phone) > 50 || len(preferredTime) > 50 {
+		return contactFormError(c, http.StatusBadRequest, "One or more fields are too long")
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return contactFormError(c, http.StatusBadRequest, "Please enter a valid email address")
 	}
 
-	// In a real app, save to database and send email here
-	_ = models.ContactSubmission{
+	submission := models.ContactSubmission{
 		Name:          name,
 		Email:         email,
 		Phone:         phone,
 		Message:       message,
-		InquiryType:   models.InquiryType(inquiryType),
+		InquiryType:   models.InquiryTypeGeneral,
 		PreferredTime: preferredTime,
 	}
-	_ = propertySlug
-	_ = preferredDate
+
+	if inquiryType != "" {
+		submission.InquiryType = models.InquiryType(inquiryType)
+		if !submission.InquiryType.IsValid() {
+			return contactFormError(c, http.StatusBadRequest, "Please choose a valid inquiry type")
+		}
+	}
+
+	var property *models.Property
+	if propertySlug != "" {
+		var err error
+		property, err = h.Store.Properties.GetBySlug(ctx, propertySlug)
+		if errors.Is(err, repository.ErrNotFound) {
+			return contactFormError(c, http.StatusBadRequest, "The selected property is no longer listed")
+		}
+		if err != nil {
+			c.Logger().Errorf("resolve property %s: %v", propertySlug, err)
+			return contactFormError(c, http.StatusInternalServerError, "We couldn't send your message. Please try again later.")
+		}
+		submission.PropertyID = &property.ID
+	}
+
+	if preferredDate != "" {
+		date, err := time.Parse("2006-01-02", preferredDate)
+		if err != nil {
+			return contactFormError(c, http.StatusBadRequest, "Please enter a valid preferred date")
+		}
+		now := h.now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		if date.Before(today) {
+			return contactFormError(c, http.StatusBadRequest, "Preferred date can't be in the past")
+		}
+		submission.PreferredDate = &date
+	}
+
+	// A chosen showing slot replaces the free-form preferred date and time
+	var slot *schedule.Slot
+	if slotValue := c.FormValue("slot"); slotValue != "" {
+		if submission.InquiryType != models.InquiryTypeViewing || property == nil {
+			return contactFormError(c, http.StatusBadRequest, "Choose a property and Schedule Viewing to book a showing time")
+		}
+		start, err := time.Parse(time.RFC3339, slotValue)
+		if err != nil {
+			return contactFormError(c, http.StatusBadRequest, "Please choose a valid showing time")
+		}
+		slots, err := h.openSlots(ctx, property.ID)
+		if err != nil {
+			c.Logger().Errorf("open slots for property %d: %v", property.ID, err)
+			return contactFormError(c, http.StatusInternalServerError, "We couldn't send your message. Please try again later.")
+		}
+		s, ok := schedule.Find(slots, start)
+		if !ok {
+			return contactFormError(c, http.StatusConflict, "That showing time is no longer available. Please choose another.")
+		}
+		slot = &s
+
+		local := slot.Start.In(h.Location)
+		date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+		submission.PreferredDate = &date
+		submission.PreferredTime = local.Format("3:04 PM")
+	}
+
+	if err := h.Store.Contacts.Create(ctx, &submission); err != nil {
+		c.Logger().Errorf("save contact submission: %v", err)
+		return contactFormError(c, http.StatusInternalServerError, "We couldn't send your message. Please try again later.")
+	}
+
+	if slot != nil {
+		showing, err := h.bookShowing(c, &submission, property, *slot)
+		if err != nil {
+			// Without the showing the inquiry would promise a time nobody holds
+			if delErr := h.Store.Contacts.Delete(ctx, submission.ID); delErr != nil {
+				c.Logger().Errorf("delete contact submission %d: %v", submission.ID, delErr)
+			}
+			if errors.Is(err, repository.ErrSlotTaken) {
+				return contactFormError(c, http.StatusConflict, "That showing time was just booked. Please choose another.")
+			}
+			c.Logger().Errorf("book showing: %v", err)
+			return contactFormError(c, http.StatusInternalServerError, "We couldn't book your showing. Please try again later.")
+		}
+		return Render(c, http.StatusOK, components.ShowingRequested(*showing, *property))
+	}
 
 	// Return success message for HTMX swap
-	return Render(c, http.StatusOK, components.ContactFormSuccess(inquiryType))
+	return Render(c, http.StatusOK, components.ContactFormSuccess(string(submission.InquiryType)))
+}
+
+// contactFormError renders an error into the form's error slot, leaving the
+// user's input in place
+func contactFormError(c echo.Context, statusCode int, message string) error {
+	c.Response().Header().Set("HX-Retarget", "#contact-form-error")
+	c.Response().Header().Set("HX-Reswap", "innerHTML")
+	return Render(c, statusCode, components.ContactFormError(message))
 }
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/repository"
+)
+
+// RunJob runs the background job named in the URL straight away. Hosts
+// that can't keep the scheduler running, like Vercel, call it from their
+// cron instead.
+func (h *Handler) RunJob(c echo.Context) error {
+	name := c.Param("name")
+	if h.Jobs == nil {
+		return c.String(http.StatusNotFound, "Jobs are not run on request here")
+	}
+	if _, ok := h.Jobs.Job(name); !ok {
+		return c.String(http.StatusNotFound, "Unknown job")
+	}
+
+	run, err := h.Jobs.RunNow(c.Request().Context(), name)
+	if errors.Is(err, repository.ErrLocked) {
+		return c.String(http.StatusConflict, "Job is already running")
+	}
+	if err != nil {
+		c.Logger().Errorf("run job %s: %v", name, err)
+		return c.String(http.StatusInternalServerError, "Job failed")
+	}
+	return c.String(http.StatusOK, run.Summary)
+}
@@ -1,15 +1,69 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 
 	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
 	"russ-rentals/templates/pages"
 )
 
 func (h *Handler) Dashboard(c echo.Context) error {
-	userID := middleware.GetUserID(c)
-	return Render(c, http.StatusOK, pages.Dashboard(userID))
+	ctx := c.Request().Context()
+	lease, err := h.tenantLease(ctx, middleware.GetUserID(c))
+	if err != nil {
+		c.Logger().Errorf("Failed to load lease: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load dashboard")
+	}
+
+	var property *models.Property
+	var ledger models.Ledger
+	var maintenance []models.MaintenanceRequest
+	if lease != nil {
+		if property, err = h.Store.Properties.GetByID(ctx, lease.PropertyID); err != nil {
+			c.Logger().Errorf("Failed to load property %d: %v", lease.PropertyID, err)
+			return c.String(http.StatusInternalServerError, "Failed to load dashboard")
+		}
+		if ledger, err = h.leaseLedger(ctx, lease); err != nil {
+			c.Logger().Errorf("Failed to load ledger for lease %d: %v", lease.ID, err)
+			return c.String(http.StatusInternalServerError, "Failed to load dashboard")
+		}
+		requests, err := h.Store.Maintenance.ListByLease(ctx, lease.ID)
+		if err != nil {
+			c.Logger().Errorf("Failed to list maintenance requests for lease %d: %v", lease.ID, err)
+			return c.String(http.StatusInternalServerError, "Failed to load dashboard")
+		}
+		for _, req := range requests {
+			if req.Status != models.MaintenanceStatusResolved {
+				maintenance = append(maintenance, req)
+			}
+		}
+	}
+	return Render(c, http.StatusOK, pages.Dashboard(lease, property, ledger, maintenance, models.Date(h.now())))
+}
+
+// tenantLease returns the lease a tenant's dashboard shows: their active
+// lease, else one awaiting signatures, else their latest past lease. It
+// returns nil if staff haven't shared any lease with them yet.
+func (h *Handler) tenantLease(ctx context.Context, userID string) (*models.Lease, error) {
+	leases, err := h.Store.Leases.ListByTenant(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var shown *models.Lease
+	for i := range leases {
+		l := &leases[i]
+		switch {
+		case !l.Status.VisibleToTenant():
+			continue
+		case l.Status == models.LeaseStatusActive:
+			return l, nil
+		case shown == nil, l.Status == models.LeaseStatusPendingSignature && shown.Status != models.LeaseStatusPendingSignature:
+			shown = l
+		}
+	}
+	return shown, nil
 }
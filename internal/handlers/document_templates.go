@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/billing"
+	"russ-rentals/internal/docgen"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/pdf"
+	"russ-rentals/templates/pages"
+)
+
+// AdminDocumentTemplateForm re-renders the generate form for the template
+// picked in it, showing the details that template asks for
+func (h *Handler) AdminDocumentTemplateForm(c echo.Context) error {
+	lease, err := h.adminLease(c)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	t, ok := docgen.Lookup(c.QueryParam("template"))
+	if !ok {
+		t = docgen.Templates[0]
+	}
+	return Render(c, http.StatusOK, pages.AdminGenerateDocumentForm(*lease, t, defaultNotice(*lease), nil))
+}
+
+// AdminGenerateDocument fills in a lease or notice template for a lease,
+// renders it to a PDF and adds it to the lease's vault
+func (h *Handler) AdminGenerateDocument(c echo.Context) error {
+	ctx := c.Request().Context()
+	lease, err := h.adminLease(c)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+
+	t, notice, errs := parseGenerateForm(c, *lease, models.Date(h.now()))
+	if len(errs) > 0 {
+		return Render(c, http.StatusUnprocessableEntity, pages.AdminGenerateDocumentForm(*lease, t, notice, errs))
+	}
+
+	property, err := h.Store.Properties.GetByID(ctx, lease.PropertyID)
+	if err != nil {
+		c.Logger().Errorf("get property %d: %v", lease.PropertyID, err)
+		return c.String(http.StatusInternalServerError, "Failed to generate document")
+	}
+	policies, err := h.Store.LateFees.List(ctx)
+	if err != nil {
+		c.Logger().Errorf("list late fee policies: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to generate document")
+	}
+	var policy *models.LateFeePolicy
+	if p, ok := billing.LateFeePolicyFor(*lease, policies); ok {
+		policy = &p
+	}
+
+	data := docgen.NewData(t, *lease, *property, policy, notice, h.now())
+	file, err := docgen.Render(data)
+	if err != nil {
+		c.Logger().Errorf("render %s for lease %d: %v", t.Name, lease.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to generate document")
+	}
+	source, err := json.Marshal(data)
+	if err != nil {
+		c.Logger().Errorf("encode %s data for lease %d: %v", t.Name, lease.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to generate document")
+	}
+
+	doc := models.Document{
+		Kind:         t.Kind,
+		Title:        t.Title,
+		Filename:     data.Filename(),
+		ContentType:  pdf.ContentType,
+		Template:     t.Name,
+		TemplateData: source,
+	}
+	if err := h.saveDocument(c, lease, &doc, file); err != nil {
+		return saveDocumentError(c, err)
+	}
+
+	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/admin/leases/%d/documents", lease.ID))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// defaultNotice suggests notice details for lease: renewing it for another
+// year at the same rent, with a month to respond
+func defaultNotice(lease models.Lease) docgen.Notice {
+	return docgen.Notice{
+		NewRentCents: lease.MonthlyRentCents,
+		NewEndDate:   lease.EndDate.AddDate(1, 0, 0),
+		RespondBy:    lease.EndDate.AddDate(0, -1, 0),
+	}
+}
+
+// parseGenerateForm reads the generate form: which template to fill in and
+// the notice details it asks for
+func parseGenerateForm(c echo.Context, lease models.Lease, today time.Time) (docgen.Template, docgen.Notice, map[string]string) {
+	errs := make(map[string]string)
+	f := propertyForm{c: c, errs: errs}
+
+	t, ok := docgen.Lookup(c.FormValue("template"))
+	if !ok {
+		errs["template"] = "Choose a document to generate"
+		return docgen.Templates[0], defaultNotice(lease), errs
+	}
+
+	var notice docgen.Notice
+	if t.Uses(docgen.FieldNewRent) {
+		notice.NewRentCents = f.cents(docgen.FieldNewRent, "Monthly rent", true)
+	}
+	if t.Uses(docgen.FieldEffectiveDate) {
+		notice.EffectiveDate = f.date(docgen.FieldEffectiveDate, "Date")
+		if _, failed := errs[docgen.FieldEffectiveDate]; !failed && !notice.EffectiveDate.After(today) {
+			errs[docgen.FieldEffectiveDate] = "Choose a date after today"
+		}
+	}
+	if t.Uses(docgen.FieldNewEndDate) {
+		notice.NewEndDate = f.date(docgen.FieldNewEndDate, "End date")
+		if _, failed := errs[docgen.FieldNewEndDate]; !failed && !notice.NewEndDate.After(lease.EndDate) {
+			errs[docgen.FieldNewEndDate] = "The renewal must end after " + lease.EndDate.Format("Jan 2, 2006")
+		}
+	}
+	if t.Uses(docgen.FieldRespondBy) {
+		notice.RespondBy = f.date(docgen.FieldRespondBy, "Response date")
+		if _, failed := errs[docgen.FieldRespondBy]; !failed && (notice.RespondBy.Before(today) || notice.RespondBy.After(lease.EndDate)) {
+			errs[docgen.FieldRespondBy] = "Choose a date between today and the end of the lease"
+		}
+	}
+	if t.Uses(docgen.FieldMessage) {
+		notice.Message = f.text(docgen.FieldMessage, "Note", maxNoteLength, false)
+	}
+	if t.Name == docgen.TemplateRentIncrease && notice.NewRentCents == lease.MonthlyRentCents {
+		errs[docgen.FieldNewRent] = "Enter a rent different from the current " + models.FormatCents(lease.MonthlyRentCents)
+	}
+	return t, notice, errs
+}
@@ -0,0 +1,406 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/docgen"
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/internal/storage"
+	"russ-rentals/internal/token"
+	"russ-rentals/templates/pages"
+)
+
+const (
+	documentPurpose = "document"
+	// documentLinkTTL is how long a signed document link works. Links are
+	// issued on each click, so they only need to outlive the redirect.
+	documentLinkTTL = 5 * time.Minute
+	// maxDocumentUpload is the largest document accepted, in bytes
+	maxDocumentUpload = 20 << 20
+)
+
+// documentTypes are the file types the vault accepts, by sniffed content
+// type
+var documentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// AdminLeaseDocuments shows a lease's documents with the upload form and
+// the log of who opened them
+func (h *Handler) AdminLeaseDocuments(c echo.Context) error {
+	lease, err := h.adminLease(c)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	doc := models.Document{Kind: models.DocumentKindLease}
+	return h.renderLeaseDocuments(c, http.StatusOK, lease, doc, nil)
+}
+
+// AdminUploadDocument adds a file to a lease's vault and emails the
+// tenants that it's there
+func (h *Handler) AdminUploadDocument(c echo.Context) error {
+	lease, err := h.adminLease(c)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+
+	// Leave room for the other multipart fields
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxDocumentUpload+1<<20)
+
+	doc, data, errs := parseDocumentForm(c)
+	doc.LeaseID = lease.ID
+	if len(errs) > 0 {
+		return Render(c, http.StatusUnprocessableEntity, pages.AdminDocumentForm(*lease, doc, errs))
+	}
+
+	if err := h.saveDocument(c, lease, &doc, data); err != nil {
+		return saveDocumentError(c, err)
+	}
+
+	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/admin/leases/%d/documents", lease.ID))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AdminOpenDocument sends staff to a signed link for a document
+func (h *Handler) AdminOpenDocument(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Document not found")
+	}
+	doc, err := h.Store.Documents.Get(c.Request().Context(), id)
+	if err != nil {
+		return documentError(c, err)
+	}
+	return h.redirectToDocument(c, doc)
+}
+
+// TenantDocuments lists the documents on every lease the tenant can see
+func (h *Handler) TenantDocuments(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+	leases, err := h.Store.Leases.ListByTenant(ctx, userID)
+	if err != nil {
+		c.Logger().Errorf("Failed to load leases: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load documents")
+	}
+
+	var vaults []pages.LeaseDocuments
+	for _, lease := range leases {
+		if !lease.Status.VisibleToTenant() {
+			continue
+		}
+		docs, err := h.Store.Documents.ListByLease(ctx, lease.ID)
+		if err != nil {
+			c.Logger().Errorf("Failed to list documents for lease %d: %v", lease.ID, err)
+			return c.String(http.StatusInternalServerError, "Failed to load documents")
+		}
+		property, err := h.Store.Properties.GetByID(ctx, lease.PropertyID)
+		if err != nil {
+			c.Logger().Errorf("Failed to load property %d: %v", lease.PropertyID, err)
+			return c.String(http.StatusInternalServerError, "Failed to load documents")
+		}
+		requests, err := h.Store.Signatures.ListByLease(ctx, lease.ID)
+		if err != nil {
+			c.Logger().Errorf("Failed to list signature requests for lease %d: %v", lease.ID, err)
+			return c.String(http.StatusInternalServerError, "Failed to load documents")
+		}
+		v := pages.LeaseDocuments{Lease: lease, Property: *property, Documents: docs}
+		for _, r := range requests {
+			if r.UserID == userID && r.Role == docgen.RoleTenant && r.Status == models.SignatureStatusPending {
+				v.ToSign = append(v.ToSign, r)
+			}
+		}
+		vaults = append(vaults, v)
+	}
+	return Render(c, http.StatusOK, pages.TenantDocuments(vaults))
+}
+
+// TenantOpenDocument sends a tenant to a signed link for one of their own
+// lease's documents. Anyone else's documents are reported as missing.
+func (h *Handler) TenantOpenDocument(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Document not found")
+	}
+	doc, err := h.Store.Documents.Get(ctx, id)
+	if err != nil {
+		return documentError(c, err)
+	}
+	lease, err := h.Store.Leases.Get(ctx, doc.LeaseID)
+	if err != nil {
+		return documentError(c, err)
+	}
+	if !lease.HasTenant(middleware.GetUserID(c)) || !lease.Status.VisibleToTenant() {
+		return c.String(http.StatusNotFound, "Document not found")
+	}
+	return h.redirectToDocument(c, doc)
+}
+
+// OpenDocument serves a document from a signed link and logs the access.
+// The link carries who it was issued to, so it needs no session, but it
+// expires within minutes.
+func (h *Handler) OpenDocument(c echo.Context) error {
+	ctx := c.Request().Context()
+	subject, err := h.Tokens.Verify(c.QueryParam("token"), documentPurpose)
+	if errors.Is(err, token.ErrExpired) {
+		return c.String(http.StatusGone, "This link has expired. Open the document again from your dashboard.")
+	}
+	if err != nil {
+		return c.String(http.StatusNotFound, "Document not found")
+	}
+	idPart, userID, _ := strings.Cut(subject, ":")
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil || userID == "" {
+		return c.String(http.StatusNotFound, "Document not found")
+	}
+
+	doc, err := h.Store.Documents.Get(ctx, id)
+	if err != nil {
+		return documentError(c, err)
+	}
+	return h.serveDocument(c, doc, userID)
+}
+
+// serveDocument streams doc's file, logging that userID opened it. The
+// download query parameter asks for it as an attachment.
+func (h *Handler) serveDocument(c echo.Context, doc *models.Document, userID string) error {
+	ctx := c.Request().Context()
+	file, err := h.Documents.Open(ctx, doc.StorageKey)
+	if err != nil {
+		return documentError(c, err)
+	}
+	defer file.Close()
+
+	download := c.QueryParam("download") == "1"
+	access := models.DocumentAccess{
+		DocumentID: doc.ID,
+		UserID:     userID,
+		IPAddress:  c.RealIP(),
+		UserAgent:  truncate(c.Request().UserAgent(), 500),
+		Download:   download,
+	}
+	if err := h.Store.Documents.LogAccess(ctx, &access); err != nil {
+		// Nothing is served that can't be accounted for
+		c.Logger().Errorf("log access to document %d: %v", doc.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to open document")
+	}
+
+	disposition := "inline"
+	if download {
+		disposition = "attachment"
+	}
+	header := c.Response().Header()
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": doc.Filename}))
+	header.Set("Cache-Control", "private, no-store")
+	header.Set("X-Content-Type-Options", "nosniff")
+	return c.Stream(http.StatusOK, doc.ContentType, file)
+}
+
+// redirectToDocument issues the current user a short-lived link to doc,
+// passing on whether they asked to download it
+func (h *Handler) redirectToDocument(c echo.Context, doc *models.Document) error {
+	subject := fmt.Sprintf("%d:%s", doc.ID, middleware.GetUserID(c))
+	link := "/documents/open?token=" + url.QueryEscape(h.Tokens.Sign(documentPurpose, subject, documentLinkTTL))
+	if c.QueryParam("download") == "1" {
+		link += "&download=1"
+	}
+	return c.Redirect(http.StatusSeeOther, link)
+}
+
+func (h *Handler) renderLeaseDocuments(c echo.Context, status int, lease *models.Lease, doc models.Document, errs map[string]string) error {
+	ctx := c.Request().Context()
+	docs, err := h.Store.Documents.ListByLease(ctx, lease.ID)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	accesses, err := h.Store.Documents.ListAccessesByLease(ctx, lease.ID)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	requests, err := h.Store.Signatures.ListByLease(ctx, lease.ID)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	events, err := h.Store.Signatures.EventsByLease(ctx, lease.ID)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	staff, err := h.staffDirectory(ctx, "")
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	return Render(c, status, pages.AdminLeaseDocuments(*lease, docs, accesses, requests, events, staff, doc, errs))
+}
+
+// saveDocument stores data as a new document on lease, filling in doc's
+// storage key, uploader and ID, and emails the tenants unless the lease is
+// still a draft
+func (h *Handler) saveDocument(c echo.Context, lease *models.Lease, doc *models.Document, data []byte) error {
+	return h.storeDocument(c, lease, doc, data, h.Store.Documents.Create)
+}
+
+// storeDocument is saveDocument with create in place of adding the record
+// to the vault
+func (h *Handler) storeDocument(c echo.Context, lease *models.Lease, doc *models.Document, data []byte, create func(context.Context, *models.Document) error) error {
+	ctx := c.Request().Context()
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	doc.LeaseID = lease.ID
+	doc.StorageKey = fmt.Sprintf("leases/%d/%s%s", lease.ID, hex.EncodeToString(id), documentTypes[doc.ContentType])
+	doc.UploadedBy = middleware.GetUserID(c)
+	doc.SizeBytes = int64(len(data))
+
+	if err := h.Documents.Put(ctx, doc.StorageKey, bytes.NewReader(data), doc.ContentType); err != nil {
+		return err
+	}
+	if err := create(ctx, doc); err != nil {
+		if err := h.Documents.Delete(ctx, doc.StorageKey); err != nil {
+			c.Logger().Warnf("delete document %s: %v", doc.StorageKey, err)
+		}
+		return err
+	}
+
+	if lease.Status.VisibleToTenant() {
+		h.sendDocumentNotice(c, lease, doc)
+	}
+	return nil
+}
+
+// sendDocumentNotice tells each tenant on lease that doc was added
+func (h *Handler) sendDocumentNotice(c echo.Context, lease *models.Lease, doc *models.Document) {
+	link := h.absoluteURL("/dashboard/documents")
+	for _, t := range lease.Tenants {
+		if t.Email == "" {
+			continue
+		}
+		body := fmt.Sprintf(`Hi %s,
+
+A new document was added to your lease: %s (%s).
+
+View your documents: %s
+`, t.Name, doc.Title, strings.ToLower(doc.Kind.Label()), link)
+
+		err := h.Mailer.Send(c.Request().Context(), mailer.Message{
+			To:      t.Email,
+			Subject: "New document: " + doc.Title,
+			Body:    body,
+		})
+		if err != nil {
+			c.Logger().Warnf("email tenant of document %d: %v", doc.ID, err)
+		}
+	}
+}
+
+func saveDocumentError(c echo.Context, err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Lease not found")
+	}
+	if errors.Is(err, storage.ErrUnavailable) {
+		return c.String(http.StatusServiceUnavailable, documentsUnavailable)
+	}
+	c.Logger().Errorf("save document for lease %s: %v", c.Param("id"), err)
+	return c.String(http.StatusInternalServerError, "Failed to save document")
+}
+
+// documentsUnavailable explains failures on deployments without document
+// storage
+const documentsUnavailable = "Document storage isn't set up on this server"
+
+func documentError(c echo.Context, err error) error {
+	if errors.Is(err, repository.ErrNotFound) || errors.Is(err, storage.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Document not found")
+	}
+	if errors.Is(err, storage.ErrUnavailable) {
+		return c.String(http.StatusServiceUnavailable, documentsUnavailable)
+	}
+	c.Logger().Errorf("document %s: %v", c.Param("id"), err)
+	return c.String(http.StatusInternalServerError, "Failed to open document")
+}
+
+// parseDocumentForm reads the admin upload form and the uploaded file. The
+// file's type is sniffed from its contents rather than trusted from the
+// browser. The title defaults to the file's name.
+func parseDocumentForm(c echo.Context) (models.Document, []byte, map[string]string) {
+	errs := make(map[string]string)
+	f := propertyForm{c: c, errs: errs}
+
+	doc := models.Document{
+		Kind:  models.DocumentKind(c.FormValue("kind")),
+		Title: f.text("title", "Title", 255, false),
+	}
+	if !doc.Kind.IsValid() {
+		errs["kind"] = "Choose what kind of document this is"
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		errs["file"] = "Choose a file to upload"
+		return doc, nil, errs
+	}
+	if file.Size > maxDocumentUpload {
+		errs["file"] = fmt.Sprintf("Documents must be %d MB or smaller", maxDocumentUpload>>20)
+		return doc, nil, errs
+	}
+	src, err := file.Open()
+	if err != nil {
+		errs["file"] = "Could not read the uploaded file"
+		return doc, nil, errs
+	}
+	defer src.Close()
+	data, err := io.ReadAll(io.LimitReader(src, maxDocumentUpload+1))
+	if err != nil || len(data) > maxDocumentUpload {
+		errs["file"] = "Could not read the uploaded file"
+		return doc, nil, errs
+	}
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	if _, ok := documentTypes[contentType]; !ok {
+		errs["file"] = "Documents must be PDFs or JPEG or PNG images"
+		return doc, nil, errs
+	}
+	doc.ContentType = contentType
+	doc.Filename = truncate(filepath.Base(strings.ReplaceAll(file.Filename, `\`, "/")), 255)
+	if doc.Filename == "" || doc.Filename == "." || doc.Filename == "/" {
+		doc.Filename = "document" + documentTypes[contentType]
+	}
+	if doc.Title == "" {
+		doc.Title = truncate(strings.TrimSuffix(doc.Filename, filepath.Ext(doc.Filename)), 255)
+	}
+	return doc, data, errs
+}
+
+// truncate cuts s to at most n bytes without splitting a character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
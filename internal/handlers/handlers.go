@@ -1,17 +1,62 @@
 package handlers
 
 import (
-	"russ-rentals/internal/database"
+	"strings"
+	"time"
+
+	"russ-rentals/internal/jobs"
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/payment"
+	"russ-rentals/internal/repository"
+	"russ-rentals/internal/storage"
+	"russ-rentals/internal/token"
 )
 
 // Handler holds dependencies for HTTP handlers
 type Handler struct {
-	DB *database.DB
+	Store    *repository.Store
+	Mailer   mailer.Mailer
+	Tokens   *token.Signer
+	Uploads  storage.Storage
+	Payments payment.Gateway
+	// Documents holds lease documents. Unlike Uploads it is never served
+	// publicly; tenants reach files through signed links.
+	Documents storage.Storage
+	// BaseURL is the public origin used in emailed links. It is never
+	// taken from requests, whose Host header anyone can set.
+	BaseURL string
+	// Jobs runs background jobs on request, for hosts whose cron calls
+	// RunJob. It is nil where the in-process scheduler runs them.
+	Jobs *jobs.Scheduler
+	// AdminIDs are users who are admins whatever their stored role, as
+	// given to middleware.Roles. Calendar feeds, which have no session,
+	// check them.
+	AdminIDs []string
+	// Location is the business's time zone. Showing and visit times are
+	// entered and shown in it, and it decides what day it is.
+	Location *time.Location
 }
 
 // NewHandler creates a new Handler with dependencies
-func NewHandler(db *database.DB) *Handler {
+func NewHandler(store *repository.Store, m mailer.Mailer, tokens *token.Signer, uploads, documents storage.Storage, payments payment.Gateway, baseURL string, loc *time.Location) *Handler {
 	return &Handler{
-		DB: db,
+		Store:     store,
+		Mailer:    m,
+		Tokens:    tokens,
+		Uploads:   uploads,
+		Documents: documents,
+		Payments:  payments,
+		BaseURL:   baseURL,
+		Location:  loc,
 	}
 }
+
+// now returns the current time in the business's time zone
+func (h *Handler) now() time.Time {
+	return time.Now().In(h.Location)
+}
+
+// absoluteURL turns an app path into a full URL suitable for emails
+func (h *Handler) absoluteURL(path string) string {
+	return strings.TrimRight(h.BaseURL, "/") + path
+}
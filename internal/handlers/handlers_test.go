@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/payment"
+	"russ-rentals/internal/repository"
+	"russ-rentals/internal/storage"
+	"russ-rentals/internal/token"
+)
+
+// outbox records the messages sent through it
+type outbox struct {
+	sent []mailer.Message
+}
+
+func (o *outbox) Send(ctx context.Context, msg mailer.Message) error {
+	o.sent = append(o.sent, msg)
+	return nil
+}
+
+// testHandler returns a Handler over a memory store holding properties,
+// paying through the fake gateway and keeping documents in a temporary
+// directory
+func testHandler(t *testing.T, properties ...models.Property) (*Handler, *payment.FakeGateway, *outbox) {
+	t.Helper()
+	documents, err := storage.NewLocalStorage(t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gateway := payment.NewFakeGateway("usd", "whsec")
+	mail := &outbox{}
+	store := repository.NewMemoryStore(properties)
+	h := NewHandler(store, mail, token.NewSigner("test-secret"), storage.Unavailable{}, documents, gateway, "https://example.com", time.UTC)
+	return h, gateway, mail
+}
+
+// serve calls handler as userID with form posted and the given path
+// parameters, as name, value pairs
+func serve(t *testing.T, handler echo.HandlerFunc, method, userID string, form url.Values, params ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	route, path := "", ""
+	for i := 0; i+1 < len(params); i += 2 {
+		route += "/:" + params[i]
+		path += "/" + url.PathEscape(params[i+1])
+	}
+	e := echo.New()
+	e.Add(method, route+"/", handler, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if userID != "" {
+				c.Set("userID", userID)
+				c.Set("isAuthenticated", true)
+			}
+			return next(c)
+		}
+	})
+
+	req := httptest.NewRequest(method, path+"/", strings.NewReader(form.Encode()))
+	if form != nil {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+// wantStatus fails the test unless rec has status code
+func wantStatus(t *testing.T, rec *httptest.ResponseRecorder, code int) {
+	t.Helper()
+	if rec.Code != code {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, code, rec.Body.String())
+	}
+}
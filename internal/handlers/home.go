@@ -11,5 +11,12 @@ import (
 
 func (h *Handler) Home(c echo.Context) error {
 	isAuth := middleware.IsAuthenticated(c)
-	return Render(c, http.StatusOK, pages.Home(isAuth))
+
+	featured, err := h.getFeaturedProperties(c.Request().Context())
+	if err != nil {
+		c.Logger().Errorf("featured properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load featured properties")
+	}
+
+	return Render(c, http.StatusOK, pages.Home(featured, isAuth))
 }
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/billing"
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/templates/pages"
+)
+
+// Ledger shows the full ledger of the lease on the tenant's dashboard
+func (h *Handler) Ledger(c echo.Context) error {
+	ctx := c.Request().Context()
+	lease, err := h.tenantLease(ctx, middleware.GetUserID(c))
+	if err != nil {
+		c.Logger().Errorf("Failed to load lease: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load ledger")
+	}
+	if lease == nil {
+		return c.Redirect(http.StatusSeeOther, "/dashboard")
+	}
+
+	ledger, err := h.leaseLedger(ctx, lease)
+	if err != nil {
+		c.Logger().Errorf("Failed to load ledger for lease %d: %v", lease.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load ledger")
+	}
+	property, err := h.Store.Properties.GetByID(ctx, lease.PropertyID)
+	if err != nil {
+		c.Logger().Errorf("Failed to load property %d: %v", lease.PropertyID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load ledger")
+	}
+	return Render(c, http.StatusOK, pages.TenantLedger(*lease, *property, ledger, models.Date(h.now())))
+}
+
+// LedgerCSV downloads the tenant's ledger
+func (h *Handler) LedgerCSV(c echo.Context) error {
+	ctx := c.Request().Context()
+	lease, err := h.tenantLease(ctx, middleware.GetUserID(c))
+	if err != nil {
+		c.Logger().Errorf("Failed to load lease: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to export ledger")
+	}
+	if lease == nil {
+		return c.String(http.StatusNotFound, "No lease found")
+	}
+	return h.ledgerCSV(c, lease)
+}
+
+// AdminLeaseLedger shows a lease's ledger with a form to post charges,
+// credits and payments
+func (h *Handler) AdminLeaseLedger(c echo.Context) error {
+	lease, err := h.adminLease(c)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	ledger, err := h.leaseLedger(c.Request().Context(), lease)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	entry := models.LedgerEntry{Kind: models.LedgerKindPayment, PostedOn: models.Date(h.now())}
+	return Render(c, http.StatusOK, pages.AdminLeaseLedger(*lease, ledger, entry, models.Date(h.now())))
+}
+
+// AdminPostLedgerEntry posts a charge, credit or payment to a lease's
+// ledger. Entries can't be edited afterwards; mistakes are corrected by
+// posting the opposite entry.
+func (h *Handler) AdminPostLedgerEntry(c echo.Context) error {
+	lease, err := h.adminLease(c)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+
+	errs := map[string]string{}
+	f := propertyForm{c, errs}
+	entry := models.LedgerEntry{
+		LeaseID:     lease.ID,
+		Kind:        models.LedgerKind(c.FormValue("kind")),
+		Description: f.text("description", "Description", 255, true),
+		AmountCents: f.cents("amount", "Amount", true),
+		PostedOn:    f.date("postedOn", "Date"),
+		CreatedBy:   middleware.GetUserID(c),
+	}
+	if !slices.Contains(models.ManualLedgerKinds, entry.Kind) {
+		errs["kind"] = "Choose a charge, credit or payment"
+	}
+	if _, ok := errs["amount"]; !ok && entry.AmountCents <= 0 {
+		errs["amount"] = "Amount must be more than zero"
+	}
+	if len(errs) > 0 {
+		return Render(c, http.StatusUnprocessableEntity, pages.AdminLedgerEntryForm(*lease, entry, errs))
+	}
+
+	if err := h.Store.Ledger.Create(c.Request().Context(), &entry); err != nil {
+		c.Logger().Errorf("Failed to post ledger entry for lease %d: %v", lease.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to post entry")
+	}
+	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/admin/leases/%d/ledger", lease.ID))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AdminLeaseLedgerCSV downloads a lease's ledger
+func (h *Handler) AdminLeaseLedgerCSV(c echo.Context) error {
+	lease, err := h.adminLease(c)
+	if err != nil {
+		return adminLeaseError(c, err)
+	}
+	return h.ledgerCSV(c, lease)
+}
+
+// leaseLedger posts any rent that has fallen due on lease, then lists its
+// ledger
+func (h *Handler) leaseLedger(ctx context.Context, lease *models.Lease) (models.Ledger, error) {
+	if _, err := billing.PostRent(ctx, h.Store.Ledger, *lease, h.now()); err != nil {
+		return nil, err
+	}
+	return h.Store.Ledger.ListByLease(ctx, lease.ID)
+}
+
+// ledgerCSV writes lease's ledger as a CSV download, one row per entry with
+// the running balance
+func (h *Handler) ledgerCSV(c echo.Context, lease *models.Lease) error {
+	ledger, err := h.leaseLedger(c.Request().Context(), lease)
+	if err != nil {
+		c.Logger().Errorf("Failed to load ledger for lease %d: %v", lease.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to export ledger")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"Date", "Type", "Description", "Charge", "Payment", "Balance"})
+	balances := ledger.RunningBalances()
+	for i, e := range ledger {
+		charge, payment := csvCents(e.AmountCents), ""
+		if !e.Kind.IsCharge() {
+			charge, payment = "", charge
+		}
+		w.Write([]string{
+			e.PostedOn.Format("2006-01-02"),
+			e.Kind.Label(),
+			csvText(e.Description),
+			charge,
+			payment,
+			csvCents(balances[i]),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		c.Logger().Errorf("Failed to write ledger CSV for lease %d: %v", lease.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to export ledger")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="lease-%d-ledger.csv"`, lease.ID))
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// csvCents writes cents as a plain decimal amount that spreadsheets read as
+// a number, e.g. -1234.50
+func csvCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// csvText keeps a spreadsheet from running text that starts like a formula
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
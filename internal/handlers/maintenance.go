@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/imaging"
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/internal/storage"
+	"russ-rentals/internal/token"
+	"russ-rentals/templates/pages"
+)
+
+const (
+	// maxMaintenancePhotos caps the photos attached to one maintenance
+	// request
+	maxMaintenancePhotos = 5
+	// maintenancePhotoPurpose signs links to maintenance photos, which
+	// last as long as document links
+	maintenancePhotoPurpose = "maintenance-photo"
+)
+
+// Maintenance lists the maintenance requests on the tenant's lease
+func (h *Handler) Maintenance(c echo.Context) error {
+	ctx := c.Request().Context()
+	lease, err := h.tenantLease(ctx, middleware.GetUserID(c))
+	if err != nil {
+		c.Logger().Errorf("Failed to load lease: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance requests")
+	}
+	if lease == nil {
+		return c.Redirect(http.StatusSeeOther, "/dashboard")
+	}
+
+	requests, err := h.Store.Maintenance.ListByLease(ctx, lease.ID)
+	if err != nil {
+		c.Logger().Errorf("Failed to list maintenance requests for lease %d: %v", lease.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance requests")
+	}
+	property, err := h.Store.Properties.GetByID(ctx, lease.PropertyID)
+	if err != nil {
+		c.Logger().Errorf("Failed to load property %d: %v", lease.PropertyID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance requests")
+	}
+	return Render(c, http.StatusOK, pages.TenantMaintenance(*lease, *property, requests))
+}
+
+// NewMaintenanceRequest shows the form to report a problem. Only tenants on
+// an active lease can.
+func (h *Handler) NewMaintenanceRequest(c echo.Context) error {
+	ctx := c.Request().Context()
+	lease, err := h.tenantLease(ctx, middleware.GetUserID(c))
+	if err != nil {
+		c.Logger().Errorf("Failed to load lease: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance request form")
+	}
+	if lease == nil || lease.Status != models.LeaseStatusActive {
+		return c.Redirect(http.StatusSeeOther, "/dashboard/maintenance")
+	}
+	req := models.MaintenanceRequest{Category: models.MaintenanceCategoryOther, Urgency: models.MaintenanceUrgencyRoutine}
+	return Render(c, http.StatusOK, pages.NewMaintenanceRequest(req, nil))
+}
+
+// SubmitMaintenanceRequest files a maintenance request with its photos and
+// lets staff know about it
+func (h *Handler) SubmitMaintenanceRequest(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := middleware.GetUserID(c)
+	lease, err := h.tenantLease(ctx, userID)
+	if err != nil {
+		c.Logger().Errorf("Failed to load lease: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to submit maintenance request")
+	}
+	if lease == nil || lease.Status != models.LeaseStatusActive {
+		c.Response().Header().Set("HX-Redirect", "/dashboard/maintenance")
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	// Leave room for the other multipart fields
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxMaintenancePhotos*maxImageUpload+1<<20)
+
+	req, errs := parseMaintenanceForm(c)
+	files, msg := maintenancePhotoFiles(c)
+	if msg != "" {
+		errs["photos"] = msg
+	}
+	if len(errs) > 0 {
+		return Render(c, http.StatusUnprocessableEntity, pages.MaintenanceRequestForm(req, errs))
+	}
+
+	for _, file := range files {
+		photo, err := h.storeMaintenancePhoto(c, lease.ID, file)
+		if err != nil {
+			h.deleteMaintenancePhotos(c, req.Photos)
+			if msg := photoUploadMessage(err); msg != "" {
+				errs["photos"] = file.Filename + ": " + msg
+				return Render(c, http.StatusUnprocessableEntity, pages.MaintenanceRequestForm(req, errs))
+			}
+			c.Logger().Errorf("Failed to store maintenance photo for lease %d: %v", lease.ID, err)
+			return c.String(http.StatusInternalServerError, "Failed to submit maintenance request")
+		}
+		req.Photos = append(req.Photos, photo)
+	}
+
+	tenant, _ := lease.Tenant(userID)
+	req.LeaseID = lease.ID
+	req.PropertyID = lease.PropertyID
+	req.TenantID = userID
+	req.TenantName = tenant.Name
+	req.TenantEmail = tenant.Email
+	if err := h.Store.Maintenance.Create(ctx, &req); err != nil {
+		c.Logger().Errorf("Failed to create maintenance request for lease %d: %v", lease.ID, err)
+		h.deleteMaintenancePhotos(c, req.Photos)
+		return c.String(http.StatusInternalServerError, "Failed to submit maintenance request")
+	}
+	h.notifyStaffOfMaintenance(c, &req)
+
+	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/dashboard/maintenance/%d", req.ID))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// MaintenanceRequestDetail shows one of the tenant's requests with its
+// photos and the updates staff have shared
+func (h *Handler) MaintenanceRequestDetail(c echo.Context) error {
+	ctx := c.Request().Context()
+	req, err := h.tenantMaintenanceRequest(c)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Maintenance request not found")
+	}
+	if err != nil {
+		c.Logger().Errorf("Failed to load maintenance request %s: %v", c.Param("id"), err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance request")
+	}
+
+	events, err := h.Store.Maintenance.Timeline(ctx, req.ID)
+	if err != nil {
+		c.Logger().Errorf("Failed to load timeline of maintenance request %d: %v", req.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance request")
+	}
+	var shared []models.MaintenanceEvent
+	for _, e := range events {
+		if e.VisibleToTenant() {
+			shared = append(shared, e)
+		}
+	}
+	property, err := h.Store.Properties.GetByID(ctx, req.PropertyID)
+	if err != nil {
+		c.Logger().Errorf("Failed to load property %d: %v", req.PropertyID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load maintenance request")
+	}
+	return Render(c, http.StatusOK, pages.TenantMaintenanceRequest(*req, *property, shared))
+}
+
+// tenantMaintenanceRequest loads the request named in the URL. Tenants can
+// see every request on a lease they're on, their co-tenants' included.
+func (h *Handler) tenantMaintenanceRequest(c echo.Context) (*models.MaintenanceRequest, error) {
+	ctx := c.Request().Context()
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, repository.ErrNotFound
+	}
+	req, err := h.Store.Maintenance.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	lease, err := h.Store.Leases.Get(ctx, req.LeaseID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := lease.Tenant(middleware.GetUserID(c)); !ok || !lease.Status.VisibleToTenant() {
+		return nil, repository.ErrNotFound
+	}
+	return req, nil
+}
+
+// MaintenancePhoto sends a tenant to a signed link for a photo on one of
+// their lease's requests
+func (h *Handler) MaintenancePhoto(c echo.Context) error {
+	req, err := h.tenantMaintenanceRequest(c)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Photo not found")
+	}
+	if err != nil {
+		c.Logger().Errorf("Failed to load maintenance request %s: %v", c.Param("id"), err)
+		return c.String(http.StatusInternalServerError, "Failed to load photo")
+	}
+	return h.redirectToMaintenancePhoto(c, req)
+}
+
+// OpenMaintenancePhoto serves a maintenance photo from a signed link. The
+// link names the stored file itself, so it needs no session, but it
+// expires within minutes.
+func (h *Handler) OpenMaintenancePhoto(c echo.Context) error {
+	key, err := h.Tokens.Verify(c.QueryParam("token"), maintenancePhotoPurpose)
+	if errors.Is(err, token.ErrExpired) {
+		return c.String(http.StatusGone, "This link has expired. Open the photo again from the request.")
+	}
+	if err != nil {
+		return c.String(http.StatusNotFound, "Photo not found")
+	}
+
+	file, err := h.Documents.Open(c.Request().Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return c.String(http.StatusNotFound, "Photo not found")
+	}
+	if errors.Is(err, storage.ErrUnavailable) {
+		return c.String(http.StatusServiceUnavailable, documentsUnavailable)
+	}
+	if err != nil {
+		c.Logger().Errorf("open maintenance photo %s: %v", key, err)
+		return c.String(http.StatusInternalServerError, "Failed to load photo")
+	}
+	defer file.Close()
+
+	header := c.Response().Header()
+	header.Set("Cache-Control", "private, no-store")
+	header.Set("X-Content-Type-Options", "nosniff")
+	return c.Stream(http.StatusOK, "image/jpeg", file)
+}
+
+// redirectToMaintenancePhoto issues a short-lived link to the photo of req
+// named in the URL. The size query parameter picks the thumbnail.
+func (h *Handler) redirectToMaintenancePhoto(c echo.Context, req *models.MaintenanceRequest) error {
+	id, err := strconv.ParseInt(c.Param("photo"), 10, 64)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Photo not found")
+	}
+	variant := "full"
+	if c.QueryParam("size") == "thumbnail" {
+		variant = "thumbnail"
+	}
+	for _, p := range req.Photos {
+		if p.ID == id {
+			key := photoKey(p.StorageKey, variant)
+			link := "/maintenance/photos?token=" + url.QueryEscape(h.Tokens.Sign(maintenancePhotoPurpose, key, documentLinkTTL))
+			return c.Redirect(http.StatusSeeOther, link)
+		}
+	}
+	return c.String(http.StatusNotFound, "Photo not found")
+}
+
+// parseMaintenanceForm reads the request form's fields other than photos
+func parseMaintenanceForm(c echo.Context) (models.MaintenanceRequest, map[string]string) {
+	errs := make(map[string]string)
+	f := propertyForm{c, errs}
+	req := models.MaintenanceRequest{
+		Category:       models.MaintenanceCategory(c.FormValue("category")),
+		Urgency:        models.MaintenanceUrgency(c.FormValue("urgency")),
+		Title:          f.text("title", "Summary", 150, true),
+		Description:    f.text("description", "Description", 5000, true),
+		EntryPermitted: c.FormValue("entryPermitted") == "yes",
+		EntryNotes:     f.text("entryNotes", "Entry notes", 1000, false),
+	}
+	if !req.Category.IsValid() {
+		errs["category"] = "Choose what kind of problem it is"
+	}
+	if !req.Urgency.IsValid() {
+		errs["urgency"] = "Choose how urgent it is"
+	}
+	if v := c.FormValue("entryPermitted"); v != "yes" && v != "no" {
+		errs["entryPermitted"] = "Let us know whether we can let ourselves in"
+	}
+	return req, errs
+}
+
+// errUnreadableUpload is returned for uploaded files that can't be read
+var errUnreadableUpload = errors.New("unreadable upload")
+
+// maintenancePhotoFiles returns the photos chosen in the request form, or
+// a message for the tenant if there are too many or one is too big
+func maintenancePhotoFiles(c echo.Context) ([]*multipart.FileHeader, string) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, ""
+	}
+	var files []*multipart.FileHeader
+	for _, file := range form.File["photos"] {
+		if file.Size > 0 {
+			files = append(files, file)
+		}
+	}
+	if len(files) > maxMaintenancePhotos {
+		return nil, fmt.Sprintf("Attach up to %d photos", maxMaintenancePhotos)
+	}
+	for _, file := range files {
+		if file.Size > maxImageUpload {
+			return nil, fmt.Sprintf("%s: photos must be %d MB or smaller", file.Filename, maxImageUpload>>20)
+		}
+	}
+	return files, ""
+}
+
+// storeMaintenancePhoto saves file in each of imaging.AttachmentVariants.
+// Photos can show the inside of a tenant's home, so they go in the private
+// document store rather than with the public listing photos.
+func (h *Handler) storeMaintenancePhoto(c echo.Context, leaseID int64, file *multipart.FileHeader) (models.MaintenancePhoto, error) {
+	src, err := file.Open()
+	if err != nil {
+		return models.MaintenancePhoto{}, errUnreadableUpload
+	}
+	defer src.Close()
+	data, err := io.ReadAll(io.LimitReader(src, maxImageUpload+1))
+	if err != nil || len(data) > maxImageUpload {
+		return models.MaintenancePhoto{}, errUnreadableUpload
+	}
+
+	key, _, err := h.putPhoto(c, h.Documents, fmt.Sprintf("maintenance/%d", leaseID), data, imaging.AttachmentVariants)
+	if err != nil {
+		return models.MaintenancePhoto{}, err
+	}
+	return models.MaintenancePhoto{StorageKey: key}, nil
+}
+
+// photoUploadMessage explains why an uploaded photo was rejected, or
+// returns "" if err isn't the uploader's fault
+func photoUploadMessage(err error) string {
+	switch {
+	case errors.Is(err, errUnreadableUpload):
+		return "Could not read the uploaded file"
+	case errors.Is(err, imaging.ErrUnsupported):
+		return "Photos must be JPEG, PNG or WebP images"
+	case errors.Is(err, imaging.ErrTooLarge):
+		return "Photo dimensions are too large"
+	case errors.Is(err, storage.ErrUnavailable):
+		return "Photo uploads aren't set up on this server"
+	default:
+		return ""
+	}
+}
+
+func (h *Handler) deleteMaintenancePhotos(c echo.Context, photos []models.MaintenancePhoto) {
+	for _, p := range photos {
+		h.deletePhoto(c, h.Documents, p.StorageKey)
+	}
+}
+
+// notifyStaffOfMaintenance emails the staff with an email address on
+// record about a new request. Failures are only logged.
+func (h *Handler) notifyStaffOfMaintenance(c echo.Context, req *models.MaintenanceRequest) {
+	ctx := c.Request().Context()
+	staff, err := h.staffDirectory(ctx, "")
+	if err != nil {
+		c.Logger().Warnf("list staff to notify of maintenance request %d: %v", req.ID, err)
+		return
+	}
+
+	entry := "No, someone must be home"
+	if req.EntryPermitted {
+		entry = "Yes"
+	}
+	body := fmt.Sprintf(`New %s maintenance request from %s
+
+%s
+Category: %s
+Entry permitted: %s
+
+%s
+
+Triage it: %s
+`, strings.ToLower(req.Urgency.Label()), req.TenantName, req.Title, req.Category.Label(), entry,
+		req.Description, h.absoluteURL(fmt.Sprintf("/admin/maintenance/%d", req.ID)))
+
+	subject := fmt.Sprintf("Maintenance request #%d: %s", req.ID, req.Title)
+	if req.Urgency == models.MaintenanceUrgencyEmergency {
+		subject = "EMERGENCY " + subject
+	}
+	for _, u := range staff {
+		if u.Email == "" {
+			continue
+		}
+		err := h.Mailer.Send(ctx, mailer.Message{To: u.Email, Subject: subject, Body: body})
+		if err != nil {
+			c.Logger().Warnf("notify %s of maintenance request %d: %v", u.ClerkUserID, req.ID, err)
+		}
+	}
+}
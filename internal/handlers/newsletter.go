@@ -1,23 +1,166 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/internal/token"
 	"russ-rentals/templates/components"
+	"russ-rentals/templates/pages"
+)
+
+const (
+	newsletterConfirmPurpose     = "newsletter-confirm"
+	newsletterUnsubscribePurpose = "newsletter-unsubscribe"
+	newsletterConfirmTTL         = 7 * 24 * time.Hour
 )
 
 func (h *Handler) Newsletter(c echo.Context) error {
-	email := c.FormValue("email")
-	firstName := c.FormValue("firstName")
+	email := strings.TrimSpace(c.FormValue("email"))
+	firstName := strings.TrimSpace(c.FormValue("firstName"))
 
 	if email == "" {
 		return Render(c, http.StatusBadRequest, components.NewsletterError("Please enter your email address"))
 	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email || len(email) > 255 {
+		return Render(c, http.StatusBadRequest, components.NewsletterError("Please enter a valid email address"))
+	}
+	if len(firstName) > 100 {
+		return Render(c, http.StatusBadRequest, components.NewsletterError("Please enter a shorter first name"))
+	}
+
+	sub, err := h.Store.Newsletter.Subscribe(c.Request().Context(), email, firstName)
+	if err != nil {
+		c.Logger().Errorf("newsletter subscribe: %v", err)
+		return Render(c, http.StatusInternalServerError, components.NewsletterError("We couldn't save your subscription. Please try again later."))
+	}
+
+	if sub.Status == models.SubscriberStatusActive {
+		return Render(c, http.StatusOK, components.NewsletterSuccess("You're already subscribed!", "You'll be notified when new properties are listed."))
+	}
+
+	if err := h.sendNewsletterConfirmation(c, sub); err != nil {
+		c.Logger().Errorf("newsletter confirmation email: %v", err)
+		return Render(c, http.StatusInternalServerError, components.NewsletterError("We couldn't send your confirmation email. Please try again later."))
+	}
+
+	return Render(c, http.StatusOK, components.NewsletterSuccess("Check your inbox!", "Click the link we sent to confirm your subscription."))
+}
+
+// ConfirmNewsletter activates a pending subscriber from their emailed link
+func (h *Handler) ConfirmNewsletter(c echo.Context) error {
+	isAuth := middleware.IsAuthenticated(c)
+	ctx := c.Request().Context()
+
+	email, err := h.Tokens.Verify(c.QueryParam("token"), newsletterConfirmPurpose)
+	if err != nil {
+		return h.newsletterTokenError(c, err)
+	}
+
+	sub, err := h.Store.Newsletter.Get(ctx, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return Render(c, http.StatusNotFound, pages.NewsletterStatus("Subscription Not Found", "We couldn't find that subscription. Please sign up again from our home page.", false, isAuth))
+	}
+	if err != nil {
+		c.Logger().Errorf("newsletter confirm lookup: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to confirm subscription")
+	}
+
+	switch sub.Status {
+	case models.SubscriberStatusActive:
+		return Render(c, http.StatusOK, pages.NewsletterStatus("Already Confirmed", "Your subscription is already active. Thanks for staying in touch!", true, isAuth))
+	case models.SubscriberStatusUnsubscribed:
+		// An old confirmation link must not undo an unsubscribe
+		return Render(c, http.StatusOK, pages.NewsletterStatus("You're Unsubscribed", "This address has been unsubscribed. Sign up again from our home page if you'd like to rejoin.", false, isAuth))
+	}
+
+	if _, err := h.Store.Newsletter.Confirm(ctx, email); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		c.Logger().Errorf("newsletter confirm: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to confirm subscription")
+	}
+
+	return Render(c, http.StatusOK, pages.NewsletterStatus("Subscription Confirmed", "You'll be the first to know when new properties become available.", true, isAuth))
+}
+
+// UnsubscribeNewsletterPage asks the subscriber to confirm before removing
+// them, so link scanners in mail clients can't unsubscribe people
+func (h *Handler) UnsubscribeNewsletterPage(c echo.Context) error {
+	isAuth := middleware.IsAuthenticated(c)
+	tok := c.QueryParam("token")
+
+	email, err := h.Tokens.Verify(tok, newsletterUnsubscribePurpose)
+	if err != nil {
+		return h.newsletterTokenError(c, err)
+	}
+
+	return Render(c, http.StatusOK, pages.NewsletterUnsubscribe(email, tok, isAuth))
+}
+
+// UnsubscribeNewsletter handles both the confirmation form and RFC 8058
+// one-click unsubscribe POSTs from mail clients
+func (h *Handler) UnsubscribeNewsletter(c echo.Context) error {
+	isAuth := middleware.IsAuthenticated(c)
+
+	email, err := h.Tokens.Verify(c.FormValue("token"), newsletterUnsubscribePurpose)
+	if err != nil {
+		return h.newsletterTokenError(c, err)
+	}
+
+	err = h.Store.Newsletter.Unsubscribe(c.Request().Context(), email)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		c.Logger().Errorf("newsletter unsubscribe: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to unsubscribe")
+	}
+
+	return Render(c, http.StatusOK, pages.NewsletterStatus("You've Been Unsubscribed", "You won't receive any more listing emails from us.", true, isAuth))
+}
+
+func (h *Handler) newsletterTokenError(c echo.Context, err error) error {
+	isAuth := middleware.IsAuthenticated(c)
+	if errors.Is(err, token.ErrExpired) {
+		return Render(c, http.StatusBadRequest, pages.NewsletterStatus("Link Expired", "This link has expired. Please sign up again from our home page to get a new one.", false, isAuth))
+	}
+	return Render(c, http.StatusBadRequest, pages.NewsletterStatus("Invalid Link", "This link is invalid. Please check you copied the whole address from the email.", false, isAuth))
+}
+
+func (h *Handler) sendNewsletterConfirmation(c echo.Context, sub *models.NewsletterSubscriber) error {
+	confirmURL := h.absoluteURL("/newsletter/confirm?token=" + url.QueryEscape(h.Tokens.Sign(newsletterConfirmPurpose, sub.Email, newsletterConfirmTTL)))
+	unsubscribeURL := h.absoluteURL("/newsletter/unsubscribe?token=" + url.QueryEscape(h.Tokens.Sign(newsletterUnsubscribePurpose, sub.Email, 0)))
+
+	greeting := "Hi there,"
+	if sub.FirstName != "" {
+		greeting = fmt.Sprintf("Hi %s,", sub.FirstName)
+	}
+
+	body := fmt.Sprintf(`%s
+
+Thanks for signing up for new listing alerts from Russ Rentals. Please confirm your email address by opening the link below:
+
+%s
+
+This link expires in 7 days. If you didn't sign up, you can ignore this email.
 
-	// In a real app, save to database here
-	_ = firstName
+Unsubscribe: %s
+`, greeting, confirmURL, unsubscribeURL)
 
-	return Render(c, http.StatusOK, components.NewsletterSuccess())
+	return h.Mailer.Send(c.Request().Context(), mailer.Message{
+		To:      sub.Email,
+		Subject: "Confirm your Russ Rentals subscription",
+		Body:    body,
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
 }
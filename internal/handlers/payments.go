@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/billing"
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/payment"
+	"russ-rentals/internal/repository"
+	"russ-rentals/templates/pages"
+)
+
+// maxWebhookSize caps the payment webhook bodies that are read
+const maxWebhookSize = 1 << 20
+
+// paymentAttemptSize is the size in bytes of a payment attempt's key
+const paymentAttemptSize = 16
+
+// ApplicationPayment collects a draft's application fee, starting a payment
+// with the gateway unless an unpaid one can be retried
+func (h *Handler) ApplicationPayment(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	app, property, err := h.applicantApplication(c)
+	if err != nil {
+		return applicationError(c, err)
+	}
+	if app.Status != models.ApplicationStatusDraft {
+		return c.Redirect(http.StatusSeeOther, applicationURL(app))
+	}
+	if !app.Data.Complete() || !property.Available || applicationFeeCents(property) == 0 {
+		return c.Redirect(http.StatusSeeOther, applicationStepURL(app, ""))
+	}
+
+	fee, err := h.applicationFeePayment(ctx, app, property)
+	if err != nil {
+		c.Logger().Errorf("application %d fee: %v", app.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to start payment")
+	}
+	if fee == nil {
+		if fee, err = h.startApplicationFee(ctx, app, property); err != nil {
+			c.Logger().Errorf("application %d fee: %v", app.ID, err)
+			return c.String(http.StatusInternalServerError, "Failed to start payment")
+		}
+	}
+	if fee.Paid() {
+		return c.Redirect(http.StatusSeeOther, applicationStepURL(app, ""))
+	}
+	return Render(c, http.StatusOK, pages.ApplicationPayment(*app, *property, *fee, h.paymentCheckout(fee.FailureMessage)))
+}
+
+// PayApplication charges the application fee with the card the browser
+// collected and, once it's paid, submits the application. A "check" field
+// instead asks the gateway how an attempt the applicant finished with it
+// directly, such as a 3-D Secure check, turned out.
+func (h *Handler) PayApplication(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	app, property, err := h.applicantApplication(c)
+	if err != nil {
+		return applicationError(c, err)
+	}
+	if app.Status != models.ApplicationStatusDraft {
+		return applicationError(c, repository.ErrStatusChanged)
+	}
+	fee, err := h.applicationFeePayment(ctx, app, property)
+	if err != nil {
+		c.Logger().Errorf("application %d fee: %v", app.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to process payment")
+	}
+	if fee == nil {
+		c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/applications/%d/pay", app.ID))
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	if !fee.Paid() {
+		checkout := h.paymentCheckout("")
+		method := strings.TrimSpace(c.FormValue("payment_method"))
+		switch {
+		case !app.Data.Complete():
+			checkout.Message = "Finish every step before paying."
+		case !property.Available:
+			checkout.Message = "This property is no longer available."
+		case method == "" && c.FormValue("check") == "":
+			checkout.Message = "Enter your card details."
+		}
+		if checkout.Message != "" {
+			return Render(c, http.StatusUnprocessableEntity, pages.ApplicationPaymentForm(*app, *fee, checkout))
+		}
+
+		intent, err := h.Payments.Confirm(ctx, fee.IntentID, method)
+		if err != nil {
+			c.Logger().Errorf("confirm payment %s: %v", fee.IntentID, err)
+			return c.String(http.StatusInternalServerError, "Failed to process payment")
+		}
+		switch intent.Status {
+		case payment.StatusSucceeded:
+			fee, err = h.Store.Payments.SetStatus(ctx, fee.ID, models.PaymentStatusSucceeded, "")
+		case payment.StatusFailed, payment.StatusCanceled:
+			message := intent.FailureMessage
+			if message == "" {
+				message = "Your payment didn't go through. Please try another card."
+			}
+			if fee, err = h.Store.Payments.SetStatus(ctx, fee.ID, models.PaymentStatusFailed, message); err == nil {
+				checkout.Message = message
+				return Render(c, http.StatusUnprocessableEntity, pages.ApplicationPaymentForm(*app, *fee, checkout))
+			}
+		case payment.StatusRequiresAction:
+			checkout.ClientSecret = intent.ClientSecret
+			return Render(c, http.StatusOK, pages.ApplicationPaymentForm(*app, *fee, checkout))
+		default:
+			// The webhook submits the application once the payment clears
+			checkout.Processing = true
+			return Render(c, http.StatusOK, pages.ApplicationPaymentForm(*app, *fee, checkout))
+		}
+		if err != nil {
+			c.Logger().Errorf("record payment %s: %v", fee.IntentID, err)
+			return c.String(http.StatusInternalServerError, "Failed to process payment")
+		}
+	}
+
+	submitted, err := h.submitApplication(c, app, property, fee)
+	if errors.Is(err, repository.ErrStatusChanged) {
+		// The payment webhook got there first
+		submitted, err = h.Store.Applications.Get(ctx, app.ID)
+	}
+	if err != nil {
+		return applicationError(c, err)
+	}
+	c.Response().Header().Set("HX-Redirect", applicationURL(submitted))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PaymentWebhook records payment outcomes the gateway reports, submitting
+// applications whose fee cleared after the applicant left the page and
+// posting rent payments to the ledger.
+// Intents this app didn't start and unknown events are acknowledged and
+// ignored; other failures return an error so the gateway retries.
+func (h *Handler) PaymentWebhook(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	payload, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookSize))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid payload")
+	}
+	event, err := h.Payments.VerifyWebhook(payload, c.Request().Header)
+	if errors.Is(err, payment.ErrInvalidSignature) {
+		return c.String(http.StatusBadRequest, "Invalid signature")
+	}
+	if err != nil {
+		c.Logger().Warnf("payment webhook: %v", err)
+		return c.String(http.StatusBadRequest, "Invalid payload")
+	}
+
+	p, err := h.Store.Payments.GetByIntent(ctx, event.IntentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c.NoContent(http.StatusOK)
+	}
+	if err != nil {
+		c.Logger().Errorf("payment webhook %s: %v", event.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to record payment")
+	}
+
+	switch event.Type {
+	case payment.EventSucceeded:
+		p, err = h.Store.Payments.SetStatus(ctx, p.ID, models.PaymentStatusSucceeded, "")
+	case payment.EventFailed:
+		p, err = h.Store.Payments.SetStatus(ctx, p.ID, models.PaymentStatusFailed, event.FailureMessage)
+	case payment.EventRefunded:
+		p, err = h.Store.Payments.SetRefunded(ctx, p.ID, event.AmountRefunded)
+		if errors.Is(err, repository.ErrStatusChanged) {
+			return c.NoContent(http.StatusOK)
+		}
+	default:
+		return c.NoContent(http.StatusOK)
+	}
+	if err != nil {
+		c.Logger().Errorf("payment webhook %s: %v", event.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to record payment")
+	}
+
+	if event.Type == payment.EventSucceeded && p.Purpose == models.PaymentPurposeRent {
+		if _, err := billing.PostPayment(ctx, h.Store.Ledger, *p, h.Location); err != nil {
+			c.Logger().Errorf("post payment %s: %v", p.IntentID, err)
+			return c.String(http.StatusInternalServerError, "Failed to record payment")
+		}
+	}
+	if event.Type == payment.EventSucceeded && p.Purpose == models.PaymentPurposeApplicationFee && p.ApplicationID != nil {
+		if err := h.submitPaidApplication(c, *p.ApplicationID, p); err != nil {
+			c.Logger().Errorf("submit application %d: %v", *p.ApplicationID, err)
+			return c.String(http.StatusInternalServerError, "Failed to submit application")
+		}
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// submitPaidApplication submits the draft fee paid for, if it's still a
+// draft that can be submitted. Otherwise the applicant submits it later.
+func (h *Handler) submitPaidApplication(c echo.Context, id int64, fee *models.Payment) error {
+	ctx := c.Request().Context()
+
+	app, err := h.Store.Applications.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if app.Status != models.ApplicationStatusDraft || !app.Data.Complete() {
+		return nil
+	}
+	property, err := h.Store.Properties.GetByID(ctx, app.PropertyID)
+	if err != nil {
+		return err
+	}
+	if !property.Available {
+		return nil
+	}
+	_, err = h.submitApplication(c, app, property, fee)
+	if errors.Is(err, repository.ErrStatusChanged) {
+		return nil
+	}
+	return err
+}
+
+// submitApplication moves a draft to submitted and emails the applicant,
+// with a receipt for its fee if one was paid
+func (h *Handler) submitApplication(c echo.Context, app *models.Application, property *models.Property, fee *models.Payment) (*models.Application, error) {
+	submitted, err := h.Store.Applications.SetStatus(c.Request().Context(), app.ID, models.ApplicationStatusDraft, models.ApplicationStatusSubmitted, app.ApplicantID, "")
+	if err != nil {
+		return nil, err
+	}
+	var receipt string
+	if fee != nil && fee.PaidAt != nil {
+		receipt = fmt.Sprintf("Receipt: we received your %s application fee on %s. Payment reference: %s",
+			models.FormatCents(fee.AmountCents), fee.PaidAt.Format("Jan 2, 2006"), fee.IntentID)
+	}
+	h.sendApplicationStatus(c, submitted, property, receipt)
+	return submitted, nil
+}
+
+// AdminRefundApplicationFee refunds whatever is left of an application's
+// fee and emails the applicant
+func (h *Handler) AdminRefundApplicationFee(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	app, property, err := h.adminApplication(c)
+	if err != nil {
+		return adminApplicationError(c, err)
+	}
+	fee, err := h.applicationFeePayment(ctx, app, property)
+	if err != nil {
+		return adminApplicationError(c, err)
+	}
+	errs := make(map[string]string)
+	switch {
+	case fee == nil || !fee.Paid() || fee.RefundedCents >= fee.AmountCents:
+		errs["refund"] = "There's no application fee left to refund"
+	case fee.Gateway != h.Payments.Name():
+		errs["refund"] = "This fee was paid through " + fee.Gateway + ". Refund it from there."
+	}
+	if len(errs) > 0 {
+		return h.renderApplicationWorkflow(c, http.StatusUnprocessableEntity, app, property, errs, false)
+	}
+
+	refund, err := h.Payments.Refund(ctx, fee.IntentID, 0)
+	if err != nil {
+		c.Logger().Errorf("refund payment %s: %v", fee.IntentID, err)
+		errs["refund"] = "The payment gateway couldn't refund this fee. Please try again."
+		return h.renderApplicationWorkflow(c, http.StatusUnprocessableEntity, app, property, errs, false)
+	}
+	if _, err := h.Store.Payments.SetRefunded(ctx, fee.ID, fee.RefundedCents+refund.Amount); err != nil {
+		return adminApplicationError(c, err)
+	}
+
+	err = h.Mailer.Send(ctx, mailer.Message{
+		To:      app.Data.Applicant.Email,
+		Subject: "Your application fee for " + property.Title + " has been refunded",
+		Body: fmt.Sprintf(`Hi %s,
+
+We've refunded %s of your application fee for %s. Depending on your bank, it can take 5-10 business days to appear on your statement.
+
+Payment reference: %s
+`, app.Data.Applicant.FirstName, models.FormatCents(refund.Amount), property.Title, fee.IntentID),
+	})
+	if err != nil {
+		c.Logger().Warnf("email refund of application %d: %v", app.ID, err)
+	}
+	return h.renderApplicationWorkflow(c, http.StatusOK, app, property, nil, false)
+}
+
+// applicationFeePayment finds the payment that settles app's fee: a paid
+// one, else an unpaid attempt for the current fee that can be retried. It
+// returns nil when neither exists.
+func (h *Handler) applicationFeePayment(ctx context.Context, app *models.Application, property *models.Property) (*models.Payment, error) {
+	payments, err := h.Store.Payments.ListByApplication(ctx, app.ID)
+	if err != nil {
+		return nil, err
+	}
+	var retry *models.Payment
+	for i, p := range payments {
+		if p.Purpose != models.PaymentPurposeApplicationFee {
+			continue
+		}
+		if p.Paid() {
+			return &payments[i], nil
+		}
+		if retry == nil && p.Gateway == h.Payments.Name() && p.AmountCents == applicationFeeCents(property) {
+			retry = &payments[i]
+		}
+	}
+	return retry, nil
+}
+
+// startApplicationFee starts collecting app's fee with the gateway and
+// records the payment. Each start is a new attempt: unpaid ones are picked
+// up again by applicationFeePayment instead.
+func (h *Handler) startApplicationFee(ctx context.Context, app *models.Application, property *models.Property) (*models.Payment, error) {
+	amount := applicationFeeCents(property)
+	intent, err := h.Payments.CreateIntent(ctx, payment.IntentRequest{
+		Amount:      amount,
+		Description: "Application fee for " + property.Title,
+		Metadata: map[string]string{
+			"application_id": strconv.FormatInt(app.ID, 10),
+			"purpose":        string(models.PaymentPurposeApplicationFee),
+		},
+		IdempotencyKey: fmt.Sprintf("application-%d-fee-%s", app.ID, newPaymentAttempt()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	p := &models.Payment{
+		Gateway:       h.Payments.Name(),
+		IntentID:      intent.ID,
+		Purpose:       models.PaymentPurposeApplicationFee,
+		ApplicationID: &app.ID,
+		PayerID:       app.ApplicantID,
+		AmountCents:   amount,
+		Currency:      intent.Currency,
+	}
+	if err := h.Store.Payments.Create(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// paymentCheckout describes how the payment form collects cards from the
+// configured gateway. Each form it renders starts a new attempt.
+func (h *Handler) paymentCheckout(message string) pages.PaymentCheckout {
+	checkout := pages.PaymentCheckout{Driver: h.Payments.Name(), Message: message, Attempt: newPaymentAttempt()}
+	if stripe, ok := h.Payments.(*payment.StripeGateway); ok {
+		checkout.PublishableKey = stripe.PublishableKey()
+	}
+	return checkout
+}
+
+// paymentAttempt is the attempt a payment form was rendered for, or a new
+// one if it didn't post a valid one
+func paymentAttempt(c echo.Context) string {
+	attempt := c.FormValue("attempt")
+	if b, err := hex.DecodeString(attempt); err != nil || len(b) != paymentAttemptSize {
+		return newPaymentAttempt()
+	}
+	return attempt
+}
+
+// newPaymentAttempt returns a random key for one attempt at a payment
+func newPaymentAttempt() string {
+	b := make([]byte, paymentAttemptSize)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// paidFee drops unpaid attempts returned by applicationFeePayment
+func paidFee(fee *models.Payment) *models.Payment {
+	if fee == nil || !fee.Paid() {
+		return nil
+	}
+	return fee
+}
+
+// applicationFeeCents is property's application fee in cents
+func applicationFeeCents(property *models.Property) int64 {
+	return int64(property.ApplicationFee) * 100
+}
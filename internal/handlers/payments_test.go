@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/payment"
+)
+
+// feeApplication starts a complete draft application for a property with
+// a $50 application fee
+func feeApplication(t *testing.T) (*Handler, *payment.FakeGateway, *models.Application) {
+	t.Helper()
+	h, gateway, _ := testHandler(t, models.Property{ID: 1, Slug: "maple", Title: "Maple House", Available: true, ApplicationFee: 50})
+	app, err := h.Store.Applications.StartDraft(context.Background(), 1, "applicant1", models.ApplicationData{
+		Applicant: models.Applicant{FirstName: "Ada", LastName: "Lane", Email: "ada@example.com"},
+		Completed: slices.Clone(models.ApplicationSteps),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h, gateway, app
+}
+
+// applicationStatus is the application's status as stored
+func applicationStatus(t *testing.T, h *Handler, id int64) models.ApplicationStatus {
+	t.Helper()
+	app, err := h.Store.Applications.Get(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return app.Status
+}
+
+// feePayments lists the application fee payments recorded for id
+func feePayments(t *testing.T, h *Handler, id int64) []models.Payment {
+	t.Helper()
+	payments, err := h.Store.Payments.ListByApplication(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payments
+}
+
+func TestPayApplicationSubmitsOnlyOncePaid(t *testing.T) {
+	h, _, app := feeApplication(t)
+	id := strconv.FormatInt(app.ID, 10)
+	pay := func(form url.Values) *httptest.ResponseRecorder {
+		return serve(t, h.PayApplication, http.MethodPost, "applicant1", form, "id", id)
+	}
+
+	wantStatus(t, serve(t, h.ApplicationPayment, http.MethodGet, "applicant1", nil, "id", id), http.StatusOK)
+	if got := applicationStatus(t, h, app.ID); got != models.ApplicationStatusDraft {
+		t.Fatalf("status after starting payment = %q, want draft", got)
+	}
+
+	wantStatus(t, pay(url.Values{}), http.StatusUnprocessableEntity)
+	if got := applicationStatus(t, h, app.ID); got != models.ApplicationStatusDraft {
+		t.Fatalf("status without a card = %q, want draft", got)
+	}
+
+	wantStatus(t, pay(url.Values{"payment_method": {payment.FakeCardDeclined}}), http.StatusUnprocessableEntity)
+	if got := applicationStatus(t, h, app.ID); got != models.ApplicationStatusDraft {
+		t.Fatalf("status after a declined card = %q, want draft", got)
+	}
+	if fees := feePayments(t, h, app.ID); len(fees) != 1 || fees[0].Status != models.PaymentStatusFailed {
+		t.Fatalf("fees after a declined card = %+v, want one failed", fees)
+	}
+
+	rec := pay(url.Values{"payment_method": {payment.FakeCardSuccess}})
+	wantStatus(t, rec, http.StatusNoContent)
+	if got := rec.Header().Get("HX-Redirect"); got != "/applications/"+id {
+		t.Errorf("HX-Redirect = %q, want the application", got)
+	}
+	if got := applicationStatus(t, h, app.ID); got != models.ApplicationStatusSubmitted {
+		t.Fatalf("status after paying = %q, want submitted", got)
+	}
+	if fees := feePayments(t, h, app.ID); len(fees) != 1 || !fees[0].Paid() {
+		t.Errorf("fees after paying = %+v, want the retried attempt paid", fees)
+	}
+}
+
+func TestAbandonedApplicationFeeLeavesDraft(t *testing.T) {
+	h, gateway, app := feeApplication(t)
+	id := strconv.FormatInt(app.ID, 10)
+
+	// The applicant reaches the payment page, then leaves without paying
+	wantStatus(t, serve(t, h.ApplicationPayment, http.MethodGet, "applicant1", nil, "id", id), http.StatusOK)
+	fees := feePayments(t, h, app.ID)
+	if len(fees) != 1 {
+		t.Fatalf("recorded %d fee payments, want 1", len(fees))
+	}
+	intentID := fees[0].IntentID
+
+	webhook := func(event payment.Event) {
+		t.Helper()
+		payload, err := json.Marshal(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(payload)))
+		req.Header.Set(payment.FakeSignatureHeader, gateway.SignWebhook(payload))
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		if err := h.PaymentWebhook(c); err != nil {
+			t.Fatal(err)
+		}
+		wantStatus(t, rec, http.StatusOK)
+	}
+
+	if got := applicationStatus(t, h, app.ID); got != models.ApplicationStatusDraft {
+		t.Fatalf("status of an abandoned payment = %q, want draft", got)
+	}
+	webhook(payment.Event{ID: "evt_1", Type: payment.EventFailed, IntentID: intentID, FailureMessage: "Your card was declined."})
+	if got := applicationStatus(t, h, app.ID); got != models.ApplicationStatusDraft {
+		t.Fatalf("status after a failed payment = %q, want draft", got)
+	}
+
+	// Coming back later picks up the same attempt rather than starting
+	// another, and the application is only submitted once it's paid
+	wantStatus(t, serve(t, h.ApplicationPayment, http.MethodGet, "applicant1", nil, "id", id), http.StatusOK)
+	if fees := feePayments(t, h, app.ID); len(fees) != 1 {
+		t.Fatalf("recorded %d fee payments after returning, want 1", len(fees))
+	}
+	webhook(payment.Event{ID: "evt_2", Type: payment.EventSucceeded, IntentID: intentID})
+	if got := applicationStatus(t, h, app.ID); got != models.ApplicationStatusSubmitted {
+		t.Errorf("status after the payment cleared = %q, want submitted", got)
+	}
+}
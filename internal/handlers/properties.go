@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -8,6 +10,7 @@ import (
 
 	"russ-rentals/internal/middleware"
 	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
 	"russ-rentals/templates/components"
 	"russ-rentals/templates/pages"
 )
@@ -21,7 +24,11 @@ func (h *Handler) Properties(c echo.Context) error {
 	maxPrice := c.QueryParam("maxPrice")
 	bedrooms := c.QueryParam("bedrooms")
 
-	properties := h.getFilteredProperties(propertyType, minPrice, maxPrice, bedrooms)
+	properties, err := h.getFilteredProperties(c.Request().Context(), propertyType, minPrice, maxPrice, bedrooms)
+	if err != nil {
+		c.Logger().Errorf("filter properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load properties")
+	}
 
 	return Render(c, http.StatusOK, pages.Properties(properties, isAuth, propertyType, minPrice, maxPrice, bedrooms))
 }
@@ -33,7 +40,11 @@ func (h *Handler) FilterProperties(c echo.Context) error {
 	maxPrice := c.QueryParam("maxPrice")
 	bedrooms := c.QueryParam("bedrooms")
 
-	properties := h.getFilteredProperties(propertyType, minPrice, maxPrice, bedrooms)
+	properties, err := h.getFilteredProperties(c.Request().Context(), propertyType, minPrice, maxPrice, bedrooms)
+	if err != nil {
+		c.Logger().Errorf("filter properties: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load properties")
+	}
 
 	// Return just the grid for HTMX swap
 	return Render(c, http.StatusOK, components.PropertyGrid(properties))
@@ -43,7 +54,11 @@ func (h *Handler) PropertyDetail(c echo.Context) error {
 	slug := c.Param("slug")
 	isAuth := middleware.IsAuthenticated(c)
 
-	property := h.getPropertyBySlug(slug)
+	property, err := h.getPropertyBySlug(c.Request().Context(), slug)
+	if err != nil {
+		c.Logger().Errorf("get property %s: %v", slug, err)
+		return c.String(http.StatusInternalServerError, "Failed to load property")
+	}
 	if property == nil {
 		return c.String(http.StatusNotFound, "Property not found")
 	}
@@ -56,7 +71,11 @@ func (h *Handler) PropertyGallery(c echo.Context) error {
 	room := c.QueryParam("room")
 	indexStr := c.QueryParam("index")
 
-	property := h.getPropertyBySlug(slug)
+	property, err := h.getPropertyBySlug(c.Request().Context(), slug)
+	if err != nil {
+		c.Logger().Errorf("get property %s: %v", slug, err)
+		return c.String(http.StatusInternalServerError, "Failed to load property")
+	}
 	if property == nil {
 		return c.String(http.StatusNotFound, "Property not found")
 	}
@@ -70,7 +89,11 @@ func (h *Handler) PropertyGallery(c echo.Context) error {
 
 	images := property.Images
 	if room != "" && room != "all" {
-		images = property.ImagesByRoom(models.RoomType(room))
+		images, err = h.Store.Properties.ImagesByRoom(c.Request().Context(), property.ID, models.RoomType(room))
+		if err != nil {
+			c.Logger().Errorf("images for %s: %v", slug, err)
+			return c.String(http.StatusInternalServerError, "Failed to load images")
+		}
 	}
 
 	if index >= len(images) {
@@ -80,72 +103,47 @@ func (h *Handler) PropertyGallery(c echo.Context) error {
 	return Render(c, http.StatusOK, components.GalleryContent(images, index, property.Title, slug))
 }
 
-// Helper functions - these will be replaced with database queries
-func (h *Handler) getFilteredProperties(propertyType, minPrice, maxPrice, bedrooms string) []models.Property {
-	properties := GetSampleProperties()
+// featuredLimit caps how many featured properties the home page and carousel show
+const featuredLimit = 10
 
-	var filtered []models.Property
-	for _, p := range properties {
-		// Filter by type
-		if propertyType != "" && string(p.Type) != propertyType {
-			continue
-		}
-
-		// Filter by min price
-		if minPrice != "" {
-			if min, err := strconv.Atoi(minPrice); err == nil && p.Price < min {
-				continue
-			}
-		}
-
-		// Filter by max price
-		if maxPrice != "" {
-			if max, err := strconv.Atoi(maxPrice); err == nil && p.Price > max {
-				continue
-			}
-		}
+func (h *Handler) getFilteredProperties(ctx context.Context, propertyType, minPrice, maxPrice, bedrooms string) ([]models.Property, error) {
+	filter := repository.PropertyFilter{Type: propertyType}
 
-		// Filter by bedrooms
-		if bedrooms != "" {
-			if beds, err := strconv.Atoi(bedrooms); err == nil && p.Bedrooms < beds {
-				continue
-			}
-		}
-
-		filtered = append(filtered, p)
+	// Invalid numbers are ignored rather than rejected
+	if min, err := strconv.Atoi(minPrice); err == nil {
+		filter.MinPrice = min
+	}
+	if max, err := strconv.Atoi(maxPrice); err == nil {
+		filter.MaxPrice = max
+	}
+	if beds, err := strconv.Atoi(bedrooms); err == nil {
+		filter.MinBedrooms = beds
 	}
 
-	return filtered
+	return h.Store.Properties.Filter(ctx, filter)
 }
 
-func (h *Handler) getPropertyBySlug(slug string) *models.Property {
-	properties := GetSampleProperties()
-	for _, p := range properties {
-		if p.Slug == slug {
-			return &p
-		}
+func (h *Handler) getPropertyBySlug(ctx context.Context, slug string) (*models.Property, error) {
+	property, err := h.Store.Properties.GetBySlug(ctx, slug)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
 	}
-	return nil
+	return property, err
 }
 
-func (h *Handler) getFeaturedProperties() []models.Property {
-	properties := GetSampleProperties()
-	var featured []models.Property
-	for _, p := range properties {
-		if p.Featured && p.Available {
-			featured = append(featured, p)
-		}
+func (h *Handler) getFeaturedProperties(ctx context.Context) ([]models.Property, error) {
+	featured, err := h.Store.Properties.Featured(ctx, featuredLimit)
+	if err != nil || len(featured) > 0 {
+		return featured, err
 	}
-	if len(featured) == 0 {
-		// Return first 3 available properties if no featured ones
-		for _, p := range properties {
-			if p.Available {
-				featured = append(featured, p)
-				if len(featured) >= 3 {
-					break
-				}
-			}
-		}
+
+	// Return first 3 available properties if no featured ones
+	available, err := h.Store.Properties.ListAvailable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(available) > 3 {
+		available = available[:3]
 	}
-	return featured
+	return available, nil
 }
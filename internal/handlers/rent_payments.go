@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/billing"
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/payment"
+	"russ-rentals/internal/repository"
+	"russ-rentals/templates/pages"
+)
+
+// PayRent shows the tenant's balance with a form to pay it, or any other
+// amount, and their autopay settings
+func (h *Handler) PayRent(c echo.Context) error {
+	ctx := c.Request().Context()
+	lease, ledger, err := h.payableLease(c)
+	if err != nil {
+		c.Logger().Errorf("Failed to load lease: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load payments")
+	}
+	if lease == nil {
+		return c.Redirect(http.StatusSeeOther, "/dashboard")
+	}
+	property, err := h.Store.Properties.GetByID(ctx, lease.PropertyID)
+	if err != nil {
+		c.Logger().Errorf("Failed to load property %d: %v", lease.PropertyID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load payments")
+	}
+	autopay, err := h.leaseAutopay(ctx, lease.ID)
+	if err != nil {
+		c.Logger().Errorf("Failed to load autopay for lease %d: %v", lease.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load payments")
+	}
+
+	today := models.Date(h.now())
+	return Render(c, http.StatusOK, pages.PayRent(*lease, *property, ledger.Summary(today), autopay,
+		middleware.GetUserID(c), h.paymentCheckout(""), today))
+}
+
+// SubmitRentPayment charges the amount the tenant entered to the card the
+// browser collected and posts it to their ledger once it goes through. A
+// "check" field instead asks the gateway how an attempt the tenant
+// finished with it directly, such as a 3-D Secure check, turned out.
+func (h *Handler) SubmitRentPayment(c echo.Context) error {
+	ctx := c.Request().Context()
+	lease, ledger, err := h.payableLease(c)
+	if err != nil {
+		c.Logger().Errorf("Failed to load lease: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to process payment")
+	}
+	if lease == nil {
+		c.Response().Header().Set("HX-Redirect", "/dashboard")
+		return c.NoContent(http.StatusNoContent)
+	}
+	summary := ledger.Summary(h.now())
+	userID := middleware.GetUserID(c)
+
+	if c.FormValue("check") != "" {
+		id, _ := strconv.ParseInt(c.FormValue("payment"), 10, 64)
+		p, err := h.Store.Payments.Get(ctx, id)
+		if errors.Is(err, repository.ErrNotFound) || (err == nil && (p.LeaseID == nil || *p.LeaseID != lease.ID || p.PayerID != userID)) {
+			return c.String(http.StatusNotFound, "Payment not found")
+		}
+		if err != nil {
+			c.Logger().Errorf("Failed to load payment %d: %v", id, err)
+			return c.String(http.StatusInternalServerError, "Failed to process payment")
+		}
+		return h.confirmRentPayment(c, lease, summary, p, "")
+	}
+
+	errs := make(map[string]string)
+	f := propertyForm{c, errs}
+	amount := f.cents("amount", "Amount", true)
+	method := strings.TrimSpace(c.FormValue("payment_method"))
+	most := max(summary.BalanceCents, 0) + lease.MonthlyTotalCents()
+	if _, failed := errs["amount"]; !failed && amount > most {
+		errs["amount"] = "You can pay up to " + models.FormatCents(most) + ": your balance plus a month's rent"
+	}
+	if method == "" {
+		errs["payment_method"] = "Enter your card details"
+	}
+	if len(errs) > 0 {
+		return Render(c, http.StatusUnprocessableEntity, pages.RentPaymentForm(*lease, summary, c.FormValue("amount"), 0, errs, h.paymentCheckout("")))
+	}
+
+	p, err := h.startRentPayment(ctx, lease, userID, amount, paymentAttempt(c))
+	if err != nil {
+		c.Logger().Errorf("Failed to start payment for lease %d: %v", lease.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to process payment")
+	}
+	return h.confirmRentPayment(c, lease, summary, p, method)
+}
+
+// EnrollAutopay saves the card the browser collected and the day of the
+// month to charge it, replacing the tenant's earlier autopay settings. If
+// this month's charge day has passed, autopay starts next month.
+func (h *Handler) EnrollAutopay(c echo.Context) error {
+	ctx := c.Request().Context()
+	lease, _, err := h.payableLease(c)
+	if err != nil {
+		c.Logger().Errorf("Failed to load lease: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to save autopay")
+	}
+	if lease == nil {
+		c.Response().Header().Set("HX-Redirect", "/dashboard")
+		return c.NoContent(http.StatusNoContent)
+	}
+	existing, err := h.leaseAutopay(ctx, lease.ID)
+	if err != nil {
+		c.Logger().Errorf("Failed to load autopay for lease %d: %v", lease.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to save autopay")
+	}
+
+	userID := middleware.GetUserID(c)
+	today := models.Date(h.now())
+	checkout := h.paymentCheckout("")
+	errs := make(map[string]string)
+	f := propertyForm{c, errs}
+	chargeDay := f.integer("chargeDay", "Charge day", 1, models.AutopayMaxChargeDay)
+	method := strings.TrimSpace(c.FormValue("payment_method"))
+	switch {
+	case lease.Status != models.LeaseStatusActive:
+		errs["autopay"] = "Autopay is only available while your lease is active"
+	case existing != nil && existing.PayerID != userID:
+		errs["autopay"] = "A co-tenant already pays this lease by autopay"
+	case method == "":
+		errs["payment_method"] = "Enter your card details"
+	}
+	if len(errs) > 0 {
+		return Render(c, http.StatusUnprocessableEntity, pages.AutopayPanel(*lease, existing, userID, errs, checkout, today))
+	}
+
+	tenant, _ := lease.Tenant(userID)
+	saved, err := h.Payments.SaveMethod(ctx, payment.SaveMethodRequest{
+		PaymentMethod: method,
+		Email:         tenant.Email,
+		Name:          tenant.Name,
+		Metadata:      map[string]string{"lease_id": strconv.FormatInt(lease.ID, 10)},
+	})
+	if err != nil {
+		c.Logger().Warnf("save autopay card for lease %d: %v", lease.ID, err)
+		errs["payment_method"] = "We couldn't save this card. Please check its details or try another card."
+		return Render(c, http.StatusUnprocessableEntity, pages.AutopayPanel(*lease, existing, userID, errs, checkout, today))
+	}
+
+	a := &models.Autopay{
+		LeaseID:     lease.ID,
+		PayerID:     userID,
+		PayerEmail:  tenant.Email,
+		Gateway:     h.Payments.Name(),
+		CustomerID:  saved.CustomerID,
+		MethodID:    saved.ID,
+		MethodLabel: saved.Label,
+		ChargeDay:   chargeDay,
+	}
+	// Don't charge again in a month that's been charged or whose charge
+	// day has passed
+	period := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if today.Day() >= chargeDay || (existing != nil && existing.Period != nil && !existing.Period.Before(period)) {
+		a.Period = &period
+	}
+	if err := h.Store.Autopay.Enroll(ctx, a); err != nil {
+		c.Logger().Errorf("Failed to save autopay for lease %d: %v", lease.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to save autopay")
+	}
+	return Render(c, http.StatusOK, pages.AutopayPanel(*lease, a, userID, nil, checkout, today))
+}
+
+// CancelAutopay turns off the tenant's autopay. Only whoever set it up can.
+func (h *Handler) CancelAutopay(c echo.Context) error {
+	ctx := c.Request().Context()
+	lease, _, err := h.payableLease(c)
+	if err != nil {
+		c.Logger().Errorf("Failed to load lease: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to cancel autopay")
+	}
+	if lease == nil {
+		c.Response().Header().Set("HX-Redirect", "/dashboard")
+		return c.NoContent(http.StatusNoContent)
+	}
+	existing, err := h.leaseAutopay(ctx, lease.ID)
+	if err != nil {
+		c.Logger().Errorf("Failed to load autopay for lease %d: %v", lease.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to cancel autopay")
+	}
+
+	userID := middleware.GetUserID(c)
+	today := models.Date(h.now())
+	if existing != nil && existing.PayerID != userID {
+		errs := map[string]string{"autopay": "Only the co-tenant who set up autopay can turn it off"}
+		return Render(c, http.StatusUnprocessableEntity, pages.AutopayPanel(*lease, existing, userID, errs, h.paymentCheckout(""), today))
+	}
+	if existing != nil {
+		if err := h.Store.Autopay.Cancel(ctx, lease.ID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			c.Logger().Errorf("Failed to cancel autopay for lease %d: %v", lease.ID, err)
+			return c.String(http.StatusInternalServerError, "Failed to cancel autopay")
+		}
+	}
+	return Render(c, http.StatusOK, pages.AutopayPanel(*lease, nil, userID, nil, h.paymentCheckout(""), today))
+}
+
+// confirmRentPayment charges p with paymentMethod, or checks on it when
+// paymentMethod is empty, and shows the outcome
+func (h *Handler) confirmRentPayment(c echo.Context, lease *models.Lease, summary models.LedgerSummary, p *models.Payment, paymentMethod string) error {
+	ctx := c.Request().Context()
+	checkout := h.paymentCheckout("")
+	amount := csvCents(p.AmountCents)
+
+	intent, err := h.Payments.Confirm(ctx, p.IntentID, paymentMethod)
+	if err != nil {
+		c.Logger().Errorf("confirm payment %s: %v", p.IntentID, err)
+		return c.String(http.StatusInternalServerError, "Failed to process payment")
+	}
+	switch intent.Status {
+	case payment.StatusSucceeded:
+		if p, err = h.Store.Payments.SetStatus(ctx, p.ID, models.PaymentStatusSucceeded, ""); err != nil {
+			break
+		}
+		if _, err = billing.PostPayment(ctx, h.Store.Ledger, *p, h.Location); err != nil {
+			break
+		}
+		h.sendRentReceipt(c, lease, p)
+		return Render(c, http.StatusOK, pages.RentPaymentReceipt(*p))
+	case payment.StatusFailed, payment.StatusCanceled:
+		message := intent.FailureMessage
+		if message == "" {
+			message = "Your payment didn't go through. Please try another card."
+		}
+		if p, err = h.Store.Payments.SetStatus(ctx, p.ID, models.PaymentStatusFailed, message); err == nil {
+			checkout.Message = message
+			return Render(c, http.StatusUnprocessableEntity, pages.RentPaymentForm(*lease, summary, amount, 0, nil, checkout))
+		}
+	case payment.StatusRequiresAction:
+		checkout.ClientSecret = intent.ClientSecret
+		return Render(c, http.StatusOK, pages.RentPaymentForm(*lease, summary, amount, p.ID, nil, checkout))
+	default:
+		// The webhook posts the payment to the ledger once it clears
+		checkout.Processing = true
+		return Render(c, http.StatusOK, pages.RentPaymentForm(*lease, summary, amount, p.ID, nil, checkout))
+	}
+	c.Logger().Errorf("record payment %s: %v", intent.ID, err)
+	return c.String(http.StatusInternalServerError, "Failed to process payment")
+}
+
+// startRentPayment starts collecting amount towards lease's balance with
+// the gateway and records the payment. A resubmitted attempt gets back the
+// payment it already started.
+func (h *Handler) startRentPayment(ctx context.Context, lease *models.Lease, payerID string, amount int64, attempt string) (*models.Payment, error) {
+	intent, err := h.Payments.CreateIntent(ctx, payment.IntentRequest{
+		Amount:      amount,
+		Description: fmt.Sprintf("Rent payment for lease %d", lease.ID),
+		Metadata: map[string]string{
+			"lease_id": strconv.FormatInt(lease.ID, 10),
+			"purpose":  string(models.PaymentPurposeRent),
+		},
+		IdempotencyKey: billing.RentIdempotencyKey(lease.ID, attempt),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if p, err := h.Store.Payments.GetByIntent(ctx, intent.ID); !errors.Is(err, repository.ErrNotFound) {
+		return p, err
+	}
+	p := &models.Payment{
+		Gateway:     h.Payments.Name(),
+		IntentID:    intent.ID,
+		Purpose:     models.PaymentPurposeRent,
+		LeaseID:     &lease.ID,
+		PayerID:     payerID,
+		AmountCents: amount,
+		Currency:    intent.Currency,
+	}
+	if err := h.Store.Payments.Create(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// sendRentReceipt emails the tenant who paid a receipt. Failures are only
+// logged: the payment went through regardless.
+func (h *Handler) sendRentReceipt(c echo.Context, lease *models.Lease, p *models.Payment) {
+	tenant, ok := lease.Tenant(p.PayerID)
+	if !ok || tenant.Email == "" {
+		return
+	}
+	err := h.Mailer.Send(c.Request().Context(), mailer.Message{
+		To:      tenant.Email,
+		Subject: "Receipt for your " + models.FormatCents(p.AmountCents) + " rent payment",
+		Body: fmt.Sprintf(`Hi %s,
+
+We received your payment of %s on %s. Thank you!
+
+Payment reference: %s
+
+Your ledger: %s
+`, tenant.Name, models.FormatCents(p.AmountCents), p.PaidAt.Format("January 2, 2006"), p.IntentID, h.absoluteURL("/dashboard/ledger")),
+	})
+	if err != nil {
+		c.Logger().Warnf("email receipt for payment %s: %v", p.IntentID, err)
+	}
+}
+
+// payableLease returns the tenant's lease and its ledger if it's one they
+// can pay towards, an active or ended lease, or nil if they have none
+func (h *Handler) payableLease(c echo.Context) (*models.Lease, models.Ledger, error) {
+	ctx := c.Request().Context()
+	lease, err := h.tenantLease(ctx, middleware.GetUserID(c))
+	if err != nil || lease == nil {
+		return nil, nil, err
+	}
+	if lease.Status != models.LeaseStatusActive && lease.Status != models.LeaseStatusEnded {
+		return nil, nil, nil
+	}
+	ledger, err := h.leaseLedger(ctx, lease)
+	if err != nil {
+		return nil, nil, err
+	}
+	return lease, ledger, nil
+}
+
+// leaseAutopay returns a lease's autopay enrollment, or nil if it has none
+func (h *Handler) leaseAutopay(ctx context.Context, leaseID int64) (*models.Autopay, error) {
+	a, err := h.Store.Autopay.GetByLease(ctx, leaseID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	return a, err
+}
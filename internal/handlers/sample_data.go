@@ -7,7 +7,7 @@ import (
 )
 
 // GetSampleProperties returns the sample property data
-// This will be replaced with database queries once the DB is set up
+// It seeds the memory store and the database via `server seed`
 func GetSampleProperties() []models.Property {
 	availableDate1 := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
 	availableDate2 := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+	"russ-rentals/internal/schedule"
+	"russ-rentals/internal/token"
+	"russ-rentals/templates/components"
+	"russ-rentals/templates/pages"
+)
+
+const (
+	showingConfirmPurpose = "showing-confirm"
+	showingCancelPurpose  = "showing-cancel"
+	// showingHorizon is how far ahead visitors can book a showing
+	showingHorizon = 14 * 24 * time.Hour
+	// showingNotice is the least notice a visitor can book a showing with
+	showingNotice = 2 * time.Hour
+)
+
+// ShowingSlots lists open showing times on the contact form once a visitor
+// picks a property and asks for a viewing
+func (h *Handler) ShowingSlots(c echo.Context) error {
+	ctx := c.Request().Context()
+	slug := c.QueryParam("property")
+	if slug == "" || c.QueryParam("inquiryType") != string(models.InquiryTypeViewing) {
+		return Render(c, http.StatusOK, components.ShowingSlots(nil, false))
+	}
+
+	property, err := h.Store.Properties.GetBySlug(ctx, slug)
+	if errors.Is(err, repository.ErrNotFound) {
+		return Render(c, http.StatusOK, components.ShowingSlots(nil, false))
+	}
+	if err != nil {
+		c.Logger().Errorf("get property %s: %v", slug, err)
+		return c.String(http.StatusInternalServerError, "Failed to load showing times")
+	}
+
+	slots, err := h.openSlots(ctx, property.ID)
+	if err != nil {
+		c.Logger().Errorf("open slots for property %d: %v", property.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load showing times")
+	}
+	return Render(c, http.StatusOK, components.ShowingSlots(schedule.ByDay(slots, h.Location), true))
+}
+
+// openSlots lists the showing slots of propertyID that can still be booked
+func (h *Handler) openSlots(ctx context.Context, propertyID int64) ([]schedule.Slot, error) {
+	now := time.Now()
+	until := now.Add(showingHorizon)
+
+	windows, err := h.Store.Showings.Windows(ctx, propertyID, now)
+	if err != nil || len(windows) == 0 {
+		return nil, err
+	}
+	var agents []string
+	for _, w := range windows {
+		if !slices.Contains(agents, w.AgentID) {
+			agents = append(agents, w.AgentID)
+		}
+	}
+	busy, err := h.Store.Showings.Busy(ctx, propertyID, agents, now, until)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := schedule.OpenSlots(propertyID, windows, busy, now.Add(showingNotice))
+	return slices.DeleteFunc(slots, func(s schedule.Slot) bool { return s.Start.After(until) }), nil
+}
+
+// bookShowing holds slot for the visitor behind sub and emails them links to
+// confirm or cancel. The booking is cancelled again if the email fails.
+func (h *Handler) bookShowing(c echo.Context, sub *models.ContactSubmission, property *models.Property, slot schedule.Slot) (*models.Showing, error) {
+	ctx := c.Request().Context()
+	showing := models.Showing{
+		PropertyID: property.ID,
+		AgentID:    slot.AgentID,
+		InquiryID:  &sub.ID,
+		Name:       sub.Name,
+		Email:      sub.Email,
+		Phone:      sub.Phone,
+		StartsAt:   slot.Start,
+		EndsAt:     slot.End,
+	}
+	if err := h.Store.Showings.Book(ctx, &showing); err != nil {
+		return nil, err
+	}
+
+	if err := h.sendShowingRequested(c, &showing, property); err != nil {
+		if _, cancelErr := h.Store.Showings.SetStatus(ctx, showing.ID, models.ShowingStatusCancelled); cancelErr != nil {
+			c.Logger().Errorf("release showing %d: %v", showing.ID, cancelErr)
+		}
+		return nil, err
+	}
+	return &showing, nil
+}
+
+// ConfirmShowing confirms a pending showing from the visitor's emailed link
+func (h *Handler) ConfirmShowing(c echo.Context) error {
+	isAuth := middleware.IsAuthenticated(c)
+
+	showing, property, err := h.showingFromToken(c, c.QueryParam("token"), showingConfirmPurpose)
+	if err != nil {
+		return h.showingTokenError(c, err)
+	}
+
+	switch showing.Status {
+	case models.ShowingStatusConfirmed:
+		return Render(c, http.StatusOK, pages.ShowingStatus("Already Confirmed", "Your showing "+h.showingWhen(showing)+" is already confirmed.", true, isAuth))
+	case models.ShowingStatusCancelled:
+		return Render(c, http.StatusOK, pages.ShowingStatus("Showing Cancelled", "This showing was cancelled. Please book another time from our contact page.", false, isAuth))
+	}
+
+	showing, err = h.Store.Showings.SetStatus(c.Request().Context(), showing.ID, models.ShowingStatusConfirmed)
+	if err != nil {
+		c.Logger().Errorf("confirm showing: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to confirm showing")
+	}
+	h.notifyAgent(c, showing, property, "Showing confirmed")
+
+	return Render(c, http.StatusOK, pages.ShowingStatus("Showing Confirmed", fmt.Sprintf("See you %s at %s.", h.showingWhen(showing), property.Address), true, isAuth))
+}
+
+// CancelShowingPage asks the visitor to confirm before cancelling, so link
+// scanners in mail clients can't cancel showings
+func (h *Handler) CancelShowingPage(c echo.Context) error {
+	isAuth := middleware.IsAuthenticated(c)
+	tok := c.QueryParam("token")
+
+	showing, property, err := h.showingFromToken(c, tok, showingCancelPurpose)
+	if err != nil {
+		return h.showingTokenError(c, err)
+	}
+	if !showing.Active() {
+		return Render(c, http.StatusOK, pages.ShowingStatus("Showing Cancelled", "This showing has already been cancelled.", true, isAuth))
+	}
+	return Render(c, http.StatusOK, pages.ShowingCancel(*showing, *property, tok, isAuth))
+}
+
+func (h *Handler) CancelShowing(c echo.Context) error {
+	isAuth := middleware.IsAuthenticated(c)
+
+	showing, property, err := h.showingFromToken(c, c.FormValue("token"), showingCancelPurpose)
+	if err != nil {
+		return h.showingTokenError(c, err)
+	}
+
+	if showing.Active() {
+		showing, err = h.Store.Showings.SetStatus(c.Request().Context(), showing.ID, models.ShowingStatusCancelled)
+		if err != nil {
+			c.Logger().Errorf("cancel showing: %v", err)
+			return c.String(http.StatusInternalServerError, "Failed to cancel showing")
+		}
+		h.notifyAgent(c, showing, property, "Showing cancelled")
+	}
+
+	return Render(c, http.StatusOK, pages.ShowingStatus("Showing Cancelled", "Your showing has been cancelled. You're welcome to book another time.", true, isAuth))
+}
+
+// showingFromToken verifies a tokenized showing link and loads the showing
+// and its property
+func (h *Handler) showingFromToken(c echo.Context, tok, purpose string) (*models.Showing, *models.Property, error) {
+	ctx := c.Request().Context()
+
+	subject, err := h.Tokens.Verify(tok, purpose)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := strconv.ParseInt(subject, 10, 64)
+	if err != nil {
+		return nil, nil, token.ErrInvalid
+	}
+
+	showing, err := h.Store.Showings.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	property, err := h.Store.Properties.GetByID(ctx, showing.PropertyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return showing, property, nil
+}
+
+func (h *Handler) showingTokenError(c echo.Context, err error) error {
+	isAuth := middleware.IsAuthenticated(c)
+	switch {
+	case errors.Is(err, token.ErrExpired):
+		return Render(c, http.StatusBadRequest, pages.ShowingStatus("Link Expired", "This showing has already started or passed, so the link no longer works.", false, isAuth))
+	case errors.Is(err, token.ErrInvalid):
+		return Render(c, http.StatusBadRequest, pages.ShowingStatus("Invalid Link", "This link is invalid. Please check you copied the whole address from the email.", false, isAuth))
+	case errors.Is(err, repository.ErrNotFound):
+		return Render(c, http.StatusNotFound, pages.ShowingStatus("Showing Not Found", "We couldn't find that showing. Please book again from our contact page.", false, isAuth))
+	default:
+		c.Logger().Errorf("load showing: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load showing")
+	}
+}
+
+// showingLinks returns signed links for the visitor to confirm or cancel
+// showing. Both stop working when the showing starts.
+func (h *Handler) showingLinks(c echo.Context, showing *models.Showing) (confirmURL, cancelURL string) {
+	ttl := time.Until(showing.StartsAt)
+	id := strconv.FormatInt(showing.ID, 10)
+	confirmURL = h.absoluteURL("/showings/confirm?token=" + url.QueryEscape(h.Tokens.Sign(showingConfirmPurpose, id, ttl)))
+	cancelURL = h.absoluteURL("/showings/cancel?token=" + url.QueryEscape(h.Tokens.Sign(showingCancelPurpose, id, ttl)))
+	return confirmURL, cancelURL
+}
+
+func (h *Handler) sendShowingRequested(c echo.Context, showing *models.Showing, property *models.Property) error {
+	confirmURL, cancelURL := h.showingLinks(c, showing)
+
+	body := fmt.Sprintf(`Hi %s,
+
+We're holding a showing of %s for you %s.
+
+Please confirm you can make it by opening this link:
+
+%s
+
+The address is %s, %s, %s %s.
+
+Can't make it? Cancel here so someone else can have the time:
+
+%s
+`, showing.Name, property.Title, h.showingWhen(showing), confirmURL,
+		property.Address, property.City, property.State, property.ZipCode, cancelURL)
+
+	return h.Mailer.Send(c.Request().Context(), mailer.Message{
+		To:          showing.Email,
+		Subject:     "Confirm your showing of " + property.Title,
+		Body:        body,
+		Attachments: []mailer.Attachment{h.showingInvite(c, showing, property, false)},
+	})
+}
+
+// notifyAgent emails the showing's agent about a change, if they have an
+// email address on record. Failures are only logged.
+func (h *Handler) notifyAgent(c echo.Context, showing *models.Showing, property *models.Property, subject string) {
+	ctx := c.Request().Context()
+
+	agent, err := h.Store.Users.GetByClerkID(ctx, showing.AgentID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && agent.Email == "") {
+		return
+	}
+	if err != nil {
+		c.Logger().Warnf("look up agent %s: %v", showing.AgentID, err)
+		return
+	}
+
+	body := fmt.Sprintf(`%s: %s
+
+When: %s
+Visitor: %s, %s, %s
+Status: %s
+
+Manage showings: %s
+`, subject, property.Title, h.showingWhen(showing), showing.Name, showing.Email, showing.Phone,
+		showing.Status.Label(), h.absoluteURL("/admin/showings"))
+
+	err = h.Mailer.Send(ctx, mailer.Message{
+		To:          agent.Email,
+		Subject:     subject + ": " + property.Title,
+		Body:        body,
+		Attachments: []mailer.Attachment{h.showingInvite(c, showing, property, true)},
+	})
+	if err != nil {
+		c.Logger().Warnf("notify agent of showing %d: %v", showing.ID, err)
+	}
+}
+
+// showingWhen describes a showing's time for visitors, in the business's
+// time zone
+func (h *Handler) showingWhen(s *models.Showing) string {
+	return "on " + s.StartsAt.In(h.Location).Format("Monday, January 2 at 3:04 PM")
+}
@@ -0,0 +1,607 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/docgen"
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/middleware"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/pdf"
+	"russ-rentals/internal/repository"
+	"russ-rentals/internal/storage"
+	"russ-rentals/internal/token"
+	"russ-rentals/templates/pages"
+)
+
+const (
+	signaturePurpose = "signature"
+	// signatureLinkTTL is how long an emailed signing link works. Tenants
+	// can get a fresh one from their dashboard.
+	signatureLinkTTL = 30 * 24 * time.Hour
+	// maxSignatureDrawing is the largest drawn signature accepted, in bytes
+	// of PNG
+	maxSignatureDrawing = 200 << 10
+	// maxSignatureWidth and maxSignatureHeight bound a drawn signature, in
+	// pixels
+	maxSignatureWidth  = 1200
+	maxSignatureHeight = 600
+	// drawingPrefix starts the data URL a drawn signature is posted as
+	drawingPrefix = "data:image/png;base64,"
+)
+
+// AdminRequestSignatures sends a generated document to each of its
+// parties to sign. Tenants are emailed signing links; the landlord's
+// request goes to the staff member sending it. Sending a lease agreement
+// on a draft lease moves the lease on to awaiting signatures.
+func (h *Handler) AdminRequestSignatures(c echo.Context) error {
+	ctx := c.Request().Context()
+	doc, err := h.adminDocument(c)
+	if err != nil {
+		return documentError(c, err)
+	}
+	if doc.Template == "" {
+		return c.String(http.StatusUnprocessableEntity, "Only generated documents can be sent for signature")
+	}
+	lease, err := h.Store.Leases.Get(ctx, doc.LeaseID)
+	if err != nil {
+		return documentError(c, err)
+	}
+	var data docgen.Data
+	if err := json.Unmarshal(doc.TemplateData, &data); err != nil {
+		c.Logger().Errorf("decode data of document %d: %v", doc.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to send document")
+	}
+	sum, err := h.documentSHA256(ctx, doc)
+	if err != nil {
+		return documentError(c, err)
+	}
+
+	staffID := middleware.GetUserID(c)
+	staffEmail := ""
+	if u, err := h.Store.Users.GetByClerkID(ctx, staffID); err == nil {
+		staffEmail = u.Email
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		c.Logger().Errorf("get user %s: %v", staffID, err)
+		return c.String(http.StatusInternalServerError, "Failed to send document")
+	}
+
+	requests := make([]models.SignatureRequest, len(data.Parties))
+	for i, p := range data.Parties {
+		requests[i] = models.SignatureRequest{
+			Position:       i,
+			Name:           p.Name,
+			Role:           p.Role,
+			Email:          p.Email,
+			UserID:         p.UserID,
+			DocumentSHA256: sum,
+			RequestedBy:    staffID,
+		}
+		if p.Role == docgen.RoleLandlord {
+			requests[i].Email = staffEmail
+			requests[i].UserID = staffID
+		}
+	}
+	sent := models.SignatureEvent{
+		ActorID:   staffID,
+		IPAddress: c.RealIP(),
+		UserAgent: truncate(c.Request().UserAgent(), 500),
+	}
+	err = h.Store.Signatures.Request(ctx, doc.ID, requests, sent)
+	if errors.Is(err, repository.ErrStatusChanged) {
+		return c.String(http.StatusConflict, "This document was already sent for signature. Please reload the page.")
+	}
+	if err != nil {
+		c.Logger().Errorf("request signatures for document %d: %v", doc.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to send document")
+	}
+
+	if doc.Template == docgen.TemplateLease && lease.Status == models.LeaseStatusDraft {
+		_, err := h.Store.Leases.SetStatus(ctx, lease.ID, models.LeaseStatusDraft, models.LeaseStatusPendingSignature)
+		if err != nil && !errors.Is(err, repository.ErrStatusChanged) {
+			c.Logger().Warnf("mark lease %d awaiting signatures: %v", lease.ID, err)
+		}
+	}
+	for _, r := range requests {
+		h.sendSignatureRequest(c, doc, &r)
+	}
+
+	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/admin/leases/%d/documents", doc.LeaseID))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AdminVoidSignatures withdraws a document's signature requests, so that
+// it can be corrected and sent again
+func (h *Handler) AdminVoidSignatures(c echo.Context) error {
+	doc, err := h.adminDocument(c)
+	if err != nil {
+		return documentError(c, err)
+	}
+	voided := models.SignatureEvent{
+		ActorID:   middleware.GetUserID(c),
+		IPAddress: c.RealIP(),
+		UserAgent: truncate(c.Request().UserAgent(), 500),
+	}
+	err = h.Store.Signatures.Void(c.Request().Context(), doc.ID, voided)
+	if errors.Is(err, repository.ErrStatusChanged) {
+		return c.String(http.StatusConflict, "This document has no open signature requests. Please reload the page.")
+	}
+	if err != nil {
+		c.Logger().Errorf("void signatures for document %d: %v", doc.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to void signatures")
+	}
+	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/admin/leases/%d/documents", doc.LeaseID))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AdminFinishSigning builds the signed copy of a document everyone has
+// signed. It's normally built as the last party signs; this retries it if
+// that failed.
+func (h *Handler) AdminFinishSigning(c echo.Context) error {
+	doc, err := h.adminDocument(c)
+	if err != nil {
+		return documentError(c, err)
+	}
+	err = h.finishSigning(c, doc)
+	if errors.Is(err, repository.ErrStatusChanged) {
+		return c.String(http.StatusConflict, "This document isn't ready for a signed copy. Please reload the page.")
+	}
+	if errors.Is(err, storage.ErrUnavailable) {
+		return c.String(http.StatusServiceUnavailable, documentsUnavailable)
+	}
+	if err != nil {
+		c.Logger().Errorf("finish signing document %d: %v", doc.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to build the signed copy")
+	}
+	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/admin/leases/%d/documents", doc.LeaseID))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AdminSignDocument sends staff to the signing page of a landlord's
+// signature request
+func (h *Handler) AdminSignDocument(c echo.Context) error {
+	r, err := h.signatureRequest(c)
+	if err != nil {
+		return documentError(c, err)
+	}
+	if r.Role != docgen.RoleLandlord {
+		return c.String(http.StatusNotFound, "Document not found")
+	}
+	return c.Redirect(http.StatusSeeOther, h.signingPath(r))
+}
+
+// TenantSignDocument sends a tenant to the signing page of one of their
+// own signature requests. Anyone else's are reported as missing.
+func (h *Handler) TenantSignDocument(c echo.Context) error {
+	r, err := h.signatureRequest(c)
+	if err != nil {
+		return documentError(c, err)
+	}
+	if r.UserID != middleware.GetUserID(c) || r.Role != docgen.RoleTenant {
+		return c.String(http.StatusNotFound, "Document not found")
+	}
+	return c.Redirect(http.StatusSeeOther, h.signingPath(r))
+}
+
+// SignaturePage shows a party the document they were asked to sign, from
+// their signing link, and records that they opened it. Once they've signed
+// it shows how the signing is going instead.
+func (h *Handler) SignaturePage(c echo.Context) error {
+	ctx := c.Request().Context()
+	tok := c.QueryParam("token")
+	r, doc, err := h.signatureFromToken(c, tok)
+	if err != nil {
+		return h.signatureTokenError(c, err)
+	}
+	if r.Status != models.SignatureStatusPending {
+		return renderSigningOutcome(c, r, doc, tok)
+	}
+
+	lease, err := h.Store.Leases.Get(ctx, doc.LeaseID)
+	if err != nil {
+		return h.signatureTokenError(c, err)
+	}
+	property, err := h.Store.Properties.GetByID(ctx, lease.PropertyID)
+	if err != nil {
+		return h.signatureTokenError(c, err)
+	}
+	viewed := models.SignatureEvent{
+		DocumentID: doc.ID,
+		RequestID:  &r.ID,
+		Kind:       models.SignatureEventViewed,
+		ActorID:    r.UserID,
+		IPAddress:  c.RealIP(),
+		UserAgent:  truncate(c.Request().UserAgent(), 500),
+	}
+	if err := h.Store.Signatures.LogEvent(ctx, &viewed); err != nil {
+		c.Logger().Errorf("log view of signature request %d: %v", r.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to load document")
+	}
+	return Render(c, http.StatusOK, pages.SignDocument(*r, *doc, *property, tok, middleware.IsAuthenticated(c)))
+}
+
+// SignatureDocument serves the document a signing link is for, or its
+// signed copy with signed=1, logging the access under the signer
+func (h *Handler) SignatureDocument(c echo.Context) error {
+	ctx := c.Request().Context()
+	r, doc, err := h.signatureFromToken(c, c.QueryParam("token"))
+	if err != nil {
+		return h.signatureTokenError(c, err)
+	}
+	if r.Status == models.SignatureStatusVoid {
+		return c.String(http.StatusNotFound, "Document not found")
+	}
+	if c.QueryParam("signed") == "1" {
+		if doc.SignedDocumentID == nil {
+			return c.String(http.StatusNotFound, "Document not found")
+		}
+		if doc, err = h.Store.Documents.Get(ctx, *doc.SignedDocumentID); err != nil {
+			return documentError(c, err)
+		}
+	}
+	reader := r.UserID
+	if reader == "" {
+		reader = r.Email
+	}
+	return h.serveDocument(c, doc, reader)
+}
+
+// SubmitSignature signs a document from a signing link, with a typed name
+// or a drawing, recording where it was signed from. The last party to sign
+// triggers the signed copy.
+func (h *Handler) SubmitSignature(c echo.Context) error {
+	ctx := c.Request().Context()
+	tok := c.FormValue("token")
+	r, doc, err := h.signatureFromToken(c, tok)
+	if err != nil {
+		return h.signatureTokenError(c, err)
+	}
+	outcome := "/sign?token=" + url.QueryEscape(tok)
+	if r.Status != models.SignatureStatusPending {
+		c.Response().Header().Set("HX-Redirect", outcome)
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	sig := *r
+	errs := parseSignatureForm(c, &sig)
+	if len(errs) > 0 {
+		return Render(c, http.StatusUnprocessableEntity, pages.SignatureForm(*r, tok, sig, c.FormValue("drawing"), errs))
+	}
+	sig.IPAddress = c.RealIP()
+	sig.UserAgent = truncate(c.Request().UserAgent(), 500)
+
+	complete, err := h.Store.Signatures.Sign(ctx, &sig)
+	if err != nil && !errors.Is(err, repository.ErrStatusChanged) {
+		c.Logger().Errorf("sign signature request %d: %v", r.ID, err)
+		return c.String(http.StatusInternalServerError, "Failed to save your signature")
+	}
+	if complete {
+		// The signature is saved either way; staff can build the copy
+		// again from the lease's documents
+		if err := h.finishSigning(c, doc); err != nil {
+			c.Logger().Errorf("finish signing document %d: %v", doc.ID, err)
+		}
+	}
+
+	c.Response().Header().Set("HX-Redirect", outcome)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// finishSigning renders the signed copy of doc, which every party has
+// signed, with its certificate and adds it to the vault. A signed lease
+// agreement makes a lease awaiting signatures active, unless another
+// active lease overlaps it.
+func (h *Handler) finishSigning(c echo.Context, doc *models.Document) error {
+	ctx := c.Request().Context()
+	requests, err := h.Store.Signatures.ListByDocument(ctx, doc.ID)
+	if err != nil {
+		return err
+	}
+	current := models.CurrentSignatureRequests(requests)
+	if len(current) == 0 || doc.SignedDocumentID != nil {
+		return repository.ErrStatusChanged
+	}
+	events, err := h.Store.Signatures.EventsByDocument(ctx, doc.ID)
+	if err != nil {
+		return err
+	}
+
+	// The signatures are for the file as it was sent
+	sum, err := h.documentSHA256(ctx, doc)
+	if err != nil {
+		return err
+	}
+	if sum != current[0].DocumentSHA256 {
+		return fmt.Errorf("document %d changed after it was sent for signature", doc.ID)
+	}
+
+	var data docgen.Data
+	if err := json.Unmarshal(doc.TemplateData, &data); err != nil {
+		return fmt.Errorf("decode data of document %d: %w", doc.ID, err)
+	}
+	now := time.Now()
+	events = append(events, models.SignatureEvent{DocumentID: doc.ID, Kind: models.SignatureEventCompleted, CreatedAt: now})
+	file, err := docgen.RenderSigned(data, docgen.Certificate{
+		DocumentID:  doc.ID,
+		SHA256:      sum,
+		Requests:    requests,
+		Events:      events,
+		CompletedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	lease, err := h.Store.Leases.Get(ctx, doc.LeaseID)
+	if err != nil {
+		return err
+	}
+	signed := models.Document{
+		Kind:        doc.Kind,
+		Title:       doc.Title + " (signed)",
+		Filename:    strings.TrimSuffix(doc.Filename, ".pdf") + "-signed.pdf",
+		ContentType: pdf.ContentType,
+	}
+	err = h.storeDocument(c, lease, &signed, file, func(ctx context.Context, d *models.Document) error {
+		return h.Store.Signatures.Complete(ctx, doc.ID, d)
+	})
+	if err != nil {
+		return err
+	}
+	doc.SignedDocumentID = &signed.ID
+
+	if doc.Template == docgen.TemplateLease && lease.Status == models.LeaseStatusPendingSignature {
+		h.activateSignedLease(c, lease)
+	}
+	h.sendSigningComplete(c, doc, current)
+	return nil
+}
+
+// activateSignedLease makes lease active now that its agreement is signed.
+// A lease overlapping another active one is left for staff to sort out.
+func (h *Handler) activateSignedLease(c echo.Context, lease *models.Lease) {
+	ctx := c.Request().Context()
+	other, err := h.overlappingLease(ctx, lease)
+	if err != nil {
+		c.Logger().Warnf("activate lease %d: %v", lease.ID, err)
+		return
+	}
+	if other != nil {
+		c.Logger().Warnf("activate lease %d: lease %d is already active for its dates", lease.ID, other.ID)
+		return
+	}
+	_, err = h.Store.Leases.SetStatus(ctx, lease.ID, models.LeaseStatusPendingSignature, models.LeaseStatusActive)
+	if err != nil && !errors.Is(err, repository.ErrStatusChanged) {
+		c.Logger().Warnf("activate lease %d: %v", lease.ID, err)
+	}
+}
+
+// adminDocument loads the document named by the :id path parameter
+func (h *Handler) adminDocument(c echo.Context) (*models.Document, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, repository.ErrNotFound
+	}
+	return h.Store.Documents.Get(c.Request().Context(), id)
+}
+
+// signatureRequest loads the pending signature request named by the :id
+// path parameter
+func (h *Handler) signatureRequest(c echo.Context) (*models.SignatureRequest, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, repository.ErrNotFound
+	}
+	r, err := h.Store.Signatures.Get(c.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if r.Status != models.SignatureStatusPending {
+		return nil, repository.ErrNotFound
+	}
+	return r, nil
+}
+
+// signingPath is a fresh signing link for r
+func (h *Handler) signingPath(r *models.SignatureRequest) string {
+	return "/sign?token=" + url.QueryEscape(h.Tokens.Sign(signaturePurpose, strconv.FormatInt(r.ID, 10), signatureLinkTTL))
+}
+
+// signatureFromToken verifies a signing link and loads its request and
+// document
+func (h *Handler) signatureFromToken(c echo.Context, tok string) (*models.SignatureRequest, *models.Document, error) {
+	ctx := c.Request().Context()
+	subject, err := h.Tokens.Verify(tok, signaturePurpose)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := strconv.ParseInt(subject, 10, 64)
+	if err != nil {
+		return nil, nil, token.ErrInvalid
+	}
+	r, err := h.Store.Signatures.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, err := h.Store.Documents.Get(ctx, r.DocumentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, doc, nil
+}
+
+func (h *Handler) signatureTokenError(c echo.Context, err error) error {
+	isAuth := middleware.IsAuthenticated(c)
+	switch {
+	case errors.Is(err, token.ErrExpired):
+		return Render(c, http.StatusBadRequest, pages.SigningStatus("Link Expired", "This signing link has expired. Tenants can sign from the documents page of their dashboard, or contact us for a new link.", false, isAuth))
+	case errors.Is(err, token.ErrInvalid):
+		return Render(c, http.StatusBadRequest, pages.SigningStatus("Invalid Link", "This link is invalid. Please check you copied the whole address from the email.", false, isAuth))
+	case errors.Is(err, repository.ErrNotFound):
+		return Render(c, http.StatusNotFound, pages.SigningStatus("Document Not Found", "We couldn't find the document this link is for. Please contact us.", false, isAuth))
+	default:
+		c.Logger().Errorf("load signature request: %v", err)
+		return c.String(http.StatusInternalServerError, "Failed to load document")
+	}
+}
+
+// renderSigningOutcome tells a party who already acted on their signing
+// link where the signing stands
+func renderSigningOutcome(c echo.Context, r *models.SignatureRequest, doc *models.Document, tok string) error {
+	isAuth := middleware.IsAuthenticated(c)
+	switch {
+	case r.Status == models.SignatureStatusVoid:
+		return Render(c, http.StatusGone, pages.SigningStatus("Signing Cancelled",
+			fmt.Sprintf("The request to sign the %s was withdrawn. If you weren't expecting that, please contact us.", doc.Title), false, isAuth))
+	case doc.SignedDocumentID != nil:
+		return Render(c, http.StatusOK, pages.SigningComplete(*r, *doc, tok, isAuth))
+	default:
+		return Render(c, http.StatusOK, pages.SigningStatus("Thanks for Signing",
+			fmt.Sprintf("You signed the %s. Once everyone else has signed, we'll add the signed copy to the lease's documents.", doc.Title), true, isAuth))
+	}
+}
+
+// documentSHA256 hashes doc's file, as hex
+func (h *Handler) documentSHA256(ctx context.Context, doc *models.Document) (string, error) {
+	file, err := h.Documents.Open(ctx, doc.StorageKey)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// sendSignatureRequest emails r's party a link to sign doc
+func (h *Handler) sendSignatureRequest(c echo.Context, doc *models.Document, r *models.SignatureRequest) {
+	if r.Email == "" {
+		return
+	}
+	body := fmt.Sprintf(`Hi %s,
+
+%s has sent you the %s to sign.
+
+Review and sign it here:
+
+%s
+
+The link works for %d days.
+`, r.Name, docgen.Landlord, doc.Title, h.absoluteURL(h.signingPath(r)), int(signatureLinkTTL.Hours()/24))
+
+	err := h.Mailer.Send(c.Request().Context(), mailer.Message{
+		To:      r.Email,
+		Subject: "Please sign: " + doc.Title,
+		Body:    body,
+	})
+	if err != nil {
+		c.Logger().Warnf("email signature request %d: %v", r.ID, err)
+	}
+}
+
+// sendSigningComplete tells the staff member who sent doc for signature
+// that everyone has signed. Tenants hear of the signed copy as it's added
+// to their documents.
+func (h *Handler) sendSigningComplete(c echo.Context, doc *models.Document, requests []models.SignatureRequest) {
+	ctx := c.Request().Context()
+	staffID := requests[0].RequestedBy
+	u, err := h.Store.Users.GetByClerkID(ctx, staffID)
+	if err != nil || u.Email == "" {
+		return
+	}
+	body := fmt.Sprintf(`All parties have signed the %s for lease #%d.
+
+The signed copy, with its signature certificate, is in the lease's documents:
+
+%s
+`, doc.Title, doc.LeaseID, h.absoluteURL(fmt.Sprintf("/admin/leases/%d/documents", doc.LeaseID)))
+
+	err = h.Mailer.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Signed: " + doc.Title,
+		Body:    body,
+	})
+	if err != nil {
+		c.Logger().Warnf("email staff of signed document %d: %v", doc.ID, err)
+	}
+}
+
+// parseSignatureForm reads the signing form into sig: a typed name or a
+// drawing, and the signer's agreement to sign electronically
+func parseSignatureForm(c echo.Context, sig *models.SignatureRequest) map[string]string {
+	errs := make(map[string]string)
+	f := propertyForm{c: c, errs: errs}
+
+	sig.Method = models.SignatureMethod(c.FormValue("method"))
+	switch sig.Method {
+	case models.SignatureMethodTyped:
+		sig.TypedName = f.text("typedName", "Your full name", 100, true)
+	case models.SignatureMethodDrawn:
+		sig.Drawing = parseDrawing(errs, c.FormValue("drawing"))
+	default:
+		errs["method"] = "Choose how to sign"
+	}
+	if c.FormValue("agree") == "" {
+		errs["agree"] = "Agree to sign electronically to continue"
+	}
+	return errs
+}
+
+// parseDrawing decodes a drawn signature posted as a PNG data URL. It
+// must be a reasonably sized image with something drawn on it.
+func parseDrawing(errs map[string]string, value string) []byte {
+	encoded, ok := strings.CutPrefix(value, drawingPrefix)
+	if !ok {
+		errs["drawing"] = "Draw your signature in the box"
+		return nil
+	}
+	if len(encoded) > base64.StdEncoding.EncodedLen(maxSignatureDrawing) {
+		errs["drawing"] = "Your drawing is too large. Please clear it and sign again."
+		return nil
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		errs["drawing"] = "Your drawing couldn't be read. Please clear it and sign again."
+		return nil
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width > maxSignatureWidth || config.Height > maxSignatureHeight {
+		errs["drawing"] = "Your drawing couldn't be read. Please clear it and sign again."
+		return nil
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		errs["drawing"] = "Your drawing couldn't be read. Please clear it and sign again."
+		return nil
+	}
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a > 0x8000 {
+				return data
+			}
+		}
+	}
+	errs["drawing"] = "Draw your signature in the box"
+	return nil
+}
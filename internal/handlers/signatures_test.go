@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"russ-rentals/internal/docgen"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+)
+
+// leaseForSigning creates a draft lease for two tenants, generates its
+// lease agreement and sends that out for signature, returning the lease,
+// the agreement and its requests in signing order
+func leaseForSigning(t *testing.T, h *Handler) (*models.Lease, *models.Document, []models.SignatureRequest) {
+	t.Helper()
+	ctx := context.Background()
+	lease := models.Lease{
+		PropertyID:       1,
+		Status:           models.LeaseStatusDraft,
+		StartDate:        time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:          time.Date(2027, time.February, 28, 0, 0, 0, 0, time.UTC),
+		MonthlyRentCents: 150000,
+		Tenants: []models.LeaseTenant{
+			{UserID: "ten1", Name: "Tess Lane", Email: "tess@example.com"},
+			{UserID: "ten2", Name: "Tom Lane", Email: "tom@example.com"},
+		},
+	}
+	if err := h.Store.Leases.Create(ctx, &lease); err != nil {
+		t.Fatal(err)
+	}
+	id := strconv.FormatInt(lease.ID, 10)
+	wantStatus(t, serve(t, h.AdminGenerateDocument, http.MethodPost, "agent1", url.Values{"template": {docgen.TemplateLease}}, "id", id), http.StatusNoContent)
+
+	docs, err := h.Store.Documents.ListByLease(ctx, lease.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("generated %d documents, want 1", len(docs))
+	}
+	doc := docs[0]
+	wantStatus(t, serve(t, h.AdminRequestSignatures, http.MethodPost, "agent1", nil, "id", strconv.FormatInt(doc.ID, 10)), http.StatusNoContent)
+
+	requests, err := h.Store.Signatures.ListByDocument(ctx, doc.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &lease, &doc, requests
+}
+
+// sign submits the signing form for r under typedName
+func sign(t *testing.T, h *Handler, r models.SignatureRequest, typedName string) {
+	t.Helper()
+	form := url.Values{
+		"token":     {h.Tokens.Sign(signaturePurpose, strconv.FormatInt(r.ID, 10), signatureLinkTTL)},
+		"method":    {string(models.SignatureMethodTyped)},
+		"typedName": {typedName},
+		"agree":     {"1"},
+	}
+	wantStatus(t, serve(t, h.SubmitSignature, http.MethodPost, r.UserID, form), http.StatusNoContent)
+}
+
+func TestLeaseActiveOnceEveryoneSigns(t *testing.T) {
+	ctx := context.Background()
+	h, _, _ := testHandler(t, models.Property{ID: 1, Slug: "maple", Title: "Maple House"})
+	lease, doc, requests := leaseForSigning(t, h)
+	if len(requests) != 3 {
+		t.Fatalf("sent %d signature requests, want both tenants and the landlord", len(requests))
+	}
+
+	leaseStatus := func() models.LeaseStatus {
+		t.Helper()
+		l, err := h.Store.Leases.Get(ctx, lease.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return l.Status
+	}
+	signedCopy := func() *int64 {
+		t.Helper()
+		d, err := h.Store.Documents.Get(ctx, doc.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d.SignedDocumentID
+	}
+
+	if got := leaseStatus(); got != models.LeaseStatusPendingSignature {
+		t.Fatalf("lease status once sent = %q, want %q", got, models.LeaseStatusPendingSignature)
+	}
+	for i, r := range requests {
+		sign(t, h, r, r.Name)
+		if i == len(requests)-1 {
+			break
+		}
+		if got := leaseStatus(); got != models.LeaseStatusPendingSignature {
+			t.Fatalf("lease status after %d of %d signed = %q, want %q", i+1, len(requests), got, models.LeaseStatusPendingSignature)
+		}
+		if signedCopy() != nil {
+			t.Fatalf("signed copy built after %d of %d signed", i+1, len(requests))
+		}
+	}
+
+	if got := leaseStatus(); got != models.LeaseStatusActive {
+		t.Errorf("lease status after everyone signed = %q, want %q", got, models.LeaseStatusActive)
+	}
+	if signedCopy() == nil {
+		t.Error("no signed copy after everyone signed")
+	}
+}
+
+func TestSigningTwiceRejected(t *testing.T) {
+	ctx := context.Background()
+	h, _, _ := testHandler(t, models.Property{ID: 1, Slug: "maple", Title: "Maple House"})
+	lease, doc, requests := leaseForSigning(t, h)
+	first := requests[0]
+
+	sign(t, h, first, "Tess Lane")
+	sign(t, h, first, "Someone Else")
+
+	r, err := h.Store.Signatures.Get(ctx, first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != models.SignatureStatusSigned || r.TypedName != "Tess Lane" {
+		t.Errorf("request after signing twice = %q signed as %q, want the first signature kept", r.Status, r.TypedName)
+	}
+	events, err := h.Store.Signatures.EventsByDocument(ctx, doc.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signings := 0
+	for _, e := range events {
+		if e.Kind == models.SignatureEventSigned {
+			signings++
+		}
+	}
+	if signings != 1 {
+		t.Errorf("logged %d signings, want 1", signings)
+	}
+
+	again := *r
+	again.TypedName = "Someone Else"
+	if _, err := h.Store.Signatures.Sign(ctx, &again); !errors.Is(err, repository.ErrStatusChanged) {
+		t.Errorf("Sign() on a signed request error = %v, want ErrStatusChanged", err)
+	}
+	wantStatus(t, serve(t, h.TenantSignDocument, http.MethodGet, first.UserID, nil, "id", strconv.FormatInt(first.ID, 10)), http.StatusNotFound)
+
+	// A tenant signing twice doesn't stand in for the other tenant
+	l, err := h.Store.Leases.Get(ctx, lease.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Status != models.LeaseStatusPendingSignature {
+		t.Errorf("lease status = %q, want %q", l.Status, models.LeaseStatusPendingSignature)
+	}
+}
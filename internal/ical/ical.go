@@ -0,0 +1,124 @@
+// Package ical writes iCalendar (RFC 5545) files for calendar feeds and email
+// attachments.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the MIME type of an encoded Calendar
+const ContentType = "text/calendar; charset=utf-8"
+
+// Event statuses
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Calendar is a VCALENDAR of events
+type Calendar struct {
+	// ProdID identifies the product that created the calendar
+	ProdID string
+	// Name is shown by clients that support X-WR-CALNAME
+	Name string
+	// Method is the iTIP method, PUBLISH if empty
+	Method string
+	Events []Event
+}
+
+// Event is a VEVENT. Clients match updates by UID and keep the copy with the
+// highest Sequence.
+type Event struct {
+	UID      string
+	Sequence int
+	Status   string
+	Start    time.Time
+	End      time.Time
+	// Stamp is when the event was last changed
+	Stamp       time.Time
+	Summary     string
+	Location    string
+	Description string
+	URL         string
+}
+
+// Encode renders the calendar with CRLF line endings and long lines folded
+func (c Calendar) Encode() []byte {
+	var w writer
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", c.ProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	method := c.Method
+	if method == "" {
+		method = "PUBLISH"
+	}
+	w.line("METHOD", method)
+	if c.Name != "" {
+		w.line("X-WR-CALNAME", escape(c.Name))
+	}
+	for _, e := range c.Events {
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", e.UID)
+		w.line("DTSTAMP", utc(e.Stamp))
+		w.line("DTSTART", utc(e.Start))
+		w.line("DTEND", utc(e.End))
+		w.line("SEQUENCE", fmt.Sprint(e.Sequence))
+		if e.Status != "" {
+			w.line("STATUS", e.Status)
+		}
+		w.line("SUMMARY", escape(e.Summary))
+		if e.Location != "" {
+			w.line("LOCATION", escape(e.Location))
+		}
+		if e.Description != "" {
+			w.line("DESCRIPTION", escape(e.Description))
+		}
+		if e.URL != "" {
+			w.line("URL", e.URL)
+		}
+		w.line("END", "VEVENT")
+	}
+	w.line("END", "VCALENDAR")
+	return w.buf.Bytes()
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+// line writes a content line, folding it at 75 octets without splitting
+// UTF-8 sequences
+func (w *writer) line(name, value string) {
+	s := name + ":" + value
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines lose one octet to the leading space
+		limit = 74
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escape escapes a TEXT value
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+func utc(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
@@ -0,0 +1,127 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEncodeFoldsLongLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+		lines   int
+	}{
+		{"exactly 75 octets", strings.Repeat("a", 75-len("SUMMARY:")), 1},
+		{"one octet over", strings.Repeat("a", 76-len("SUMMARY:")), 2},
+		{"several continuations", strings.Repeat("abcdefghij", 20), 3},
+		{"two-byte runes", strings.Repeat("é", 80), 3},
+		{"three-byte runes", strings.Repeat("€", 60), 3},
+		{"mixed widths", strings.Repeat("a€é😀", 20), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summaryLines(t, Calendar{Events: []Event{{Summary: tt.summary}}})
+			if len(got) != tt.lines {
+				t.Errorf("SUMMARY folded into %d lines, want %d: %q", len(got), tt.lines, got)
+			}
+			for i, l := range got {
+				if len(l) > 75 {
+					t.Errorf("line %d is %d octets, want at most 75", i, len(l))
+				}
+				if !utf8.ValidString(l) {
+					t.Errorf("line %d splits a character: %q", i, l)
+				}
+				if i > 0 && !strings.HasPrefix(l, " ") {
+					t.Errorf("continuation line %d doesn't start with a space: %q", i, l)
+				}
+			}
+			if unfolded := strings.Join(got, "\r\n"); strings.ReplaceAll(unfolded, "\r\n ", "") != "SUMMARY:"+tt.summary {
+				t.Errorf("unfolded line = %q, want the summary back", unfolded)
+			}
+		})
+	}
+}
+
+func TestEncodeEscapesText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "Showing at Maple House", "Showing at Maple House"},
+		{"comma", "Austin, TX", `Austin\, TX`},
+		{"semicolon", "Unit A; rear entrance", `Unit A\; rear entrance`},
+		{"backslash", `C:\keys`, `C:\\keys`},
+		{"newline", "Visitor: Vic\nStatus: Pending", `Visitor: Vic\nStatus: Pending`},
+		{"CRLF", "Line one\r\nLine two", `Line one\nLine two`},
+		{"escapes not doubled", `a\,b`, `a\\\,b`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summaryLines(t, Calendar{Events: []Event{{Summary: tt.in}}})
+			if len(got) != 1 || got[0] != "SUMMARY:"+tt.want {
+				t.Errorf("SUMMARY = %q, want %q", got, "SUMMARY:"+tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeEvent(t *testing.T) {
+	start := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.FixedZone("CST", -6*60*60))
+	cal := Calendar{
+		ProdID: "-//Test//EN",
+		Name:   "Showings, Maple",
+		Events: []Event{{
+			UID:      "showing-1@example.com",
+			Sequence: 2,
+			Status:   StatusConfirmed,
+			Start:    start,
+			End:      start.Add(30 * time.Minute),
+			Stamp:    start.Add(-time.Hour),
+			Summary:  "Showing",
+		}},
+	}
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Test//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		`X-WR-CALNAME:Showings\, Maple`,
+		"BEGIN:VEVENT",
+		"UID:showing-1@example.com",
+		"DTSTAMP:20260302T140000Z",
+		"DTSTART:20260302T150000Z",
+		"DTEND:20260302T153000Z",
+		"SEQUENCE:2",
+		"STATUS:CONFIRMED",
+		"SUMMARY:Showing",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if got := string(cal.Encode()); got != want {
+		t.Errorf("Encode() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// summaryLines returns the physical lines cal's first SUMMARY was folded
+// into
+func summaryLines(t *testing.T, cal Calendar) []string {
+	t.Helper()
+	var lines []string
+	for _, l := range strings.Split(string(cal.Encode()), "\r\n") {
+		switch {
+		case strings.HasPrefix(l, "SUMMARY:") && lines == nil:
+			lines = append(lines, l)
+		case strings.HasPrefix(l, " ") && lines != nil:
+			lines = append(lines, l)
+		case lines != nil:
+			return lines
+		}
+	}
+	t.Fatal("no SUMMARY line")
+	return nil
+}
@@ -0,0 +1,121 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// photo is a 64x32 JPEG, white with a red square in its top-left corner,
+// tagged with EXIF orientation o in the given byte order. o 0 leaves the
+// EXIF segment out.
+func photo(t *testing.T, o int, order binary.ByteOrder) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 64, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 64; x++ {
+			c := color.RGBA{255, 255, 255, 255}
+			if x < 16 && y < 16 {
+				c = color.RGBA{255, 0, 0, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if o == 0 {
+		return data
+	}
+
+	// A TIFF header and IFD0 holding just the Orientation tag
+	tiff := make([]byte, 26)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3) // SHORT
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], uint16(o))
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, app1...)
+	return append(out, data[2:]...)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for o := 1; o <= 8; o++ {
+			if got := jpegOrientation(photo(t, o, order)); got != o {
+				t.Errorf("jpegOrientation() of %v orientation %d = %d", order, o, got)
+			}
+		}
+	}
+
+	tagged := photo(t, 6, binary.BigEndian)
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"no EXIF", photo(t, 0, nil)},
+		{"out of range", photo(t, 9, binary.BigEndian)},
+		{"not a JPEG", []byte("\x89PNG\r\n\x1a\n")},
+		{"truncated segment", tagged[:20]},
+		{"empty", nil},
+	}
+	for _, tt := range tests {
+		if got := jpegOrientation(tt.data); got != 1 {
+			t.Errorf("jpegOrientation() of %s = %d, want 1", tt.name, got)
+		}
+	}
+}
+
+func TestDecodeAppliesOrientation(t *testing.T) {
+	tests := []struct {
+		o             int
+		width, height int
+		red           image.Point // where the red corner ends up
+	}{
+		{1, 64, 32, image.Pt(4, 4)},
+		{3, 64, 32, image.Pt(59, 27)},
+		{6, 32, 64, image.Pt(27, 4)},
+		{8, 32, 64, image.Pt(4, 59)},
+	}
+	for _, tt := range tests {
+		img, err := Decode(photo(t, tt.o, binary.LittleEndian))
+		if err != nil {
+			t.Fatalf("orientation %d: Decode() error = %v", tt.o, err)
+		}
+		b := img.Bounds()
+		if b.Dx() != tt.width || b.Dy() != tt.height {
+			t.Errorf("orientation %d: decoded %dx%d, want %dx%d", tt.o, b.Dx(), b.Dy(), tt.width, tt.height)
+			continue
+		}
+		corners := []image.Point{image.Pt(4, 4), image.Pt(b.Dx()-5, 4), image.Pt(4, b.Dy()-5), image.Pt(b.Dx()-5, b.Dy()-5)}
+		for _, p := range corners {
+			if isRed(img.At(b.Min.X+p.X, b.Min.Y+p.Y)) != (p == tt.red) {
+				t.Errorf("orientation %d: pixel %v red = %t, want the red corner at %v", tt.o, p, p != tt.red, tt.red)
+			}
+		}
+	}
+}
+
+func isRed(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r > 0xC000 && g < 0x4000 && b < 0x4000
+}
@@ -0,0 +1,122 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// MaxPixels caps decoded image size so a small, highly compressed upload
+// cannot exhaust memory
+const MaxPixels = 50_000_000
+
+// ErrUnsupported is returned for files that are not JPEG, PNG or WebP images
+var ErrUnsupported = errors.New("unsupported image type")
+
+// ErrTooLarge is returned for images with more than MaxPixels pixels
+var ErrTooLarge = errors.New("image dimensions are too large")
+
+// Variant is a resized copy of an uploaded photo
+type Variant struct {
+	Name      string
+	MaxWidth  int
+	MaxHeight int
+}
+
+// PhotoVariants are generated for every property photo
+var PhotoVariants = []Variant{
+	{Name: "thumbnail", MaxWidth: 320, MaxHeight: 240},
+	{Name: "card", MaxWidth: 800, MaxHeight: 600},
+	{Name: "full", MaxWidth: 1920, MaxHeight: 1440},
+}
+
+// AttachmentVariants are generated for photos tenants attach to
+// maintenance requests
+var AttachmentVariants = []Variant{
+	{Name: "thumbnail", MaxWidth: 320, MaxHeight: 240},
+	{Name: "full", MaxWidth: 1920, MaxHeight: 1440},
+}
+
+// ContentType sniffs data and returns its MIME type if it is a supported
+// image format
+func ContentType(data []byte) (string, error) {
+	switch ct := http.DetectContentType(data); ct {
+	case "image/jpeg", "image/png", "image/webp":
+		return ct, nil
+	default:
+		return "", ErrUnsupported
+	}
+}
+
+// Decode decodes a JPEG, PNG or WebP image. JPEG EXIF orientation is applied
+// to the pixels; all other metadata is dropped.
+func Decode(data []byte) (image.Image, error) {
+	ct, err := ContentType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var decodeConfig func(io.Reader) (image.Config, error)
+	var decode func(io.Reader) (image.Image, error)
+	switch ct {
+	case "image/jpeg":
+		decodeConfig, decode = jpeg.DecodeConfig, jpeg.Decode
+	case "image/png":
+		decodeConfig, decode = png.DecodeConfig, png.Decode
+	case "image/webp":
+		decodeConfig, decode = webp.DecodeConfig, webp.Decode
+	}
+
+	cfg, err := decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	img, err := decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if ct == "image/jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+	return img, nil
+}
+
+// Fit scales img down to fit within maxWidth x maxHeight, keeping its aspect
+// ratio. Images that already fit are returned unscaled.
+func Fit(img image.Image, maxWidth, maxHeight int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxWidth && h <= maxHeight {
+		return img
+	}
+
+	scale := min(float64(maxWidth)/float64(w), float64(maxHeight)/float64(h))
+	dw := max(1, int(float64(w)*scale+0.5))
+	dh := max(1, int(float64(h)*scale+0.5))
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// EncodeJPEG writes img as a JPEG. Transparent areas become white.
+func EncodeJPEG(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, b.Min, draw.Over)
+	return jpeg.Encode(w, flat, &jpeg.Options{Quality: 85})
+}
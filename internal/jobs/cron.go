@@ -0,0 +1,214 @@
+package jobs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	expr                               string
+	minutes, hours, days, months, dows uint64
+	// anyDay and anyDow record a day of month or day of week starting
+	// with *, which decides how the two combine
+	anyDay, anyDow bool
+	// fixed is set when neither minute nor hour starts with *, so the
+	// schedule fires at set times of day rather than every so often
+	fixed bool
+}
+
+// cronAliases are the shorthand expressions ParseSchedule accepts
+var cronAliases = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// ParseSchedule parses a standard five-field cron expression: minute, hour,
+// day of month, month and day of week (0 or 7 is Sunday). Fields take *,
+// numbers, ranges (1-5), lists (1,15) and steps (*/15, 1-10/2). As in cron,
+// when neither day of month nor day of week starts with * a day matching
+// either runs, else a day must match both. The aliases @hourly, @daily,
+// @weekly, @monthly and @yearly work too.
+func ParseSchedule(expr string) (Schedule, error) {
+	s := Schedule{expr: expr}
+	spec := strings.TrimSpace(expr)
+	if alias, ok := cronAliases[spec]; ok {
+		spec = alias
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("cron expression %q: want 5 fields, got %d", expr, len(fields))
+	}
+
+	var err error
+	parse := func(field string, min, max int) uint64 {
+		if err != nil {
+			return 0
+		}
+		var bits uint64
+		bits, err = parseCronField(field, min, max)
+		if err != nil {
+			err = fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+		return bits
+	}
+	s.minutes = parse(fields[0], 0, 59)
+	s.hours = parse(fields[1], 0, 23)
+	s.days = parse(fields[2], 1, 31)
+	s.months = parse(fields[3], 1, 12)
+	s.dows = parse(fields[4], 0, 7)
+	if err != nil {
+		return Schedule{}, err
+	}
+	// Sunday is both 0 and 7
+	if s.dows&(1<<7) != 0 {
+		s.dows |= 1
+	}
+	s.anyDay = strings.HasPrefix(fields[2], "*")
+	s.anyDow = strings.HasPrefix(fields[4], "*")
+	s.fixed = !strings.HasPrefix(fields[0], "*") && !strings.HasPrefix(fields[1], "*")
+	return s, nil
+}
+
+// MustParseSchedule is like ParseSchedule but panics on a bad expression.
+// It's for schedules fixed in code.
+func MustParseSchedule(expr string) Schedule {
+	s, err := ParseSchedule(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (s Schedule) String() string {
+	return s.expr
+}
+
+// Next returns the first time after after that the schedule fires, in
+// after's location. It returns the zero time if the schedule never fires,
+// e.g. on February 30th. Across daylight saving changes it follows cron:
+// a schedule with a set time of day that falls in the hour the clocks skip
+// fires as they go forward, and doesn't fire again in the hour they
+// repeat. Other schedules fire by the time elapsed.
+func (s Schedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !has(s.months, int(t.Month())):
+			t = wallTime(t.Year(), t.Month()+1, 1, loc)
+		case !s.matchesDay(t):
+			t = wallTime(t.Year(), t.Month(), t.Day()+1, loc)
+		case s.fixed && s.skipped(t):
+			return t
+		case !has(s.hours, t.Hour()):
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case !has(s.minutes, t.Minute()):
+			t = t.Add(time.Minute)
+		case s.fixed && repeated(t):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// skipped reports whether the clocks just went forward at t, past a time
+// the schedule fires
+func (s Schedule) skipped(t time.Time) bool {
+	start, _ := t.ZoneBounds()
+	if !t.Equal(start) {
+		return false
+	}
+	_, before := start.Add(-time.Nanosecond).Zone()
+	_, offset := t.Zone()
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	for w := wall.Add(-time.Duration(offset-before) * time.Second); w.Before(wall); w = w.Add(time.Minute) {
+		if has(s.hours, w.Hour()) && has(s.minutes, w.Minute()) {
+			return true
+		}
+	}
+	return false
+}
+
+// repeated reports whether the clocks already read t once, before they
+// went back
+func repeated(t time.Time) bool {
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return false
+	}
+	_, before := start.Add(-time.Nanosecond).Zone()
+	_, offset := t.Zone()
+	return t.Sub(start) < time.Duration(before-offset)*time.Second
+}
+
+// wallTime is when the clocks in loc first read midnight on the given day,
+// or when they go forward past it if they skip it
+func wallTime(year int, month time.Month, day int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	if t.Hour() != 0 {
+		_, end := t.ZoneBounds()
+		return end
+	}
+	return t
+}
+
+func (s Schedule) matchesDay(t time.Time) bool {
+	day, dow := has(s.days, t.Day()), has(s.dows, int(t.Weekday()))
+	if s.anyDay || s.anyDow {
+		return day && dow
+	}
+	return day || dow
+}
+
+func has(bits uint64, n int) bool {
+	return bits&(1<<uint(n)) != 0
+}
+
+// parseCronField turns one field into a bit set of the values it matches
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("bad step %q", part)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			loText, hiText, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(loText); err != nil {
+				return 0, fmt.Errorf("bad value %q", part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiText); err != nil {
+					return 0, fmt.Errorf("bad range %q", part)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for n := lo; n <= hi; n += step {
+			bits |= 1 << uint(n)
+		}
+	}
+	return bits, nil
+}
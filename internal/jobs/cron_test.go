@@ -0,0 +1,194 @@
+package jobs
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseCronField(t *testing.T) {
+	tests := []struct {
+		field    string
+		min, max int
+		want     []int
+	}{
+		{"*", 0, 6, []int{0, 1, 2, 3, 4, 5, 6}},
+		{"5", 0, 59, []int{5}},
+		{"1-5", 0, 7, []int{1, 2, 3, 4, 5}},
+		{"1,15", 1, 31, []int{1, 15}},
+		{"*/15", 0, 59, []int{0, 15, 30, 45}},
+		{"*/10", 1, 31, []int{1, 11, 21, 31}},
+		{"1-10/3", 0, 23, []int{1, 4, 7, 10}},
+		{"5/20", 0, 59, []int{5, 25, 45}},
+		{"1-3,10,20-30/5", 1, 31, []int{1, 2, 3, 10, 20, 25, 30}},
+		{"3,3,1-3", 1, 12, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			bits, err := parseCronField(tt.field, tt.min, tt.max)
+			if err != nil {
+				t.Fatalf("parseCronField: %v", err)
+			}
+			var got []int
+			for n := tt.min; n <= tt.max; n++ {
+				if has(bits, n) {
+					got = append(got, n)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseCronField = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"@never",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"5-1 * * * *",
+		"1-x * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+		"-1 * * * *",
+	}
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := ParseSchedule(expr); err == nil {
+				t.Errorf("ParseSchedule(%q) succeeded, want an error", expr)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"every minute", "* * * * *", utc(2026, time.March, 1, 10, 7), utc(2026, time.March, 1, 10, 8)},
+		{"strictly after", "*/15 * * * *", utc(2026, time.March, 1, 10, 15), utc(2026, time.March, 1, 10, 30)},
+		{"seconds dropped", "*/15 * * * *", utc(2026, time.March, 1, 10, 14).Add(59 * time.Second), utc(2026, time.March, 1, 10, 15)},
+		{"step", "*/15 * * * *", utc(2026, time.March, 1, 10, 46), utc(2026, time.March, 1, 11, 0)},
+		{"list", "0 8,20 * * *", utc(2026, time.March, 1, 9, 0), utc(2026, time.March, 1, 20, 0)},
+		{"range with step", "0 1-10/3 * * *", utc(2026, time.March, 1, 4, 30), utc(2026, time.March, 1, 7, 0)},
+		{"range with step wraps to the next day", "0 1-10/3 * * *", utc(2026, time.March, 1, 10, 0), utc(2026, time.March, 2, 1, 0)},
+		{"weekdays", "0 9 * * 1-5", utc(2026, time.October, 16, 10, 0), utc(2026, time.October, 19, 9, 0)},
+		{"Sunday as 7", "0 0 * * 7", utc(2026, time.March, 2, 0, 0), utc(2026, time.March, 8, 0, 0)},
+		{"Sunday as 0", "0 0 * * 0", utc(2026, time.March, 2, 0, 0), utc(2026, time.March, 8, 0, 0)},
+		{"day of month", "0 0 31 * *", utc(2026, time.April, 1, 0, 0), utc(2026, time.May, 31, 0, 0)},
+		{"month", "0 0 1 1 *", utc(2026, time.June, 15, 0, 0), utc(2027, time.January, 1, 0, 0)},
+		{"leap day", "0 0 29 2 *", utc(2026, time.March, 1, 0, 0), utc(2028, time.February, 29, 0, 0)},
+		{"never", "0 0 30 2 *", utc(2026, time.March, 1, 0, 0), time.Time{}},
+		{"day of month or week by week", "0 0 13 * 1", utc(2026, time.March, 10, 0, 0), utc(2026, time.March, 13, 0, 0)},
+		{"day of month or week by month", "0 0 13 * 1", utc(2026, time.March, 13, 0, 0), utc(2026, time.March, 16, 0, 0)},
+		{"day of month and step of week", "0 0 1 * */2", utc(2026, time.March, 1, 0, 0), utc(2026, time.August, 1, 0, 0)},
+		{"step of month and day of week", "0 0 */2 * 1", utc(2026, time.March, 1, 0, 0), utc(2026, time.March, 9, 0, 0)},
+		{"hourly", "@hourly", utc(2026, time.March, 1, 10, 0), utc(2026, time.March, 1, 11, 0)},
+		{"daily", "@daily", utc(2026, time.March, 1, 10, 0), utc(2026, time.March, 2, 0, 0)},
+		{"weekly", "@weekly", utc(2026, time.March, 2, 10, 0), utc(2026, time.March, 8, 0, 0)},
+		{"monthly", "@monthly", utc(2026, time.December, 15, 0, 0), utc(2027, time.January, 1, 0, 0)},
+		{"yearly", "@yearly", utc(2026, time.March, 1, 0, 0), utc(2027, time.January, 1, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MustParseSchedule(tt.expr).Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNextDaylightSaving(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no time zone data: %v", err)
+	}
+	// Clocks went forward from 2:00 EST to 3:00 EDT on March 8, 2026, and
+	// go back from 2:00 EDT to 1:00 EST on November 1
+	est, edt := time.FixedZone("EST", -5*60*60), time.FixedZone("EDT", -4*60*60)
+	at := func(month time.Month, day, hour, min int, zone *time.Location) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, zone)
+	}
+
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"skipped hour fires as clocks go forward", "0 2 * * *", at(time.March, 7, 12, 0, est), at(time.March, 8, 3, 0, edt)},
+		{"skipped half hour fires as clocks go forward", "30 2 * * *", at(time.March, 7, 12, 0, est), at(time.March, 8, 3, 0, edt)},
+		{"skipped hour back the next day", "0 2 * * *", at(time.March, 8, 3, 0, edt), at(time.March, 9, 2, 0, edt)},
+		{"hour after the skipped one", "0 3 * * *", at(time.March, 8, 0, 0, est), at(time.March, 8, 3, 0, edt)},
+		{"hour before the skipped one", "30 1 * * *", at(time.March, 8, 0, 0, est), at(time.March, 8, 1, 30, est)},
+		{"interval across clocks going forward", "*/30 * * * *", at(time.March, 8, 1, 45, est), at(time.March, 8, 3, 0, edt)},
+		{"repeated hour fires once", "30 1 * * *", at(time.November, 1, 1, 30, edt), at(time.November, 2, 1, 30, est)},
+		{"repeated hour fires the first time", "30 1 * * *", at(time.November, 1, 0, 0, edt), at(time.November, 1, 1, 30, edt)},
+		{"hour after the repeated one", "0 2 * * *", at(time.November, 1, 0, 0, edt), at(time.November, 1, 2, 0, est)},
+		{"interval across clocks going back", "*/30 * * * *", at(time.November, 1, 1, 45, edt), at(time.November, 1, 1, 0, est)},
+		{"hourly across clocks going back", "0 * * * *", at(time.November, 1, 1, 0, edt), at(time.November, 1, 1, 0, est)},
+		{"daily across clocks going forward", "@daily", at(time.March, 7, 12, 0, est), at(time.March, 8, 0, 0, est)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MustParseSchedule(tt.expr).Next(tt.after.In(ny))
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.after.In(ny), got, tt.want.In(ny))
+			}
+			if got.Location() != ny {
+				t.Errorf("Next is in %s, want %s", got.Location(), ny)
+			}
+		})
+	}
+}
+
+func TestScheduleMatchesDay(t *testing.T) {
+	// March 13, 2026 is a Friday and March 16 a Monday
+	fri13, mon16, tue17 := time.Date(2026, time.March, 13, 0, 0, 0, 0, time.UTC), time.Date(2026, time.March, 16, 0, 0, 0, 0, time.UTC), time.Date(2026, time.March, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr            string
+		fri13, mon, tue bool
+	}{
+		{"0 0 * * *", true, true, true},
+		{"0 0 13 * *", true, false, false},
+		{"0 0 * * 1", false, true, false},
+		{"0 0 13 * 1", true, true, false},
+		{"0 0 13,17 * 1", true, true, true},
+		{"0 0 */2 * 1", false, false, false},
+		{"0 0 */2 * 5", true, false, false},
+		{"0 0 13 * */2", false, false, false},
+		{"0 0 1-31 * 1", true, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s := MustParseSchedule(tt.expr)
+			for _, c := range []struct {
+				day  time.Time
+				want bool
+			}{{fri13, tt.fri13}, {mon16, tt.mon}, {tue17, tt.tue}} {
+				if got := s.matchesDay(c.day); got != c.want {
+					t.Errorf("matchesDay(%s) = %t, want %t", c.day.Format("Mon Jan 2"), got, c.want)
+				}
+			}
+		})
+	}
+}
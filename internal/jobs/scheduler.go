@@ -0,0 +1,163 @@
+// Package jobs runs background work on cron schedules inside the server.
+// Every run is recorded, and a cluster-wide lock per job keeps servers
+// sharing a database from running the same job at once.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+)
+
+// Job is a piece of background work
+type Job struct {
+	Name        string
+	Description string
+	Schedule    Schedule
+	// Run does the work for a run scheduled at now, and sums up what it did
+	Run func(ctx context.Context, now time.Time) (summary string, err error)
+}
+
+// Scheduler runs jobs on their schedules
+type Scheduler struct {
+	runs repository.JobRepository
+	jobs []Job
+	now  func() time.Time
+	wg   sync.WaitGroup
+}
+
+// NewScheduler creates a Scheduler that records runs in runs. Schedules
+// are read in loc, the business's time zone, and jobs are run with the
+// time in it.
+func NewScheduler(runs repository.JobRepository, jobs []Job, loc *time.Location) *Scheduler {
+	jobs = append([]Job(nil), jobs...)
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return &Scheduler{runs: runs, jobs: jobs, now: func() time.Time { return time.Now().In(loc) }}
+}
+
+// Jobs lists the scheduler's jobs by name
+func (s *Scheduler) Jobs() []Job {
+	return s.jobs
+}
+
+// Job looks up a job by name
+func (s *Scheduler) Job(name string) (Job, bool) {
+	for _, job := range s.jobs {
+		if job.Name == name {
+			return job, true
+		}
+	}
+	return Job{}, false
+}
+
+// Run fires jobs on their schedules until ctx is done, then waits for the
+// runs in progress to finish. A run that's still going when its job next
+// fires makes that firing a no-op.
+func (s *Scheduler) Run(ctx context.Context) {
+	defer s.wg.Wait()
+
+	next := make([]time.Time, len(s.jobs))
+	for i, job := range s.jobs {
+		next[i] = job.Schedule.Next(s.now())
+		log.Printf("Job %s scheduled for %s", job.Name, next[i].Format(time.RFC3339))
+	}
+
+	for {
+		var wake time.Time
+		for _, t := range next {
+			if !t.IsZero() && (wake.IsZero() || t.Before(wake)) {
+				wake = t
+			}
+		}
+		if wake.IsZero() {
+			<-ctx.Done()
+			return
+		}
+
+		timer := time.NewTimer(time.Until(wake))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := s.now()
+		for i, job := range s.jobs {
+			if next[i].IsZero() || next[i].After(now) {
+				continue
+			}
+			scheduledFor := next[i]
+			next[i] = job.Schedule.Next(now)
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				if _, err := s.execute(ctx, job, models.JobTriggerSchedule, scheduledFor); err != nil && !skipped(err) {
+					log.Printf("Job %s failed: %v", job.Name, err)
+				}
+			}()
+		}
+	}
+}
+
+// RunNow runs the job named name straight away, e.g. from the command line.
+// It returns ErrLocked if the job is already running somewhere.
+func (s *Scheduler) RunNow(ctx context.Context, name string) (*models.JobRun, error) {
+	job, ok := s.Job(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown job %q", name)
+	}
+	return s.execute(ctx, job, models.JobTriggerManual, s.now())
+}
+
+// execute runs job once while holding its lock, recording the run. The
+// run's error is returned as well as recorded.
+func (s *Scheduler) execute(ctx context.Context, job Job, trigger models.JobTrigger, scheduledFor time.Time) (*models.JobRun, error) {
+	release, err := s.runs.TryLock(ctx, job.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	run := &models.JobRun{Job: job.Name, Trigger: trigger, ScheduledFor: scheduledFor}
+	if err := s.runs.StartRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	summary, runErr := runJob(ctx, job, s.now())
+	status, errMsg := models.JobRunStatusSucceeded, ""
+	if runErr != nil {
+		status, errMsg = models.JobRunStatusFailed, runErr.Error()
+	}
+	// Record the outcome even if ctx was cancelled mid-run
+	finished, err := s.runs.FinishRun(context.WithoutCancel(ctx), run.ID, status, summary, errMsg)
+	if err != nil {
+		return run, errors.Join(runErr, fmt.Errorf("record run %d: %w", run.ID, err))
+	}
+	if runErr == nil {
+		log.Printf("Job %s: %s", job.Name, summary)
+	}
+	return finished, runErr
+}
+
+// runJob calls job.Run, turning a panic into an error
+func runJob(ctx context.Context, job Job, now time.Time) (summary string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx, now)
+}
+
+// skipped reports whether err means another server has the run in hand
+func skipped(err error) bool {
+	return errors.Is(err, repository.ErrLocked) || errors.Is(err, repository.ErrDuplicateRun)
+}
@@ -0,0 +1,408 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"russ-rentals/internal/billing"
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/payment"
+	"russ-rentals/internal/repository"
+)
+
+// rentReminderDays is how many days before rent is due tenants are
+// reminded
+const rentReminderDays = 3
+
+// leaseExpiryNoticeDays are how many days before their lease ends tenants
+// are warned
+var leaseExpiryNoticeDays = []int{60, 30}
+
+// Tasks is the server's recurring work
+type Tasks struct {
+	Store    *repository.Store
+	Mailer   mailer.Mailer
+	Payments payment.Gateway
+	// BaseURL is the site's public URL, for links in emails
+	BaseURL string
+}
+
+// Jobs lists the tasks as jobs for a Scheduler
+func (t *Tasks) Jobs() []Job {
+	return []Job{
+		{
+			Name:        "post-rent",
+			Description: "Post rent and pet rent that has fallen due to lease ledgers",
+			Schedule:    MustParseSchedule("15 0 * * *"),
+			Run:         t.PostRent,
+		},
+		{
+			Name:        "autopay",
+			Description: "Charge autopay cards on their charge day and retry declined charges",
+			Schedule:    MustParseSchedule("0 1 * * *"),
+			Run:         t.Autopay,
+		},
+		{
+			Name:        "late-fees",
+			Description: "Charge late fees on overdue rent under each lease's late fee policy",
+			Schedule:    MustParseSchedule("0 2 * * *"),
+			Run:         t.LateFees,
+		},
+		{
+			Name:        "rent-reminders",
+			Description: fmt.Sprintf("Remind tenants %d days before rent is due", rentReminderDays),
+			Schedule:    MustParseSchedule("0 9 * * *"),
+			Run:         t.RentReminders,
+		},
+		{
+			Name:        "lease-expiry",
+			Description: "End leases past their end date and warn of leases ending in 60 and 30 days",
+			Schedule:    MustParseSchedule("30 0 * * *"),
+			Run:         t.LeaseExpiry,
+		},
+	}
+}
+
+// PostRent posts the charges every active lease has fallen due for. A
+// failing lease doesn't stop the rest being billed.
+func (t *Tasks) PostRent(ctx context.Context, now time.Time) (string, error) {
+	leases, err := t.Store.Leases.Filter(ctx, repository.LeaseFilter{Status: models.LeaseStatusActive})
+	if err != nil {
+		return "", err
+	}
+	var errs []error
+	posted, billed := 0, 0
+	for _, lease := range leases {
+		n, err := billing.PostRent(ctx, t.Store.Ledger, lease, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("lease %d: %w", lease.ID, err))
+			continue
+		}
+		if n > 0 {
+			posted += n
+			billed++
+		}
+	}
+	return fmt.Sprintf("posted %d charges to %d of %d active leases", posted, billed, len(leases)), errors.Join(errs...)
+}
+
+// LateFees posts the late fees due on overdue rent
+func (t *Tasks) LateFees(ctx context.Context, now time.Time) (string, error) {
+	assessments, err := billing.AssessLateFees(ctx, t.Store, now, false)
+	var fees int
+	var total int64
+	for _, a := range assessments {
+		fees += len(a.Fees)
+		total += a.TotalCents()
+	}
+	return fmt.Sprintf("posted %d late fees totalling %s to %d leases", fees, models.FormatCents(total), len(assessments)), err
+}
+
+// Autopay charges the autopay enrollments that are due and emails each
+// payer a receipt, or why their card was declined and what happens next
+func (t *Tasks) Autopay(ctx context.Context, now time.Time) (string, error) {
+	charges, err := billing.ChargeAutopay(ctx, t.Store, t.Payments, now)
+	errs := []error{err}
+	paid, declined := 0, 0
+	var total int64
+	for _, c := range charges {
+		if c.Payment == nil {
+			continue
+		}
+		switch c.Payment.Status {
+		case models.PaymentStatusSucceeded:
+			paid++
+			total += c.Payment.AmountCents
+		case models.PaymentStatusFailed:
+			declined++
+		default:
+			continue
+		}
+		if err := t.autopayNotice(ctx, c); err != nil {
+			errs = append(errs, fmt.Errorf("lease %d: %w", c.Lease.ID, err))
+		}
+	}
+	return fmt.Sprintf("charged %s to %d autopay cards, %d declined", models.FormatCents(total), paid, declined), errors.Join(errs...)
+}
+
+// autopayNotice emails the payer of an autopay charge that went through or
+// was declined
+func (t *Tasks) autopayNotice(ctx context.Context, c billing.AutopayCharge) error {
+	property, err := t.Store.Properties.GetByID(ctx, c.Lease.PropertyID)
+	if err != nil {
+		return err
+	}
+	a, p := c.Autopay, c.Payment
+
+	msg := mailer.Message{To: a.PayerEmail}
+	if p.Status == models.PaymentStatusSucceeded {
+		msg.Subject = "Autopay receipt for " + property.Title
+		msg.Body = fmt.Sprintf(`Hello,
+
+We charged %s to your %s for rent at %s.
+
+Payment reference: %s
+
+Your ledger: %s
+`, models.FormatCents(p.AmountCents), a.MethodLabel, property.Title, p.IntentID, t.url("/dashboard/ledger"))
+		return t.Mailer.Send(ctx, msg)
+	}
+
+	next := "We won't try this card again this month. Please pay online to avoid late fees."
+	if a.RetryOn != nil {
+		next = fmt.Sprintf("We'll try again on %s. To avoid late fees, you can pay online before then or switch to another card.", a.RetryOn.Format("Monday, January 2"))
+	}
+	msg.Subject = "Your autopay payment for " + property.Title + " didn't go through"
+	msg.Body = fmt.Sprintf(`Hello,
+
+We tried to charge %s to your %s for rent at %s, but it was declined: %s
+
+%s
+
+Pay or update your card: %s
+`, models.FormatCents(p.AmountCents), a.MethodLabel, property.Title, p.FailureMessage, next, t.url("/dashboard/pay"))
+	return t.Mailer.Send(ctx, msg)
+}
+
+// RentReminders emails the tenants of each active lease with rent due
+// within rentReminderDays days what they'll owe that day. Each due date is
+// reminded of once, even if the job runs again; a reminder that fails to
+// send is tried again on the next run.
+func (t *Tasks) RentReminders(ctx context.Context, now time.Time) (string, error) {
+	leases, err := t.Store.Leases.Filter(ctx, repository.LeaseFilter{Status: models.LeaseStatusActive})
+	if err != nil {
+		return "", err
+	}
+	today := models.Date(now)
+	var errs []error
+	sent := 0
+	for _, lease := range leases {
+		due, ok := lease.NextRentDue(today)
+		if !ok || due.After(today.AddDate(0, 0, rentReminderDays)) {
+			continue
+		}
+		first, err := t.Store.Leases.MarkNotified(ctx, lease.ID, "rent-reminder", due)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("lease %d: %w", lease.ID, err))
+			continue
+		}
+		if !first {
+			continue
+		}
+		n, err := t.remindRent(ctx, lease, due, now)
+		sent += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("lease %d: %w", lease.ID, err))
+			if err := t.Store.Leases.ClearNotified(ctx, lease.ID, "rent-reminder", due); err != nil {
+				errs = append(errs, fmt.Errorf("lease %d: %w", lease.ID, err))
+			}
+		}
+	}
+	return fmt.Sprintf("sent %d rent reminders", sent), errors.Join(errs...)
+}
+
+// remindRent emails lease's tenants about the rent due on due, returning
+// how many emails went out
+func (t *Tasks) remindRent(ctx context.Context, lease models.Lease, due, now time.Time) (int, error) {
+	if _, err := billing.PostRent(ctx, t.Store.Ledger, lease, now); err != nil {
+		return 0, err
+	}
+	ledger, err := t.Store.Ledger.ListByLease(ctx, lease.ID)
+	if err != nil {
+		return 0, err
+	}
+	property, err := t.Store.Properties.GetByID(ctx, lease.PropertyID)
+	if err != nil {
+		return 0, err
+	}
+
+	var charge int64
+	for _, e := range lease.ScheduledCharges(due) {
+		if e.PostedOn.Equal(due) {
+			charge += e.AmountCents
+		}
+	}
+	owed := ledger.BalanceCents() + charge
+	if owed <= 0 {
+		return 0, nil
+	}
+
+	balance := ""
+	if b := ledger.BalanceCents(); b > 0 {
+		balance = fmt.Sprintf(" This includes %s still outstanding on your account.", models.FormatCents(b))
+	} else if b < 0 {
+		balance = fmt.Sprintf(" This takes off your %s credit.", models.FormatCents(-b))
+	}
+
+	var errs []error
+	sent := 0
+	for _, tenant := range lease.Tenants {
+		if tenant.Email == "" {
+			continue
+		}
+		err := t.Mailer.Send(ctx, mailer.Message{
+			To:      tenant.Email,
+			Subject: "Rent for " + property.Title + " is due " + due.Format("January 2"),
+			Body: fmt.Sprintf(`Hi %s,
+
+This is a reminder that %s is due for %s on %s.%s
+
+You can see your ledger and pay online at %s
+`, tenant.Name, models.FormatCents(owed), property.Title, due.Format("Monday, January 2"), balance, t.url("/dashboard/pay")),
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("email %s: %w", tenant.Email, err))
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+// LeaseExpiry ends active leases whose end date has passed and warns
+// tenants leaseExpiryNoticeDays before their lease ends. Staff get a
+// digest of anything that changed.
+func (t *Tasks) LeaseExpiry(ctx context.Context, now time.Time) (string, error) {
+	leases, err := t.Store.Leases.Filter(ctx, repository.LeaseFilter{Status: models.LeaseStatusActive})
+	if err != nil {
+		return "", err
+	}
+	today := models.Date(now)
+	var errs []error
+	var ended, noticed []string
+	sent := 0
+	for _, lease := range leases {
+		if lease.EndDate.Before(today) {
+			_, err := t.Store.Leases.SetStatus(ctx, lease.ID, models.LeaseStatusActive, models.LeaseStatusEnded)
+			if errors.Is(err, repository.ErrStatusChanged) {
+				continue
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("end lease %d: %w", lease.ID, err))
+				continue
+			}
+			ended = append(ended, t.leaseLine(ctx, lease))
+			continue
+		}
+
+		for _, days := range leaseExpiryNoticeDays {
+			if !lease.EndDate.Equal(today.AddDate(0, 0, days)) {
+				continue
+			}
+			first, err := t.Store.Leases.MarkNotified(ctx, lease.ID, fmt.Sprintf("lease-expiry-%d", days), lease.EndDate)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("lease %d: %w", lease.ID, err))
+				continue
+			}
+			if !first {
+				continue
+			}
+			n, err := t.warnExpiry(ctx, lease, days)
+			sent += n
+			if err != nil {
+				errs = append(errs, fmt.Errorf("lease %d: %w", lease.ID, err))
+			}
+			noticed = append(noticed, t.leaseLine(ctx, lease))
+		}
+	}
+
+	if len(ended) > 0 || len(noticed) > 0 {
+		if err := t.expiryDigest(ctx, today, ended, noticed); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return fmt.Sprintf("ended %d leases, sent %d expiry notices", len(ended), sent), errors.Join(errs...)
+}
+
+// warnExpiry emails lease's tenants that it ends in days days
+func (t *Tasks) warnExpiry(ctx context.Context, lease models.Lease, days int) (int, error) {
+	property, err := t.Store.Properties.GetByID(ctx, lease.PropertyID)
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	sent := 0
+	for _, tenant := range lease.Tenants {
+		if tenant.Email == "" {
+			continue
+		}
+		err := t.Mailer.Send(ctx, mailer.Message{
+			To:      tenant.Email,
+			Subject: fmt.Sprintf("Your lease at %s ends in %d days", property.Title, days),
+			Body: fmt.Sprintf(`Hi %s,
+
+Your lease at %s ends on %s, %d days from now.
+
+If you'd like to renew, or to arrange your move-out inspection, just reply to this email and we'll be in touch.
+
+Your lease details: %s
+`, tenant.Name, property.Title, lease.EndDate.Format("January 2, 2006"), days, t.url("/dashboard")),
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("email %s: %w", tenant.Email, err))
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+// expiryDigest tells admins and staff which leases ended today and which
+// tenants were warned theirs is ending
+func (t *Tasks) expiryDigest(ctx context.Context, today time.Time, ended, noticed []string) error {
+	users, err := t.Store.Users.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body strings.Builder
+	if len(ended) > 0 {
+		body.WriteString("These leases passed their end date and are now marked ended:\n\n")
+		for _, line := range ended {
+			body.WriteString("  - " + line + "\n")
+		}
+		body.WriteString("\n")
+	}
+	if len(noticed) > 0 {
+		body.WriteString("Tenants were reminded that these leases end soon:\n\n")
+		for _, line := range noticed {
+			body.WriteString("  - " + line + "\n")
+		}
+		body.WriteString("\n")
+	}
+	body.WriteString("Manage leases: " + t.url("/admin/leases") + "\n")
+
+	var errs []error
+	for _, u := range users {
+		if u.Email == "" || (u.Role != models.RoleAdmin && u.Role != models.RoleStaff) {
+			continue
+		}
+		err := t.Mailer.Send(ctx, mailer.Message{
+			To:      u.Email,
+			Subject: "Lease expiry update for " + today.Format("January 2, 2006"),
+			Body:    body.String(),
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("email %s: %w", u.Email, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// leaseLine describes lease in one line for staff
+func (t *Tasks) leaseLine(ctx context.Context, lease models.Lease) string {
+	where := fmt.Sprintf("property %d", lease.PropertyID)
+	if property, err := t.Store.Properties.GetByID(ctx, lease.PropertyID); err == nil {
+		where = property.Title
+	}
+	return fmt.Sprintf("%s, %s (ends %s): %s", where, strings.Join(lease.TenantNames(), ", "),
+		lease.EndDate.Format("Jan 2, 2006"), t.url(fmt.Sprintf("/admin/leases/%d", lease.ID)))
+}
+
+func (t *Tasks) url(path string) string {
+	return strings.TrimRight(t.BaseURL, "/") + path
+}
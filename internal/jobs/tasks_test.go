@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"russ-rentals/internal/mailer"
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+)
+
+// flakyMailer fails every send while down is set
+type flakyMailer struct {
+	down bool
+	sent []mailer.Message
+}
+
+func (m *flakyMailer) Send(ctx context.Context, msg mailer.Message) error {
+	if m.down {
+		return errors.New("mail server unavailable")
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestRentRemindersRetryFailedSends(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore([]models.Property{{ID: 1, Title: "Maple House"}})
+	lease := models.Lease{
+		PropertyID:       1,
+		Status:           models.LeaseStatusDraft,
+		StartDate:        time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:          time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC),
+		MonthlyRentCents: 150000,
+		Tenants:          []models.LeaseTenant{{UserID: "ten1", Name: "Tess", Email: "tess@example.com"}},
+	}
+	if err := store.Leases.Create(ctx, &lease); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Leases.SetStatus(ctx, lease.ID, models.LeaseStatusDraft, models.LeaseStatusActive); err != nil {
+		t.Fatal(err)
+	}
+	mail := &flakyMailer{down: true}
+	tasks := &Tasks{Store: store, Mailer: mail, BaseURL: "https://example.com"}
+
+	// Rent is due March 1, three days after February 26
+	runs := []struct {
+		day     time.Time
+		down    bool
+		wantErr bool
+		sent    int
+	}{
+		{time.Date(2026, time.February, 25, 9, 0, 0, 0, time.UTC), false, false, 0},
+		{time.Date(2026, time.February, 26, 9, 0, 0, 0, time.UTC), true, true, 0},
+		{time.Date(2026, time.February, 27, 9, 0, 0, 0, time.UTC), false, false, 1},
+		{time.Date(2026, time.February, 27, 10, 0, 0, 0, time.UTC), false, false, 1},
+		{time.Date(2026, time.February, 28, 9, 0, 0, 0, time.UTC), false, false, 1},
+	}
+	for _, run := range runs {
+		mail.down = run.down
+		_, err := tasks.RentReminders(ctx, run.day)
+		if (err != nil) != run.wantErr {
+			t.Fatalf("RentReminders on %s: err = %v, want error %t", run.day, err, run.wantErr)
+		}
+		if len(mail.sent) != run.sent {
+			t.Fatalf("after the run on %s, %d reminders were sent, want %d", run.day, len(mail.sent), run.sent)
+		}
+	}
+}
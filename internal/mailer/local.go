@@ -0,0 +1,51 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// LogMailer writes messages to the standard logger instead of sending them
+type LogMailer struct {
+	from string
+}
+
+// NewLogMailer creates a Mailer for local development
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{from: from}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("mail to=%q subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	for _, a := range msg.Attachments {
+		log.Printf("mail attachment %q (%s, %d bytes)", a.Filename, a.ContentType, len(a.Data))
+	}
+	return nil
+}
+
+// FileMailer writes each message as an .eml file so links can be clicked
+// through during development
+type FileMailer struct {
+	from string
+	dir  string
+}
+
+// NewFileMailer creates a Mailer that writes into dir, creating it if needed
+func NewFileMailer(from, dir string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &FileMailer{from: from, dir: dir}, nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]+`)
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000000"), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	return os.WriteFile(filepath.Join(m.dir, name), compose(m.from, msg), 0o644)
+}
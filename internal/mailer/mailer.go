@@ -0,0 +1,139 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"sort"
+	"time"
+)
+
+// Drivers accepted by New
+const (
+	DriverLog  = "log"
+	DriverFile = "file"
+	DriverSMTP = "smtp"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+	// Headers are extra headers such as List-Unsubscribe
+	Headers map[string]string
+	// Attachments are sent after the body
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to a Message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Mailer sends email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Options configures the mailer drivers
+type Options struct {
+	From string
+	// Dir is where the file driver writes messages
+	Dir string
+	// SMTPAddr is host:port of the SMTP server
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// New creates a Mailer for the named driver
+func New(driver string, opts Options) (Mailer, error) {
+	switch driver {
+	case DriverLog:
+		return NewLogMailer(opts.From), nil
+	case DriverFile:
+		return NewFileMailer(opts.From, opts.Dir)
+	case DriverSMTP:
+		if opts.SMTPAddr == "" {
+			return nil, fmt.Errorf("SMTP_ADDR is required for the smtp mail driver")
+		}
+		return NewSMTPMailer(opts.From, opts.SMTPAddr, opts.SMTPUsername, opts.SMTPPassword), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", driver)
+	}
+}
+
+// compose renders msg as an RFC 5322 message
+func compose(from string, msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mimeHeader(msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	keys := make([]string, 0, len(msg.Headers))
+	for k := range msg.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "%s: %s\r\n", k, msg.Headers[k])
+	}
+
+	if len(msg.Attachments) == 0 {
+		buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+		buf.WriteString("\r\n")
+		buf.WriteString(msg.Body)
+		return buf.Bytes()
+	}
+
+	// Writes to a bytes.Buffer can't fail
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mw.Boundary())
+
+	part, _ := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"8bit"},
+	})
+	part.Write([]byte(msg.Body))
+
+	for _, a := range msg.Attachments {
+		mediaType, params, err := mime.ParseMediaType(a.ContentType)
+		if err != nil {
+			mediaType, params = "application/octet-stream", map[string]string{}
+		}
+		params["name"] = a.Filename
+		part, _ := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(mediaType, params)},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		writeBase64(part, a.Data)
+	}
+	mw.Close()
+	return buf.Bytes()
+}
+
+// writeBase64 writes data base64-encoded in 76 character lines
+func writeBase64(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		w.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	w.Write([]byte(encoded + "\r\n"))
+}
+
+func mimeHeader(s string) string {
+	return mime.QEncoding.Encode("utf-8", s)
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+)
+
+// SMTPMailer sends messages through an SMTP server
+type SMTPMailer struct {
+	from     string
+	addr     string
+	username string
+	password string
+}
+
+// NewSMTPMailer creates a Mailer that relays through addr (host:port)
+func NewSMTPMailer(from, addr, username, password string) *SMTPMailer {
+	return &SMTPMailer{from: from, addr: addr, username: username, password: password}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	from, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		host, _, _ := net.SplitHostPort(m.addr)
+		auth = smtp.PlainAuth("", m.username, m.password, host)
+	}
+
+	return smtp.SendMail(m.addr, auth, from.Address, []string{msg.To}, compose(m.from, msg))
+}
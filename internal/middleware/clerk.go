@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"os"
 	"strings"
@@ -76,6 +77,9 @@ func verifySession(c echo.Context) (*clerk.SessionClaims, error) {
 	// Verify the session token
 	claims, err := jwt.Verify(c.Request().Context(), &jwt.VerifyParams{
 		Token: sessionToken,
+		CustomClaimsConstructor: func(context.Context) any {
+			return &roleClaims{}
+		},
 	})
 	if err != nil {
 		return nil, err
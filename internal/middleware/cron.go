@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CronAuth only lets through requests bearing secret, as Vercel cron sends
+// CRON_SECRET. Without a secret every request is refused.
+func CronAuth(secret string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			got := c.Request().Header.Get("Authorization")
+			if secret == "" || subtle.ConstantTimeCompare([]byte(got), []byte("Bearer "+secret)) != 1 {
+				return c.String(http.StatusUnauthorized, "Unauthorized")
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type locationContextKey struct{}
+
+// Location stores the business's time zone on the request context, for
+// templates to show times in
+func Location(loc *time.Location) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := context.WithValue(c.Request().Context(), locationContextKey{}, loc)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// GetLocation returns the time zone stored by Location, or UTC outside a
+// request
+func GetLocation(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(locationContextKey{}).(*time.Location); ok {
+		return loc
+	}
+	return time.UTC
+}
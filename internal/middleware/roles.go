@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/labstack/echo/v4"
+
+	"russ-rentals/internal/models"
+	"russ-rentals/internal/repository"
+)
+
+// roleClaims holds the role from a customised Clerk session token. Add either
+// "role": "{{user.public_metadata.role}}" or "metadata":
+// "{{user.public_metadata}}" to the session token template in the Clerk
+// dashboard; private_metadata works the same way.
+type roleClaims struct {
+	Role     string `json:"role"`
+	Metadata struct {
+		Role string `json:"role"`
+	} `json:"metadata"`
+}
+
+type roleContextKey struct{}
+
+type tenantContextKey struct{}
+
+// Roles resolves the signed-in user's role, and whether they are a tenant,
+// and stores them on the echo and request contexts. It must run after
+// OptionalClerkAuth or ClerkAuth.
+//
+// Roles are looked up in order: adminIDs (to bootstrap the first admins),
+// Clerk session claims, then the local users table. Users with none of
+// these have no role. Tenancy comes from the leases themselves: users on a
+// lease shared with its tenants are tenants, whatever their role.
+func Roles(users repository.UserRepository, leases repository.LeaseRepository, adminIDs []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID := GetUserID(c)
+			if userID == "" {
+				return next(c)
+			}
+
+			role, err := resolveRole(c, users, adminIDs, userID)
+			if err != nil {
+				c.Logger().Errorf("resolve role for %s: %v", userID, err)
+				return c.String(http.StatusInternalServerError, "Failed to load account")
+			}
+			tenant, err := isTenant(c.Request().Context(), leases, userID)
+			if err != nil {
+				c.Logger().Errorf("resolve leases for %s: %v", userID, err)
+				return c.String(http.StatusInternalServerError, "Failed to load account")
+			}
+
+			c.Set("role", role)
+			c.Set("isTenant", tenant)
+			ctx := context.WithValue(c.Request().Context(), roleContextKey{}, role)
+			ctx = context.WithValue(ctx, tenantContextKey{}, tenant)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// isTenant reports whether userID is on a lease staff have shared with its
+// tenants. Drafts don't count, as the tenant portal doesn't show them.
+func isTenant(ctx context.Context, leases repository.LeaseRepository, userID string) (bool, error) {
+	list, err := leases.ListByTenant(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(list, func(l models.Lease) bool { return l.Status.VisibleToTenant() }), nil
+}
+
+func resolveRole(c echo.Context, users repository.UserRepository, adminIDs []string, userID string) (models.Role, error) {
+	if slices.Contains(adminIDs, userID) {
+		return models.RoleAdmin, nil
+	}
+
+	if claims, ok := c.Get("sessionClaims").(*clerk.SessionClaims); ok {
+		if custom, ok := claims.Custom.(*roleClaims); ok {
+			for _, r := range []string{custom.Role, custom.Metadata.Role} {
+				if role := models.Role(r); role.IsValid() {
+					return role, nil
+				}
+			}
+		}
+	}
+
+	user, err := users.GetByClerkID(c.Request().Context(), userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return user.Role, nil
+}
+
+// RequireRole only lets through users holding one of roles. Admins are
+// always let through. It must run after ClerkAuth and Roles.
+func RequireRole(roles ...models.Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !HasRole(c.Request().Context(), roles...) {
+				return c.String(http.StatusForbidden, "You do not have access to this page")
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireTenant only lets through users on a lease shared with them. It
+// must run after ClerkAuth and Roles.
+func RequireTenant() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !IsTenant(c.Request().Context()) {
+				return c.String(http.StatusForbidden, "You do not have access to this page")
+			}
+			return next(c)
+		}
+	}
+}
+
+// GetRole retrieves the user's role from context. It is empty for anonymous
+// users and users without a role.
+func GetRole(c echo.Context) models.Role {
+	if role, ok := c.Get("role").(models.Role); ok {
+		return role
+	}
+	return ""
+}
+
+// HasRole reports whether the user behind ctx is an admin or holds one of
+// roles. Templates call it with their ctx to show role-specific content.
+func HasRole(ctx context.Context, roles ...models.Role) bool {
+	role, _ := ctx.Value(roleContextKey{}).(models.Role)
+	if role == "" {
+		return false
+	}
+	return role == models.RoleAdmin || slices.Contains(roles, role)
+}
+
+// IsTenant reports whether the user behind ctx is on a lease shared with
+// them. Templates call it with their ctx to link to the tenant portal.
+func IsTenant(ctx context.Context) bool {
+	tenant, _ := ctx.Value(tenantContextKey{}).(bool)
+	return tenant
+}
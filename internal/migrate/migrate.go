@@ -0,0 +1,318 @@
+package migrate
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"russ-rentals/internal/database"
+)
+
+// ErrNoMigrations is returned by Load when fsys holds no migrations
+var ErrNoMigrations = errors.New("no migrations found")
+
+// versionTable is shared with the goose CLI so databases migrated with
+// `goose up` are recognised and either tool can be used.
+const versionTable = "goose_db_version"
+
+// lockKey serialises migration runs across replicas via pg_advisory_lock
+const lockKey int64 = 0x72_75_73_73_6d_69_67 // "russmig"
+
+// Migration is one goose-formatted SQL file
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+	// NoTx is set by "-- +goose NO TRANSACTION" for statements such as
+	// CREATE INDEX CONCURRENTLY
+	NoTx bool
+}
+
+// Status reports whether a migration has been applied
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Load reads and parses every .sql file in fsys, ordered by version
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, ErrNoMigrations
+	}
+
+	var migrations []Migration
+	seen := make(map[int64]string)
+	for _, name := range names {
+		m, err := parseFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := seen[m.Version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", m.Version, other, name)
+		}
+		seen[m.Version] = name
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func parseFile(fsys fs.FS, name string) (Migration, error) {
+	prefix, _, ok := strings.Cut(path.Base(name), "_")
+	if !ok {
+		return Migration{}, fmt.Errorf("migration %s: name must start with a version number", name)
+	}
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil || version < 1 {
+		return Migration{}, fmt.Errorf("migration %s: invalid version %q", name, prefix)
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return Migration{}, err
+	}
+
+	m := Migration{Version: version, Name: name}
+	var up, down strings.Builder
+	var section *strings.Builder
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if directive, ok := strings.CutPrefix(strings.TrimSpace(line), "-- +goose "); ok {
+			switch strings.ToUpper(strings.TrimSpace(directive)) {
+			case "UP":
+				section = &up
+			case "DOWN":
+				section = &down
+			case "NO TRANSACTION":
+				m.NoTx = true
+			case "STATEMENTBEGIN", "STATEMENTEND":
+				// Sections run as a single multi-statement exec, so
+				// statement grouping needs no special handling
+			default:
+				return Migration{}, fmt.Errorf("migration %s: unknown directive %q", name, directive)
+			}
+			continue
+		}
+		if section != nil {
+			section.WriteString(line)
+			section.WriteByte('\n')
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Migration{}, err
+	}
+	if strings.TrimSpace(up.String()) == "" {
+		return Migration{}, fmt.Errorf("migration %s: missing -- +goose Up section", name)
+	}
+
+	m.Up = up.String()
+	m.Down = down.String()
+	return m, nil
+}
+
+// Migrator applies migrations to a database
+type Migrator struct {
+	db         *database.DB
+	migrations []Migration
+}
+
+// New creates a Migrator for the given, already loaded, migrations
+func New(db *database.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Up applies every pending migration in order and returns those applied
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(conn *pgx.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := versions[mig.Version]; ok {
+				continue
+			}
+			if err := run(ctx, conn, mig, mig.Up, func(tx execer) error {
+				_, err := tx.Exec(ctx, "INSERT INTO "+versionTable+" (version_id, is_applied) VALUES ($1, true)", mig.Version)
+				return err
+			}); err != nil {
+				return err
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the most recently applied migration. It returns nil if
+// nothing is applied.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	var rolledBack *Migration
+	err := m.locked(ctx, func(conn *pgx.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := versions[mig.Version]; !ok {
+				continue
+			}
+			if err := run(ctx, conn, mig, mig.Down, func(tx execer) error {
+				_, err := tx.Exec(ctx, "DELETE FROM "+versionTable+" WHERE version_id = $1", mig.Version)
+				return err
+			}); err != nil {
+				return err
+			}
+			rolledBack = &mig
+			return nil
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Status lists every known migration with its applied state
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.locked(ctx, func(conn *pgx.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			at, ok := versions[mig.Version]
+			statuses = append(statuses, Status{Migration: mig, Applied: ok, AppliedAt: at})
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// locked runs fn on a dedicated connection holding the migration lock
+func (m *Migrator) locked(ctx context.Context, fn func(conn *pgx.Conn) error) error {
+	conn, err := m.db.Pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+
+	if err := ensureVersionTable(ctx, conn.Conn()); err != nil {
+		return err
+	}
+	return fn(conn.Conn())
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// run executes one migration direction and records it via record, inside a
+// transaction unless the migration opted out
+func run(ctx context.Context, conn *pgx.Conn, mig Migration, sql string, record func(execer) error) error {
+	if mig.NoTx {
+		if strings.TrimSpace(sql) != "" {
+			if _, err := conn.Exec(ctx, sql); err != nil {
+				return fmt.Errorf("migration %s: %w", mig.Name, err)
+			}
+		}
+		return record(conn)
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if strings.TrimSpace(sql) != "" {
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return fmt.Errorf("migration %s: %w", mig.Name, err)
+		}
+	}
+	if err := record(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func ensureVersionTable(ctx context.Context, conn *pgx.Conn) error {
+	var exists bool
+	err := conn.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", versionTable).Scan(&exists)
+	if err != nil || exists {
+		return err
+	}
+
+	// Same layout goose creates, including its version 0 marker row
+	_, err = conn.Exec(ctx, `
+		CREATE TABLE `+versionTable+` (
+			id SERIAL PRIMARY KEY,
+			version_id BIGINT NOT NULL,
+			is_applied BOOLEAN NOT NULL,
+			tstamp TIMESTAMP DEFAULT NOW()
+		);
+		INSERT INTO `+versionTable+` (version_id, is_applied) VALUES (0, true);
+	`)
+	return err
+}
+
+// appliedVersions returns applied versions mapped to when they were applied
+func appliedVersions(ctx context.Context, conn *pgx.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.Query(ctx, "SELECT version_id, is_applied, tstamp FROM "+versionTable+" ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		var tstamp *time.Time
+		if err := rows.Scan(&version, &isApplied, &tstamp); err != nil {
+			return nil, err
+		}
+		if version == 0 {
+			continue
+		}
+		// Older goose releases recorded rollbacks as is_applied = false rows
+		if !isApplied {
+			delete(versions, version)
+			continue
+		}
+		var at time.Time
+		if tstamp != nil {
+			at = *tstamp
+		}
+		versions[version] = at
+	}
+	return versions, rows.Err()
+}
@@ -0,0 +1,218 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+type ApplicationStatus string
+
+const (
+	ApplicationStatusDraft       ApplicationStatus = "draft"
+	ApplicationStatusSubmitted   ApplicationStatus = "submitted"
+	ApplicationStatusUnderReview ApplicationStatus = "under_review"
+	ApplicationStatusApproved    ApplicationStatus = "approved"
+	ApplicationStatusDenied      ApplicationStatus = "denied"
+	ApplicationStatusWithdrawn   ApplicationStatus = "withdrawn"
+)
+
+// ApplicationStatuses lists the statuses staff can filter by, in lifecycle
+// order. Drafts are private to the applicant.
+var ApplicationStatuses = []ApplicationStatus{
+	ApplicationStatusSubmitted,
+	ApplicationStatusUnderReview,
+	ApplicationStatusApproved,
+	ApplicationStatusDenied,
+	ApplicationStatusWithdrawn,
+}
+
+// applicationTransitions lists the statuses each status can move to
+var applicationTransitions = map[ApplicationStatus][]ApplicationStatus{
+	ApplicationStatusDraft:       {ApplicationStatusSubmitted},
+	ApplicationStatusSubmitted:   {ApplicationStatusUnderReview, ApplicationStatusApproved, ApplicationStatusDenied, ApplicationStatusWithdrawn},
+	ApplicationStatusUnderReview: {ApplicationStatusApproved, ApplicationStatusDenied, ApplicationStatusWithdrawn},
+}
+
+// CanBecome reports whether an application can move from s to next
+func (s ApplicationStatus) CanBecome(next ApplicationStatus) bool {
+	return slices.Contains(applicationTransitions[s], next)
+}
+
+// Open reports whether the application is submitted and awaiting a decision
+func (s ApplicationStatus) Open() bool {
+	return s == ApplicationStatusSubmitted || s == ApplicationStatusUnderReview
+}
+
+func (s ApplicationStatus) Label() string {
+	switch s {
+	case ApplicationStatusDraft:
+		return "Draft"
+	case ApplicationStatusSubmitted:
+		return "Submitted"
+	case ApplicationStatusUnderReview:
+		return "Under review"
+	case ApplicationStatusApproved:
+		return "Approved"
+	case ApplicationStatusDenied:
+		return "Denied"
+	case ApplicationStatusWithdrawn:
+		return "Withdrawn"
+	default:
+		return string(s)
+	}
+}
+
+// ApplicationStep is one page of the application form
+type ApplicationStep string
+
+const (
+	ApplicationStepApplicant    ApplicationStep = "applicant"
+	ApplicationStepResidences   ApplicationStep = "residences"
+	ApplicationStepEmployment   ApplicationStep = "employment"
+	ApplicationStepReferences   ApplicationStep = "references"
+	ApplicationStepPets         ApplicationStep = "pets"
+	ApplicationStepCoApplicants ApplicationStep = "co-applicants"
+)
+
+// ApplicationSteps lists the form's steps in order
+var ApplicationSteps = []ApplicationStep{
+	ApplicationStepApplicant,
+	ApplicationStepResidences,
+	ApplicationStepEmployment,
+	ApplicationStepReferences,
+	ApplicationStepPets,
+	ApplicationStepCoApplicants,
+}
+
+func (s ApplicationStep) IsValid() bool {
+	return slices.Contains(ApplicationSteps, s)
+}
+
+func (s ApplicationStep) Label() string {
+	switch s {
+	case ApplicationStepApplicant:
+		return "About You"
+	case ApplicationStepResidences:
+		return "Residence History"
+	case ApplicationStepEmployment:
+		return "Employment & Income"
+	case ApplicationStepReferences:
+		return "References"
+	case ApplicationStepPets:
+		return "Pets"
+	case ApplicationStepCoApplicants:
+		return "Co-Applicants"
+	default:
+		return string(s)
+	}
+}
+
+// Application is a rental application for one property. ApplicantID is the
+// applicant's Clerk user ID.
+type Application struct {
+	ID          int64             `json:"id"`
+	PropertyID  int64             `json:"propertyId"`
+	ApplicantID string            `json:"applicantId"`
+	Status      ApplicationStatus `json:"status"`
+	Data        ApplicationData   `json:"data"`
+	SubmittedAt *time.Time        `json:"submittedAt,omitempty"`
+	DecidedAt   *time.Time        `json:"decidedAt,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+// ApplicationData is the applicant's answers, one field per form step.
+// Dates are kept as entered (YYYY-MM-DD, or YYYY-MM for residence and job
+// history) and amounts are whole dollars.
+type ApplicationData struct {
+	Applicant    Applicant     `json:"applicant"`
+	Residences   []Residence   `json:"residences"`
+	Employment   []Employment  `json:"employment"`
+	References   []Reference   `json:"references"`
+	Pets         []Pet         `json:"pets"`
+	CoApplicants []CoApplicant `json:"coApplicants"`
+	// Completed lists the steps the applicant has finished
+	Completed []ApplicationStep `json:"completed"`
+}
+
+// Complete reports whether every step has been finished
+func (d ApplicationData) Complete() bool {
+	for _, s := range ApplicationSteps {
+		if !slices.Contains(d.Completed, s) {
+			return false
+		}
+	}
+	return true
+}
+
+type Applicant struct {
+	FirstName   string `json:"firstName"`
+	LastName    string `json:"lastName"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	DateOfBirth string `json:"dateOfBirth"`
+	MoveInDate  string `json:"moveInDate"`
+	LeaseTerm   string `json:"leaseTerm"`
+	Occupants   int    `json:"occupants"`
+}
+
+// Name is the applicant's full name
+func (a Applicant) Name() string {
+	if a.LastName == "" {
+		return a.FirstName
+	}
+	return a.FirstName + " " + a.LastName
+}
+
+type Residence struct {
+	Address       string `json:"address"`
+	City          string `json:"city"`
+	State         string `json:"state"`
+	ZipCode       string `json:"zipCode"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+	MonthlyRent   int    `json:"monthlyRent"`
+	LandlordName  string `json:"landlordName"`
+	LandlordPhone string `json:"landlordPhone"`
+	Reason        string `json:"reason"`
+}
+
+type Employment struct {
+	Employer      string `json:"employer"`
+	Position      string `json:"position"`
+	Phone         string `json:"phone"`
+	From          string `json:"from"`
+	MonthlyIncome int    `json:"monthlyIncome"`
+}
+
+type Reference struct {
+	Name         string `json:"name"`
+	Relationship string `json:"relationship"`
+	Phone        string `json:"phone"`
+	Email        string `json:"email"`
+}
+
+type Pet struct {
+	Kind   string `json:"kind"`
+	Breed  string `json:"breed"`
+	Weight int    `json:"weight"`
+}
+
+// CoApplicant is another adult who will live at the property and must
+// apply too
+type CoApplicant struct {
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+	Relationship string `json:"relationship"`
+}
+
+// ApplicationEvent records a status change on an application
+type ApplicationEvent struct {
+	ID            int64             `json:"id"`
+	ApplicationID int64             `json:"applicationId"`
+	ActorID       string            `json:"actorId"`
+	Status        ApplicationStatus `json:"status"`
+	Note          string            `json:"note"`
+	CreatedAt     time.Time         `json:"createdAt"`
+}
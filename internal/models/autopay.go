@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// AutopayMaxChargeDay is the latest day of the month autopay can charge
+// on, so every month has it
+const AutopayMaxChargeDay = 28
+
+// AutopayRetryDays are how many days after a failed autopay charge each
+// retry happens. Once they're used up, the month is left to the tenant.
+var AutopayRetryDays = []int{1, 3}
+
+// Autopay is a tenant's standing instruction to pay their lease's balance
+// each month with a card saved with the payment gateway. PayerID is the
+// tenant's Clerk user ID; a lease has at most one Autopay.
+type Autopay struct {
+	ID          int64  `json:"id"`
+	LeaseID     int64  `json:"leaseId"`
+	PayerID     string `json:"payerId"`
+	PayerEmail  string `json:"payerEmail"`
+	Gateway     string `json:"gateway"`
+	CustomerID  string `json:"customerId"`
+	MethodID    string `json:"methodId"`
+	MethodLabel string `json:"methodLabel"`
+	// ChargeDay is the day of the month the balance is charged
+	ChargeDay int `json:"chargeDay"`
+	// Period is the first of the month last charged. Attempts counts the
+	// charges that failed that month, and RetryOn is when the next retry
+	// is due, if there is one.
+	Period    *time.Time `json:"period,omitempty"`
+	Attempts  int        `json:"attempts"`
+	RetryOn   *time.Time `json:"retryOn,omitempty"`
+	LastError string     `json:"lastError,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// Due reports whether autopay should charge today, and for which month:
+// on or after the charge day of a month not yet charged, or on a retry
+// day
+func (a Autopay) Due(today time.Time) (time.Time, bool) {
+	today = Date(today)
+	if a.RetryOn != nil && a.Period != nil {
+		return *a.Period, !today.Before(*a.RetryOn)
+	}
+	period := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if a.Period != nil && !a.Period.Before(period) {
+		return period, false
+	}
+	return period, today.Day() >= a.ChargeDay
+}
+
+// NextCharge is the next day autopay will charge, counting today if it's
+// due, a retry included
+func (a Autopay) NextCharge(today time.Time) time.Time {
+	if a.RetryOn != nil {
+		return *a.RetryOn
+	}
+	period, due := a.Due(today)
+	if due {
+		return Date(today)
+	}
+	next := time.Date(period.Year(), period.Month(), a.ChargeDay, 0, 0, 0, 0, time.UTC)
+	if a.Period != nil && !a.Period.Before(period) {
+		next = next.AddDate(0, 1, 0)
+	}
+	return next
+}
+
+// Schedule describes when autopay charges, e.g. "on the 1st of each month"
+func (a Autopay) Schedule() string {
+	return "on the " + Ordinal(a.ChargeDay) + " of each month"
+}
+
+// Ordinal writes n as 1st, 2nd, 3rd, 4th and so on
+func Ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type DocumentKind string
+
+const (
+	DocumentKindLease    DocumentKind = "lease"
+	DocumentKindAddendum DocumentKind = "addendum"
+	DocumentKindNotice   DocumentKind = "notice"
+	DocumentKindOther    DocumentKind = "other"
+)
+
+// DocumentKinds lists every kind in the order the upload form offers them
+var DocumentKinds = []DocumentKind{
+	DocumentKindLease,
+	DocumentKindAddendum,
+	DocumentKindNotice,
+	DocumentKindOther,
+}
+
+func (k DocumentKind) IsValid() bool {
+	return slices.Contains(DocumentKinds, k)
+}
+
+func (k DocumentKind) Label() string {
+	switch k {
+	case DocumentKindLease:
+		return "Lease"
+	case DocumentKindAddendum:
+		return "Addendum"
+	case DocumentKindNotice:
+		return "Notice"
+	case DocumentKindOther:
+		return "Other"
+	default:
+		return string(k)
+	}
+}
+
+// Document is a file kept in a lease's document vault. The file is in
+// private storage under StorageKey; tenants on the lease reach it through
+// short-lived signed links. UploadedBy is the Clerk user ID of the staff
+// member who added it. Generated documents record the template they came
+// from and, as JSON, the data they were rendered with; both are empty for
+// uploads. Once everyone has signed a generated document, SignedDocumentID
+// is its signed copy.
+type Document struct {
+	ID               int64        `json:"id"`
+	LeaseID          int64        `json:"leaseId"`
+	Kind             DocumentKind `json:"kind"`
+	Title            string       `json:"title"`
+	Filename         string       `json:"filename"`
+	ContentType      string       `json:"contentType"`
+	SizeBytes        int64        `json:"sizeBytes"`
+	StorageKey       string       `json:"-"`
+	UploadedBy       string       `json:"uploadedBy"`
+	Template         string       `json:"template,omitempty"`
+	TemplateData     []byte       `json:"-"`
+	SignedDocumentID *int64       `json:"signedDocumentId,omitempty"`
+	CreatedAt        time.Time    `json:"createdAt"`
+}
+
+// Size describes the file's size for people, e.g. "1.2 MB"
+func (d Document) Size() string {
+	switch {
+	case d.SizeBytes >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(d.SizeBytes)/(1<<20))
+	case d.SizeBytes >= 1<<10:
+		return fmt.Sprintf("%d KB", d.SizeBytes>>10)
+	default:
+		return fmt.Sprintf("%d bytes", d.SizeBytes)
+	}
+}
+
+// IsPDF reports whether the file is a PDF rather than an image
+func (d Document) IsPDF() bool {
+	return strings.HasPrefix(d.ContentType, "application/pdf")
+}
+
+// DocumentAccess records one time a document was served. UserID is the
+// Clerk user ID the signed link was issued to.
+type DocumentAccess struct {
+	ID         int64     `json:"id"`
+	DocumentID int64     `json:"documentId"`
+	UserID     string    `json:"userId"`
+	IPAddress  string    `json:"ipAddress"`
+	UserAgent  string    `json:"userAgent"`
+	Download   bool      `json:"download"`
+	AccessedAt time.Time `json:"accessedAt"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+type InquiryStatus string
+
+const (
+	InquiryStatusNew       InquiryStatus = "new"
+	InquiryStatusContacted InquiryStatus = "contacted"
+	InquiryStatusScheduled InquiryStatus = "scheduled"
+	InquiryStatusClosed    InquiryStatus = "closed"
+)
+
+// InquiryStatuses lists every status in workflow order
+var InquiryStatuses = []InquiryStatus{
+	InquiryStatusNew, InquiryStatusContacted, InquiryStatusScheduled, InquiryStatusClosed,
+}
+
+type InquiryEventKind string
+
+const (
+	InquiryEventStatus   InquiryEventKind = "status"
+	InquiryEventAssigned InquiryEventKind = "assigned"
+	InquiryEventNote     InquiryEventKind = "note"
+)
+
+// InquiryEvent is one entry on an inquiry's timeline. Detail holds the new
+// status, the new assignee's Clerk user ID (empty when unassigned) or the
+// note text, depending on Kind.
+type InquiryEvent struct {
+	ID           int64            `json:"id"`
+	SubmissionID int64            `json:"submissionId"`
+	ActorID      string           `json:"actorId"`
+	Kind         InquiryEventKind `json:"kind"`
+	Detail       string           `json:"detail"`
+	CreatedAt    time.Time        `json:"createdAt"`
+}
+
+// Helper for InquiryStatus
+func (s InquiryStatus) IsValid() bool {
+	switch s {
+	case InquiryStatusNew, InquiryStatusContacted, InquiryStatusScheduled, InquiryStatusClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s InquiryStatus) Label() string {
+	switch s {
+	case InquiryStatusNew:
+		return "New"
+	case InquiryStatusContacted:
+		return "Contacted"
+	case InquiryStatusScheduled:
+		return "Scheduled"
+	case InquiryStatusClosed:
+		return "Closed"
+	default:
+		return string(s)
+	}
+}
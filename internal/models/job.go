@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+type JobRunStatus string
+
+const (
+	JobRunStatusRunning   JobRunStatus = "running"
+	JobRunStatusSucceeded JobRunStatus = "succeeded"
+	JobRunStatusFailed    JobRunStatus = "failed"
+)
+
+func (s JobRunStatus) Label() string {
+	switch s {
+	case JobRunStatusRunning:
+		return "Running"
+	case JobRunStatusSucceeded:
+		return "Succeeded"
+	case JobRunStatusFailed:
+		return "Failed"
+	default:
+		return string(s)
+	}
+}
+
+// JobTrigger says what started a job run
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "schedule"
+	JobTriggerManual   JobTrigger = "manual"
+)
+
+// JobRun records one run of a background job. ScheduledFor is the time the
+// schedule fired, or when a manual run was asked for; a job runs at most
+// once per scheduled time.
+type JobRun struct {
+	ID           int64        `json:"id"`
+	Job          string       `json:"job"`
+	Trigger      JobTrigger   `json:"trigger"`
+	ScheduledFor time.Time    `json:"scheduledFor"`
+	Status       JobRunStatus `json:"status"`
+	// Summary says what the run did, e.g. "posted 4 charges"
+	Summary    string     `json:"summary,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Duration is how long the run took, or has taken so far
+func (r JobRun) Duration(now time.Time) time.Duration {
+	if r.FinishedAt != nil {
+		return r.FinishedAt.Sub(r.StartedAt)
+	}
+	return now.Sub(r.StartedAt)
+}
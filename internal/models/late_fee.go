@@ -0,0 +1,194 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LateFeeType says how the first late fee on a month's rent is worked out
+type LateFeeType string
+
+const (
+	LateFeeTypeFlat    LateFeeType = "flat"
+	LateFeeTypePercent LateFeeType = "percent"
+)
+
+// LateFeeTypes lists every late fee type
+var LateFeeTypes = []LateFeeType{LateFeeTypeFlat, LateFeeTypePercent}
+
+func (t LateFeeType) IsValid() bool {
+	return slices.Contains(LateFeeTypes, t)
+}
+
+func (t LateFeeType) Label() string {
+	switch t {
+	case LateFeeTypeFlat:
+		return "Flat amount"
+	case LateFeeTypePercent:
+		return "Percent of rent"
+	default:
+		return string(t)
+	}
+}
+
+// LateFeePolicy sets the late fees on rent. Once rent has gone unpaid for
+// GraceDays days after it was due, a fee of FlatCents or PercentBps of the
+// month's rent is charged, then DailyCents for each further day it stays
+// unpaid. MaxCents, when set, caps the fees on a month's rent, as does the
+// law of the property's state. A lease uses its own policy if it has one,
+// else its property's.
+type LateFeePolicy struct {
+	ID        int64       `json:"id"`
+	Name      string      `json:"name"`
+	GraceDays int         `json:"graceDays"`
+	Type      LateFeeType `json:"type"`
+	FlatCents int64       `json:"flatCents"`
+	// PercentBps is in basis points: 500 is 5%
+	PercentBps int   `json:"percentBps"`
+	DailyCents int64 `json:"dailyCents"`
+	MaxCents   int64 `json:"maxCents"`
+	// PropertyIDs are the properties whose leases use the policy by default
+	PropertyIDs []int64   `json:"propertyIds"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Terms sums up the policy, e.g. "5 days' grace, then $50.00 and $10.00 a
+// day, up to $150.00"
+func (p LateFeePolicy) Terms() string {
+	fee := FormatCents(p.FlatCents)
+	if p.Type == LateFeeTypePercent {
+		fee = FormatPercent(p.PercentBps) + " of rent"
+	}
+	terms := fmt.Sprintf("%d days' grace, then %s", p.GraceDays, fee)
+	if p.DailyCents > 0 {
+		terms += fmt.Sprintf(" and %s a day", FormatCents(p.DailyCents))
+	}
+	if p.MaxCents > 0 {
+		terms += ", up to " + FormatCents(p.MaxCents)
+	}
+	return terms
+}
+
+// FeeCents works out the total late fees on a month's rent of rentCents,
+// due on due and still unpaid on day, for a property in state. It's 0
+// until the grace period is over.
+func (p LateFeePolicy) FeeCents(rentCents int64, due, day time.Time, state string) int64 {
+	limit, limited := StateLateFeeLimit(state)
+	grace := p.GraceDays
+	if limited {
+		grace = max(grace, limit.MinGraceDays)
+	}
+	daysLate := int(Date(day).Sub(Date(due)).Hours()/24) - grace
+	if daysLate <= 0 {
+		return 0
+	}
+
+	fee := p.FlatCents
+	if p.Type == LateFeeTypePercent {
+		fee = percentOf(rentCents, p.PercentBps)
+	}
+	fee += p.DailyCents * int64(daysLate-1)
+	if p.MaxCents > 0 {
+		fee = min(fee, p.MaxCents)
+	}
+	if most, ok := limit.MaxCents(rentCents); limited && ok {
+		fee = min(fee, most)
+	}
+	return fee
+}
+
+// LateFeeLimit is a state's legal limit on late fees
+type LateFeeLimit struct {
+	// MinGraceDays is the fewest days after rent is due before a fee may
+	// be charged
+	MinGraceDays int
+	// PercentBps and FlatCents cap the fees on a month's rent. With both
+	// set the cap is the lower of the two, or the higher when Greater is
+	// set.
+	PercentBps int
+	FlatCents  int64
+	Greater    bool
+}
+
+// MaxCents is the most the fees on a month's rent of rentCents may come
+// to, and false if the state sets no cap
+func (l LateFeeLimit) MaxCents(rentCents int64) (int64, bool) {
+	switch {
+	case l.PercentBps > 0 && l.FlatCents > 0:
+		if l.Greater {
+			return max(percentOf(rentCents, l.PercentBps), l.FlatCents), true
+		}
+		return min(percentOf(rentCents, l.PercentBps), l.FlatCents), true
+	case l.PercentBps > 0:
+		return percentOf(rentCents, l.PercentBps), true
+	case l.FlatCents > 0:
+		return l.FlatCents, true
+	default:
+		return 0, false
+	}
+}
+
+// Describe sums up the limit, e.g. "5 days' grace; lesser of $50.00 or 5%
+// of rent"
+func (l LateFeeLimit) Describe() string {
+	var parts []string
+	if l.MinGraceDays > 0 {
+		parts = append(parts, fmt.Sprintf("%d days' grace", l.MinGraceDays))
+	}
+	switch {
+	case l.PercentBps > 0 && l.FlatCents > 0:
+		which := "lesser"
+		if l.Greater {
+			which = "greater"
+		}
+		parts = append(parts, fmt.Sprintf("%s of %s or %s of rent", which, FormatCents(l.FlatCents), FormatPercent(l.PercentBps)))
+	case l.PercentBps > 0:
+		parts = append(parts, FormatPercent(l.PercentBps)+" of rent")
+	case l.FlatCents > 0:
+		parts = append(parts, FormatCents(l.FlatCents))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// LateFeeLimits are the statutory late fee limits of the states that set
+// them, by postal code. They're applied on top of every policy. Laws
+// change and cities add their own rules, so check with counsel before
+// relying on this table.
+var LateFeeLimits = map[string]LateFeeLimit{
+	"CO": {MinGraceDays: 7, PercentBps: 500, FlatCents: 5000, Greater: true},
+	"DC": {MinGraceDays: 5, PercentBps: 500},
+	"DE": {MinGraceDays: 5, PercentBps: 500},
+	"HI": {MinGraceDays: 5, PercentBps: 800},
+	"MA": {MinGraceDays: 30},
+	"MD": {PercentBps: 500},
+	"ME": {MinGraceDays: 15, PercentBps: 400},
+	"MN": {PercentBps: 800},
+	"NC": {MinGraceDays: 5, PercentBps: 500, FlatCents: 1500, Greater: true},
+	"NM": {PercentBps: 1000},
+	"NV": {PercentBps: 500},
+	"NY": {MinGraceDays: 5, PercentBps: 500, FlatCents: 5000},
+	"OR": {MinGraceDays: 4},
+	"TX": {MinGraceDays: 2, PercentBps: 1200},
+	"VA": {MinGraceDays: 5, PercentBps: 1000},
+	"WA": {MinGraceDays: 5},
+}
+
+// StateLateFeeLimit looks up the late fee limit for a state's postal code
+func StateLateFeeLimit(state string) (LateFeeLimit, bool) {
+	limit, ok := LateFeeLimits[strings.ToUpper(strings.TrimSpace(state))]
+	return limit, ok
+}
+
+// FormatPercent formats basis points as a percentage, e.g. 750 as "7.5%"
+func FormatPercent(bps int) string {
+	return strconv.FormatFloat(float64(bps)/100, 'f', -1, 64) + "%"
+}
+
+// percentOf is bps basis points of cents, rounded to the nearest cent
+func percentOf(cents int64, bps int) int64 {
+	return (cents*int64(bps) + 5000) / 10000
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestLateFeePolicyFeeCents(t *testing.T) {
+	flat := LateFeePolicy{GraceDays: 5, Type: LateFeeTypeFlat, FlatCents: 5000, DailyCents: 1000}
+	percent := LateFeePolicy{GraceDays: 5, Type: LateFeeTypePercent, PercentBps: 500}
+	capped := flat
+	capped.MaxCents = 6000
+	due := date(2026, time.March, 1)
+
+	tests := []struct {
+		name   string
+		policy LateFeePolicy
+		rent   int64
+		day    time.Time
+		state  string
+		want   int64
+	}{
+		{"before due", flat, 150000, date(2026, time.February, 28), "CA", 0},
+		{"last day of grace", flat, 150000, date(2026, time.March, 6), "CA", 0},
+		{"first day late", flat, 150000, date(2026, time.March, 7), "CA", 5000},
+		{"time of day ignored", flat, 150000, time.Date(2026, time.March, 7, 23, 59, 0, 0, time.UTC), "CA", 5000},
+		{"daily accrual", flat, 150000, date(2026, time.March, 9), "CA", 7000},
+		{"no grace", LateFeePolicy{Type: LateFeeTypeFlat, FlatCents: 2500}, 150000, date(2026, time.March, 2), "CA", 2500},
+		{"percent of rent", percent, 150000, date(2026, time.March, 7), "CA", 7500},
+		{"percent rounds to the cent", percent, 123456, date(2026, time.March, 7), "CA", 6173},
+		{"percent with daily accrual", LateFeePolicy{GraceDays: 5, Type: LateFeeTypePercent, PercentBps: 500, DailyCents: 500}, 150000, date(2026, time.March, 10), "CA", 9000},
+		{"under policy cap", capped, 150000, date(2026, time.March, 7), "CA", 5000},
+		{"policy cap", capped, 150000, date(2026, time.March, 20), "CA", 6000},
+		{"state grace overrides shorter policy grace", LateFeePolicy{GraceDays: 2, Type: LateFeeTypeFlat, FlatCents: 4000}, 150000, date(2026, time.March, 6), "NY", 0},
+		{"longer policy grace kept", LateFeePolicy{GraceDays: 10, Type: LateFeeTypeFlat, FlatCents: 4000}, 150000, date(2026, time.March, 10), "NY", 0},
+		{"state lesser of cap", flat, 150000, date(2026, time.March, 20), "NY", 5000},
+		{"state lesser of cap on low rent", flat, 60000, date(2026, time.March, 20), "NY", 3000},
+		{"state greater of cap", LateFeePolicy{GraceDays: 7, Type: LateFeeTypeFlat, FlatCents: 5000, DailyCents: 1000}, 150000, date(2026, time.March, 30), "CO", 7500},
+		{"state greater of cap on low rent", LateFeePolicy{GraceDays: 7, Type: LateFeeTypeFlat, FlatCents: 5000, DailyCents: 1000}, 60000, date(2026, time.March, 30), "CO", 5000},
+		{"state percent cap", flat, 150000, date(2026, time.March, 30), "TX", 18000},
+		{"state grace without a cap", flat, 150000, date(2026, time.March, 20), "MA", 0},
+		{"state without a cap charges in full", flat, 150000, date(2026, time.April, 20), "MA", 5000 + 1000*19},
+		{"state matched loosely", flat, 150000, date(2026, time.March, 20), " ny ", 5000},
+		{"policy cap under state cap", capped, 150000, date(2026, time.March, 20), "TX", 6000},
+		{"across month end", flat, 150000, date(2026, time.April, 1), "CA", 5000 + 1000*25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.FeeCents(tt.rent, due, tt.day, tt.state); got != tt.want {
+				t.Errorf("FeeCents = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLateFeeLimitMaxCents(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  LateFeeLimit
+		rent   int64
+		want   int64
+		capped bool
+	}{
+		{"no cap", LateFeeLimit{MinGraceDays: 5}, 150000, 0, false},
+		{"percent", LateFeeLimit{PercentBps: 800}, 150000, 12000, true},
+		{"flat", LateFeeLimit{FlatCents: 2500}, 150000, 2500, true},
+		{"lesser of", LateFeeLimit{PercentBps: 500, FlatCents: 5000}, 150000, 5000, true},
+		{"greater of", LateFeeLimit{PercentBps: 500, FlatCents: 1500, Greater: true}, 150000, 7500, true},
+		{"greater of on low rent", LateFeeLimit{PercentBps: 500, FlatCents: 1500, Greater: true}, 20000, 1500, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, capped := tt.limit.MaxCents(tt.rent)
+			if got != tt.want || capped != tt.capped {
+				t.Errorf("MaxCents = %d, %t, want %d, %t", got, capped, tt.want, tt.capped)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+type LeaseStatus string
+
+const (
+	LeaseStatusDraft            LeaseStatus = "draft"
+	LeaseStatusPendingSignature LeaseStatus = "pending_signature"
+	LeaseStatusActive           LeaseStatus = "active"
+	LeaseStatusEnded            LeaseStatus = "ended"
+	LeaseStatusTerminated       LeaseStatus = "terminated"
+)
+
+// LeaseStatuses lists every lease status in lifecycle order
+var LeaseStatuses = []LeaseStatus{
+	LeaseStatusDraft,
+	LeaseStatusPendingSignature,
+	LeaseStatusActive,
+	LeaseStatusEnded,
+	LeaseStatusTerminated,
+}
+
+// leaseTransitions lists the statuses each status can move to
+var leaseTransitions = map[LeaseStatus][]LeaseStatus{
+	LeaseStatusDraft:            {LeaseStatusPendingSignature, LeaseStatusActive, LeaseStatusTerminated},
+	LeaseStatusPendingSignature: {LeaseStatusDraft, LeaseStatusActive, LeaseStatusTerminated},
+	LeaseStatusActive:           {LeaseStatusEnded, LeaseStatusTerminated},
+}
+
+// CanBecome reports whether a lease can move from s to next
+func (s LeaseStatus) CanBecome(next LeaseStatus) bool {
+	return slices.Contains(leaseTransitions[s], next)
+}
+
+// VisibleToTenant reports whether tenants can see a lease in status s.
+// Drafts are still being prepared by staff.
+func (s LeaseStatus) VisibleToTenant() bool {
+	return s != LeaseStatusDraft
+}
+
+func (s LeaseStatus) Label() string {
+	switch s {
+	case LeaseStatusDraft:
+		return "Draft"
+	case LeaseStatusPendingSignature:
+		return "Awaiting signatures"
+	case LeaseStatusActive:
+		return "Active"
+	case LeaseStatusEnded:
+		return "Ended"
+	case LeaseStatusTerminated:
+		return "Terminated"
+	default:
+		return string(s)
+	}
+}
+
+// Lease is a rental agreement for a property. StartDate and EndDate are
+// the first and last days of the lease, at midnight UTC. Amounts are in
+// cents; rent is due on the first of each month.
+type Lease struct {
+	ID         int64 `json:"id"`
+	PropertyID int64 `json:"propertyId"`
+	// ApplicationID is the application the lease came from, if any
+	ApplicationID    *int64      `json:"applicationId,omitempty"`
+	Status           LeaseStatus `json:"status"`
+	StartDate        time.Time   `json:"startDate"`
+	EndDate          time.Time   `json:"endDate"`
+	Term             string      `json:"term"`
+	MonthlyRentCents int64       `json:"monthlyRentCents"`
+	DepositCents     int64       `json:"depositCents"`
+	PetRentCents     int64       `json:"petRentCents"`
+	// LateFeePolicyID overrides the property's late fee policy
+	LateFeePolicyID *int64        `json:"lateFeePolicyId,omitempty"`
+	Notes           string        `json:"notes,omitempty"`
+	Tenants         []LeaseTenant `json:"tenants"`
+	CreatedAt       time.Time     `json:"createdAt"`
+	UpdatedAt       time.Time     `json:"updatedAt"`
+}
+
+// LeaseTenant is a person on a lease. UserID is their Clerk user ID.
+type LeaseTenant struct {
+	UserID string `json:"userId"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+}
+
+// MonthlyTotalCents is the rent and pet rent due each month
+func (l Lease) MonthlyTotalCents() int64 {
+	return l.MonthlyRentCents + l.PetRentCents
+}
+
+// HasTenant reports whether userID is one of the lease's tenants
+func (l Lease) HasTenant(userID string) bool {
+	return slices.ContainsFunc(l.Tenants, func(t LeaseTenant) bool { return t.UserID == userID })
+}
+
+// Tenant finds the tenant whose user ID is userID
+func (l Lease) Tenant(userID string) (LeaseTenant, bool) {
+	for _, t := range l.Tenants {
+		if t.UserID == userID {
+			return t, true
+		}
+	}
+	return LeaseTenant{}, false
+}
+
+// TenantNames lists the tenants' names
+func (l Lease) TenantNames() []string {
+	names := make([]string, len(l.Tenants))
+	for i, t := range l.Tenants {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// Covers reports whether day falls within the lease's dates
+func (l Lease) Covers(day time.Time) bool {
+	day = Date(day)
+	return !day.Before(l.StartDate) && !day.After(l.EndDate)
+}
+
+// Overlaps reports whether the lease's dates share any day with other's
+func (l Lease) Overlaps(other Lease) bool {
+	return !l.EndDate.Before(other.StartDate) && !other.EndDate.Before(l.StartDate)
+}
+
+// NextRentDue returns the first rent due date on or after day, and false
+// once the lease has no more due dates. The first month's rent is due on
+// the start date.
+func (l Lease) NextRentDue(day time.Time) (time.Time, bool) {
+	day = Date(day)
+	if !day.After(l.StartDate) {
+		return l.StartDate, true
+	}
+	due := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if due.Before(day) {
+		due = due.AddDate(0, 1, 0)
+	}
+	if due.After(l.EndDate) {
+		return time.Time{}, false
+	}
+	return due, true
+}
+
+// Date truncates t to midnight UTC on its calendar day
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
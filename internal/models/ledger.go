@@ -0,0 +1,255 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// LedgerKind says what a ledger entry records. Rent, pet rent, late fees
+// and other charges add to what the tenant owes; credits and payments take
+// from it.
+type LedgerKind string
+
+const (
+	LedgerKindRent    LedgerKind = "rent"
+	LedgerKindPetRent LedgerKind = "pet_rent"
+	LedgerKindLateFee LedgerKind = "late_fee"
+	LedgerKindCharge  LedgerKind = "charge"
+	LedgerKindCredit  LedgerKind = "credit"
+	LedgerKindPayment LedgerKind = "payment"
+)
+
+// LedgerKinds lists every ledger entry kind
+var LedgerKinds = []LedgerKind{
+	LedgerKindRent,
+	LedgerKindPetRent,
+	LedgerKindLateFee,
+	LedgerKindCharge,
+	LedgerKindCredit,
+	LedgerKindPayment,
+}
+
+// ManualLedgerKinds lists the kinds staff post by hand. Rent and pet rent
+// are posted from the lease, and late fees by its late fee policy.
+var ManualLedgerKinds = []LedgerKind{
+	LedgerKindCharge,
+	LedgerKindCredit,
+	LedgerKindPayment,
+}
+
+func (k LedgerKind) IsValid() bool {
+	return slices.Contains(LedgerKinds, k)
+}
+
+// IsCharge reports whether entries of kind k add to the balance
+func (k LedgerKind) IsCharge() bool {
+	return k == LedgerKindRent || k == LedgerKindPetRent || k == LedgerKindLateFee || k == LedgerKindCharge
+}
+
+// IsRent reports whether entries of kind k are rent the lease charges
+func (k LedgerKind) IsRent() bool {
+	return k == LedgerKindRent || k == LedgerKindPetRent
+}
+
+func (k LedgerKind) Label() string {
+	switch k {
+	case LedgerKindRent:
+		return "Rent"
+	case LedgerKindPetRent:
+		return "Pet rent"
+	case LedgerKindLateFee:
+		return "Late fee"
+	case LedgerKindCharge:
+		return "Charge"
+	case LedgerKindCredit:
+		return "Credit"
+	case LedgerKindPayment:
+		return "Payment"
+	default:
+		return string(k)
+	}
+}
+
+// LedgerEntry is one line of a lease's ledger. AmountCents is always
+// positive; Kind says which way it moves the balance. Charges are due on
+// PostedOn. Period is the first of the month a scheduled charge covers;
+// each lease has at most one entry per kind and period, except late fees,
+// which have at most one per period and day.
+type LedgerEntry struct {
+	ID          int64      `json:"id"`
+	LeaseID     int64      `json:"leaseId"`
+	Kind        LedgerKind `json:"kind"`
+	Description string     `json:"description"`
+	AmountCents int64      `json:"amountCents"`
+	PostedOn    time.Time  `json:"postedOn"`
+	Period      *time.Time `json:"period,omitempty"`
+	// CreatedBy is the Clerk user ID of the staff member who posted the
+	// entry, empty for entries posted automatically
+	CreatedBy string `json:"createdBy,omitempty"`
+	// PaymentID is the gateway payment a payment entry records; each is
+	// posted once
+	PaymentID *int64    `json:"paymentId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SignedCents is the entry's effect on the balance
+func (e LedgerEntry) SignedCents() int64 {
+	if e.Kind.IsCharge() {
+		return e.AmountCents
+	}
+	return -e.AmountCents
+}
+
+// Ledger is a lease's entries, oldest first
+type Ledger []LedgerEntry
+
+// Sort orders the entries by posting date, then by ID
+func (l Ledger) Sort() {
+	sort.SliceStable(l, func(i, j int) bool {
+		if !l[i].PostedOn.Equal(l[j].PostedOn) {
+			return l[i].PostedOn.Before(l[j].PostedOn)
+		}
+		return l[i].ID < l[j].ID
+	})
+}
+
+// BalanceCents is what the tenant owes; negative when they're in credit
+func (l Ledger) BalanceCents() int64 {
+	var balance int64
+	for _, e := range l {
+		balance += e.SignedCents()
+	}
+	return balance
+}
+
+// RunningBalances returns the balance after each entry
+func (l Ledger) RunningBalances() []int64 {
+	balances := make([]int64, len(l))
+	var balance int64
+	for i, e := range l {
+		balance += e.SignedCents()
+		balances[i] = balance
+	}
+	return balances
+}
+
+// LedgerSummary is where a ledger stands on a given day
+type LedgerSummary struct {
+	BalanceCents int64
+	// PastDueCents is the part of the balance from charges due before the
+	// day
+	PastDueCents int64
+	// OldestUnpaid is the due date of the oldest charge not yet covered by
+	// payments and credits, zero if everything is paid
+	OldestUnpaid time.Time
+}
+
+// Outstanding returns how much of each entry is still owed. Payments and
+// credits pay off rent and pet rent first, oldest first, then the other
+// charges, so an unpaid fee never leaves rent unpaid to run up more late
+// fees. Payments and credits themselves are always 0.
+func (l Ledger) Outstanding() []int64 {
+	var paid int64
+	for _, e := range l {
+		if !e.Kind.IsCharge() {
+			paid += e.AmountCents
+		}
+	}
+
+	outstanding := make([]int64, len(l))
+	for _, rent := range []bool{true, false} {
+		for i, e := range l {
+			if !e.Kind.IsCharge() || e.Kind.IsRent() != rent {
+				continue
+			}
+			covered := min(paid, e.AmountCents)
+			paid -= covered
+			outstanding[i] = e.AmountCents - covered
+		}
+	}
+	return outstanding
+}
+
+// Summary works out the balance and what's past due on day
+func (l Ledger) Summary(day time.Time) LedgerSummary {
+	day = Date(day)
+	s := LedgerSummary{BalanceCents: l.BalanceCents()}
+	for i, owed := range l.Outstanding() {
+		if owed == 0 {
+			continue
+		}
+		e := l[i]
+		if s.OldestUnpaid.IsZero() {
+			s.OldestUnpaid = e.PostedOn
+		}
+		if e.PostedOn.Before(day) {
+			s.PastDueCents += owed
+		}
+	}
+	return s
+}
+
+// Label sums up the ledger for a tenant
+func (s LedgerSummary) Label() string {
+	switch {
+	case s.PastDueCents > 0:
+		return "Past due"
+	case s.BalanceCents > 0:
+		return "Due"
+	case s.BalanceCents < 0:
+		return "Credit"
+	default:
+		return "Paid"
+	}
+}
+
+// ScheduledCharges lists the rent and pet rent charges falling due on or
+// before through. Each month's charge is due on the first, except the
+// first month's, which is due on the start date. Months the lease only
+// partly covers are prorated by day.
+func (l Lease) ScheduledCharges(through time.Time) []LedgerEntry {
+	through = Date(through)
+	var entries []LedgerEntry
+	for period := time.Date(l.StartDate.Year(), l.StartDate.Month(), 1, 0, 0, 0, 0, time.UTC); !period.After(l.EndDate); period = period.AddDate(0, 1, 0) {
+		due := period
+		if due.Before(l.StartDate) {
+			due = l.StartDate
+		}
+		if due.After(through) {
+			break
+		}
+
+		monthEnd := period.AddDate(0, 1, -1)
+		last := monthEnd
+		if last.After(l.EndDate) {
+			last = l.EndDate
+		}
+		days := int64(last.Sub(due).Hours()/24) + 1
+		inMonth := int64(monthEnd.Day())
+		month := period.Format("January 2006")
+		note := ""
+		if days < inMonth {
+			note = fmt.Sprintf(" (%d of %d days)", days, inMonth)
+		}
+
+		charge := func(kind LedgerKind, description string, monthly int64) {
+			if monthly <= 0 {
+				return
+			}
+			p := period
+			entries = append(entries, LedgerEntry{
+				LeaseID:     l.ID,
+				Kind:        kind,
+				Description: description + " for " + month + note,
+				AmountCents: (monthly*days + inMonth/2) / inMonth,
+				PostedOn:    due,
+				Period:      &p,
+			})
+		}
+		charge(LedgerKindRent, "Rent", l.MonthlyRentCents)
+		charge(LedgerKindPetRent, "Pet rent", l.PetRentCents)
+	}
+	return entries
+}
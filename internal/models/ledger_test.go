@@ -0,0 +1,264 @@
+package models
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLeaseScheduledCharges(t *testing.T) {
+	type charge struct {
+		kind  LedgerKind
+		due   time.Time
+		cents int64
+	}
+	rent := func(due time.Time, cents int64) charge { return charge{LedgerKindRent, due, cents} }
+	pet := func(due time.Time, cents int64) charge { return charge{LedgerKindPetRent, due, cents} }
+
+	tests := []struct {
+		name    string
+		lease   Lease
+		through time.Time
+		want    []charge
+	}{
+		{
+			name:    "nothing due before the start",
+			lease:   Lease{StartDate: date(2026, time.January, 1), EndDate: date(2026, time.December, 31), MonthlyRentCents: 150000},
+			through: date(2025, time.December, 31),
+		},
+		{
+			name:    "whole months due on the first",
+			lease:   Lease{StartDate: date(2026, time.January, 1), EndDate: date(2026, time.December, 31), MonthlyRentCents: 150000},
+			through: date(2026, time.March, 15),
+			want: []charge{
+				rent(date(2026, time.January, 1), 150000),
+				rent(date(2026, time.February, 1), 150000),
+				rent(date(2026, time.March, 1), 150000),
+			},
+		},
+		{
+			name:    "time of day ignored",
+			lease:   Lease{StartDate: date(2026, time.January, 1), EndDate: date(2026, time.December, 31), MonthlyRentCents: 150000},
+			through: time.Date(2026, time.February, 1, 23, 30, 0, 0, time.UTC),
+			want: []charge{
+				rent(date(2026, time.January, 1), 150000),
+				rent(date(2026, time.February, 1), 150000),
+			},
+		},
+		{
+			name:    "partial first month due on the start",
+			lease:   Lease{StartDate: date(2026, time.January, 15), EndDate: date(2026, time.December, 31), MonthlyRentCents: 150000},
+			through: date(2026, time.February, 1),
+			want: []charge{
+				rent(date(2026, time.January, 15), 82258),
+				rent(date(2026, time.February, 1), 150000),
+			},
+		},
+		{
+			name:    "partial last month",
+			lease:   Lease{StartDate: date(2026, time.April, 1), EndDate: date(2026, time.June, 10), MonthlyRentCents: 150000},
+			through: date(2026, time.December, 31),
+			want: []charge{
+				rent(date(2026, time.April, 1), 150000),
+				rent(date(2026, time.May, 1), 150000),
+				rent(date(2026, time.June, 1), 50000),
+			},
+		},
+		{
+			name:    "start and end in one month",
+			lease:   Lease{StartDate: date(2026, time.March, 10), EndDate: date(2026, time.March, 20), MonthlyRentCents: 150000},
+			through: date(2026, time.December, 31),
+			want:    []charge{rent(date(2026, time.March, 10), 53226)},
+		},
+		{
+			name:    "start on the last day of a month",
+			lease:   Lease{StartDate: date(2026, time.January, 31), EndDate: date(2026, time.December, 31), MonthlyRentCents: 150000},
+			through: date(2026, time.February, 1),
+			want: []charge{
+				rent(date(2026, time.January, 31), 4839),
+				rent(date(2026, time.February, 1), 150000),
+			},
+		},
+		{
+			name:    "start mid February",
+			lease:   Lease{StartDate: date(2026, time.February, 15), EndDate: date(2027, time.February, 14), MonthlyRentCents: 150000},
+			through: date(2026, time.February, 28),
+			want:    []charge{rent(date(2026, time.February, 15), 75000)},
+		},
+		{
+			name:    "start mid February in a leap year",
+			lease:   Lease{StartDate: date(2028, time.February, 16), EndDate: date(2029, time.February, 15), MonthlyRentCents: 150000},
+			through: date(2028, time.February, 29),
+			want:    []charge{rent(date(2028, time.February, 16), 72414)},
+		},
+		{
+			name:    "start on leap day",
+			lease:   Lease{StartDate: date(2028, time.February, 29), EndDate: date(2029, time.February, 28), MonthlyRentCents: 150000},
+			through: date(2028, time.March, 1),
+			want: []charge{
+				rent(date(2028, time.February, 29), 5172),
+				rent(date(2028, time.March, 1), 150000),
+			},
+		},
+		{
+			name:    "whole leap February",
+			lease:   Lease{StartDate: date(2028, time.February, 1), EndDate: date(2028, time.February, 29), MonthlyRentCents: 150000},
+			through: date(2028, time.December, 31),
+			want:    []charge{rent(date(2028, time.February, 1), 150000)},
+		},
+		{
+			name:    "pet rent prorated alongside rent",
+			lease:   Lease{StartDate: date(2026, time.January, 15), EndDate: date(2026, time.December, 31), MonthlyRentCents: 150000, PetRentCents: 5000},
+			through: date(2026, time.February, 1),
+			want: []charge{
+				rent(date(2026, time.January, 15), 82258),
+				pet(date(2026, time.January, 15), 2742),
+				rent(date(2026, time.February, 1), 150000),
+				pet(date(2026, time.February, 1), 5000),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := tt.lease.ScheduledCharges(tt.through)
+			got := make([]charge, len(entries))
+			for i, e := range entries {
+				got[i] = charge{e.Kind, e.PostedOn, e.AmountCents}
+				period := time.Date(e.PostedOn.Year(), e.PostedOn.Month(), 1, 0, 0, 0, 0, time.UTC)
+				if e.Period == nil || !e.Period.Equal(period) {
+					t.Errorf("entry %d period = %v, want %s", i, e.Period, period.Format(time.DateOnly))
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ScheduledCharges = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeaseScheduledChargesDescription(t *testing.T) {
+	lease := Lease{StartDate: date(2026, time.January, 15), EndDate: date(2026, time.December, 31), MonthlyRentCents: 150000}
+	entries := lease.ScheduledCharges(date(2026, time.February, 1))
+	want := []string{"Rent for January 2026 (17 of 31 days)", "Rent for February 2026"}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if e.Description != want[i] {
+			t.Errorf("entry %d description = %q, want %q", i, e.Description, want[i])
+		}
+	}
+}
+
+func TestLedgerOutstanding(t *testing.T) {
+	entry := func(kind LedgerKind, posted time.Time, cents int64) LedgerEntry {
+		return LedgerEntry{Kind: kind, PostedOn: posted, AmountCents: cents}
+	}
+	jan, feb, mar := date(2026, time.January, 1), date(2026, time.February, 1), date(2026, time.March, 1)
+
+	tests := []struct {
+		name        string
+		ledger      Ledger
+		outstanding []int64
+		balance     int64
+	}{
+		{
+			name:   "empty",
+			ledger: Ledger{},
+		},
+		{
+			name: "nothing paid",
+			ledger: Ledger{
+				entry(LedgerKindRent, jan, 150000),
+				entry(LedgerKindPetRent, jan, 5000),
+			},
+			outstanding: []int64{150000, 5000},
+			balance:     155000,
+		},
+		{
+			name: "oldest rent paid first",
+			ledger: Ledger{
+				entry(LedgerKindRent, jan, 150000),
+				entry(LedgerKindRent, feb, 150000),
+				entry(LedgerKindPayment, feb, 200000),
+			},
+			outstanding: []int64{0, 100000, 0},
+			balance:     100000,
+		},
+		{
+			name: "credits count as payments",
+			ledger: Ledger{
+				entry(LedgerKindRent, jan, 150000),
+				entry(LedgerKindCredit, jan, 20000),
+				entry(LedgerKindPayment, jan, 100000),
+			},
+			outstanding: []int64{30000, 0, 0},
+			balance:     30000,
+		},
+		{
+			name: "payment before the charge",
+			ledger: Ledger{
+				entry(LedgerKindPayment, jan, 150000),
+				entry(LedgerKindRent, feb, 150000),
+			},
+			outstanding: []int64{0, 0},
+			balance:     0,
+		},
+		{
+			name: "overpaid",
+			ledger: Ledger{
+				entry(LedgerKindRent, jan, 150000),
+				entry(LedgerKindPayment, jan, 200000),
+			},
+			outstanding: []int64{0, 0},
+			balance:     -50000,
+		},
+		{
+			name: "rent before an older late fee",
+			ledger: Ledger{
+				entry(LedgerKindRent, jan, 150000),
+				entry(LedgerKindLateFee, date(2026, time.January, 7), 5000),
+				entry(LedgerKindRent, feb, 150000),
+				entry(LedgerKindPayment, feb, 300000),
+			},
+			outstanding: []int64{0, 5000, 0, 0},
+			balance:     5000,
+		},
+		{
+			name: "rent before an older charge",
+			ledger: Ledger{
+				entry(LedgerKindCharge, jan, 30000),
+				entry(LedgerKindRent, feb, 150000),
+				entry(LedgerKindPetRent, feb, 5000),
+				entry(LedgerKindPayment, feb, 165000),
+			},
+			outstanding: []int64{20000, 0, 0, 0},
+			balance:     20000,
+		},
+		{
+			name: "fees and charges oldest first once rent is paid",
+			ledger: Ledger{
+				entry(LedgerKindLateFee, jan, 5000),
+				entry(LedgerKindCharge, feb, 30000),
+				entry(LedgerKindRent, mar, 150000),
+				entry(LedgerKindPayment, mar, 160000),
+			},
+			outstanding: []int64{0, 25000, 0, 0},
+			balance:     25000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outstanding := tt.ledger.Outstanding()
+			if len(tt.outstanding) == 0 {
+				tt.outstanding = make([]int64, len(tt.ledger))
+			}
+			if !slices.Equal(outstanding, tt.outstanding) {
+				t.Errorf("Outstanding = %v, want %v", outstanding, tt.outstanding)
+			}
+			if balance := tt.ledger.BalanceCents(); balance != tt.balance {
+				t.Errorf("BalanceCents = %d, want %d", balance, tt.balance)
+			}
+		})
+	}
+}
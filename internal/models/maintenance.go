@@ -0,0 +1,222 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+type MaintenanceCategory string
+
+const (
+	MaintenanceCategoryPlumbing       MaintenanceCategory = "plumbing"
+	MaintenanceCategoryElectrical     MaintenanceCategory = "electrical"
+	MaintenanceCategoryHeatingCooling MaintenanceCategory = "heating_cooling"
+	MaintenanceCategoryAppliance      MaintenanceCategory = "appliance"
+	MaintenanceCategoryPests          MaintenanceCategory = "pests"
+	MaintenanceCategoryDoorsLocks     MaintenanceCategory = "doors_locks"
+	MaintenanceCategoryExterior       MaintenanceCategory = "exterior"
+	MaintenanceCategoryOther          MaintenanceCategory = "other"
+)
+
+// MaintenanceCategories lists every category in the order the form offers
+// them
+var MaintenanceCategories = []MaintenanceCategory{
+	MaintenanceCategoryPlumbing,
+	MaintenanceCategoryElectrical,
+	MaintenanceCategoryHeatingCooling,
+	MaintenanceCategoryAppliance,
+	MaintenanceCategoryPests,
+	MaintenanceCategoryDoorsLocks,
+	MaintenanceCategoryExterior,
+	MaintenanceCategoryOther,
+}
+
+func (c MaintenanceCategory) IsValid() bool {
+	return slices.Contains(MaintenanceCategories, c)
+}
+
+func (c MaintenanceCategory) Label() string {
+	switch c {
+	case MaintenanceCategoryPlumbing:
+		return "Plumbing"
+	case MaintenanceCategoryElectrical:
+		return "Electrical"
+	case MaintenanceCategoryHeatingCooling:
+		return "Heating & cooling"
+	case MaintenanceCategoryAppliance:
+		return "Appliance"
+	case MaintenanceCategoryPests:
+		return "Pests"
+	case MaintenanceCategoryDoorsLocks:
+		return "Doors & locks"
+	case MaintenanceCategoryExterior:
+		return "Exterior & grounds"
+	case MaintenanceCategoryOther:
+		return "Other"
+	default:
+		return string(c)
+	}
+}
+
+type MaintenanceUrgency string
+
+const (
+	MaintenanceUrgencyRoutine   MaintenanceUrgency = "routine"
+	MaintenanceUrgencyUrgent    MaintenanceUrgency = "urgent"
+	MaintenanceUrgencyEmergency MaintenanceUrgency = "emergency"
+)
+
+// MaintenanceUrgencies lists every urgency, least urgent first
+var MaintenanceUrgencies = []MaintenanceUrgency{
+	MaintenanceUrgencyRoutine,
+	MaintenanceUrgencyUrgent,
+	MaintenanceUrgencyEmergency,
+}
+
+func (u MaintenanceUrgency) IsValid() bool {
+	return slices.Contains(MaintenanceUrgencies, u)
+}
+
+func (u MaintenanceUrgency) Label() string {
+	switch u {
+	case MaintenanceUrgencyRoutine:
+		return "Routine"
+	case MaintenanceUrgencyUrgent:
+		return "Urgent"
+	case MaintenanceUrgencyEmergency:
+		return "Emergency"
+	default:
+		return string(u)
+	}
+}
+
+// Description explains to tenants when to choose u
+func (u MaintenanceUrgency) Description() string {
+	switch u {
+	case MaintenanceUrgencyRoutine:
+		return "Can wait for a scheduled visit, like a dripping tap or a sticking door."
+	case MaintenanceUrgencyUrgent:
+		return "Needs fixing within a day or two, like no hot water or a broken fridge."
+	case MaintenanceUrgencyEmergency:
+		return "Risk to people or the home, like flooding, no heat in winter or a gas smell."
+	default:
+		return ""
+	}
+}
+
+type MaintenanceStatus string
+
+const (
+	MaintenanceStatusOpen       MaintenanceStatus = "open"
+	MaintenanceStatusScheduled  MaintenanceStatus = "scheduled"
+	MaintenanceStatusInProgress MaintenanceStatus = "in_progress"
+	MaintenanceStatusResolved   MaintenanceStatus = "resolved"
+)
+
+// MaintenanceStatuses lists every status in workflow order
+var MaintenanceStatuses = []MaintenanceStatus{
+	MaintenanceStatusOpen,
+	MaintenanceStatusScheduled,
+	MaintenanceStatusInProgress,
+	MaintenanceStatusResolved,
+}
+
+func (s MaintenanceStatus) IsValid() bool {
+	return slices.Contains(MaintenanceStatuses, s)
+}
+
+func (s MaintenanceStatus) Label() string {
+	switch s {
+	case MaintenanceStatusOpen:
+		return "Open"
+	case MaintenanceStatusScheduled:
+		return "Scheduled"
+	case MaintenanceStatusInProgress:
+		return "In progress"
+	case MaintenanceStatusResolved:
+		return "Resolved"
+	default:
+		return string(s)
+	}
+}
+
+type MaintenanceEventKind string
+
+const (
+	MaintenanceEventStatus    MaintenanceEventKind = "status"
+	MaintenanceEventScheduled MaintenanceEventKind = "scheduled"
+	MaintenanceEventAssigned  MaintenanceEventKind = "assigned"
+	MaintenanceEventVendor    MaintenanceEventKind = "vendor"
+	MaintenanceEventMessage   MaintenanceEventKind = "message"
+	MaintenanceEventNote      MaintenanceEventKind = "note"
+)
+
+// MaintenanceEvent is one entry on a request's timeline. Detail holds the
+// new status, the visit time (RFC 3339, empty when unscheduled), the new
+// assignee's Clerk user ID, the vendor or the message text, depending on
+// Kind.
+type MaintenanceEvent struct {
+	ID        int64                `json:"id"`
+	RequestID int64                `json:"requestId"`
+	ActorID   string               `json:"actorId"`
+	Kind      MaintenanceEventKind `json:"kind"`
+	Detail    string               `json:"detail"`
+	CreatedAt time.Time            `json:"createdAt"`
+}
+
+// VisibleToTenant reports whether tenants see e on their timeline.
+// Assignments and notes are internal to staff.
+func (e MaintenanceEvent) VisibleToTenant() bool {
+	return e.Kind != MaintenanceEventAssigned && e.Kind != MaintenanceEventNote
+}
+
+// ScheduledFor parses the visit time of a scheduled event. It returns nil
+// when the visit was cancelled.
+func (e MaintenanceEvent) ScheduledFor() *time.Time {
+	if e.Kind != MaintenanceEventScheduled {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, e.Detail)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// MaintenancePhoto is a photo a tenant attached to a request. It is kept
+// in private storage under StorageKey and only served through signed
+// links.
+type MaintenancePhoto struct {
+	ID         int64     `json:"id"`
+	RequestID  int64     `json:"requestId"`
+	StorageKey string    `json:"-"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// MaintenanceRequest is a repair a tenant asked for on their lease's
+// property. TenantID is the submitter's Clerk user ID and AssigneeID that of
+// the staff member handling it. Vendor is who is doing the work.
+type MaintenanceRequest struct {
+	ID          int64               `json:"id"`
+	LeaseID     int64               `json:"leaseId"`
+	PropertyID  int64               `json:"propertyId"`
+	TenantID    string              `json:"tenantId"`
+	TenantName  string              `json:"tenantName"`
+	TenantEmail string              `json:"tenantEmail"`
+	Category    MaintenanceCategory `json:"category"`
+	Urgency     MaintenanceUrgency  `json:"urgency"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	// EntryPermitted is whether staff and vendors may let themselves in
+	// when no one is home. EntryNotes covers pets, alarms and the like.
+	EntryPermitted bool               `json:"entryPermitted"`
+	EntryNotes     string             `json:"entryNotes"`
+	Status         MaintenanceStatus  `json:"status"`
+	AssigneeID     string             `json:"assigneeId,omitempty"`
+	Vendor         string             `json:"vendor,omitempty"`
+	ScheduledFor   *time.Time         `json:"scheduledFor,omitempty"`
+	ResolvedAt     *time.Time         `json:"resolvedAt,omitempty"`
+	Photos         []MaintenancePhoto `json:"photos"`
+	CreatedAt      time.Time          `json:"createdAt"`
+	UpdatedAt      time.Time          `json:"updatedAt"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending   PaymentStatus = "pending"
+	PaymentStatusSucceeded PaymentStatus = "succeeded"
+	PaymentStatusFailed    PaymentStatus = "failed"
+	PaymentStatusRefunded  PaymentStatus = "refunded"
+)
+
+func (s PaymentStatus) Label() string {
+	switch s {
+	case PaymentStatusPending:
+		return "Pending"
+	case PaymentStatusSucceeded:
+		return "Paid"
+	case PaymentStatusFailed:
+		return "Failed"
+	case PaymentStatusRefunded:
+		return "Refunded"
+	default:
+		return string(s)
+	}
+}
+
+// PaymentPurpose says what a payment was for
+type PaymentPurpose string
+
+const (
+	PaymentPurposeApplicationFee PaymentPurpose = "application_fee"
+	// PaymentPurposeRent pays towards a lease's balance
+	PaymentPurposeRent PaymentPurpose = "rent"
+)
+
+// Payment records money collected through the payment gateway. IntentID is
+// the gateway's ID for it and PayerID the payer's Clerk user ID. Amounts
+// are in cents.
+type Payment struct {
+	ID            int64          `json:"id"`
+	Gateway       string         `json:"gateway"`
+	IntentID      string         `json:"intentId"`
+	Purpose       PaymentPurpose `json:"purpose"`
+	ApplicationID *int64         `json:"applicationId,omitempty"`
+	LeaseID       *int64         `json:"leaseId,omitempty"`
+	PayerID       string         `json:"payerId"`
+	AmountCents   int64          `json:"amountCents"`
+	RefundedCents int64          `json:"refundedCents"`
+	Currency      string         `json:"currency"`
+	Status        PaymentStatus  `json:"status"`
+	// FailureMessage explains why the last attempt was declined
+	FailureMessage string     `json:"failureMessage,omitempty"`
+	PaidAt         *time.Time `json:"paidAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// Paid reports whether the payment went through, even if it was refunded
+// since
+func (p Payment) Paid() bool {
+	return p.Status == PaymentStatusSucceeded || p.Status == PaymentStatusRefunded
+}
+
+// FormatCents shows an amount in cents as dollars, e.g. $1,234.50
+func FormatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	dollars := fmt.Sprint(cents / 100)
+	for i := len(dollars) - 3; i > 0; i -= 3 {
+		dollars = dollars[:i] + "," + dollars[i:]
+	}
+	return fmt.Sprintf("%s$%s.%02d", sign, dollars, cents%100)
+}
@@ -34,47 +34,60 @@ const (
 	InquiryTypeGeneral     InquiryType = "general"
 )
 
+type SubscriberStatus string
+
+const (
+	SubscriberStatusPending      SubscriberStatus = "pending"
+	SubscriberStatusActive       SubscriberStatus = "active"
+	SubscriberStatusUnsubscribed SubscriberStatus = "unsubscribed"
+)
+
 type Property struct {
-	ID             int64        `json:"id"`
-	Slug           string       `json:"slug"`
-	Title          string       `json:"title"`
-	Type           PropertyType `json:"type"`
-	Address        string       `json:"address"`
-	City           string       `json:"city"`
-	State          string       `json:"state"`
-	ZipCode        string       `json:"zipCode"`
-	Price          int          `json:"price"`
-	Deposit        int          `json:"deposit"`
-	ApplicationFee int          `json:"applicationFee"`
-	Bedrooms       int          `json:"bedrooms"`
-	Bathrooms      float64      `json:"bathrooms"`
-	SquareFeet     int          `json:"squareFeet"`
-	Description    string       `json:"description"`
-	Features       []string     `json:"features"`
-	Available      bool         `json:"available"`
-	AvailableDate  *time.Time   `json:"availableDate,omitempty"`
-	PetFriendly    bool         `json:"petFriendly"`
-	PetDeposit     *int         `json:"petDeposit,omitempty"`
-	PetRent        *int         `json:"petRent,omitempty"`
-	Parking        string       `json:"parking"`
-	Laundry        string       `json:"laundry"`
-	YearBuilt      *int         `json:"yearBuilt,omitempty"`
-	Utilities      []string     `json:"utilities"`
-	LeaseTerms     []string     `json:"leaseTerms"`
-	Featured       bool         `json:"featured"`
-	CreatedAt      time.Time    `json:"createdAt"`
-	UpdatedAt      time.Time    `json:"updatedAt"`
+	ID             int64           `json:"id"`
+	Slug           string          `json:"slug"`
+	Title          string          `json:"title"`
+	Type           PropertyType    `json:"type"`
+	Address        string          `json:"address"`
+	City           string          `json:"city"`
+	State          string          `json:"state"`
+	ZipCode        string          `json:"zipCode"`
+	Price          int             `json:"price"`
+	Deposit        int             `json:"deposit"`
+	ApplicationFee int             `json:"applicationFee"`
+	Bedrooms       int             `json:"bedrooms"`
+	Bathrooms      float64         `json:"bathrooms"`
+	SquareFeet     int             `json:"squareFeet"`
+	Description    string          `json:"description"`
+	Features       []string        `json:"features"`
+	Available      bool            `json:"available"`
+	AvailableDate  *time.Time      `json:"availableDate,omitempty"`
+	PetFriendly    bool            `json:"petFriendly"`
+	PetDeposit     *int            `json:"petDeposit,omitempty"`
+	PetRent        *int            `json:"petRent,omitempty"`
+	Parking        string          `json:"parking"`
+	Laundry        string          `json:"laundry"`
+	YearBuilt      *int            `json:"yearBuilt,omitempty"`
+	Utilities      []string        `json:"utilities"`
+	LeaseTerms     []string        `json:"leaseTerms"`
+	Featured       bool            `json:"featured"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
 	Images         []PropertyImage `json:"images,omitempty"`
 }
 
 type PropertyImage struct {
-	ID           int64    `json:"id"`
-	PropertyID   int64    `json:"propertyId"`
-	URL          string   `json:"url"`
-	Caption      string   `json:"caption"`
-	Room         RoomType `json:"room"`
-	DisplayOrder int      `json:"displayOrder"`
+	ID           int64     `json:"id"`
+	PropertyID   int64     `json:"propertyId"`
+	URL          string    `json:"url"`
+	Caption      string    `json:"caption"`
+	Room         RoomType  `json:"room"`
+	DisplayOrder int       `json:"displayOrder"`
 	CreatedAt    time.Time `json:"createdAt"`
+	// ThumbnailURL and CardURL are resized copies of uploaded photos
+	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
+	CardURL      string `json:"cardUrl,omitempty"`
+	// StorageKey is the key prefix of an uploaded photo's files
+	StorageKey string `json:"-"`
 }
 
 type ContactSubmission struct {
@@ -88,13 +101,21 @@ type ContactSubmission struct {
 	PreferredTime string      `json:"preferredTime,omitempty"`
 	Message       string      `json:"message"`
 	CreatedAt     time.Time   `json:"createdAt"`
+	// Status and AssigneeID track staff follow-up; AssigneeID is a Clerk
+	// user ID
+	Status     InquiryStatus `json:"status"`
+	AssigneeID string        `json:"assigneeId,omitempty"`
+	UpdatedAt  time.Time     `json:"updatedAt"`
 }
 
 type NewsletterSubscriber struct {
-	ID           int64      `json:"id"`
-	Email        string     `json:"email"`
-	FirstName    string     `json:"firstName,omitempty"`
-	SubscribedAt time.Time  `json:"subscribedAt"`
+	ID             int64            `json:"id"`
+	Email          string           `json:"email"`
+	FirstName      string           `json:"firstName,omitempty"`
+	Status         SubscriberStatus `json:"status"`
+	SubscribedAt   time.Time        `json:"subscribedAt"`
+	ConfirmedAt    *time.Time       `json:"confirmedAt,omitempty"`
+	UnsubscribedAt *time.Time       `json:"unsubscribedAt,omitempty"`
 }
 
 // Helper methods for Property
@@ -162,7 +183,74 @@ func (p *Property) UniqueRooms() []RoomType {
 	return rooms
 }
 
+// Thumbnail returns the smallest available URL for the image
+func (img PropertyImage) Thumbnail() string {
+	if img.ThumbnailURL != "" {
+		return img.ThumbnailURL
+	}
+	return img.URL
+}
+
+// Card returns the URL sized for property cards
+func (img PropertyImage) Card() string {
+	if img.CardURL != "" {
+		return img.CardURL
+	}
+	return img.URL
+}
+
+// Helper for PropertyType
+func (t PropertyType) IsValid() bool {
+	switch t {
+	case PropertyTypeHouse, PropertyTypeApartment, PropertyTypeDuplex:
+		return true
+	default:
+		return false
+	}
+}
+
+// Helper for InquiryType
+func (t InquiryType) IsValid() bool {
+	switch t {
+	case InquiryTypeViewing, InquiryTypeApplication, InquiryTypeGeneral:
+		return true
+	default:
+		return false
+	}
+}
+
+// InquiryTypes lists every inquiry type in display order
+var InquiryTypes = []InquiryType{InquiryTypeViewing, InquiryTypeApplication, InquiryTypeGeneral}
+
+func (t InquiryType) Label() string {
+	switch t {
+	case InquiryTypeViewing:
+		return "Viewing"
+	case InquiryTypeApplication:
+		return "Application"
+	case InquiryTypeGeneral:
+		return "General"
+	default:
+		return string(t)
+	}
+}
+
+// RoomTypes lists every room in display order
+var RoomTypes = []RoomType{
+	RoomTypeExterior, RoomTypeLiving, RoomTypeKitchen, RoomTypeBedroom, RoomTypeBathroom,
+	RoomTypeDining, RoomTypeBackyard, RoomTypeGarage, RoomTypeOther,
+}
+
 // Helper for RoomType
+func (r RoomType) IsValid() bool {
+	for _, room := range RoomTypes {
+		if r == room {
+			return true
+		}
+	}
+	return false
+}
+
 func (r RoomType) Label() string {
 	switch r {
 	case RoomTypeExterior:
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+)
+
+type ShowingStatus string
+
+const (
+	ShowingStatusPending   ShowingStatus = "pending"
+	ShowingStatusConfirmed ShowingStatus = "confirmed"
+	ShowingStatusCancelled ShowingStatus = "cancelled"
+)
+
+// ShowingWindow is a stretch of time when AgentID, a Clerk user ID, can show
+// a property. It is offered to visitors as back-to-back slots.
+type ShowingWindow struct {
+	ID          int64     `json:"id"`
+	PropertyID  int64     `json:"propertyId"`
+	AgentID     string    `json:"agentId"`
+	StartsAt    time.Time `json:"startsAt"`
+	EndsAt      time.Time `json:"endsAt"`
+	SlotMinutes int       `json:"slotMinutes"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Showing is a visitor's booking of one slot. Pending showings hold their
+// slot until the visitor confirms or someone cancels.
+type Showing struct {
+	ID         int64         `json:"id"`
+	PropertyID int64         `json:"propertyId"`
+	AgentID    string        `json:"agentId"`
+	InquiryID  *int64        `json:"inquiryId,omitempty"`
+	Name       string        `json:"name"`
+	Email      string        `json:"email"`
+	Phone      string        `json:"phone"`
+	StartsAt   time.Time     `json:"startsAt"`
+	EndsAt     time.Time     `json:"endsAt"`
+	Status     ShowingStatus `json:"status"`
+	// Sequence counts changes to the showing, for calendar clients
+	Sequence  int       `json:"sequence"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SlotLength is how long each showing in the window lasts
+func (w ShowingWindow) SlotLength() time.Duration {
+	return time.Duration(w.SlotMinutes) * time.Minute
+}
+
+// Active reports whether the showing still holds its slot
+func (s Showing) Active() bool {
+	return s.Status != ShowingStatusCancelled
+}
+
+// Overlaps reports whether the showing overlaps [start, end)
+func (s Showing) Overlaps(start, end time.Time) bool {
+	return s.StartsAt.Before(end) && start.Before(s.EndsAt)
+}
+
+func (s ShowingStatus) Label() string {
+	switch s {
+	case ShowingStatusPending:
+		return "Awaiting confirmation"
+	case ShowingStatusConfirmed:
+		return "Confirmed"
+	case ShowingStatusCancelled:
+		return "Cancelled"
+	default:
+		return string(s)
+	}
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"time"
+)
+
+type SignatureStatus string
+
+const (
+	SignatureStatusPending SignatureStatus = "pending"
+	SignatureStatusSigned  SignatureStatus = "signed"
+	// SignatureStatusVoid marks a request staff withdrew before everyone
+	// signed
+	SignatureStatusVoid SignatureStatus = "void"
+)
+
+func (s SignatureStatus) Label() string {
+	switch s {
+	case SignatureStatusPending:
+		return "Awaiting signature"
+	case SignatureStatusSigned:
+		return "Signed"
+	case SignatureStatusVoid:
+		return "Void"
+	default:
+		return string(s)
+	}
+}
+
+// SignatureMethod is how a signer made their signature
+type SignatureMethod string
+
+const (
+	SignatureMethodTyped SignatureMethod = "typed"
+	SignatureMethodDrawn SignatureMethod = "drawn"
+)
+
+func (m SignatureMethod) IsValid() bool {
+	return m == SignatureMethodTyped || m == SignatureMethodDrawn
+}
+
+// SignatureRequest asks one party to sign a generated document. Position
+// is the party's place among the document's signature blocks, and UserID
+// their Clerk user ID, if they have one. DocumentSHA256 is the hex SHA-256
+// of the file as it was sent. Once signed, the signature is TypedName or
+// Drawing, a PNG image, depending on Method, and IPAddress and UserAgent
+// are where it was made from.
+type SignatureRequest struct {
+	ID             int64           `json:"id"`
+	DocumentID     int64           `json:"documentId"`
+	Position       int             `json:"position"`
+	Name           string          `json:"name"`
+	Role           string          `json:"role"`
+	Email          string          `json:"email,omitempty"`
+	UserID         string          `json:"userId,omitempty"`
+	Status         SignatureStatus `json:"status"`
+	DocumentSHA256 string          `json:"documentSha256"`
+	Method         SignatureMethod `json:"method,omitempty"`
+	TypedName      string          `json:"typedName,omitempty"`
+	Drawing        []byte          `json:"-"`
+	IPAddress      string          `json:"ipAddress,omitempty"`
+	UserAgent      string          `json:"userAgent,omitempty"`
+	SignedAt       *time.Time      `json:"signedAt,omitempty"`
+	// RequestedBy is the Clerk user ID of the staff member who sent it
+	RequestedBy string    `json:"requestedBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Party describes the signer, e.g. "Tina Ruiz (Tenant)"
+func (r SignatureRequest) Party() string {
+	return r.Name + " (" + r.Role + ")"
+}
+
+// CurrentSignatureRequests picks the requests that weren't voided out of
+// requests: those of a document's latest round of signing
+func CurrentSignatureRequests(requests []SignatureRequest) []SignatureRequest {
+	var current []SignatureRequest
+	for _, r := range requests {
+		if r.Status != SignatureStatusVoid {
+			current = append(current, r)
+		}
+	}
+	return current
+}
+
+type SignatureEventKind string
+
+const (
+	SignatureEventSent      SignatureEventKind = "sent"
+	SignatureEventViewed    SignatureEventKind = "viewed"
+	SignatureEventSigned    SignatureEventKind = "signed"
+	SignatureEventVoided    SignatureEventKind = "voided"
+	SignatureEventCompleted SignatureEventKind = "completed"
+)
+
+func (k SignatureEventKind) Label() string {
+	switch k {
+	case SignatureEventSent:
+		return "Sent for signature"
+	case SignatureEventViewed:
+		return "Opened"
+	case SignatureEventSigned:
+		return "Signed"
+	case SignatureEventVoided:
+		return "Voided"
+	case SignatureEventCompleted:
+		return "Completed"
+	default:
+		return string(k)
+	}
+}
+
+// SignatureEvent is one entry in a document's signing audit trail.
+// RequestID is the request it concerns; completion concerns the whole
+// document and has none. ActorID is the Clerk user ID of whoever acted,
+// when known, and IPAddress and UserAgent where they acted from.
+type SignatureEvent struct {
+	ID         int64              `json:"id"`
+	DocumentID int64              `json:"documentId"`
+	RequestID  *int64             `json:"requestId,omitempty"`
+	Kind       SignatureEventKind `json:"kind"`
+	ActorID    string             `json:"actorId,omitempty"`
+	IPAddress  string             `json:"ipAddress,omitempty"`
+	UserAgent  string             `json:"userAgent,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+type Role string
+
+const (
+	RoleAdmin    Role = "admin"
+	RoleStaff    Role = "staff"
+	RoleLandlord Role = "landlord"
+	RoleTenant   Role = "tenant"
+)
+
+// User is a local record for a Clerk user. Only users whose role is not
+// carried in their Clerk session claims need one.
+type User struct {
+	ID          int64     `json:"id"`
+	ClerkUserID string    `json:"clerkUserId"`
+	Email       string    `json:"email,omitempty"`
+	Role        Role      `json:"role"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Helper for Role
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleAdmin, RoleStaff, RoleLandlord, RoleTenant:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r Role) Label() string {
+	switch r {
+	case RoleAdmin:
+		return "Admin"
+	case RoleStaff:
+		return "Staff"
+	case RoleLandlord:
+		return "Landlord"
+	case RoleTenant:
+		return "Tenant"
+	default:
+		return string(r)
+	}
+}
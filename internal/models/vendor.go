@@ -0,0 +1,39 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Vendor is a contractor staff send work orders to. Trades are the
+// maintenance categories they cover. Rates are in cents, 0 when none has
+// been agreed.
+type Vendor struct {
+	ID              int64                 `json:"id"`
+	Name            string                `json:"name"`
+	Trades          []MaintenanceCategory `json:"trades"`
+	ContactName     string                `json:"contactName"`
+	Email           string                `json:"email"`
+	Phone           string                `json:"phone"`
+	HourlyRateCents int64                 `json:"hourlyRateCents"`
+	CalloutFeeCents int64                 `json:"calloutFeeCents"`
+	Notes           string                `json:"notes"`
+	// Active vendors are offered for new work orders
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Covers reports whether v works on problems in category c
+func (v Vendor) Covers(c MaintenanceCategory) bool {
+	return slices.Contains(v.Trades, c)
+}
+
+// TradeLabels lists the labels of v's trades
+func (v Vendor) TradeLabels() []string {
+	labels := make([]string, len(v.Trades))
+	for i, t := range v.Trades {
+		labels[i] = t.Label()
+	}
+	return labels
+}
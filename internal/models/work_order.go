@@ -0,0 +1,111 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+type WorkOrderStatus string
+
+const (
+	WorkOrderStatusOpen      WorkOrderStatus = "open"
+	WorkOrderStatusCompleted WorkOrderStatus = "completed"
+	WorkOrderStatusCancelled WorkOrderStatus = "cancelled"
+)
+
+// WorkOrderStatuses lists every status in workflow order
+var WorkOrderStatuses = []WorkOrderStatus{
+	WorkOrderStatusOpen,
+	WorkOrderStatusCompleted,
+	WorkOrderStatusCancelled,
+}
+
+func (s WorkOrderStatus) IsValid() bool {
+	return slices.Contains(WorkOrderStatuses, s)
+}
+
+func (s WorkOrderStatus) Label() string {
+	switch s {
+	case WorkOrderStatusOpen:
+		return "Open"
+	case WorkOrderStatusCompleted:
+		return "Completed"
+	case WorkOrderStatusCancelled:
+		return "Cancelled"
+	default:
+		return string(s)
+	}
+}
+
+// WorkOrderBillTo is who a work order's cost is charged back to
+type WorkOrderBillTo string
+
+const (
+	WorkOrderBillToOwner  WorkOrderBillTo = "owner"
+	WorkOrderBillToTenant WorkOrderBillTo = "tenant"
+)
+
+// WorkOrderBillTos lists everyone a cost can be charged back to
+var WorkOrderBillTos = []WorkOrderBillTo{
+	WorkOrderBillToOwner,
+	WorkOrderBillToTenant,
+}
+
+func (b WorkOrderBillTo) IsValid() bool {
+	return slices.Contains(WorkOrderBillTos, b)
+}
+
+func (b WorkOrderBillTo) Label() string {
+	switch b {
+	case WorkOrderBillToOwner:
+		return "Owner"
+	case WorkOrderBillToTenant:
+		return "Tenant"
+	default:
+		return string(b)
+	}
+}
+
+// WorkOrder is a job issued to a vendor for a maintenance request. Amounts
+// are in cents; InvoiceCents is 0 until the vendor's invoice is recorded.
+// ScheduledOn and InvoicedOn are dates. LedgerEntryID is the charge posted
+// to the tenant's ledger for orders billed to them, once it is.
+type WorkOrder struct {
+	ID              int64           `json:"id"`
+	RequestID       int64           `json:"requestId"`
+	PropertyID      int64           `json:"propertyId"`
+	VendorID        int64           `json:"vendorId"`
+	Status          WorkOrderStatus `json:"status"`
+	Scope           string          `json:"scope"`
+	ScheduledOn     *time.Time      `json:"scheduledOn,omitempty"`
+	EstimateCents   int64           `json:"estimateCents"`
+	BillTo          WorkOrderBillTo `json:"billTo"`
+	CompletionNotes string          `json:"completionNotes"`
+	CompletedAt     *time.Time      `json:"completedAt,omitempty"`
+	InvoiceNumber   string          `json:"invoiceNumber"`
+	InvoiceCents    int64           `json:"invoiceCents"`
+	InvoicedOn      *time.Time      `json:"invoicedOn,omitempty"`
+	LedgerEntryID   *int64          `json:"ledgerEntryId,omitempty"`
+	// CreatedBy is the Clerk user ID of the staff member who issued it
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (o WorkOrder) Invoiced() bool {
+	return o.InvoiceCents > 0
+}
+
+// CostCents is the invoiced amount once there is one, else the estimate
+func (o WorkOrder) CostCents() int64 {
+	if o.Invoiced() {
+		return o.InvoiceCents
+	}
+	return o.EstimateCents
+}
+
+// Chargeable reports whether o's invoice can be posted to the tenant's
+// ledger: it's billed to them, invoiced and not charged yet
+func (o WorkOrder) Chargeable() bool {
+	return o.BillTo == WorkOrderBillToTenant && o.Invoiced() && o.LedgerEntryID == nil
+}
@@ -0,0 +1,184 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Test card numbers understood by FakeGateway. Any other card succeeds.
+const (
+	FakeCardSuccess      = "4242424242424242"
+	FakeCardDeclined     = "4000000000000002"
+	FakeCardInsufficient = "4000000000009995"
+)
+
+// FakeSignatureHeader carries the signature of FakeGateway webhooks
+const FakeSignatureHeader = "Fake-Signature"
+
+// fakeMethodPrefix starts the IDs of FakeGateway's saved methods, which
+// are followed by the card number so any process can charge them
+const fakeMethodPrefix = "pm_fake_"
+
+// FakeGateway settles payments in memory, for development and tests. Its
+// payment methods are card numbers, and webhook payloads are JSON Events
+// signed with SignWebhook.
+type FakeGateway struct {
+	mu            sync.Mutex
+	currency      string
+	webhookSecret []byte
+	nextID        int
+	intents       map[string]*fakeIntent
+	idempotent    map[string]string
+}
+
+type fakeIntent struct {
+	Intent
+	refunded int64
+}
+
+// NewFakeGateway creates an empty FakeGateway
+func NewFakeGateway(currency, webhookSecret string) *FakeGateway {
+	return &FakeGateway{
+		currency:      currency,
+		webhookSecret: []byte(webhookSecret),
+		nextID:        1,
+		intents:       make(map[string]*fakeIntent),
+		idempotent:    make(map[string]string),
+	}
+}
+
+func (g *FakeGateway) Name() string { return DriverFake }
+
+func (g *FakeGateway) CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("payment amount must be positive")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if id, ok := g.idempotent[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		intent := g.intents[id].Intent
+		return &intent, nil
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = g.currency
+	}
+	id := fmt.Sprintf("pi_fake_%d", g.nextID)
+	g.nextID++
+	g.intents[id] = &fakeIntent{Intent: Intent{
+		ID:           id,
+		Amount:       req.Amount,
+		Currency:     currency,
+		Status:       StatusPending,
+		ClientSecret: id + "_secret",
+	}}
+	if req.IdempotencyKey != "" {
+		g.idempotent[req.IdempotencyKey] = id
+	}
+	intent := g.intents[id].Intent
+	return &intent, nil
+}
+
+func (g *FakeGateway) Confirm(ctx context.Context, intentID, paymentMethod string) (*Intent, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	fi, ok := g.intents[intentID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	card := strings.TrimPrefix(strings.ReplaceAll(paymentMethod, " ", ""), fakeMethodPrefix)
+	if card != "" && fi.Status != StatusSucceeded && fi.Status != StatusCanceled {
+		switch card {
+		case FakeCardDeclined:
+			fi.Status, fi.FailureMessage = StatusFailed, "Your card was declined."
+		case FakeCardInsufficient:
+			fi.Status, fi.FailureMessage = StatusFailed, "Your card has insufficient funds."
+		default:
+			fi.Status, fi.FailureMessage = StatusSucceeded, ""
+		}
+	}
+	intent := fi.Intent
+	return &intent, nil
+}
+
+// SaveMethod accepts any card number. Declined test cards are saved too,
+// and fail when they're charged.
+func (g *FakeGateway) SaveMethod(ctx context.Context, req SaveMethodRequest) (*SavedMethod, error) {
+	card := strings.ReplaceAll(req.PaymentMethod, " ", "")
+	if len(card) < 12 || strings.Trim(card, "0123456789") != "" {
+		return nil, fmt.Errorf("invalid card number")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := g.nextID
+	g.nextID++
+	return &SavedMethod{
+		ID:         fakeMethodPrefix + card,
+		CustomerID: fmt.Sprintf("cus_fake_%d", id),
+		Label:      "Card ending " + card[len(card)-4:],
+	}, nil
+}
+
+func (g *FakeGateway) ChargeSaved(ctx context.Context, req IntentRequest, method SavedMethod) (*Intent, error) {
+	if !strings.HasPrefix(method.ID, fakeMethodPrefix) {
+		return nil, fmt.Errorf("unknown payment method %q", method.ID)
+	}
+	intent, err := g.CreateIntent(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return g.Confirm(ctx, intent.ID, method.ID)
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, intentID string, amount int64) (*Refund, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	fi, ok := g.intents[intentID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	remaining := fi.Amount - fi.refunded
+	if fi.Status != StatusSucceeded || remaining == 0 {
+		return nil, fmt.Errorf("payment %s has nothing to refund", intentID)
+	}
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount < 0 || amount > remaining {
+		return nil, fmt.Errorf("refund of %d exceeds the %d left on payment %s", amount, remaining, intentID)
+	}
+	fi.refunded += amount
+	return &Refund{ID: fmt.Sprintf("re_fake_%s_%d", intentID, fi.refunded), IntentID: intentID, Amount: amount}, nil
+}
+
+func (g *FakeGateway) VerifyWebhook(payload []byte, header http.Header) (*Event, error) {
+	if !hmac.Equal([]byte(header.Get(FakeSignatureHeader)), []byte(g.SignWebhook(payload))) {
+		return nil, ErrInvalidSignature
+	}
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("decode webhook: %w", err)
+	}
+	return &event, nil
+}
+
+// SignWebhook returns the FakeSignatureHeader value for payload
+func (g *FakeGateway) SignWebhook(payload []byte) string {
+	mac := hmac.New(sha256.New, g.webhookSecret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,164 @@
+// Package payment collects card payments through a pluggable gateway.
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Drivers accepted by New
+const (
+	DriverFake   = "fake"
+	DriverStripe = "stripe"
+)
+
+var (
+	// ErrNotFound is returned for intents the gateway doesn't know
+	ErrNotFound = errors.New("payment intent not found")
+	// ErrInvalidSignature is returned by VerifyWebhook for payloads that
+	// weren't signed with the webhook secret
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// Gateway charges customers. A payment starts as an Intent for an amount,
+// which is confirmed with a payment method the browser collected. Methods
+// can also be saved for a customer and charged later without them.
+type Gateway interface {
+	// Name identifies the driver on stored payment records
+	Name() string
+	CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error)
+	// Confirm charges the intent with paymentMethod. With no paymentMethod
+	// it reports the outcome of an attempt the customer finished with the
+	// gateway directly, such as a 3-D Secure check. A declined card is not
+	// an error: the intent comes back Failed with a FailureMessage.
+	Confirm(ctx context.Context, intentID, paymentMethod string) (*Intent, error)
+	// SaveMethod keeps a payment method the browser collected on file for a
+	// new customer, so it can be charged later with ChargeSaved
+	SaveMethod(ctx context.Context, req SaveMethodRequest) (*SavedMethod, error)
+	// ChargeSaved creates an intent for req and charges it to a saved
+	// method at once, while the customer is away. As with Confirm, a
+	// declined card comes back as a Failed intent rather than an error.
+	ChargeSaved(ctx context.Context, req IntentRequest, method SavedMethod) (*Intent, error)
+	// Refund returns amount of a succeeded intent to the customer, or
+	// whatever hasn't been refunded yet when amount is 0
+	Refund(ctx context.Context, intentID string, amount int64) (*Refund, error)
+	// VerifyWebhook checks a webhook request's signature and decodes it
+	VerifyWebhook(payload []byte, header http.Header) (*Event, error)
+}
+
+// Status is where an Intent is in collecting its payment
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	// StatusRequiresAction means the customer has to finish the payment with
+	// the gateway, e.g. a 3-D Secure check, using the ClientSecret
+	StatusRequiresAction Status = "requires_action"
+	StatusProcessing     Status = "processing"
+	StatusSucceeded      Status = "succeeded"
+	// StatusFailed means the last attempt was declined. The intent can be
+	// confirmed again with another payment method.
+	StatusFailed   Status = "failed"
+	StatusCanceled Status = "canceled"
+)
+
+// IntentRequest describes a payment to collect
+type IntentRequest struct {
+	// Amount is in the currency's smallest unit, e.g. cents
+	Amount int64
+	// Currency is an ISO 4217 code; the gateway's default when empty
+	Currency    string
+	Description string
+	Metadata    map[string]string
+	// IdempotencyKey makes retried requests return the original intent
+	IdempotencyKey string
+}
+
+// Intent is a payment being collected
+type Intent struct {
+	ID       string
+	Amount   int64
+	Currency string
+	Status   Status
+	// ClientSecret lets the gateway's browser script act on the intent
+	ClientSecret   string
+	FailureMessage string
+}
+
+// SaveMethodRequest describes a payment method to keep on file
+type SaveMethodRequest struct {
+	// PaymentMethod is what the browser collected, as passed to Confirm
+	PaymentMethod string
+	Email         string
+	Name          string
+	Metadata      map[string]string
+}
+
+// SavedMethod is a payment method on file with the gateway
+type SavedMethod struct {
+	ID         string
+	CustomerID string
+	// Label describes the method to its owner, e.g. "Visa ending 4242"
+	Label string
+}
+
+type Refund struct {
+	ID       string
+	IntentID string
+	Amount   int64
+}
+
+// EventType is the kind of a webhook Event. Types not listed here are
+// passed through as the gateway names them.
+type EventType string
+
+const (
+	EventSucceeded EventType = "payment.succeeded"
+	EventFailed    EventType = "payment.failed"
+	EventRefunded  EventType = "payment.refunded"
+)
+
+// Event is a webhook notification about an intent
+type Event struct {
+	ID             string    `json:"id"`
+	Type           EventType `json:"type"`
+	IntentID       string    `json:"intentId"`
+	FailureMessage string    `json:"failureMessage,omitempty"`
+	// AmountRefunded is the intent's total refunded so far, for
+	// EventRefunded
+	AmountRefunded int64 `json:"amountRefunded,omitempty"`
+}
+
+// Options configures the payment drivers
+type Options struct {
+	// Currency is used for intents that don't name one
+	Currency string
+	// WebhookSecret signs webhook payloads
+	WebhookSecret string
+	// SecretKey and PublishableKey are the Stripe API keys
+	SecretKey      string
+	PublishableKey string
+	// APIURL is the Stripe-compatible API origin, https://api.stripe.com
+	// unless set
+	APIURL string
+}
+
+// New creates a Gateway for the named driver
+func New(driver string, opts Options) (Gateway, error) {
+	if opts.Currency == "" {
+		opts.Currency = "usd"
+	}
+	switch driver {
+	case DriverFake:
+		return NewFakeGateway(opts.Currency, opts.WebhookSecret), nil
+	case DriverStripe:
+		if opts.SecretKey == "" || opts.PublishableKey == "" {
+			return nil, fmt.Errorf("STRIPE_SECRET_KEY and STRIPE_PUBLISHABLE_KEY are required for the stripe payment driver")
+		}
+		return NewStripeGateway(opts), nil
+	default:
+		return nil, fmt.Errorf("unknown payment driver %q", driver)
+	}
+}
@@ -0,0 +1,363 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stripeWebhookTolerance is how old a signed webhook may be, limiting replays
+const stripeWebhookTolerance = 5 * time.Minute
+
+// StripeGateway uses the Stripe PaymentIntents API, or any service that
+// speaks it such as stripe-mock. Browsers collect cards with Stripe.js and
+// send the resulting PaymentMethod ID to be confirmed here.
+type StripeGateway struct {
+	apiURL         string
+	secretKey      string
+	publishableKey string
+	webhookSecret  string
+	currency       string
+	client         *http.Client
+	now            func() time.Time
+}
+
+// NewStripeGateway creates a StripeGateway from opts
+func NewStripeGateway(opts Options) *StripeGateway {
+	apiURL := opts.APIURL
+	if apiURL == "" {
+		apiURL = "https://api.stripe.com"
+	}
+	return &StripeGateway{
+		apiURL:         strings.TrimRight(apiURL, "/"),
+		secretKey:      opts.SecretKey,
+		publishableKey: opts.PublishableKey,
+		webhookSecret:  opts.WebhookSecret,
+		currency:       opts.Currency,
+		client:         &http.Client{Timeout: 30 * time.Second},
+		now:            time.Now,
+	}
+}
+
+func (g *StripeGateway) Name() string { return DriverStripe }
+
+// PublishableKey is the key Stripe.js is loaded with
+func (g *StripeGateway) PublishableKey() string {
+	return g.publishableKey
+}
+
+func (g *StripeGateway) CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error) {
+	currency := req.Currency
+	if currency == "" {
+		currency = g.currency
+	}
+	form := url.Values{
+		"amount":                 {strconv.FormatInt(req.Amount, 10)},
+		"currency":               {currency},
+		"payment_method_types[]": {"card"},
+	}
+	if req.Description != "" {
+		form.Set("description", req.Description)
+	}
+	for k, v := range req.Metadata {
+		form.Set("metadata["+k+"]", v)
+	}
+
+	var pi stripeIntent
+	if err := g.do(ctx, http.MethodPost, "/v1/payment_intents", form, req.IdempotencyKey, &pi); err != nil {
+		return nil, err
+	}
+	return pi.intent(), nil
+}
+
+func (g *StripeGateway) Confirm(ctx context.Context, intentID, paymentMethod string) (*Intent, error) {
+	path := "/v1/payment_intents/" + url.PathEscape(intentID)
+	var pi stripeIntent
+	if paymentMethod == "" {
+		if err := g.do(ctx, http.MethodGet, path, nil, "", &pi); err != nil {
+			return nil, err
+		}
+		return pi.intent(), nil
+	}
+
+	form := url.Values{
+		"payment_method": {paymentMethod},
+		// Let Stripe.js handle any 3-D Secure step in the page
+		"use_stripe_sdk": {"true"},
+	}
+	err := g.do(ctx, http.MethodPost, path+"/confirm", form, "", &pi)
+	if intent, ok := declinedIntent(err); ok {
+		return intent, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pi.intent(), nil
+}
+
+// SaveMethod creates a customer and attaches the PaymentMethod Stripe.js
+// collected to it
+func (g *StripeGateway) SaveMethod(ctx context.Context, req SaveMethodRequest) (*SavedMethod, error) {
+	form := url.Values{"email": {req.Email}}
+	if req.Name != "" {
+		form.Set("name", req.Name)
+	}
+	for k, v := range req.Metadata {
+		form.Set("metadata["+k+"]", v)
+	}
+	var customer struct {
+		ID string `json:"id"`
+	}
+	if err := g.do(ctx, http.MethodPost, "/v1/customers", form, "", &customer); err != nil {
+		return nil, err
+	}
+
+	var pm struct {
+		ID   string `json:"id"`
+		Card *struct {
+			Brand string `json:"brand"`
+			Last4 string `json:"last4"`
+		} `json:"card"`
+	}
+	path := "/v1/payment_methods/" + url.PathEscape(req.PaymentMethod) + "/attach"
+	if err := g.do(ctx, http.MethodPost, path, url.Values{"customer": {customer.ID}}, "", &pm); err != nil {
+		return nil, err
+	}
+
+	label := "Saved card"
+	if pm.Card != nil && pm.Card.Brand != "" {
+		label = strings.ToUpper(pm.Card.Brand[:1]) + pm.Card.Brand[1:] + " ending " + pm.Card.Last4
+	}
+	return &SavedMethod{ID: pm.ID, CustomerID: customer.ID, Label: label}, nil
+}
+
+// ChargeSaved creates and confirms an off-session intent. Cards that need
+// the customer to authenticate come back Failed.
+func (g *StripeGateway) ChargeSaved(ctx context.Context, req IntentRequest, method SavedMethod) (*Intent, error) {
+	currency := req.Currency
+	if currency == "" {
+		currency = g.currency
+	}
+	form := url.Values{
+		"amount":         {strconv.FormatInt(req.Amount, 10)},
+		"currency":       {currency},
+		"customer":       {method.CustomerID},
+		"payment_method": {method.ID},
+		"off_session":    {"true"},
+		"confirm":        {"true"},
+	}
+	if req.Description != "" {
+		form.Set("description", req.Description)
+	}
+	for k, v := range req.Metadata {
+		form.Set("metadata["+k+"]", v)
+	}
+
+	var pi stripeIntent
+	err := g.do(ctx, http.MethodPost, "/v1/payment_intents", form, req.IdempotencyKey, &pi)
+	if intent, ok := declinedIntent(err); ok {
+		return intent, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pi.intent(), nil
+}
+
+func (g *StripeGateway) Refund(ctx context.Context, intentID string, amount int64) (*Refund, error) {
+	form := url.Values{"payment_intent": {intentID}}
+	if amount > 0 {
+		form.Set("amount", strconv.FormatInt(amount, 10))
+	}
+	var r struct {
+		ID            string `json:"id"`
+		PaymentIntent string `json:"payment_intent"`
+		Amount        int64  `json:"amount"`
+	}
+	if err := g.do(ctx, http.MethodPost, "/v1/refunds", form, "", &r); err != nil {
+		return nil, err
+	}
+	return &Refund{ID: r.ID, IntentID: r.PaymentIntent, Amount: r.Amount}, nil
+}
+
+// VerifyWebhook checks the Stripe-Signature header: a timestamp and one or
+// more HMAC-SHA256 signatures of "<timestamp>.<payload>"
+func (g *StripeGateway) VerifyWebhook(payload []byte, header http.Header) (*Event, error) {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header.Get("Stripe-Signature"), ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			timestamp = v
+		case "v1":
+			signatures = append(signatures, v)
+		}
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || g.webhookSecret == "" {
+		return nil, ErrInvalidSignature
+	}
+	if age := g.now().Sub(time.Unix(ts, 0)); age > stripeWebhookTolerance || age < -stripeWebhookTolerance {
+		return nil, ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(g.webhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	valid := false
+	for _, sig := range signatures {
+		valid = valid || hmac.Equal([]byte(sig), []byte(expected))
+	}
+	if !valid {
+		return nil, ErrInvalidSignature
+	}
+
+	var raw struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object json.RawMessage `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("decode webhook: %w", err)
+	}
+
+	event := Event{ID: raw.ID, Type: EventType(raw.Type)}
+	switch raw.Type {
+	case "payment_intent.succeeded", "payment_intent.payment_failed":
+		var pi stripeIntent
+		if err := json.Unmarshal(raw.Data.Object, &pi); err != nil {
+			return nil, fmt.Errorf("decode webhook: %w", err)
+		}
+		event.IntentID = pi.ID
+		event.Type = EventSucceeded
+		if raw.Type == "payment_intent.payment_failed" {
+			event.Type = EventFailed
+			event.FailureMessage = pi.intent().FailureMessage
+		}
+	case "charge.refunded":
+		var charge struct {
+			PaymentIntent  string `json:"payment_intent"`
+			AmountRefunded int64  `json:"amount_refunded"`
+		}
+		if err := json.Unmarshal(raw.Data.Object, &charge); err != nil {
+			return nil, fmt.Errorf("decode webhook: %w", err)
+		}
+		event.Type = EventRefunded
+		event.IntentID = charge.PaymentIntent
+		event.AmountRefunded = charge.AmountRefunded
+	}
+	return &event, nil
+}
+
+// do sends a form-encoded API request and decodes the JSON response into out
+func (g *StripeGateway) do(ctx context.Context, method, path string, form url.Values, idempotencyKey string, out any) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.apiURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+g.secretKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Error stripeError `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Error.Message == "" {
+			return fmt.Errorf("stripe %s %s: %s", method, path, resp.Status)
+		}
+		if resp.StatusCode == http.StatusNotFound && e.Error.Code == "resource_missing" {
+			return ErrNotFound
+		}
+		return &e.Error
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+type stripeError struct {
+	Type          string        `json:"type"`
+	Code          string        `json:"code"`
+	Message       string        `json:"message"`
+	PaymentIntent *stripeIntent `json:"payment_intent"`
+}
+
+func (e *stripeError) Error() string {
+	return "stripe: " + e.Message
+}
+
+// declinedIntent turns a card error into the intent it carries, now
+// waiting for another card
+func declinedIntent(err error) (*Intent, bool) {
+	var declined *stripeError
+	if !errors.As(err, &declined) || declined.PaymentIntent == nil {
+		return nil, false
+	}
+	intent := declined.PaymentIntent.intent()
+	intent.Status = StatusFailed
+	intent.FailureMessage = declined.Message
+	return intent, true
+}
+
+type stripeIntent struct {
+	ID               string `json:"id"`
+	Amount           int64  `json:"amount"`
+	Currency         string `json:"currency"`
+	Status           string `json:"status"`
+	ClientSecret     string `json:"client_secret"`
+	LastPaymentError *struct {
+		Message string `json:"message"`
+	} `json:"last_payment_error"`
+}
+
+func (pi *stripeIntent) intent() *Intent {
+	intent := &Intent{
+		ID:           pi.ID,
+		Amount:       pi.Amount,
+		Currency:     pi.Currency,
+		ClientSecret: pi.ClientSecret,
+	}
+	switch pi.Status {
+	case "succeeded":
+		intent.Status = StatusSucceeded
+	case "processing":
+		intent.Status = StatusProcessing
+	case "requires_action", "requires_confirmation":
+		intent.Status = StatusRequiresAction
+	case "canceled":
+		intent.Status = StatusCanceled
+	default:
+		intent.Status = StatusPending
+	}
+	if pi.LastPaymentError != nil && intent.Status == StatusPending {
+		intent.Status = StatusFailed
+		intent.FailureMessage = pi.LastPaymentError.Message
+	}
+	return intent
+}
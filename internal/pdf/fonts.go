@@ -0,0 +1,126 @@
+package pdf
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Character widths of the standard fonts, in thousandths of the font size,
+// for the printable ASCII characters from space to "~". The oblique font
+// has the same widths as the regular one.
+var (
+	regularWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0 to 9
+		278, 278, 584, 584, 584, 556, 1015, // : to @
+		667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A to M
+		722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N to Z
+		278, 278, 278, 469, 556, 333, // [ to `
+		556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a to m
+		556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n to z
+		334, 260, 334, 584, // { to ~
+	}
+	boldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556,
+		333, 333, 584, 584, 584, 611, 975,
+		722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833,
+		722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611,
+		333, 278, 333, 584, 556, 333,
+		556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889,
+		611, 611, 611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500,
+		389, 280, 389, 584,
+	}
+)
+
+// winAnsi maps the characters Windows-1252 puts in 0x80-0x9F to their
+// codes. Its other codes match Unicode.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encode converts s to Windows-1252, replacing what it can't show with "?"
+func encode(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			b.WriteByte(' ')
+		case r >= 0x20 && r < 0x7F, r >= 0xA0 && r <= 0xFF:
+			b.WriteByte(byte(r))
+		case winAnsi[r] != 0:
+			b.WriteByte(winAnsi[r])
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// Width is how wide s is in font at size points
+func Width(font Font, size float64, s string) float64 {
+	widths := &regularWidths
+	if font == Bold {
+		widths = &boldWidths
+	}
+	total := 0
+	for _, c := range []byte(encode(s)) {
+		switch {
+		case c >= 0x20 && c < 0x7F:
+			total += widths[c-0x20]
+		case c == 0x97 || c == 0x85 || c == 0x89:
+			// em dash, ellipsis and per mille
+			total += 1000
+		default:
+			// Close enough for accented letters and punctuation
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// wrap breaks s into lines no wider than width. Runs of whitespace
+// collapse to one space; words too long for a line are split.
+func wrap(font Font, size, width float64, s string) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if Width(font, size, candidate) <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		for Width(font, size, word) > width {
+			n := fit(font, size, width, word)
+			lines = append(lines, word[:n])
+			word = word[n:]
+		}
+		line = word
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// fit is how many bytes of word fit in width, at least one character's
+func fit(font Font, fontSize, width float64, word string) int {
+	n := 0
+	for n < len(word) {
+		_, size := utf8.DecodeRuneInString(word[n:])
+		if n > 0 && Width(font, fontSize, word[:n+size]) > width {
+			break
+		}
+		n += size
+	}
+	return n
+}
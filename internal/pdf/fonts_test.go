@@ -0,0 +1,106 @@
+package pdf
+
+import (
+	"slices"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"ASCII", "Lease for 12 Elm St.", "Lease for 12 Elm St."},
+		{"Latin-1", "Café Zoë", "Caf\xe9 Zo\xeb"},
+		{"Windows-1252 punctuation", "€5 — “OK” ‘no’ …", "\x805 \x97 \x93OK\x94 \x91no\x92 \x85"},
+		{"tab as a space", "a\tb", "a b"},
+		{"outside Windows-1252", "日本 ✓", "?? ?"},
+		{"one mark per character", "😀x", "?x"},
+		{"control characters", "a\x00b\u0085c", "a?b?c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encode(tt.in); got != tt.want {
+				t.Errorf("encode(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "plain"},
+		{"(a)", `\(a\)`},
+		{`C:\docs`, `C:\\docs`},
+		{`\(`, `\\\(`},
+		{"one\r\ntwo", "one two"},
+		{encode("(€) ‘q’"), "\\(\x80\\) \x91q\x92"},
+	}
+	for _, tt := range tests {
+		if got := escape(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	// At 10 points, "a" and "é" are 5.56 points wide and a space 2.78
+	tests := []struct {
+		name  string
+		width float64
+		in    string
+		want  []string
+	}{
+		{"fits", 500, "hello world", []string{"hello world"}},
+		{"whitespace collapsed", 500, "  hello \n\t world  ", []string{"hello world"}},
+		{"empty", 500, "", []string{""}},
+		{"breaks between words", 50, "aaaa aaaa aaaa", []string{"aaaa aaaa", "aaaa"}},
+		{"exactly full", 47.26, "aaaa aaaa aaaa", []string{"aaaa aaaa", "aaaa"}},
+		{"long word split", 25, "aaaaaaaaaa", []string{"aaaa", "aaaa", "aa"}},
+		{"words follow a split word", 26, "aaaaaa aa", []string{"aaaa", "aa aa"}},
+		{"split between characters", 25, "éééééé", []string{"éééé", "éé"}},
+		{"a character per line at least", 1, "ab", []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrap(Regular, 10, tt.width, tt.in)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("wrap() = %q, want %q", got, tt.want)
+			}
+			for _, line := range got {
+				if !utf8.ValidString(line) {
+					t.Errorf("line %q splits a character", line)
+				}
+				if w := Width(Regular, 10, line); w > tt.width && utf8.RuneCountInString(line) > 1 {
+					t.Errorf("line %q is %.2f points wide, want at most %.2f", line, w, tt.width)
+				}
+			}
+		})
+	}
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		font Font
+		in   string
+		want float64
+	}{
+		{Regular, "", 0},
+		{Regular, "a a", 13.9},
+		{Bold, "a a", 13.9},
+		{Regular, "W", 9.44},
+		{Bold, "W", 9.44},
+		{Regular, "—", 10},
+		{Regular, "日", 5.56},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.font, 10, tt.in); got < tt.want-0.001 || got > tt.want+0.001 {
+			t.Errorf("Width(%v, 10, %q) = %.3f, want %.3f", tt.font, tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// Package pdf writes simple text documents as PDF 1.4 files: wrapped
+// paragraphs in the standard Helvetica fonts on US Letter pages, with a
+// numbered footer on each page, and the odd image. The standard fonts need
+// no embedding, so only Windows-1252 characters can be shown; others print
+// as "?".
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"strings"
+	"time"
+)
+
+// ContentType is the MIME type of an encoded Document
+const ContentType = "application/pdf"
+
+// Page size and margins, in points
+const (
+	PageWidth  = 612.0
+	PageHeight = 792.0
+	Margin     = 72.0
+	// footerY is the baseline of the page footer
+	footerY = 40.0
+)
+
+// Font is one of the standard fonts
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+	Italic
+)
+
+var fontNames = []string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique"}
+
+// Document lays out text top to bottom, starting new pages as it fills
+// them
+type Document struct {
+	// Title is saved in the file's metadata and printed in each page's
+	// footer
+	Title   string
+	Created time.Time
+	// Stamp, if set, is printed at the right of each page's footer
+	Stamp  string
+	pages  []*bytes.Buffer
+	images []image.Image
+	// y is the baseline the next line is drawn above, measured from the
+	// bottom of the page
+	y float64
+}
+
+// New starts a document with one empty page
+func New(title string, created time.Time) *Document {
+	d := &Document{Title: title, Created: created}
+	d.NewPage()
+	return d
+}
+
+// NewPage starts a new page
+func (d *Document) NewPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = PageHeight - Margin
+}
+
+// Need starts a new page unless height points are left on this one, so
+// that what follows isn't split across pages
+func (d *Document) Need(height float64) {
+	if d.y-height < Margin {
+		d.NewPage()
+	}
+}
+
+// Space moves down by height points
+func (d *Document) Space(height float64) {
+	d.y -= height
+	if d.y < Margin {
+		d.NewPage()
+	}
+}
+
+// Text draws s in font at size points, wrapped to the page's width
+func (d *Document) Text(font Font, size float64, s string) {
+	d.text(font, size, 0, "", s)
+}
+
+// Indented draws s like Text, but indented by indent points. A non-empty
+// marker, such as a bullet, is drawn in the indent of the first line.
+func (d *Document) Indented(font Font, size, indent float64, marker, s string) {
+	d.text(font, size, indent, marker, s)
+}
+
+func (d *Document) text(font Font, size, indent float64, marker, s string) {
+	leading := size * 1.35
+	for i, line := range wrap(font, size, PageWidth-2*Margin-indent, s) {
+		if d.y-leading < Margin {
+			d.NewPage()
+		}
+		d.y -= leading
+		if i == 0 && marker != "" {
+			d.show(font, size, Margin, d.y, marker)
+		}
+		d.show(font, size, Margin+indent, d.y, line)
+	}
+}
+
+// Columns draws a row of short texts in font at size points, each starting
+// at its offset from the left margin. Texts don't wrap.
+func (d *Document) Columns(font Font, size float64, offsets []float64, texts ...string) {
+	leading := size * 1.35
+	if d.y-leading < Margin {
+		d.NewPage()
+	}
+	d.y -= leading
+	for i, s := range texts {
+		if i < len(offsets) {
+			d.show(font, size, Margin+offsets[i], d.y, s)
+		}
+	}
+}
+
+// Rule draws a line across the page
+func (d *Document) Rule() {
+	d.Line(0, PageWidth-2*Margin)
+}
+
+// Line draws a line at the current position from x1 to x2 points from the
+// left margin, then moves down past it
+func (d *Document) Line(x1, x2 float64) {
+	d.Space(6)
+	fmt.Fprintf(d.page(), "0.5 w %s %s m %s %s l S\n", num(Margin+x1), num(d.y), num(Margin+x2), num(d.y))
+	d.Space(6)
+}
+
+// Image moves down by height points and draws img there, x points from the
+// left margin, scaled to fit within width by height points without
+// changing its shape. Images are drawn in grayscale, on white.
+func (d *Document) Image(img image.Image, x, width, height float64) {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return
+	}
+	if d.y-height < Margin {
+		d.NewPage()
+	}
+	d.y -= height
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+	scale := min(width/w, height/h)
+	d.images = append(d.images, img)
+	fmt.Fprintf(d.page(), "q %s 0 0 %s %s %s cm /Im%d Do Q\n", num(w*scale), num(h*scale), num(Margin+x), num(d.y), len(d.images))
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+func (d *Document) show(font Font, size, x, y float64, s string) {
+	fmt.Fprintf(d.page(), "BT /F%d %s Tf %s %s Td (%s) Tj ET\n", font+1, num(size), num(x), num(y), escape(encode(s)))
+}
+
+// Bytes encodes the document
+func (d *Document) Bytes() []byte {
+	var w writer
+	w.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-3 are the catalog, page tree and info; the fonts follow,
+	// then each page and its contents, then the images
+	const firstFont = 4
+	firstPage := firstFont + len(fontNames)
+	firstImage := firstPage + 2*len(d.pages)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	w.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	w.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	w.object(3, fmt.Sprintf("<< /Title (%s) /Producer (Russ Rentals) /CreationDate (%s) >>",
+		escape(encode(d.Title)), d.Created.UTC().Format("D:20060102150405Z")))
+	fonts := make([]string, len(fontNames))
+	for i, name := range fontNames {
+		w.object(firstFont+i, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+		fonts[i] = fmt.Sprintf("/F%d %d 0 R", i+1, firstFont+i)
+	}
+	resources := fmt.Sprintf("/Font << %s >>", strings.Join(fonts, " "))
+	if len(d.images) > 0 {
+		images := make([]string, len(d.images))
+		for i := range d.images {
+			images[i] = fmt.Sprintf("/Im%d %d 0 R", i+1, firstImage+i)
+		}
+		resources += fmt.Sprintf(" /XObject << %s >>", strings.Join(images, " "))
+	}
+
+	for i, content := range d.pages {
+		var page bytes.Buffer
+		page.Write(content.Bytes())
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		if d.Title != "" {
+			footer = d.Title + "  |  " + footer
+		}
+		fmt.Fprintf(&page, "0.4 g BT /F1 8 Tf %s %s Td (%s) Tj ET\n", num(Margin), num(footerY), escape(encode(footer)))
+		if d.Stamp != "" {
+			x := PageWidth - Margin - Width(Regular, 8, d.Stamp)
+			fmt.Fprintf(&page, "BT /F1 8 Tf %s %s Td (%s) Tj ET\n", num(x), num(footerY), escape(encode(d.Stamp)))
+		}
+
+		n := firstPage + 2*i
+		w.object(n, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << %s >> /Contents %d 0 R >>",
+			num(PageWidth), num(PageHeight), resources, n+1))
+		w.stream(n+1, "", page.Bytes())
+	}
+
+	for i, img := range d.images {
+		bounds := img.Bounds()
+		dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8 ",
+			bounds.Dx(), bounds.Dy())
+		w.stream(firstImage+i, dict, grayOnWhite(img))
+	}
+
+	return w.finish(firstImage + len(d.images))
+}
+
+// grayOnWhite flattens img onto a white background and converts it to 8
+// bit grayscale samples, top row first
+func grayOnWhite(img image.Image) []byte {
+	bounds := img.Bounds()
+	samples := make([]byte, 0, bounds.Dx()*bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			// Colors are premultiplied, so adding what's transparent of
+			// white composites them onto it
+			r, g, b, a := img.At(x, y).RGBA()
+			white := 0xffff - a
+			lum := (19595*(r+white) + 38470*(g+white) + 7471*(b+white) + 1<<15) >> 24
+			samples = append(samples, byte(lum))
+		}
+	}
+	return samples
+}
+
+// writer tracks where each object starts for the cross-reference table
+type writer struct {
+	bytes.Buffer
+	offsets []int
+}
+
+func (w *writer) object(n int, body string) {
+	w.start(n)
+	fmt.Fprintf(w, "%d 0 obj\n%s\nendobj\n", n, body)
+}
+
+// stream writes data compressed, with extra entries in its dictionary
+func (w *writer) stream(n int, dict string, data []byte) {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	zw.Write(data)
+	zw.Close()
+
+	w.start(n)
+	fmt.Fprintf(w, "%d 0 obj\n<< %s/Length %d /Filter /FlateDecode >>\nstream\n", n, dict, z.Len())
+	w.Write(z.Bytes())
+	w.WriteString("\nendstream\nendobj\n")
+}
+
+func (w *writer) start(n int) {
+	for len(w.offsets) < n {
+		w.offsets = append(w.offsets, 0)
+	}
+	w.offsets[n-1] = w.Len()
+}
+
+func (w *writer) finish(size int) []byte {
+	xref := w.Len()
+	fmt.Fprintf(w, "xref\n0 %d\n0000000000 65535 f \n", size)
+	for _, off := range w.offsets {
+		fmt.Fprintf(w, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(w, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", size, xref)
+	return w.Bytes()
+}
+
+// num formats a coordinate without needless decimals
+func num(f float64) string {
+	s := fmt.Sprintf("%.2f", f)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// escape makes s safe inside a PDF string literal
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\r", "", "\n", " ")
+	return r.Replace(s)
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+func TestMemoryUpdateImages(t *testing.T) {
+	store := NewMemoryStore([]models.Property{{ID: 1, Title: "Maple House"}, {ID: 2, Title: "Oak Flat"}})
+	testUpdateImages(t, store, 1, 2)
+}
+
+// TestPostgresUpdateImages runs against the database in TEST_DATABASE_URL.
+// It is skipped when unset.
+func TestPostgresUpdateImages(t *testing.T) {
+	ctx := context.Background()
+	store := postgresTestStore(t)
+	var ids [2]int64
+	for i := range ids {
+		p := models.Property{
+			Slug:  fmt.Sprintf("update-images-%d-%d", time.Now().UnixNano(), i),
+			Title: "Photo test",
+			Type:  models.PropertyTypeHouse,
+		}
+		if err := store.Properties.Create(ctx, &p); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { store.Properties.Delete(ctx, p.ID) })
+		ids[i] = p.ID
+	}
+	testUpdateImages(t, store, ids[0], ids[1])
+}
+
+// testUpdateImages gives property three photos and other one, checking
+// each store saves only a complete set of property's photos
+func testUpdateImages(t *testing.T, store *Store, property, other int64) {
+	ctx := context.Background()
+	add := func(propertyID int64, caption string, order int) models.PropertyImage {
+		t.Helper()
+		img := models.PropertyImage{PropertyID: propertyID, URL: "/uploads/" + caption + ".jpg", Caption: caption, Room: models.RoomTypeExterior, DisplayOrder: order}
+		if err := store.Properties.AddImage(ctx, &img); err != nil {
+			t.Fatal(err)
+		}
+		return img
+	}
+	front, kitchen, bath := add(property, "front", 0), add(property, "kitchen", 1), add(property, "bath", 2)
+	elsewhere := add(other, "elsewhere", 0)
+
+	edit := func(img models.PropertyImage, order int) models.PropertyImage {
+		img.Caption += " (edited)"
+		img.Room = models.RoomTypeLiving
+		img.DisplayOrder = order
+		return img
+	}
+	captions := func() []string {
+		t.Helper()
+		p, err := store.Properties.GetByID(ctx, property)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, img := range p.Images {
+			got = append(got, img.Caption)
+		}
+		return got
+	}
+	want := fmt.Sprint([]string{"front", "kitchen", "bath"})
+
+	rejected := []struct {
+		name   string
+		images []models.PropertyImage
+	}{
+		{"missing a photo", []models.PropertyImage{edit(bath, 0), edit(front, 1)}},
+		{"a photo twice", []models.PropertyImage{edit(bath, 0), edit(front, 1), edit(bath, 2)}},
+		{"another property's photo", []models.PropertyImage{edit(bath, 0), edit(front, 1), edit(elsewhere, 2)}},
+		{"a photo too many", []models.PropertyImage{edit(bath, 0), edit(front, 1), edit(kitchen, 2), edit(elsewhere, 3)}},
+	}
+	for _, tt := range rejected {
+		if err := store.Properties.UpdateImages(ctx, property, tt.images); !errors.Is(err, ErrNotFound) {
+			t.Errorf("UpdateImages() with %s error = %v, want ErrNotFound", tt.name, err)
+		}
+		if got := fmt.Sprint(captions()); got != want {
+			t.Errorf("photos after UpdateImages() with %s = %s, want %s unchanged", tt.name, got, want)
+		}
+	}
+	if err := store.Properties.UpdateImages(ctx, -1, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateImages() of a missing property error = %v, want ErrNotFound", err)
+	}
+
+	err := store.Properties.UpdateImages(ctx, property, []models.PropertyImage{edit(bath, 0), edit(front, 1), edit(kitchen, 2)})
+	if err != nil {
+		t.Fatalf("UpdateImages() error = %v", err)
+	}
+	if got, want := fmt.Sprint(captions()), fmt.Sprint([]string{"bath (edited)", "front (edited)", "kitchen (edited)"}); got != want {
+		t.Errorf("photos after UpdateImages() = %s, want %s", got, want)
+	}
+}
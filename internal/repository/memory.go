@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// NewMemoryStore creates a Store that keeps everything in process memory,
+// starting from the given properties
+func NewMemoryStore(properties []models.Property) *Store {
+	ledger := NewMemoryLedgerRepository()
+	documents := NewMemoryDocumentRepository()
+	return &Store{
+		Properties:   NewMemoryPropertyRepository(properties),
+		Contacts:     NewMemoryContactRepository(),
+		Newsletter:   NewMemoryNewsletterRepository(),
+		Users:        NewMemoryUserRepository(),
+		Showings:     NewMemoryShowingRepository(),
+		Applications: NewMemoryApplicationRepository(),
+		Payments:     NewMemoryPaymentRepository(),
+		Leases:       NewMemoryLeaseRepository(),
+		Ledger:       ledger,
+		LateFees:     NewMemoryLateFeeRepository(),
+		Autopay:      NewMemoryAutopayRepository(),
+		Maintenance:  NewMemoryMaintenanceRepository(),
+		Vendors:      NewMemoryVendorRepository(),
+		WorkOrders:   NewMemoryWorkOrderRepository(ledger),
+		Documents:    documents,
+		Signatures:   NewMemorySignatureRepository(documents),
+		Jobs:         NewMemoryJobRepository(),
+	}
+}
+
+// MemoryPropertyRepository serves properties from an in-memory slice
+type MemoryPropertyRepository struct {
+	mu          sync.RWMutex
+	nextID      int64
+	nextImageID int64
+	properties  []models.Property
+}
+
+// NewMemoryPropertyRepository creates a PropertyRepository holding properties
+func NewMemoryPropertyRepository(properties []models.Property) *MemoryPropertyRepository {
+	r := &MemoryPropertyRepository{nextID: 1, nextImageID: 1, properties: properties}
+	for _, p := range properties {
+		if p.ID >= r.nextID {
+			r.nextID = p.ID + 1
+		}
+		for _, img := range p.Images {
+			if img.ID >= r.nextImageID {
+				r.nextImageID = img.ID + 1
+			}
+		}
+	}
+	return r
+}
+
+func (r *MemoryPropertyRepository) List(ctx context.Context) ([]models.Property, error) {
+	return r.where(func(p models.Property) bool { return true }), nil
+}
+
+func (r *MemoryPropertyRepository) ListAvailable(ctx context.Context) ([]models.Property, error) {
+	return r.where(func(p models.Property) bool { return p.Available }), nil
+}
+
+func (r *MemoryPropertyRepository) Filter(ctx context.Context, filter PropertyFilter) ([]models.Property, error) {
+	return r.where(func(p models.Property) bool {
+		if filter.Type != "" && string(p.Type) != filter.Type {
+			return false
+		}
+		if filter.MinPrice != 0 && p.Price < filter.MinPrice {
+			return false
+		}
+		if filter.MaxPrice != 0 && p.Price > filter.MaxPrice {
+			return false
+		}
+		if filter.MinBedrooms != 0 && p.Bedrooms < filter.MinBedrooms {
+			return false
+		}
+		return true
+	}), nil
+}
+
+func (r *MemoryPropertyRepository) Featured(ctx context.Context, limit int) ([]models.Property, error) {
+	featured := r.where(func(p models.Property) bool { return p.Featured && p.Available })
+	if len(featured) > limit {
+		featured = featured[:limit]
+	}
+	return featured, nil
+}
+
+func (r *MemoryPropertyRepository) GetBySlug(ctx context.Context, slug string) (*models.Property, error) {
+	matches := r.where(func(p models.Property) bool { return p.Slug == slug })
+	if len(matches) == 0 {
+		return nil, ErrNotFound
+	}
+	return &matches[0], nil
+}
+
+func (r *MemoryPropertyRepository) GetByID(ctx context.Context, id int64) (*models.Property, error) {
+	matches := r.where(func(p models.Property) bool { return p.ID == id })
+	if len(matches) == 0 {
+		return nil, ErrNotFound
+	}
+	return &matches[0], nil
+}
+
+func (r *MemoryPropertyRepository) Images(ctx context.Context, propertyID int64) ([]models.PropertyImage, error) {
+	p, err := r.GetByID(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	return p.Images, nil
+}
+
+func (r *MemoryPropertyRepository) ImagesByRoom(ctx context.Context, propertyID int64, room models.RoomType) ([]models.PropertyImage, error) {
+	p, err := r.GetByID(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	return p.ImagesByRoom(room), nil
+}
+
+func (r *MemoryPropertyRepository) Create(ctx context.Context, p *models.Property) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.properties {
+		if existing.Slug == p.Slug {
+			return ErrSlugTaken
+		}
+	}
+
+	p.ID = r.nextID
+	p.CreatedAt = time.Now()
+	p.UpdatedAt = p.CreatedAt
+	p.Images = nil
+	r.nextID++
+	r.properties = append(r.properties, *p)
+	return nil
+}
+
+func (r *MemoryPropertyRepository) Update(ctx context.Context, p *models.Property) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.properties {
+		if existing.ID == p.ID {
+			updated := *p
+			updated.Slug = existing.Slug
+			updated.Images = existing.Images
+			updated.CreatedAt = existing.CreatedAt
+			updated.UpdatedAt = time.Now()
+			r.properties[i] = updated
+			p.UpdatedAt = updated.UpdatedAt
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryPropertyRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, p := range r.properties {
+		if p.ID == id {
+			r.properties = append(r.properties[:i], r.properties[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryPropertyRepository) AddImage(ctx context.Context, img *models.PropertyImage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.properties {
+		if r.properties[i].ID == img.PropertyID {
+			img.ID = r.nextImageID
+			img.CreatedAt = time.Now()
+			r.nextImageID++
+			r.properties[i].Images = append(r.properties[i].Images, *img)
+			sortImages(r.properties[i].Images)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryPropertyRepository) UpdateImages(ctx context.Context, propertyID int64, images []models.PropertyImage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.properties {
+		if r.properties[i].ID != propertyID {
+			continue
+		}
+
+		// Check every image before changing any, so a bad ID saves nothing
+		updated := append([]models.PropertyImage(nil), r.properties[i].Images...)
+		if len(images) != len(updated) {
+			return ErrNotFound
+		}
+		seen := make(map[int64]bool, len(images))
+		for _, img := range images {
+			j := slices.IndexFunc(updated, func(existing models.PropertyImage) bool { return existing.ID == img.ID })
+			if j < 0 || seen[img.ID] {
+				return ErrNotFound
+			}
+			seen[img.ID] = true
+			updated[j].Caption = img.Caption
+			updated[j].Room = img.Room
+			updated[j].DisplayOrder = img.DisplayOrder
+		}
+		sortImages(updated)
+		r.properties[i].Images = updated
+		return nil
+	}
+	return ErrNotFound
+}
+
+// sortImages keeps images in gallery order, like ORDER BY display_order
+func sortImages(images []models.PropertyImage) {
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].DisplayOrder < images[j].DisplayOrder
+	})
+}
+
+// where returns copies of the properties matching keep, in insertion order
+func (r *MemoryPropertyRepository) where(keep func(models.Property) bool) []models.Property {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []models.Property
+	for _, p := range r.properties {
+		if keep(p) {
+			p.Images = append([]models.PropertyImage(nil), p.Images...)
+			matches = append(matches, p)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryApplicationRepository keeps rental applications in memory
+type MemoryApplicationRepository struct {
+	mu          sync.RWMutex
+	nextID      int64
+	nextEventID int64
+	apps        []models.Application
+	events      []models.ApplicationEvent
+}
+
+// NewMemoryApplicationRepository creates an empty ApplicationRepository
+func NewMemoryApplicationRepository() *MemoryApplicationRepository {
+	return &MemoryApplicationRepository{nextID: 1, nextEventID: 1}
+}
+
+func (r *MemoryApplicationRepository) StartDraft(ctx context.Context, propertyID int64, applicantID string, data models.ApplicationData) (*models.Application, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, a := range r.apps {
+		if a.ApplicantID == applicantID && a.PropertyID == propertyID && a.Status == models.ApplicationStatusDraft {
+			a = cloneApplication(a)
+			return &a, nil
+		}
+	}
+
+	now := time.Now()
+	app := models.Application{
+		ID:          r.nextID,
+		PropertyID:  propertyID,
+		ApplicantID: applicantID,
+		Status:      models.ApplicationStatusDraft,
+		Data:        data,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	r.nextID++
+	r.apps = append(r.apps, cloneApplication(app))
+	return &app, nil
+}
+
+func (r *MemoryApplicationRepository) Get(ctx context.Context, id int64) (*models.Application, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, a := range r.apps {
+		if a.ID == id {
+			a = cloneApplication(a)
+			return &a, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryApplicationRepository) ListByApplicant(ctx context.Context, applicantID string) ([]models.Application, error) {
+	apps := r.where(func(a models.Application) bool { return a.ApplicantID == applicantID })
+	sort.SliceStable(apps, func(i, j int) bool { return apps[i].UpdatedAt.After(apps[j].UpdatedAt) })
+	return apps, nil
+}
+
+func (r *MemoryApplicationRepository) Filter(ctx context.Context, filter ApplicationFilter) ([]models.Application, error) {
+	apps := r.where(func(a models.Application) bool {
+		return a.Status != models.ApplicationStatusDraft &&
+			(filter.PropertyID == 0 || a.PropertyID == filter.PropertyID) &&
+			(filter.Status == "" || a.Status == filter.Status)
+	})
+	sort.SliceStable(apps, func(i, j int) bool { return apps[i].SubmittedAt.After(*apps[j].SubmittedAt) })
+	return apps, nil
+}
+
+func (r *MemoryApplicationRepository) SaveDraft(ctx context.Context, id int64, data models.ApplicationData) (*models.Application, error) {
+	return r.update(id, models.ApplicationStatusDraft, func(a *models.Application) {
+		a.Data = data
+	})
+}
+
+func (r *MemoryApplicationRepository) SetStatus(ctx context.Context, id int64, from, to models.ApplicationStatus, actorID, note string) (*models.Application, error) {
+	app, err := r.update(id, from, func(a *models.Application) {
+		now := time.Now()
+		a.Status = to
+		switch to {
+		case models.ApplicationStatusSubmitted:
+			a.SubmittedAt = &now
+		case models.ApplicationStatusApproved, models.ApplicationStatusDenied:
+			a.DecidedAt = &now
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, models.ApplicationEvent{
+		ID:            r.nextEventID,
+		ApplicationID: id,
+		ActorID:       actorID,
+		Status:        to,
+		Note:          note,
+		CreatedAt:     time.Now(),
+	})
+	r.nextEventID++
+	return app, nil
+}
+
+func (r *MemoryApplicationRepository) Timeline(ctx context.Context, id int64) ([]models.ApplicationEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []models.ApplicationEvent
+	for _, e := range r.events {
+		if e.ApplicationID == id {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// update applies change to an application whose status is still status
+func (r *MemoryApplicationRepository) update(id int64, status models.ApplicationStatus, change func(*models.Application)) (*models.Application, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.apps, func(a models.Application) bool { return a.ID == id })
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	if r.apps[i].Status != status {
+		return nil, ErrStatusChanged
+	}
+	app := cloneApplication(r.apps[i])
+	change(&app)
+	app.UpdatedAt = time.Now()
+	r.apps[i] = cloneApplication(app)
+	return &app, nil
+}
+
+func (r *MemoryApplicationRepository) where(keep func(models.Application) bool) []models.Application {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []models.Application
+	for _, a := range r.apps {
+		if keep(a) {
+			matches = append(matches, cloneApplication(a))
+		}
+	}
+	return matches
+}
+
+// cloneApplication copies a's answers so callers can't modify stored ones
+func cloneApplication(a models.Application) models.Application {
+	a.Data.Residences = slices.Clone(a.Data.Residences)
+	a.Data.Employment = slices.Clone(a.Data.Employment)
+	a.Data.References = slices.Clone(a.Data.References)
+	a.Data.Pets = slices.Clone(a.Data.Pets)
+	a.Data.CoApplicants = slices.Clone(a.Data.CoApplicants)
+	a.Data.Completed = slices.Clone(a.Data.Completed)
+	return a
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryAutopayRepository keeps autopay enrollments in memory
+type MemoryAutopayRepository struct {
+	mu          sync.RWMutex
+	nextID      int64
+	enrollments []models.Autopay
+}
+
+// NewMemoryAutopayRepository creates an empty AutopayRepository
+func NewMemoryAutopayRepository() *MemoryAutopayRepository {
+	return &MemoryAutopayRepository{nextID: 1}
+}
+
+func (r *MemoryAutopayRepository) Enroll(ctx context.Context, a *models.Autopay) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	a.Attempts = 0
+	a.RetryOn = nil
+	a.LastError = ""
+	a.UpdatedAt = now
+	if i := slices.IndexFunc(r.enrollments, func(e models.Autopay) bool { return e.LeaseID == a.LeaseID }); i >= 0 {
+		a.ID = r.enrollments[i].ID
+		a.CreatedAt = r.enrollments[i].CreatedAt
+		r.enrollments[i] = cloneAutopay(*a)
+		return nil
+	}
+	a.ID = r.nextID
+	a.CreatedAt = now
+	r.nextID++
+	r.enrollments = append(r.enrollments, cloneAutopay(*a))
+	return nil
+}
+
+func (r *MemoryAutopayRepository) GetByLease(ctx context.Context, leaseID int64) (*models.Autopay, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, a := range r.enrollments {
+		if a.LeaseID == leaseID {
+			a = cloneAutopay(a)
+			return &a, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryAutopayRepository) List(ctx context.Context) ([]models.Autopay, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	enrollments := make([]models.Autopay, len(r.enrollments))
+	for i, a := range r.enrollments {
+		enrollments[i] = cloneAutopay(a)
+	}
+	return enrollments, nil
+}
+
+func (r *MemoryAutopayRepository) SaveAttempt(ctx context.Context, a *models.Autopay) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.enrollments, func(e models.Autopay) bool { return e.ID == a.ID })
+	if i < 0 {
+		return ErrNotFound
+	}
+	stored := &r.enrollments[i]
+	stored.Period = a.Period
+	stored.Attempts = a.Attempts
+	stored.RetryOn = a.RetryOn
+	stored.LastError = a.LastError
+	stored.UpdatedAt = time.Now()
+	*stored = cloneAutopay(*stored)
+	*a = cloneAutopay(*stored)
+	return nil
+}
+
+func (r *MemoryAutopayRepository) Cancel(ctx context.Context, leaseID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.enrollments, func(e models.Autopay) bool { return e.LeaseID == leaseID })
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.enrollments = slices.Delete(r.enrollments, i, i+1)
+	return nil
+}
+
+// cloneAutopay copies a's dates so callers can't modify stored ones
+func cloneAutopay(a models.Autopay) models.Autopay {
+	if a.Period != nil {
+		period := *a.Period
+		a.Period = &period
+	}
+	if a.RetryOn != nil {
+		retryOn := *a.RetryOn
+		a.RetryOn = &retryOn
+	}
+	return a
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryContactRepository keeps contact submissions in memory
+type MemoryContactRepository struct {
+	mu          sync.RWMutex
+	nextID      int64
+	nextEventID int64
+	subs        []models.ContactSubmission
+	events      []models.InquiryEvent
+}
+
+// NewMemoryContactRepository creates an empty ContactRepository
+func NewMemoryContactRepository() *MemoryContactRepository {
+	return &MemoryContactRepository{nextID: 1, nextEventID: 1}
+}
+
+func (r *MemoryContactRepository) Create(ctx context.Context, sub *models.ContactSubmission) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sub.ID = r.nextID
+	sub.Status = models.InquiryStatusNew
+	sub.AssigneeID = ""
+	sub.CreatedAt = time.Now()
+	sub.UpdatedAt = sub.CreatedAt
+	r.nextID++
+	r.subs = append(r.subs, *sub)
+	return nil
+}
+
+func (r *MemoryContactRepository) Get(ctx context.Context, id int64) (*models.ContactSubmission, error) {
+	matches := r.where(func(s models.ContactSubmission) bool { return s.ID == id })
+	if len(matches) == 0 {
+		return nil, ErrNotFound
+	}
+	return &matches[0], nil
+}
+
+func (r *MemoryContactRepository) List(ctx context.Context) ([]models.ContactSubmission, error) {
+	return r.where(func(s models.ContactSubmission) bool { return true }), nil
+}
+
+func (r *MemoryContactRepository) ListByProperty(ctx context.Context, propertyID int64) ([]models.ContactSubmission, error) {
+	return r.where(func(s models.ContactSubmission) bool {
+		return s.PropertyID != nil && *s.PropertyID == propertyID
+	}), nil
+}
+
+func (r *MemoryContactRepository) ListByEmail(ctx context.Context, email string) ([]models.ContactSubmission, error) {
+	return r.where(func(s models.ContactSubmission) bool { return s.Email == email }), nil
+}
+
+func (r *MemoryContactRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, s := range r.subs {
+		if s.ID == id {
+			r.subs = append(r.subs[:i], r.subs[i+1:]...)
+			break
+		}
+	}
+	r.events = slices.DeleteFunc(r.events, func(e models.InquiryEvent) bool { return e.SubmissionID == id })
+	return nil
+}
+
+func (r *MemoryContactRepository) Filter(ctx context.Context, filter InquiryFilter) ([]models.ContactSubmission, error) {
+	return r.where(func(s models.ContactSubmission) bool {
+		switch {
+		case filter.PropertyID != 0 && (s.PropertyID == nil || *s.PropertyID != filter.PropertyID):
+			return false
+		case filter.Type != "" && s.InquiryType != filter.Type:
+			return false
+		case filter.Status != "" && s.Status != filter.Status:
+			return false
+		case filter.AssigneeID != "" && s.AssigneeID != filter.AssigneeID:
+			return false
+		case !filter.ReceivedFrom.IsZero() && s.CreatedAt.Before(filter.ReceivedFrom):
+			return false
+		case !filter.ReceivedBefore.IsZero() && !s.CreatedAt.Before(filter.ReceivedBefore):
+			return false
+		}
+		return true
+	}), nil
+}
+
+func (r *MemoryContactRepository) SetStatus(ctx context.Context, id int64, status models.InquiryStatus, actorID string) (*models.ContactSubmission, error) {
+	return r.update(id, actorID, models.InquiryEventStatus, string(status), func(s *models.ContactSubmission) {
+		s.Status = status
+	})
+}
+
+func (r *MemoryContactRepository) Assign(ctx context.Context, id int64, assigneeID, actorID string) (*models.ContactSubmission, error) {
+	return r.update(id, actorID, models.InquiryEventAssigned, assigneeID, func(s *models.ContactSubmission) {
+		s.AssigneeID = assigneeID
+	})
+}
+
+// update applies change to a submission and records it on its timeline
+func (r *MemoryContactRepository) update(id int64, actorID string, kind models.InquiryEventKind, detail string, change func(*models.ContactSubmission)) (*models.ContactSubmission, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.subs {
+		if r.subs[i].ID == id {
+			change(&r.subs[i])
+			r.subs[i].UpdatedAt = time.Now()
+			r.addEvent(id, actorID, kind, detail)
+			sub := r.subs[i]
+			return &sub, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryContactRepository) AddNote(ctx context.Context, id int64, note, actorID string) (*models.InquiryEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !slices.ContainsFunc(r.subs, func(s models.ContactSubmission) bool { return s.ID == id }) {
+		return nil, ErrNotFound
+	}
+	event := r.addEvent(id, actorID, models.InquiryEventNote, note)
+	return &event, nil
+}
+
+func (r *MemoryContactRepository) Timeline(ctx context.Context, id int64) ([]models.InquiryEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []models.InquiryEvent
+	for _, e := range r.events {
+		if e.SubmissionID == id {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// addEvent appends to the timeline. The caller must hold r.mu.
+func (r *MemoryContactRepository) addEvent(id int64, actorID string, kind models.InquiryEventKind, detail string) models.InquiryEvent {
+	event := models.InquiryEvent{
+		ID:           r.nextEventID,
+		SubmissionID: id,
+		ActorID:      actorID,
+		Kind:         kind,
+		Detail:       detail,
+		CreatedAt:    time.Now(),
+	}
+	r.nextEventID++
+	r.events = append(r.events, event)
+	return event
+}
+
+// where returns the matching submissions, newest first
+func (r *MemoryContactRepository) where(keep func(models.ContactSubmission) bool) []models.ContactSubmission {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []models.ContactSubmission
+	for i := len(r.subs) - 1; i >= 0; i-- {
+		if keep(r.subs[i]) {
+			matches = append(matches, r.subs[i])
+		}
+	}
+	return matches
+}
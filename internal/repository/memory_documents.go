@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryDocumentRepository keeps lease documents and their access log in
+// memory
+type MemoryDocumentRepository struct {
+	mu           sync.RWMutex
+	nextID       int64
+	nextAccessID int64
+	documents    []models.Document
+	accesses     []models.DocumentAccess
+}
+
+// NewMemoryDocumentRepository creates an empty DocumentRepository
+func NewMemoryDocumentRepository() *MemoryDocumentRepository {
+	return &MemoryDocumentRepository{nextID: 1, nextAccessID: 1}
+}
+
+func (r *MemoryDocumentRepository) Create(ctx context.Context, d *models.Document) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d.ID = r.nextID
+	d.CreatedAt = time.Now()
+	r.nextID++
+	r.documents = append(r.documents, *d)
+	return nil
+}
+
+func (r *MemoryDocumentRepository) Get(ctx context.Context, id int64) (*models.Document, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, d := range r.documents {
+		if d.ID == id {
+			return &d, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryDocumentRepository) ListByLease(ctx context.Context, leaseID int64) ([]models.Document, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var docs []models.Document
+	for _, d := range r.documents {
+		if d.LeaseID == leaseID {
+			docs = append(docs, d)
+		}
+	}
+	sort.SliceStable(docs, func(i, j int) bool { return docs[i].ID > docs[j].ID })
+	return docs, nil
+}
+
+func (r *MemoryDocumentRepository) LogAccess(ctx context.Context, a *models.DocumentAccess) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a.ID = r.nextAccessID
+	a.AccessedAt = time.Now()
+	r.nextAccessID++
+	r.accesses = append(r.accesses, *a)
+	return nil
+}
+
+func (r *MemoryDocumentRepository) ListAccessesByLease(ctx context.Context, leaseID int64) ([]models.DocumentAccess, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var ids []int64
+	for _, d := range r.documents {
+		if d.LeaseID == leaseID {
+			ids = append(ids, d.ID)
+		}
+	}
+	var accesses []models.DocumentAccess
+	for _, a := range r.accesses {
+		if slices.Contains(ids, a.DocumentID) {
+			accesses = append(accesses, a)
+		}
+	}
+	sort.SliceStable(accesses, func(i, j int) bool { return accesses[i].ID > accesses[j].ID })
+	return accesses, nil
+}
+
+// leaseDocumentIDs lists the IDs of a lease's documents
+func (r *MemoryDocumentRepository) leaseDocumentIDs(leaseID int64) []int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var ids []int64
+	for _, d := range r.documents {
+		if d.LeaseID == leaseID {
+			ids = append(ids, d.ID)
+		}
+	}
+	return ids
+}
+
+// setSigned links document id to its signed copy, reporting false if it
+// doesn't exist or already has one
+func (r *MemoryDocumentRepository) setSigned(id, signedID int64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.documents {
+		if r.documents[i].ID == id && r.documents[i].SignedDocumentID == nil {
+			r.documents[i].SignedDocumentID = &signedID
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryJobRepository keeps job runs in memory. Its locks only cover this
+// process.
+type MemoryJobRepository struct {
+	mu     sync.Mutex
+	nextID int64
+	runs   []models.JobRun
+	locked map[string]bool
+}
+
+// NewMemoryJobRepository creates an empty JobRepository
+func NewMemoryJobRepository() *MemoryJobRepository {
+	return &MemoryJobRepository{nextID: 1, locked: map[string]bool{}}
+}
+
+func (r *MemoryJobRepository) TryLock(ctx context.Context, name string) (func(), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.locked[name] {
+		return nil, ErrLocked
+	}
+	r.locked[name] = true
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.locked, name)
+	}, nil
+}
+
+func (r *MemoryJobRepository) StartRun(ctx context.Context, run *models.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if run.Trigger == models.JobTriggerSchedule && slices.ContainsFunc(r.runs, func(existing models.JobRun) bool {
+		return existing.Job == run.Job && existing.Trigger == models.JobTriggerSchedule && existing.ScheduledFor.Equal(run.ScheduledFor)
+	}) {
+		return ErrDuplicateRun
+	}
+	run.ID = r.nextID
+	run.Status = models.JobRunStatusRunning
+	run.StartedAt = time.Now()
+	r.nextID++
+	r.runs = append(r.runs, *run)
+	return nil
+}
+
+func (r *MemoryJobRepository) FinishRun(ctx context.Context, id int64, status models.JobRunStatus, summary, errMsg string) (*models.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.runs, func(run models.JobRun) bool { return run.ID == id })
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	now := time.Now()
+	r.runs[i].Status = status
+	r.runs[i].Summary = summary
+	r.runs[i].Error = errMsg
+	r.runs[i].FinishedAt = &now
+	run := r.runs[i]
+	return &run, nil
+}
+
+func (r *MemoryJobRepository) ListRuns(ctx context.Context, job string, limit int) ([]models.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var runs []models.JobRun
+	for _, run := range r.runs {
+		if job == "" || run.Job == job {
+			runs = append(runs, run)
+		}
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].ID > runs[j].ID })
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryLateFeeRepository keeps late fee policies in memory. Deleting a
+// policy doesn't clear leases' references to it.
+type MemoryLateFeeRepository struct {
+	mu       sync.RWMutex
+	nextID   int64
+	policies []models.LateFeePolicy
+	// properties maps property IDs to their policy's ID
+	properties map[int64]int64
+}
+
+// NewMemoryLateFeeRepository creates an empty LateFeeRepository
+func NewMemoryLateFeeRepository() *MemoryLateFeeRepository {
+	return &MemoryLateFeeRepository{nextID: 1, properties: map[int64]int64{}}
+}
+
+func (r *MemoryLateFeeRepository) Create(ctx context.Context, p *models.LateFeePolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	p.ID = r.nextID
+	p.CreatedAt = now
+	p.UpdatedAt = now
+	r.nextID++
+	r.policies = append(r.policies, *p)
+	r.setProperties(p.ID, p.PropertyIDs)
+	*p = r.withProperties(*p)
+	return nil
+}
+
+func (r *MemoryLateFeeRepository) Get(ctx context.Context, id int64) (*models.LateFeePolicy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, p := range r.policies {
+		if p.ID == id {
+			p = r.withProperties(p)
+			return &p, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryLateFeeRepository) List(ctx context.Context) ([]models.LateFeePolicy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	policies := make([]models.LateFeePolicy, len(r.policies))
+	for i, p := range r.policies {
+		policies[i] = r.withProperties(p)
+	}
+	sort.SliceStable(policies, func(i, j int) bool { return policies[i].Name < policies[j].Name })
+	return policies, nil
+}
+
+func (r *MemoryLateFeeRepository) Update(ctx context.Context, p *models.LateFeePolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, stored := range r.policies {
+		if stored.ID == p.ID {
+			p.CreatedAt = stored.CreatedAt
+			p.UpdatedAt = time.Now()
+			r.policies[i] = *p
+			r.setProperties(p.ID, p.PropertyIDs)
+			*p = r.withProperties(*p)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *MemoryLateFeeRepository) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, p := range r.policies {
+		if p.ID == id {
+			r.policies = slices.Delete(r.policies, i, i+1)
+			r.setProperties(id, nil)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// setProperties makes policy id the policy of exactly propertyIDs. The
+// caller must hold the write lock.
+func (r *MemoryLateFeeRepository) setProperties(id int64, propertyIDs []int64) {
+	for propertyID, policyID := range r.properties {
+		if policyID == id {
+			delete(r.properties, propertyID)
+		}
+	}
+	for _, propertyID := range propertyIDs {
+		r.properties[propertyID] = id
+	}
+}
+
+// withProperties fills in p.PropertyIDs. The caller must hold the lock.
+func (r *MemoryLateFeeRepository) withProperties(p models.LateFeePolicy) models.LateFeePolicy {
+	p.PropertyIDs = nil
+	for propertyID, policyID := range r.properties {
+		if policyID == p.ID {
+			p.PropertyIDs = append(p.PropertyIDs, propertyID)
+		}
+	}
+	slices.Sort(p.PropertyIDs)
+	return p
+}
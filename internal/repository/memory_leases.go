@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryLeaseRepository keeps leases in memory
+type MemoryLeaseRepository struct {
+	mu      sync.RWMutex
+	nextID  int64
+	leases  []models.Lease
+	notices map[string]bool
+}
+
+// NewMemoryLeaseRepository creates an empty LeaseRepository
+func NewMemoryLeaseRepository() *MemoryLeaseRepository {
+	return &MemoryLeaseRepository{nextID: 1, notices: map[string]bool{}}
+}
+
+func (r *MemoryLeaseRepository) Create(ctx context.Context, l *models.Lease) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	l.ID = r.nextID
+	l.CreatedAt = now
+	l.UpdatedAt = now
+	r.nextID++
+	r.leases = append(r.leases, cloneLease(*l))
+	return nil
+}
+
+func (r *MemoryLeaseRepository) Get(ctx context.Context, id int64) (*models.Lease, error) {
+	leases := r.where(func(l models.Lease) bool { return l.ID == id })
+	if len(leases) == 0 {
+		return nil, ErrNotFound
+	}
+	return &leases[0], nil
+}
+
+func (r *MemoryLeaseRepository) Filter(ctx context.Context, filter LeaseFilter) ([]models.Lease, error) {
+	return r.where(func(l models.Lease) bool {
+		return (filter.PropertyID == 0 || l.PropertyID == filter.PropertyID) &&
+			(filter.Status == "" || l.Status == filter.Status)
+	}), nil
+}
+
+func (r *MemoryLeaseRepository) ListByTenant(ctx context.Context, userID string) ([]models.Lease, error) {
+	return r.where(func(l models.Lease) bool { return l.HasTenant(userID) }), nil
+}
+
+func (r *MemoryLeaseRepository) Update(ctx context.Context, l *models.Lease) error {
+	updated, err := r.update(l.ID, models.LeaseStatusDraft, func(stored *models.Lease) {
+		stored.PropertyID = l.PropertyID
+		stored.ApplicationID = l.ApplicationID
+		stored.StartDate = l.StartDate
+		stored.EndDate = l.EndDate
+		stored.Term = l.Term
+		stored.MonthlyRentCents = l.MonthlyRentCents
+		stored.DepositCents = l.DepositCents
+		stored.PetRentCents = l.PetRentCents
+		stored.LateFeePolicyID = l.LateFeePolicyID
+		stored.Notes = l.Notes
+		stored.Tenants = slices.Clone(l.Tenants)
+	})
+	if err != nil {
+		return err
+	}
+	*l = *updated
+	return nil
+}
+
+func (r *MemoryLeaseRepository) SetStatus(ctx context.Context, id int64, from, to models.LeaseStatus) (*models.Lease, error) {
+	return r.update(id, from, func(l *models.Lease) {
+		l.Status = to
+	})
+}
+
+func (r *MemoryLeaseRepository) MarkNotified(ctx context.Context, leaseID int64, notice string, day time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := fmt.Sprintf("%d/%s/%s", leaseID, notice, day.Format("2006-01-02"))
+	if r.notices[key] {
+		return false, nil
+	}
+	r.notices[key] = true
+	return true, nil
+}
+
+func (r *MemoryLeaseRepository) ClearNotified(ctx context.Context, leaseID int64, notice string, day time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.notices, fmt.Sprintf("%d/%s/%s", leaseID, notice, day.Format("2006-01-02")))
+	return nil
+}
+
+// update applies change to a lease whose status is still status
+func (r *MemoryLeaseRepository) update(id int64, status models.LeaseStatus, change func(*models.Lease)) (*models.Lease, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.leases, func(l models.Lease) bool { return l.ID == id })
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	if r.leases[i].Status != status {
+		return nil, ErrStatusChanged
+	}
+	lease := cloneLease(r.leases[i])
+	change(&lease)
+	lease.UpdatedAt = time.Now()
+	r.leases[i] = cloneLease(lease)
+	return &lease, nil
+}
+
+// where lists the leases keep accepts, latest start first
+func (r *MemoryLeaseRepository) where(keep func(models.Lease) bool) []models.Lease {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []models.Lease
+	for _, l := range r.leases {
+		if keep(l) {
+			matches = append(matches, cloneLease(l))
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if !matches[i].StartDate.Equal(matches[j].StartDate) {
+			return matches[i].StartDate.After(matches[j].StartDate)
+		}
+		return matches[i].ID > matches[j].ID
+	})
+	return matches
+}
+
+// cloneLease copies l's tenants so callers can't modify stored ones
+func cloneLease(l models.Lease) models.Lease {
+	l.Tenants = slices.Clone(l.Tenants)
+	return l
+}
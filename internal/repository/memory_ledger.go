@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryLedgerRepository keeps ledger entries in memory. It doesn't check
+// that leases exist.
+type MemoryLedgerRepository struct {
+	mu      sync.RWMutex
+	nextID  int64
+	entries []models.LedgerEntry
+}
+
+// NewMemoryLedgerRepository creates an empty LedgerRepository
+func NewMemoryLedgerRepository() *MemoryLedgerRepository {
+	return &MemoryLedgerRepository{nextID: 1}
+}
+
+func (r *MemoryLedgerRepository) Create(ctx context.Context, e *models.LedgerEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.insert(e)
+	return nil
+}
+
+func (r *MemoryLedgerRepository) PostScheduled(ctx context.Context, entries []models.LedgerEntry) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	posted := 0
+	for _, e := range entries {
+		if e.Period == nil || !r.posted(e) {
+			r.insert(&e)
+			posted++
+		}
+	}
+	return posted, nil
+}
+
+func (r *MemoryLedgerRepository) PostPayment(ctx context.Context, e *models.LedgerEntry) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e.PaymentID != nil {
+		for _, stored := range r.entries {
+			if stored.PaymentID != nil && *stored.PaymentID == *e.PaymentID {
+				return false, nil
+			}
+		}
+	}
+	r.insert(e)
+	return true, nil
+}
+
+func (r *MemoryLedgerRepository) ListByLease(ctx context.Context, leaseID int64) (models.Ledger, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var ledger models.Ledger
+	for _, e := range r.entries {
+		if e.LeaseID == leaseID {
+			ledger = append(ledger, cloneLedgerEntry(e))
+		}
+	}
+	ledger.Sort()
+	return ledger, nil
+}
+
+// insert stores e, filling in its ID and CreatedAt. The caller must hold
+// the write lock.
+func (r *MemoryLedgerRepository) insert(e *models.LedgerEntry) {
+	e.ID = r.nextID
+	e.CreatedAt = time.Now()
+	r.nextID++
+	r.entries = append(r.entries, cloneLedgerEntry(*e))
+}
+
+// posted reports whether a scheduled entry like e is already on the ledger.
+// The caller must hold the lock.
+func (r *MemoryLedgerRepository) posted(e models.LedgerEntry) bool {
+	for _, stored := range r.entries {
+		if stored.LeaseID != e.LeaseID || stored.Kind != e.Kind || stored.Period == nil || !stored.Period.Equal(*e.Period) {
+			continue
+		}
+		// Late fees build up day by day
+		if e.Kind != models.LedgerKindLateFee || stored.PostedOn.Equal(e.PostedOn) {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneLedgerEntry(e models.LedgerEntry) models.LedgerEntry {
+	if e.Period != nil {
+		period := *e.Period
+		e.Period = &period
+	}
+	if e.PaymentID != nil {
+		id := *e.PaymentID
+		e.PaymentID = &id
+	}
+	return e
+}
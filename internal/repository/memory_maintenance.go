@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryMaintenanceRepository keeps maintenance requests in memory. It
+// doesn't check that leases exist.
+type MemoryMaintenanceRepository struct {
+	mu          sync.RWMutex
+	nextID      int64
+	nextPhotoID int64
+	nextEventID int64
+	requests    []models.MaintenanceRequest
+	events      []models.MaintenanceEvent
+}
+
+// NewMemoryMaintenanceRepository creates an empty MaintenanceRepository
+func NewMemoryMaintenanceRepository() *MemoryMaintenanceRepository {
+	return &MemoryMaintenanceRepository{nextID: 1, nextPhotoID: 1, nextEventID: 1}
+}
+
+func (r *MemoryMaintenanceRepository) Create(ctx context.Context, req *models.MaintenanceRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	req.ID = r.nextID
+	req.Status = models.MaintenanceStatusOpen
+	req.AssigneeID = ""
+	req.Vendor = ""
+	req.ScheduledFor = nil
+	req.ResolvedAt = nil
+	req.CreatedAt = now
+	req.UpdatedAt = now
+	r.nextID++
+	for i := range req.Photos {
+		req.Photos[i].ID = r.nextPhotoID
+		req.Photos[i].RequestID = req.ID
+		req.Photos[i].CreatedAt = now
+		r.nextPhotoID++
+	}
+	r.requests = append(r.requests, cloneMaintenanceRequest(*req))
+	return nil
+}
+
+func (r *MemoryMaintenanceRepository) Get(ctx context.Context, id int64) (*models.MaintenanceRequest, error) {
+	matches := r.where(func(req models.MaintenanceRequest) bool { return req.ID == id })
+	if len(matches) == 0 {
+		return nil, ErrNotFound
+	}
+	return &matches[0], nil
+}
+
+func (r *MemoryMaintenanceRepository) ListByLease(ctx context.Context, leaseID int64) ([]models.MaintenanceRequest, error) {
+	return r.where(func(req models.MaintenanceRequest) bool { return req.LeaseID == leaseID }), nil
+}
+
+func (r *MemoryMaintenanceRepository) Filter(ctx context.Context, filter MaintenanceFilter) ([]models.MaintenanceRequest, error) {
+	return r.where(func(req models.MaintenanceRequest) bool {
+		switch {
+		case filter.PropertyID != 0 && req.PropertyID != filter.PropertyID:
+			return false
+		case filter.Status != "" && req.Status != filter.Status:
+			return false
+		case filter.Urgency != "" && req.Urgency != filter.Urgency:
+			return false
+		case filter.AssigneeID != "" && req.AssigneeID != filter.AssigneeID:
+			return false
+		}
+		return true
+	}), nil
+}
+
+func (r *MemoryMaintenanceRepository) SetStatus(ctx context.Context, id int64, status models.MaintenanceStatus, actorID string) (*models.MaintenanceRequest, error) {
+	return r.update(id, actorID, models.MaintenanceEventStatus, string(status), func(req *models.MaintenanceRequest) {
+		req.Status = status
+		req.ResolvedAt = nil
+		if status == models.MaintenanceStatusResolved {
+			now := time.Now()
+			req.ResolvedAt = &now
+		}
+	})
+}
+
+func (r *MemoryMaintenanceRepository) Schedule(ctx context.Context, id int64, at *time.Time, actorID string) (*models.MaintenanceRequest, error) {
+	return r.update(id, actorID, models.MaintenanceEventScheduled, scheduleDetail(at), func(req *models.MaintenanceRequest) {
+		req.ScheduledFor = nil
+		if at != nil {
+			t := *at
+			req.ScheduledFor = &t
+		}
+	})
+}
+
+func (r *MemoryMaintenanceRepository) Assign(ctx context.Context, id int64, assigneeID, actorID string) (*models.MaintenanceRequest, error) {
+	return r.update(id, actorID, models.MaintenanceEventAssigned, assigneeID, func(req *models.MaintenanceRequest) {
+		req.AssigneeID = assigneeID
+	})
+}
+
+func (r *MemoryMaintenanceRepository) SetVendor(ctx context.Context, id int64, vendor, actorID string) (*models.MaintenanceRequest, error) {
+	return r.update(id, actorID, models.MaintenanceEventVendor, vendor, func(req *models.MaintenanceRequest) {
+		req.Vendor = vendor
+	})
+}
+
+// update applies change to a request and records it on its timeline
+func (r *MemoryMaintenanceRepository) update(id int64, actorID string, kind models.MaintenanceEventKind, detail string, change func(*models.MaintenanceRequest)) (*models.MaintenanceRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.requests {
+		if r.requests[i].ID == id {
+			change(&r.requests[i])
+			r.requests[i].UpdatedAt = time.Now()
+			r.addEvent(id, actorID, kind, detail)
+			req := cloneMaintenanceRequest(r.requests[i])
+			return &req, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryMaintenanceRepository) AddEvent(ctx context.Context, id int64, kind models.MaintenanceEventKind, text, actorID string) (*models.MaintenanceEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !slices.ContainsFunc(r.requests, func(req models.MaintenanceRequest) bool { return req.ID == id }) {
+		return nil, ErrNotFound
+	}
+	event := r.addEvent(id, actorID, kind, text)
+	return &event, nil
+}
+
+func (r *MemoryMaintenanceRepository) Timeline(ctx context.Context, id int64) ([]models.MaintenanceEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []models.MaintenanceEvent
+	for _, e := range r.events {
+		if e.RequestID == id {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// addEvent appends to the timeline. The caller must hold r.mu.
+func (r *MemoryMaintenanceRepository) addEvent(id int64, actorID string, kind models.MaintenanceEventKind, detail string) models.MaintenanceEvent {
+	event := models.MaintenanceEvent{
+		ID:        r.nextEventID,
+		RequestID: id,
+		ActorID:   actorID,
+		Kind:      kind,
+		Detail:    detail,
+		CreatedAt: time.Now(),
+	}
+	r.nextEventID++
+	r.events = append(r.events, event)
+	return event
+}
+
+// where returns copies of the matching requests, newest first
+func (r *MemoryMaintenanceRepository) where(keep func(models.MaintenanceRequest) bool) []models.MaintenanceRequest {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []models.MaintenanceRequest
+	for i := len(r.requests) - 1; i >= 0; i-- {
+		if keep(r.requests[i]) {
+			matches = append(matches, cloneMaintenanceRequest(r.requests[i]))
+		}
+	}
+	return matches
+}
+
+// cloneMaintenanceRequest copies req so callers can't change what's stored
+func cloneMaintenanceRequest(req models.MaintenanceRequest) models.MaintenanceRequest {
+	req.Photos = slices.Clone(req.Photos)
+	if req.ScheduledFor != nil {
+		t := *req.ScheduledFor
+		req.ScheduledFor = &t
+	}
+	if req.ResolvedAt != nil {
+		t := *req.ResolvedAt
+		req.ResolvedAt = &t
+	}
+	return req
+}
+
+// scheduleDetail is the timeline detail of a visit scheduled for at
+func scheduleDetail(at *time.Time) string {
+	if at == nil {
+		return ""
+	}
+	return at.Format(time.RFC3339)
+}
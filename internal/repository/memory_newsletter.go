@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryNewsletterRepository keeps newsletter subscribers in memory
+type MemoryNewsletterRepository struct {
+	mu          sync.RWMutex
+	nextID      int64
+	subscribers map[string]*models.NewsletterSubscriber
+}
+
+// NewMemoryNewsletterRepository creates an empty NewsletterRepository
+func NewMemoryNewsletterRepository() *MemoryNewsletterRepository {
+	return &MemoryNewsletterRepository{
+		nextID:      1,
+		subscribers: make(map[string]*models.NewsletterSubscriber),
+	}
+}
+
+func (r *MemoryNewsletterRepository) Subscribe(ctx context.Context, email, firstName string) (*models.NewsletterSubscriber, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sub, ok := r.subscribers[email]
+	if !ok {
+		sub = &models.NewsletterSubscriber{
+			ID:           r.nextID,
+			Email:        email,
+			SubscribedAt: time.Now(),
+		}
+		r.nextID++
+		r.subscribers[email] = sub
+	}
+	sub.FirstName = firstName
+	sub.UnsubscribedAt = nil
+	if sub.Status != models.SubscriberStatusActive {
+		sub.Status = models.SubscriberStatusPending
+	}
+
+	copied := *sub
+	return &copied, nil
+}
+
+func (r *MemoryNewsletterRepository) Get(ctx context.Context, email string) (*models.NewsletterSubscriber, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sub, ok := r.subscribers[email]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *sub
+	return &copied, nil
+}
+
+func (r *MemoryNewsletterRepository) Confirm(ctx context.Context, email string) (*models.NewsletterSubscriber, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sub, ok := r.subscribers[email]
+	if !ok || sub.Status != models.SubscriberStatusPending {
+		return nil, ErrNotFound
+	}
+	now := time.Now()
+	sub.Status = models.SubscriberStatusActive
+	sub.ConfirmedAt = &now
+
+	copied := *sub
+	return &copied, nil
+}
+
+func (r *MemoryNewsletterRepository) Unsubscribe(ctx context.Context, email string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sub, ok := r.subscribers[email]
+	if !ok {
+		return ErrNotFound
+	}
+	now := time.Now()
+	sub.Status = models.SubscriberStatusUnsubscribed
+	sub.UnsubscribedAt = &now
+	return nil
+}
+
+func (r *MemoryNewsletterRepository) ListActive(ctx context.Context) ([]models.NewsletterSubscriber, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var active []models.NewsletterSubscriber
+	for _, sub := range r.subscribers {
+		if sub.Status == models.SubscriberStatusActive {
+			active = append(active, *sub)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].SubscribedAt.After(active[j].SubscribedAt)
+	})
+	return active, nil
+}
+
+func (r *MemoryNewsletterRepository) CountActive(ctx context.Context) (int64, error) {
+	active, err := r.ListActive(ctx)
+	return int64(len(active)), err
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryPaymentRepository keeps payments in memory
+type MemoryPaymentRepository struct {
+	mu       sync.RWMutex
+	nextID   int64
+	payments []models.Payment
+}
+
+// NewMemoryPaymentRepository creates an empty PaymentRepository
+func NewMemoryPaymentRepository() *MemoryPaymentRepository {
+	return &MemoryPaymentRepository{nextID: 1}
+}
+
+func (r *MemoryPaymentRepository) Create(ctx context.Context, p *models.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	p.ID = r.nextID
+	p.Status = models.PaymentStatusPending
+	p.RefundedCents = 0
+	p.FailureMessage = ""
+	p.PaidAt = nil
+	p.CreatedAt = now
+	p.UpdatedAt = now
+	r.nextID++
+	r.payments = append(r.payments, *p)
+	return nil
+}
+
+func (r *MemoryPaymentRepository) Get(ctx context.Context, id int64) (*models.Payment, error) {
+	return r.find(func(p models.Payment) bool { return p.ID == id })
+}
+
+func (r *MemoryPaymentRepository) GetByIntent(ctx context.Context, intentID string) (*models.Payment, error) {
+	return r.find(func(p models.Payment) bool { return p.IntentID == intentID })
+}
+
+func (r *MemoryPaymentRepository) ListByApplication(ctx context.Context, applicationID int64) ([]models.Payment, error) {
+	return r.where(func(p models.Payment) bool { return p.ApplicationID != nil && *p.ApplicationID == applicationID }), nil
+}
+
+func (r *MemoryPaymentRepository) ListByLease(ctx context.Context, leaseID int64) ([]models.Payment, error) {
+	return r.where(func(p models.Payment) bool { return p.LeaseID != nil && *p.LeaseID == leaseID }), nil
+}
+
+func (r *MemoryPaymentRepository) SetStatus(ctx context.Context, id int64, status models.PaymentStatus, failureMessage string) (*models.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.payments, func(p models.Payment) bool { return p.ID == id })
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	p := &r.payments[i]
+	if p.Status == models.PaymentStatusPending || p.Status == models.PaymentStatusFailed {
+		now := time.Now()
+		p.Status = status
+		p.FailureMessage = failureMessage
+		if status == models.PaymentStatusSucceeded {
+			p.PaidAt = &now
+		}
+		p.UpdatedAt = now
+	}
+	payment := *p
+	return &payment, nil
+}
+
+func (r *MemoryPaymentRepository) SetRefunded(ctx context.Context, id int64, refundedCents int64) (*models.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.payments, func(p models.Payment) bool { return p.ID == id })
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	p := &r.payments[i]
+	if !p.Paid() {
+		return nil, ErrStatusChanged
+	}
+	p.RefundedCents = max(p.RefundedCents, refundedCents)
+	if p.RefundedCents >= p.AmountCents {
+		p.Status = models.PaymentStatusRefunded
+	}
+	p.UpdatedAt = time.Now()
+	payment := *p
+	return &payment, nil
+}
+
+// where lists the payments keep accepts, newest first
+func (r *MemoryPaymentRepository) where(keep func(models.Payment) bool) []models.Payment {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var payments []models.Payment
+	for _, p := range r.payments {
+		if keep(p) {
+			payments = append(payments, p)
+		}
+	}
+	sort.SliceStable(payments, func(i, j int) bool { return payments[i].CreatedAt.After(payments[j].CreatedAt) })
+	return payments
+}
+
+func (r *MemoryPaymentRepository) find(match func(models.Payment) bool) (*models.Payment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, p := range r.payments {
+		if match(p) {
+			return &p, nil
+		}
+	}
+	return nil, ErrNotFound
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryShowingRepository keeps showing availability and bookings in memory
+type MemoryShowingRepository struct {
+	mu           sync.RWMutex
+	nextWindowID int64
+	nextID       int64
+	windows      []models.ShowingWindow
+	showings     []models.Showing
+}
+
+// NewMemoryShowingRepository creates an empty ShowingRepository
+func NewMemoryShowingRepository() *MemoryShowingRepository {
+	return &MemoryShowingRepository{nextWindowID: 1, nextID: 1}
+}
+
+func (r *MemoryShowingRepository) CreateWindow(ctx context.Context, w *models.ShowingWindow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w.ID = r.nextWindowID
+	w.CreatedAt = time.Now()
+	r.nextWindowID++
+	r.windows = append(r.windows, *w)
+	return nil
+}
+
+func (r *MemoryShowingRepository) Windows(ctx context.Context, propertyID int64, after time.Time) ([]models.ShowingWindow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var windows []models.ShowingWindow
+	for _, w := range r.windows {
+		if (propertyID == 0 || w.PropertyID == propertyID) && w.EndsAt.After(after) {
+			windows = append(windows, w)
+		}
+	}
+	sort.SliceStable(windows, func(i, j int) bool { return windows[i].StartsAt.Before(windows[j].StartsAt) })
+	return windows, nil
+}
+
+func (r *MemoryShowingRepository) DeleteWindow(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.windows = slices.DeleteFunc(r.windows, func(w models.ShowingWindow) bool { return w.ID == id })
+	return nil
+}
+
+func (r *MemoryShowingRepository) Book(ctx context.Context, s *models.Showing) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conflicts(*s) {
+		return ErrSlotTaken
+	}
+	s.ID = r.nextID
+	s.Status = models.ShowingStatusPending
+	s.CreatedAt = time.Now()
+	s.UpdatedAt = s.CreatedAt
+	r.nextID++
+	r.showings = append(r.showings, *s)
+	return nil
+}
+
+func (r *MemoryShowingRepository) Get(ctx context.Context, id int64) (*models.Showing, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, s := range r.showings {
+		if s.ID == id {
+			return &s, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryShowingRepository) Upcoming(ctx context.Context, from time.Time) ([]models.Showing, error) {
+	return r.where(func(s models.Showing) bool { return !s.StartsAt.Before(from) }), nil
+}
+
+func (r *MemoryShowingRepository) Feed(ctx context.Context, propertyID int64, agentID string, since time.Time) ([]models.Showing, error) {
+	return r.where(func(s models.Showing) bool {
+		return (propertyID == 0 || s.PropertyID == propertyID) && (agentID == "" || s.AgentID == agentID) && !s.StartsAt.Before(since)
+	}), nil
+}
+
+func (r *MemoryShowingRepository) Busy(ctx context.Context, propertyID int64, agentIDs []string, since, until time.Time) ([]models.Showing, error) {
+	return r.where(func(s models.Showing) bool {
+		return s.Active() && (s.PropertyID == propertyID || slices.Contains(agentIDs, s.AgentID)) && s.Overlaps(since, until)
+	}), nil
+}
+
+func (r *MemoryShowingRepository) SetStatus(ctx context.Context, id int64, status models.ShowingStatus) (*models.Showing, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.showings {
+		if r.showings[i].ID != id {
+			continue
+		}
+		// Reactivating a cancelled showing must not double-book its slot
+		if status != models.ShowingStatusCancelled && !r.showings[i].Active() && r.conflicts(r.showings[i]) {
+			return nil, ErrSlotTaken
+		}
+		r.showings[i].Status = status
+		r.showings[i].Sequence++
+		r.showings[i].UpdatedAt = time.Now()
+		s := r.showings[i]
+		return &s, nil
+	}
+	return nil, ErrNotFound
+}
+
+// conflicts reports whether s overlaps another active showing of its
+// property or agent, like the showings table's exclusion constraints. The
+// caller must hold r.mu.
+func (r *MemoryShowingRepository) conflicts(s models.Showing) bool {
+	for _, other := range r.showings {
+		if other.ID != s.ID && other.Active() && (other.PropertyID == s.PropertyID || other.AgentID == s.AgentID) && other.Overlaps(s.StartsAt, s.EndsAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// where returns the matching showings, earliest first
+func (r *MemoryShowingRepository) where(keep func(models.Showing) bool) []models.Showing {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []models.Showing
+	for _, s := range r.showings {
+		if keep(s) {
+			matches = append(matches, s)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].StartsAt.Before(matches[j].StartsAt) })
+	return matches
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemorySignatureRepository keeps signature requests and their audit trail
+// in memory, alongside the documents they're for
+type MemorySignatureRepository struct {
+	mu          sync.RWMutex
+	nextID      int64
+	nextEventID int64
+	requests    []models.SignatureRequest
+	events      []models.SignatureEvent
+	documents   *MemoryDocumentRepository
+}
+
+// NewMemorySignatureRepository creates an empty SignatureRepository for
+// the documents in documents
+func NewMemorySignatureRepository(documents *MemoryDocumentRepository) *MemorySignatureRepository {
+	return &MemorySignatureRepository{nextID: 1, nextEventID: 1, documents: documents}
+}
+
+func (r *MemorySignatureRepository) Request(ctx context.Context, documentID int64, requests []models.SignatureRequest, sent models.SignatureEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	doc, err := r.documents.Get(ctx, documentID)
+	if err != nil {
+		return err
+	}
+	if doc.SignedDocumentID != nil || len(r.current(documentID)) > 0 {
+		return ErrStatusChanged
+	}
+	now := time.Now()
+	for i := range requests {
+		requests[i].ID = r.nextID
+		requests[i].DocumentID = documentID
+		requests[i].Status = models.SignatureStatusPending
+		requests[i].CreatedAt = now
+		r.nextID++
+		r.requests = append(r.requests, cloneSignatureRequest(requests[i]))
+
+		id := requests[i].ID
+		e := sent
+		e.DocumentID = documentID
+		e.RequestID = &id
+		e.Kind = models.SignatureEventSent
+		r.logEvent(&e)
+	}
+	return nil
+}
+
+func (r *MemorySignatureRepository) Get(ctx context.Context, id int64) (*models.SignatureRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, req := range r.requests {
+		if req.ID == id {
+			req = cloneSignatureRequest(req)
+			return &req, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemorySignatureRepository) ListByDocument(ctx context.Context, documentID int64) ([]models.SignatureRequest, error) {
+	return r.where(func(req models.SignatureRequest) bool { return req.DocumentID == documentID }), nil
+}
+
+func (r *MemorySignatureRepository) ListByLease(ctx context.Context, leaseID int64) ([]models.SignatureRequest, error) {
+	ids := r.documents.leaseDocumentIDs(leaseID)
+	return r.where(func(req models.SignatureRequest) bool { return slices.Contains(ids, req.DocumentID) }), nil
+}
+
+func (r *MemorySignatureRepository) Sign(ctx context.Context, sig *models.SignatureRequest) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.requests, func(req models.SignatureRequest) bool { return req.ID == sig.ID })
+	if i < 0 {
+		return false, ErrNotFound
+	}
+	req := cloneSignatureRequest(r.requests[i])
+	if req.Status != models.SignatureStatusPending {
+		return false, ErrStatusChanged
+	}
+	now := time.Now()
+	req.Status = models.SignatureStatusSigned
+	req.Method = sig.Method
+	req.TypedName = sig.TypedName
+	req.Drawing = slices.Clone(sig.Drawing)
+	req.IPAddress = sig.IPAddress
+	req.UserAgent = sig.UserAgent
+	req.SignedAt = &now
+	r.requests[i] = req
+	*sig = cloneSignatureRequest(req)
+
+	r.logEvent(&models.SignatureEvent{
+		DocumentID: req.DocumentID,
+		RequestID:  &req.ID,
+		Kind:       models.SignatureEventSigned,
+		ActorID:    req.UserID,
+		IPAddress:  req.IPAddress,
+		UserAgent:  req.UserAgent,
+	})
+	return r.allSigned(req.DocumentID), nil
+}
+
+func (r *MemorySignatureRepository) Void(ctx context.Context, documentID int64, voided models.SignatureEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	doc, err := r.documents.Get(ctx, documentID)
+	if err != nil {
+		return err
+	}
+	if doc.SignedDocumentID != nil || len(r.current(documentID)) == 0 {
+		return ErrStatusChanged
+	}
+	for i := range r.requests {
+		req := &r.requests[i]
+		if req.DocumentID != documentID || req.Status == models.SignatureStatusVoid {
+			continue
+		}
+		req.Status = models.SignatureStatusVoid
+		id := req.ID
+		e := voided
+		e.DocumentID = documentID
+		e.RequestID = &id
+		e.Kind = models.SignatureEventVoided
+		r.logEvent(&e)
+	}
+	return nil
+}
+
+func (r *MemorySignatureRepository) Complete(ctx context.Context, documentID int64, signed *models.Document) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	doc, err := r.documents.Get(ctx, documentID)
+	if err != nil {
+		return err
+	}
+	if doc.SignedDocumentID != nil || !r.allSigned(documentID) {
+		return ErrStatusChanged
+	}
+	// Saving to the memory documents can't fail
+	r.documents.Create(ctx, signed)
+	r.documents.setSigned(documentID, signed.ID)
+	r.logEvent(&models.SignatureEvent{
+		DocumentID: documentID,
+		Kind:       models.SignatureEventCompleted,
+		ActorID:    signed.UploadedBy,
+	})
+	return nil
+}
+
+func (r *MemorySignatureRepository) LogEvent(ctx context.Context, e *models.SignatureEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.logEvent(e)
+	return nil
+}
+
+func (r *MemorySignatureRepository) EventsByDocument(ctx context.Context, documentID int64) ([]models.SignatureEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []models.SignatureEvent
+	for _, e := range r.events {
+		if e.DocumentID == documentID {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (r *MemorySignatureRepository) EventsByLease(ctx context.Context, leaseID int64) ([]models.SignatureEvent, error) {
+	ids := r.documents.leaseDocumentIDs(leaseID)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []models.SignatureEvent
+	for _, e := range r.events {
+		if slices.Contains(ids, e.DocumentID) {
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].ID > events[j].ID })
+	return events, nil
+}
+
+// logEvent records e. The caller holds the write lock.
+func (r *MemorySignatureRepository) logEvent(e *models.SignatureEvent) {
+	e.ID = r.nextEventID
+	e.CreatedAt = time.Now()
+	r.nextEventID++
+	r.events = append(r.events, *e)
+}
+
+// current lists a document's requests that weren't voided. The caller
+// holds the lock.
+func (r *MemorySignatureRepository) current(documentID int64) []models.SignatureRequest {
+	var current []models.SignatureRequest
+	for _, req := range r.requests {
+		if req.DocumentID == documentID && req.Status != models.SignatureStatusVoid {
+			current = append(current, req)
+		}
+	}
+	return current
+}
+
+// allSigned reports whether a document has current requests and all of
+// them are signed. The caller holds the lock.
+func (r *MemorySignatureRepository) allSigned(documentID int64) bool {
+	current := r.current(documentID)
+	return len(current) > 0 && !slices.ContainsFunc(current, func(req models.SignatureRequest) bool {
+		return req.Status != models.SignatureStatusSigned
+	})
+}
+
+func (r *MemorySignatureRepository) where(match func(models.SignatureRequest) bool) []models.SignatureRequest {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var requests []models.SignatureRequest
+	for _, req := range r.requests {
+		if match(req) {
+			requests = append(requests, cloneSignatureRequest(req))
+		}
+	}
+	return requests
+}
+
+func cloneSignatureRequest(req models.SignatureRequest) models.SignatureRequest {
+	req.Drawing = slices.Clone(req.Drawing)
+	if req.SignedAt != nil {
+		t := *req.SignedAt
+		req.SignedAt = &t
+	}
+	return req
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryUserRepository keeps role assignments in memory
+type MemoryUserRepository struct {
+	mu           sync.RWMutex
+	nextID       int64
+	users        map[string]*models.User
+	calendarKeys map[string]string
+}
+
+// NewMemoryUserRepository creates an empty UserRepository
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{
+		nextID:       1,
+		users:        make(map[string]*models.User),
+		calendarKeys: make(map[string]string),
+	}
+}
+
+func (r *MemoryUserRepository) GetByClerkID(ctx context.Context, clerkUserID string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[clerkUserID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *MemoryUserRepository) List(ctx context.Context) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]models.User, 0, len(r.users))
+	for _, user := range r.users {
+		users = append(users, *user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID > users[j].ID })
+	return users, nil
+}
+
+func (r *MemoryUserRepository) SetRole(ctx context.Context, clerkUserID, email string, role models.Role) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	user, ok := r.users[clerkUserID]
+	if !ok {
+		user = &models.User{ID: r.nextID, ClerkUserID: clerkUserID, CreatedAt: now}
+		r.nextID++
+		r.users[clerkUserID] = user
+	}
+	if email != "" {
+		user.Email = email
+	}
+	user.Role = role
+	user.UpdatedAt = now
+
+	copied := *user
+	return &copied, nil
+}
+
+func (r *MemoryUserRepository) Delete(ctx context.Context, clerkUserID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.users, clerkUserID)
+	delete(r.calendarKeys, clerkUserID)
+	return nil
+}
+
+func (r *MemoryUserRepository) CalendarKey(ctx context.Context, clerkUserID string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.calendarKeys[clerkUserID]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return key, nil
+}
+
+func (r *MemoryUserRepository) EnsureCalendarKey(ctx context.Context, clerkUserID, key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.calendarKeys[clerkUserID]; ok {
+		return existing, nil
+	}
+	r.calendarKeys[clerkUserID] = key
+	return key, nil
+}
+
+func (r *MemoryUserRepository) SetCalendarKey(ctx context.Context, clerkUserID, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calendarKeys[clerkUserID] = key
+	return nil
+}
@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// MemoryVendorRepository keeps the vendor directory in memory
+type MemoryVendorRepository struct {
+	mu      sync.RWMutex
+	nextID  int64
+	vendors []models.Vendor
+}
+
+// NewMemoryVendorRepository creates an empty VendorRepository
+func NewMemoryVendorRepository() *MemoryVendorRepository {
+	return &MemoryVendorRepository{nextID: 1}
+}
+
+func (r *MemoryVendorRepository) Create(ctx context.Context, v *models.Vendor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	v.ID = r.nextID
+	v.CreatedAt = now
+	v.UpdatedAt = now
+	r.nextID++
+	r.vendors = append(r.vendors, cloneVendor(*v))
+	return nil
+}
+
+func (r *MemoryVendorRepository) Get(ctx context.Context, id int64) (*models.Vendor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, v := range r.vendors {
+		if v.ID == id {
+			v = cloneVendor(v)
+			return &v, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryVendorRepository) List(ctx context.Context) ([]models.Vendor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	vendors := make([]models.Vendor, len(r.vendors))
+	for i, v := range r.vendors {
+		vendors[i] = cloneVendor(v)
+	}
+	sort.SliceStable(vendors, func(i, j int) bool { return vendors[i].Name < vendors[j].Name })
+	return vendors, nil
+}
+
+func (r *MemoryVendorRepository) Update(ctx context.Context, v *models.Vendor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, stored := range r.vendors {
+		if stored.ID == v.ID {
+			v.CreatedAt = stored.CreatedAt
+			v.UpdatedAt = time.Now()
+			r.vendors[i] = cloneVendor(*v)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func cloneVendor(v models.Vendor) models.Vendor {
+	v.Trades = slices.Clone(v.Trades)
+	return v
+}
+
+// MemoryWorkOrderRepository keeps work orders in memory and posts tenant
+// chargebacks to ledger. It doesn't check that requests or vendors exist.
+type MemoryWorkOrderRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	orders []models.WorkOrder
+	ledger *MemoryLedgerRepository
+}
+
+// NewMemoryWorkOrderRepository creates an empty WorkOrderRepository
+func NewMemoryWorkOrderRepository(ledger *MemoryLedgerRepository) *MemoryWorkOrderRepository {
+	return &MemoryWorkOrderRepository{nextID: 1, ledger: ledger}
+}
+
+func (r *MemoryWorkOrderRepository) Create(ctx context.Context, o *models.WorkOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	*o = models.WorkOrder{
+		ID:            r.nextID,
+		RequestID:     o.RequestID,
+		PropertyID:    o.PropertyID,
+		VendorID:      o.VendorID,
+		Status:        models.WorkOrderStatusOpen,
+		Scope:         o.Scope,
+		ScheduledOn:   o.ScheduledOn,
+		EstimateCents: o.EstimateCents,
+		BillTo:        o.BillTo,
+		CreatedBy:     o.CreatedBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	r.nextID++
+	r.orders = append(r.orders, cloneWorkOrder(*o))
+	return nil
+}
+
+func (r *MemoryWorkOrderRepository) Get(ctx context.Context, id int64) (*models.WorkOrder, error) {
+	matches := r.where(func(o models.WorkOrder) bool { return o.ID == id })
+	if len(matches) == 0 {
+		return nil, ErrNotFound
+	}
+	return &matches[0], nil
+}
+
+func (r *MemoryWorkOrderRepository) ListByRequest(ctx context.Context, requestID int64) ([]models.WorkOrder, error) {
+	orders := r.where(func(o models.WorkOrder) bool { return o.RequestID == requestID })
+	slices.Reverse(orders)
+	return orders, nil
+}
+
+func (r *MemoryWorkOrderRepository) Filter(ctx context.Context, filter WorkOrderFilter) ([]models.WorkOrder, error) {
+	return r.where(func(o models.WorkOrder) bool {
+		switch {
+		case filter.PropertyID != 0 && o.PropertyID != filter.PropertyID:
+			return false
+		case filter.VendorID != 0 && o.VendorID != filter.VendorID:
+			return false
+		case filter.Status != "" && o.Status != filter.Status:
+			return false
+		case filter.BillTo != "" && o.BillTo != filter.BillTo:
+			return false
+		}
+		return true
+	}), nil
+}
+
+func (r *MemoryWorkOrderRepository) Update(ctx context.Context, o *models.WorkOrder) error {
+	updated, err := r.update(o.ID, func(stored *models.WorkOrder) bool {
+		if stored.Status != models.WorkOrderStatusOpen {
+			return false
+		}
+		stored.Scope = o.Scope
+		stored.ScheduledOn = o.ScheduledOn
+		stored.EstimateCents = o.EstimateCents
+		stored.BillTo = o.BillTo
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	*o = *updated
+	return nil
+}
+
+func (r *MemoryWorkOrderRepository) Complete(ctx context.Context, id int64, notes string) (*models.WorkOrder, error) {
+	return r.update(id, func(o *models.WorkOrder) bool {
+		if o.Status != models.WorkOrderStatusOpen {
+			return false
+		}
+		now := time.Now()
+		o.Status = models.WorkOrderStatusCompleted
+		o.CompletionNotes = notes
+		o.CompletedAt = &now
+		return true
+	})
+}
+
+func (r *MemoryWorkOrderRepository) Cancel(ctx context.Context, id int64) (*models.WorkOrder, error) {
+	return r.update(id, func(o *models.WorkOrder) bool {
+		if o.Status != models.WorkOrderStatusOpen {
+			return false
+		}
+		o.Status = models.WorkOrderStatusCancelled
+		return true
+	})
+}
+
+func (r *MemoryWorkOrderRepository) RecordInvoice(ctx context.Context, o *models.WorkOrder) error {
+	updated, err := r.update(o.ID, func(stored *models.WorkOrder) bool {
+		if stored.Status != models.WorkOrderStatusCompleted || stored.LedgerEntryID != nil {
+			return false
+		}
+		stored.InvoiceNumber = o.InvoiceNumber
+		stored.InvoiceCents = o.InvoiceCents
+		stored.InvoicedOn = o.InvoicedOn
+		stored.BillTo = o.BillTo
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	*o = *updated
+	return nil
+}
+
+func (r *MemoryWorkOrderRepository) ChargeTenant(ctx context.Context, id int64, e *models.LedgerEntry) (*models.WorkOrder, error) {
+	return r.update(id, func(o *models.WorkOrder) bool {
+		if !o.Chargeable() {
+			return false
+		}
+		// Posting to the memory ledger can't fail
+		r.ledger.Create(ctx, e)
+		o.LedgerEntryID = &e.ID
+		return true
+	})
+}
+
+// update applies change to an order under the write lock. change reports
+// false, leaving the order as it was, if the order's status doesn't allow
+// it.
+func (r *MemoryWorkOrderRepository) update(id int64, change func(*models.WorkOrder) bool) (*models.WorkOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.orders {
+		if r.orders[i].ID != id {
+			continue
+		}
+		o := cloneWorkOrder(r.orders[i])
+		if !change(&o) {
+			return nil, ErrStatusChanged
+		}
+		o.UpdatedAt = time.Now()
+		r.orders[i] = cloneWorkOrder(o)
+		return &o, nil
+	}
+	return nil, ErrNotFound
+}
+
+// where returns copies of the matching orders, newest first
+func (r *MemoryWorkOrderRepository) where(keep func(models.WorkOrder) bool) []models.WorkOrder {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []models.WorkOrder
+	for i := len(r.orders) - 1; i >= 0; i-- {
+		if keep(r.orders[i]) {
+			matches = append(matches, cloneWorkOrder(r.orders[i]))
+		}
+	}
+	return matches
+}
+
+// cloneWorkOrder copies o so callers can't change what's stored
+func cloneWorkOrder(o models.WorkOrder) models.WorkOrder {
+	if o.ScheduledOn != nil {
+		t := *o.ScheduledOn
+		o.ScheduledOn = &t
+	}
+	if o.CompletedAt != nil {
+		t := *o.CompletedAt
+		o.CompletedAt = &t
+	}
+	if o.InvoicedOn != nil {
+		t := *o.InvoicedOn
+		o.InvoicedOn = &t
+	}
+	if o.LedgerEntryID != nil {
+		id := *o.LedgerEntryID
+		o.LedgerEntryID = &id
+	}
+	return o
+}
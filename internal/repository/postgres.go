@@ -0,0 +1,487 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// NewPostgresStore creates a Store whose repositories all query db
+func NewPostgresStore(db *database.DB) *Store {
+	return &Store{
+		Properties:   NewPostgresPropertyRepository(db),
+		Contacts:     NewPostgresContactRepository(db),
+		Newsletter:   NewPostgresNewsletterRepository(db),
+		Users:        NewPostgresUserRepository(db),
+		Showings:     NewPostgresShowingRepository(db),
+		Applications: NewPostgresApplicationRepository(db),
+		Payments:     NewPostgresPaymentRepository(db),
+		Leases:       NewPostgresLeaseRepository(db),
+		Ledger:       NewPostgresLedgerRepository(db),
+		LateFees:     NewPostgresLateFeeRepository(db),
+		Autopay:      NewPostgresAutopayRepository(db),
+		Maintenance:  NewPostgresMaintenanceRepository(db),
+		Vendors:      NewPostgresVendorRepository(db),
+		WorkOrders:   NewPostgresWorkOrderRepository(db),
+		Documents:    NewPostgresDocumentRepository(db),
+		Signatures:   NewPostgresSignatureRepository(db),
+		Jobs:         NewPostgresJobRepository(db),
+		db:           db,
+	}
+}
+
+// PostgresPropertyRepository serves properties from the properties and
+// property_images tables
+type PostgresPropertyRepository struct {
+	db *database.DB
+	q  *database.Queries
+}
+
+// NewPostgresPropertyRepository creates a PropertyRepository backed by db
+func NewPostgresPropertyRepository(db *database.DB) *PostgresPropertyRepository {
+	return &PostgresPropertyRepository{db: db, q: database.New(db.Pool)}
+}
+
+func (r *PostgresPropertyRepository) List(ctx context.Context) ([]models.Property, error) {
+	rows, err := r.q.ListProperties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.withImages(ctx, rows)
+}
+
+func (r *PostgresPropertyRepository) ListAvailable(ctx context.Context) ([]models.Property, error) {
+	rows, err := r.q.ListAvailableProperties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.withImages(ctx, rows)
+}
+
+func (r *PostgresPropertyRepository) Filter(ctx context.Context, filter PropertyFilter) ([]models.Property, error) {
+	rows, err := r.q.FilterProperties(ctx, database.FilterPropertiesParams{
+		TypeFilter:  filter.Type,
+		MinPrice:    int32(filter.MinPrice),
+		MaxPrice:    int32(filter.MaxPrice),
+		MinBedrooms: int32(filter.MinBedrooms),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.withImages(ctx, rows)
+}
+
+func (r *PostgresPropertyRepository) Featured(ctx context.Context, limit int) ([]models.Property, error) {
+	rows, err := r.q.ListFeaturedProperties(ctx, int32(limit))
+	if err != nil {
+		return nil, err
+	}
+	return r.withImages(ctx, rows)
+}
+
+func (r *PostgresPropertyRepository) GetBySlug(ctx context.Context, slug string) (*models.Property, error) {
+	row, err := r.q.GetPropertyBySlug(ctx, slug)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return r.single(ctx, row)
+}
+
+func (r *PostgresPropertyRepository) GetByID(ctx context.Context, id int64) (*models.Property, error) {
+	row, err := r.q.GetPropertyByID(ctx, int32(id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return r.single(ctx, row)
+}
+
+func (r *PostgresPropertyRepository) Images(ctx context.Context, propertyID int64) ([]models.PropertyImage, error) {
+	rows, err := r.q.GetImagesByPropertyID(ctx, int32(propertyID))
+	if err != nil {
+		return nil, err
+	}
+	return imagesFromRows(rows), nil
+}
+
+func (r *PostgresPropertyRepository) ImagesByRoom(ctx context.Context, propertyID int64, room models.RoomType) ([]models.PropertyImage, error) {
+	rows, err := r.q.GetImagesByPropertyIDAndRoom(ctx, database.GetImagesByPropertyIDAndRoomParams{
+		PropertyID: int32(propertyID),
+		Room:       database.RoomType(room),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return imagesFromRows(rows), nil
+}
+
+func (r *PostgresPropertyRepository) Create(ctx context.Context, p *models.Property) error {
+	row, err := r.q.CreateProperty(ctx, createPropertyParams(*p))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrSlugTaken
+		}
+		return err
+	}
+	p.ID = int64(row.ID)
+	p.CreatedAt = row.CreatedAt.Time
+	p.UpdatedAt = row.UpdatedAt.Time
+	return nil
+}
+
+func (r *PostgresPropertyRepository) Update(ctx context.Context, p *models.Property) error {
+	row, err := r.q.UpdateProperty(ctx, updatePropertyParams(int32(p.ID), createPropertyParams(*p)))
+	if err != nil {
+		return notFound(err)
+	}
+	p.UpdatedAt = row.UpdatedAt.Time
+	return nil
+}
+
+func (r *PostgresPropertyRepository) Delete(ctx context.Context, id int64) error {
+	err := r.q.DeleteProperty(ctx, int32(id))
+	if isForeignKeyViolation(err) {
+		return ErrInUse
+	}
+	return err
+}
+
+func (r *PostgresPropertyRepository) AddImage(ctx context.Context, img *models.PropertyImage) error {
+	row, err := r.q.CreateImage(ctx, database.CreateImageParams{
+		PropertyID:   int32(img.PropertyID),
+		Url:          img.URL,
+		Caption:      img.Caption,
+		Room:         database.RoomType(img.Room),
+		DisplayOrder: int32(img.DisplayOrder),
+		ThumbnailUrl: textOrNull(img.ThumbnailURL),
+		CardUrl:      textOrNull(img.CardURL),
+		StorageKey:   textOrNull(img.StorageKey),
+	})
+	if err != nil {
+		return err
+	}
+	img.ID = int64(row.ID)
+	img.CreatedAt = row.CreatedAt.Time
+	return nil
+}
+
+func (r *PostgresPropertyRepository) UpdateImages(ctx context.Context, propertyID int64, images []models.PropertyImage) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking the property holds off new photos, whose foreign key check
+	// waits on it, and locking its photos holds off edits and deletes, so
+	// the set checked here is the set saved
+	q := database.New(tx)
+	if _, err := q.LockProperty(ctx, int32(propertyID)); err != nil {
+		return notFound(err)
+	}
+	rows, err := q.LockImagesByPropertyID(ctx, int32(propertyID))
+	if err != nil {
+		return err
+	}
+	if len(images) != len(rows) {
+		return ErrNotFound
+	}
+	existing := make(map[int64]database.PropertyImage, len(rows))
+	for _, row := range rows {
+		existing[int64(row.ID)] = row
+	}
+
+	for _, img := range images {
+		row, ok := existing[img.ID]
+		if !ok {
+			return ErrNotFound
+		}
+		delete(existing, img.ID)
+		_, err := q.UpdateImage(ctx, database.UpdateImageParams{
+			ID:           row.ID,
+			Url:          row.Url,
+			Caption:      img.Caption,
+			Room:         database.RoomType(img.Room),
+			DisplayOrder: int32(img.DisplayOrder),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresPropertyRepository) single(ctx context.Context, row database.Property) (*models.Property, error) {
+	properties, err := r.withImages(ctx, []database.Property{row})
+	if err != nil {
+		return nil, err
+	}
+	return &properties[0], nil
+}
+
+// withImages converts rows to models and loads all of their images in one query
+func (r *PostgresPropertyRepository) withImages(ctx context.Context, rows []database.Property) ([]models.Property, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int32, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	images, err := r.q.GetImagesByPropertyIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byProperty := make(map[int64][]models.PropertyImage)
+	for _, img := range images {
+		byProperty[int64(img.PropertyID)] = append(byProperty[int64(img.PropertyID)], imageFromRow(img))
+	}
+
+	properties := make([]models.Property, len(rows))
+	for i, row := range rows {
+		properties[i] = propertyFromRow(row)
+		properties[i].Images = byProperty[properties[i].ID]
+	}
+	return properties, nil
+}
+
+func propertyFromRow(row database.Property) models.Property {
+	return models.Property{
+		ID:             int64(row.ID),
+		Slug:           row.Slug,
+		Title:          row.Title,
+		Type:           models.PropertyType(row.Type),
+		Address:        row.Address,
+		City:           row.City,
+		State:          row.State,
+		ZipCode:        row.ZipCode,
+		Price:          int(row.Price),
+		Deposit:        int(row.Deposit),
+		ApplicationFee: int(row.ApplicationFee),
+		Bedrooms:       int(row.Bedrooms),
+		Bathrooms:      numericToFloat(row.Bathrooms),
+		SquareFeet:     int(row.SquareFeet),
+		Description:    row.Description,
+		Features:       row.Features,
+		Available:      row.Available,
+		AvailableDate:  dateToTime(row.AvailableDate),
+		PetFriendly:    row.PetFriendly,
+		PetDeposit:     int4ToInt(row.PetDeposit),
+		PetRent:        int4ToInt(row.PetRent),
+		Parking:        row.Parking.String,
+		Laundry:        row.Laundry.String,
+		YearBuilt:      int4ToInt(row.YearBuilt),
+		Utilities:      row.Utilities,
+		LeaseTerms:     row.LeaseTerms,
+		Featured:       row.Featured,
+		CreatedAt:      row.CreatedAt.Time,
+		UpdatedAt:      row.UpdatedAt.Time,
+	}
+}
+
+func createPropertyParams(p models.Property) database.CreatePropertyParams {
+	return database.CreatePropertyParams{
+		Slug:           p.Slug,
+		Title:          p.Title,
+		Type:           database.PropertyType(p.Type),
+		Address:        p.Address,
+		City:           p.City,
+		State:          p.State,
+		ZipCode:        p.ZipCode,
+		Price:          int32(p.Price),
+		Deposit:        int32(p.Deposit),
+		ApplicationFee: int32(p.ApplicationFee),
+		Bedrooms:       int32(p.Bedrooms),
+		Bathrooms:      floatToNumeric(p.Bathrooms),
+		SquareFeet:     int32(p.SquareFeet),
+		Description:    p.Description,
+		Features:       nonNil(p.Features),
+		Available:      p.Available,
+		AvailableDate:  timeToDate(p.AvailableDate),
+		PetFriendly:    p.PetFriendly,
+		PetDeposit:     intToInt4(p.PetDeposit),
+		PetRent:        intToInt4(p.PetRent),
+		Parking:        textOrNull(p.Parking),
+		Laundry:        textOrNull(p.Laundry),
+		YearBuilt:      intToInt4(p.YearBuilt),
+		Utilities:      nonNil(p.Utilities),
+		LeaseTerms:     nonNil(p.LeaseTerms),
+		Featured:       p.Featured,
+	}
+}
+
+func updatePropertyParams(id int32, c database.CreatePropertyParams) database.UpdatePropertyParams {
+	return database.UpdatePropertyParams{
+		ID:             id,
+		Title:          c.Title,
+		Type:           c.Type,
+		Address:        c.Address,
+		City:           c.City,
+		State:          c.State,
+		ZipCode:        c.ZipCode,
+		Price:          c.Price,
+		Deposit:        c.Deposit,
+		ApplicationFee: c.ApplicationFee,
+		Bedrooms:       c.Bedrooms,
+		Bathrooms:      c.Bathrooms,
+		SquareFeet:     c.SquareFeet,
+		Description:    c.Description,
+		Features:       c.Features,
+		Available:      c.Available,
+		AvailableDate:  c.AvailableDate,
+		PetFriendly:    c.PetFriendly,
+		PetDeposit:     c.PetDeposit,
+		PetRent:        c.PetRent,
+		Parking:        c.Parking,
+		Laundry:        c.Laundry,
+		YearBuilt:      c.YearBuilt,
+		Utilities:      c.Utilities,
+		LeaseTerms:     c.LeaseTerms,
+		Featured:       c.Featured,
+	}
+}
+
+func imageFromRow(row database.PropertyImage) models.PropertyImage {
+	return models.PropertyImage{
+		ID:           int64(row.ID),
+		PropertyID:   int64(row.PropertyID),
+		URL:          row.Url,
+		Caption:      row.Caption,
+		Room:         models.RoomType(row.Room),
+		DisplayOrder: int(row.DisplayOrder),
+		CreatedAt:    row.CreatedAt.Time,
+		ThumbnailURL: row.ThumbnailUrl.String,
+		CardURL:      row.CardUrl.String,
+		StorageKey:   row.StorageKey.String,
+	}
+}
+
+func imagesFromRows(rows []database.PropertyImage) []models.PropertyImage {
+	images := make([]models.PropertyImage, len(rows))
+	for i, row := range rows {
+		images[i] = imageFromRow(row)
+	}
+	return images
+}
+
+// notFound maps pgx's no-rows error onto ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// isUniqueViolation reports whether err is a Postgres unique_violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// isExclusionViolation reports whether err is a Postgres exclusion_violation
+func isExclusionViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23P01"
+}
+
+// isForeignKeyViolation reports whether err is a Postgres
+// foreign_key_violation
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+func numericToFloat(n pgtype.Numeric) float64 {
+	f, err := n.Float64Value()
+	if err != nil || !f.Valid {
+		return 0
+	}
+	return f.Float64
+}
+
+func dateToTime(d pgtype.Date) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+	t := d.Time
+	return &t
+}
+
+func timeToDate(t *time.Time) pgtype.Date {
+	if t == nil {
+		return pgtype.Date{}
+	}
+	return pgtype.Date{Time: *t, Valid: true}
+}
+
+func int64ToInt4(v *int64) pgtype.Int4 {
+	if v == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: int32(*v), Valid: true}
+}
+
+func int4ToInt64(v pgtype.Int4) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	n := int64(v.Int32)
+	return &n
+}
+
+func timestampToTime(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
+
+func timeToTimestamp(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: !t.IsZero()}
+}
+
+func textOrNull(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func int4ToInt(v pgtype.Int4) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int32)
+	return &n
+}
+
+func intToInt4(v *int) pgtype.Int4 {
+	if v == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: int32(*v), Valid: true}
+}
+
+func floatToNumeric(f float64) pgtype.Numeric {
+	var n pgtype.Numeric
+	if err := n.Scan(strconv.FormatFloat(f, 'f', -1, 64)); err != nil {
+		return pgtype.Numeric{}
+	}
+	return n
+}
+
+// nonNil keeps NOT NULL array columns from being written as NULL
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
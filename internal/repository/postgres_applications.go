@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresApplicationRepository stores applications in rental_applications,
+// with their answers as JSON, and status history in application_events
+type PostgresApplicationRepository struct {
+	db *database.DB
+	q  *database.Queries
+}
+
+// NewPostgresApplicationRepository creates an ApplicationRepository backed by db
+func NewPostgresApplicationRepository(db *database.DB) *PostgresApplicationRepository {
+	return &PostgresApplicationRepository{db: db, q: database.New(db.Pool)}
+}
+
+func (r *PostgresApplicationRepository) StartDraft(ctx context.Context, propertyID int64, applicantID string, data models.ApplicationData) (*models.Application, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.StartApplicationDraft(ctx, database.StartApplicationDraftParams{
+		PropertyID:  int32(propertyID),
+		ApplicantID: applicantID,
+		Data:        raw,
+	})
+	if isForeignKeyViolation(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return applicationFromRow(row)
+}
+
+func (r *PostgresApplicationRepository) Get(ctx context.Context, id int64) (*models.Application, error) {
+	row, err := r.q.GetApplication(ctx, int32(id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return applicationFromRow(row)
+}
+
+func (r *PostgresApplicationRepository) ListByApplicant(ctx context.Context, applicantID string) ([]models.Application, error) {
+	rows, err := r.q.ListApplicationsByApplicant(ctx, applicantID)
+	if err != nil {
+		return nil, err
+	}
+	return applicationsFromRows(rows)
+}
+
+func (r *PostgresApplicationRepository) Filter(ctx context.Context, filter ApplicationFilter) ([]models.Application, error) {
+	rows, err := r.q.FilterApplications(ctx, database.FilterApplicationsParams{
+		PropertyFilter: int32(filter.PropertyID),
+		StatusFilter:   string(filter.Status),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applicationsFromRows(rows)
+}
+
+func (r *PostgresApplicationRepository) SaveDraft(ctx context.Context, id int64, data models.ApplicationData) (*models.Application, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.UpdateApplicationData(ctx, database.UpdateApplicationDataParams{ID: int32(id), Data: raw})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, r.missing(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return applicationFromRow(row)
+}
+
+func (r *PostgresApplicationRepository) SetStatus(ctx context.Context, id int64, from, to models.ApplicationStatus, actorID, note string) (*models.Application, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := database.New(tx)
+	row, err := q.UpdateApplicationStatus(ctx, database.UpdateApplicationStatusParams{
+		ID:         int32(id),
+		FromStatus: database.ApplicationStatus(from),
+		Status:     database.ApplicationStatus(to),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, r.missing(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, err = q.CreateApplicationEvent(ctx, database.CreateApplicationEventParams{
+		ApplicationID: int32(id),
+		ActorID:       actorID,
+		Status:        database.ApplicationStatus(to),
+		Note:          note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return applicationFromRow(row)
+}
+
+func (r *PostgresApplicationRepository) Timeline(ctx context.Context, id int64) ([]models.ApplicationEvent, error) {
+	rows, err := r.q.ListApplicationEvents(ctx, int32(id))
+	if err != nil {
+		return nil, err
+	}
+	events := make([]models.ApplicationEvent, len(rows))
+	for i, row := range rows {
+		events[i] = models.ApplicationEvent{
+			ID:            int64(row.ID),
+			ApplicationID: int64(row.ApplicationID),
+			ActorID:       row.ActorID,
+			Status:        models.ApplicationStatus(row.Status),
+			Note:          row.Note,
+			CreatedAt:     row.CreatedAt.Time,
+		}
+	}
+	return events, nil
+}
+
+// missing explains why a conditional update of application id matched no
+// row: either it doesn't exist or its status moved on
+func (r *PostgresApplicationRepository) missing(ctx context.Context, id int64) error {
+	if _, err := r.q.GetApplication(ctx, int32(id)); err != nil {
+		return notFound(err)
+	}
+	return ErrStatusChanged
+}
+
+func applicationFromRow(row database.RentalApplication) (*models.Application, error) {
+	app := models.Application{
+		ID:          int64(row.ID),
+		PropertyID:  int64(row.PropertyID),
+		ApplicantID: row.ApplicantID,
+		Status:      models.ApplicationStatus(row.Status),
+		SubmittedAt: timestampToTime(row.SubmittedAt),
+		DecidedAt:   timestampToTime(row.DecidedAt),
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+	if err := json.Unmarshal(row.Data, &app.Data); err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+func applicationsFromRows(rows []database.RentalApplication) ([]models.Application, error) {
+	apps := make([]models.Application, len(rows))
+	for i, row := range rows {
+		app, err := applicationFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		apps[i] = *app
+	}
+	return apps, nil
+}
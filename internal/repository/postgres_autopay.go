@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresAutopayRepository stores autopay enrollments in the
+// autopay_enrollments table
+type PostgresAutopayRepository struct {
+	q *database.Queries
+}
+
+// NewPostgresAutopayRepository creates an AutopayRepository backed by db
+func NewPostgresAutopayRepository(db *database.DB) *PostgresAutopayRepository {
+	return &PostgresAutopayRepository{q: database.New(db.Pool)}
+}
+
+func (r *PostgresAutopayRepository) Enroll(ctx context.Context, a *models.Autopay) error {
+	row, err := r.q.UpsertAutopay(ctx, database.UpsertAutopayParams{
+		LeaseID:     int32(a.LeaseID),
+		PayerID:     a.PayerID,
+		PayerEmail:  a.PayerEmail,
+		Gateway:     a.Gateway,
+		CustomerID:  a.CustomerID,
+		MethodID:    a.MethodID,
+		MethodLabel: a.MethodLabel,
+		ChargeDay:   int32(a.ChargeDay),
+		Period:      timeToDate(a.Period),
+	})
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	*a = autopayFromRow(row)
+	return nil
+}
+
+func (r *PostgresAutopayRepository) GetByLease(ctx context.Context, leaseID int64) (*models.Autopay, error) {
+	row, err := r.q.GetAutopayByLease(ctx, int32(leaseID))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	a := autopayFromRow(row)
+	return &a, nil
+}
+
+func (r *PostgresAutopayRepository) List(ctx context.Context) ([]models.Autopay, error) {
+	rows, err := r.q.ListAutopay(ctx)
+	if err != nil {
+		return nil, err
+	}
+	enrollments := make([]models.Autopay, len(rows))
+	for i, row := range rows {
+		enrollments[i] = autopayFromRow(row)
+	}
+	return enrollments, nil
+}
+
+func (r *PostgresAutopayRepository) SaveAttempt(ctx context.Context, a *models.Autopay) error {
+	row, err := r.q.UpdateAutopayAttempt(ctx, database.UpdateAutopayAttemptParams{
+		ID:        int32(a.ID),
+		Period:    timeToDate(a.Period),
+		Attempts:  int32(a.Attempts),
+		RetryOn:   timeToDate(a.RetryOn),
+		LastError: a.LastError,
+	})
+	if err != nil {
+		return notFound(err)
+	}
+	*a = autopayFromRow(row)
+	return nil
+}
+
+func (r *PostgresAutopayRepository) Cancel(ctx context.Context, leaseID int64) error {
+	n, err := r.q.DeleteAutopay(ctx, int32(leaseID))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func autopayFromRow(row database.AutopayEnrollment) models.Autopay {
+	return models.Autopay{
+		ID:          int64(row.ID),
+		LeaseID:     int64(row.LeaseID),
+		PayerID:     row.PayerID,
+		PayerEmail:  row.PayerEmail,
+		Gateway:     row.Gateway,
+		CustomerID:  row.CustomerID,
+		MethodID:    row.MethodID,
+		MethodLabel: row.MethodLabel,
+		ChargeDay:   int(row.ChargeDay),
+		Period:      dateToTime(row.Period),
+		Attempts:    int(row.Attempts),
+		RetryOn:     dateToTime(row.RetryOn),
+		LastError:   row.LastError,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+}
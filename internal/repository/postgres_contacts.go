@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresContactRepository stores submissions in contact_submissions
+type PostgresContactRepository struct {
+	db *database.DB
+	q  *database.Queries
+}
+
+// NewPostgresContactRepository creates a ContactRepository backed by db
+func NewPostgresContactRepository(db *database.DB) *PostgresContactRepository {
+	return &PostgresContactRepository{db: db, q: database.New(db.Pool)}
+}
+
+func (r *PostgresContactRepository) Create(ctx context.Context, sub *models.ContactSubmission) error {
+	row, err := r.q.CreateContactSubmission(ctx, database.CreateContactSubmissionParams{
+		Name:          sub.Name,
+		Email:         sub.Email,
+		Phone:         sub.Phone,
+		PropertyID:    int64ToInt4(sub.PropertyID),
+		InquiryType:   database.InquiryType(sub.InquiryType),
+		PreferredDate: timeToDate(sub.PreferredDate),
+		PreferredTime: textOrNull(sub.PreferredTime),
+		Message:       sub.Message,
+	})
+	if err != nil {
+		return err
+	}
+	*sub = contactFromRow(row)
+	return nil
+}
+
+func (r *PostgresContactRepository) Get(ctx context.Context, id int64) (*models.ContactSubmission, error) {
+	row, err := r.q.GetContactSubmission(ctx, int32(id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	sub := contactFromRow(row)
+	return &sub, nil
+}
+
+func (r *PostgresContactRepository) List(ctx context.Context) ([]models.ContactSubmission, error) {
+	rows, err := r.q.ListContactSubmissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return contactsFromRows(rows), nil
+}
+
+func (r *PostgresContactRepository) ListByProperty(ctx context.Context, propertyID int64) ([]models.ContactSubmission, error) {
+	rows, err := r.q.ListContactSubmissionsByProperty(ctx, int64ToInt4(&propertyID))
+	if err != nil {
+		return nil, err
+	}
+	return contactsFromRows(rows), nil
+}
+
+func (r *PostgresContactRepository) ListByEmail(ctx context.Context, email string) ([]models.ContactSubmission, error) {
+	rows, err := r.q.ListContactSubmissionsByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return contactsFromRows(rows), nil
+}
+
+func (r *PostgresContactRepository) Delete(ctx context.Context, id int64) error {
+	return r.q.DeleteContactSubmission(ctx, int32(id))
+}
+
+func (r *PostgresContactRepository) Filter(ctx context.Context, filter InquiryFilter) ([]models.ContactSubmission, error) {
+	rows, err := r.q.FilterContactSubmissions(ctx, database.FilterContactSubmissionsParams{
+		PropertyFilter: int32(filter.PropertyID),
+		TypeFilter:     string(filter.Type),
+		StatusFilter:   string(filter.Status),
+		AssigneeFilter: filter.AssigneeID,
+		ReceivedFrom:   timeToTimestamp(filter.ReceivedFrom),
+		ReceivedBefore: timeToTimestamp(filter.ReceivedBefore),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return contactsFromRows(rows), nil
+}
+
+func (r *PostgresContactRepository) SetStatus(ctx context.Context, id int64, status models.InquiryStatus, actorID string) (*models.ContactSubmission, error) {
+	return r.update(ctx, id, actorID, models.InquiryEventStatus, string(status), func(q *database.Queries) (database.ContactSubmission, error) {
+		return q.UpdateContactSubmissionStatus(ctx, database.UpdateContactSubmissionStatusParams{
+			ID:     int32(id),
+			Status: database.InquiryStatus(status),
+		})
+	})
+}
+
+func (r *PostgresContactRepository) Assign(ctx context.Context, id int64, assigneeID, actorID string) (*models.ContactSubmission, error) {
+	return r.update(ctx, id, actorID, models.InquiryEventAssigned, assigneeID, func(q *database.Queries) (database.ContactSubmission, error) {
+		return q.UpdateContactSubmissionAssignee(ctx, database.UpdateContactSubmissionAssigneeParams{
+			ID:         int32(id),
+			AssigneeID: textOrNull(assigneeID),
+		})
+	})
+}
+
+// update runs change and records it on the submission's timeline in one
+// transaction
+func (r *PostgresContactRepository) update(ctx context.Context, id int64, actorID string, kind models.InquiryEventKind, detail string, change func(*database.Queries) (database.ContactSubmission, error)) (*models.ContactSubmission, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := database.New(tx)
+	row, err := change(q)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	_, err = q.CreateInquiryEvent(ctx, database.CreateInquiryEventParams{
+		SubmissionID: int32(id),
+		ActorID:      actorID,
+		Kind:         database.InquiryEventKind(kind),
+		Detail:       detail,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	sub := contactFromRow(row)
+	return &sub, nil
+}
+
+func (r *PostgresContactRepository) AddNote(ctx context.Context, id int64, note, actorID string) (*models.InquiryEvent, error) {
+	row, err := r.q.CreateInquiryEvent(ctx, database.CreateInquiryEventParams{
+		SubmissionID: int32(id),
+		ActorID:      actorID,
+		Kind:         database.InquiryEventKindNote,
+		Detail:       note,
+	})
+	if isForeignKeyViolation(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	event := inquiryEventFromRow(row)
+	return &event, nil
+}
+
+func (r *PostgresContactRepository) Timeline(ctx context.Context, id int64) ([]models.InquiryEvent, error) {
+	rows, err := r.q.ListInquiryEvents(ctx, int32(id))
+	if err != nil {
+		return nil, err
+	}
+	events := make([]models.InquiryEvent, len(rows))
+	for i, row := range rows {
+		events[i] = inquiryEventFromRow(row)
+	}
+	return events, nil
+}
+
+func contactFromRow(row database.ContactSubmission) models.ContactSubmission {
+	return models.ContactSubmission{
+		ID:            int64(row.ID),
+		Name:          row.Name,
+		Email:         row.Email,
+		Phone:         row.Phone,
+		PropertyID:    int4ToInt64(row.PropertyID),
+		InquiryType:   models.InquiryType(row.InquiryType),
+		PreferredDate: dateToTime(row.PreferredDate),
+		PreferredTime: row.PreferredTime.String,
+		Message:       row.Message,
+		CreatedAt:     row.CreatedAt.Time,
+		Status:        models.InquiryStatus(row.Status),
+		AssigneeID:    row.AssigneeID.String,
+		UpdatedAt:     row.UpdatedAt.Time,
+	}
+}
+
+func contactsFromRows(rows []database.ContactSubmission) []models.ContactSubmission {
+	subs := make([]models.ContactSubmission, len(rows))
+	for i, row := range rows {
+		subs[i] = contactFromRow(row)
+	}
+	return subs
+}
+
+func inquiryEventFromRow(row database.InquiryEvent) models.InquiryEvent {
+	return models.InquiryEvent{
+		ID:           int64(row.ID),
+		SubmissionID: int64(row.SubmissionID),
+		ActorID:      row.ActorID,
+		Kind:         models.InquiryEventKind(row.Kind),
+		Detail:       row.Detail,
+		CreatedAt:    row.CreatedAt.Time,
+	}
+}
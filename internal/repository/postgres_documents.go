@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresDocumentRepository stores lease documents in the documents table
+// and their access log in document_accesses
+type PostgresDocumentRepository struct {
+	q *database.Queries
+}
+
+// NewPostgresDocumentRepository creates a DocumentRepository backed by db
+func NewPostgresDocumentRepository(db *database.DB) *PostgresDocumentRepository {
+	return &PostgresDocumentRepository{q: database.New(db.Pool)}
+}
+
+func (r *PostgresDocumentRepository) Create(ctx context.Context, d *models.Document) error {
+	row, err := r.q.CreateDocument(ctx, documentParams(d))
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	*d = documentFromRow(row)
+	return nil
+}
+
+func (r *PostgresDocumentRepository) Get(ctx context.Context, id int64) (*models.Document, error) {
+	row, err := r.q.GetDocument(ctx, int32(id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	d := documentFromRow(row)
+	return &d, nil
+}
+
+func (r *PostgresDocumentRepository) ListByLease(ctx context.Context, leaseID int64) ([]models.Document, error) {
+	rows, err := r.q.ListDocumentsByLease(ctx, int32(leaseID))
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]models.Document, len(rows))
+	for i, row := range rows {
+		docs[i] = documentFromRow(row)
+	}
+	return docs, nil
+}
+
+func (r *PostgresDocumentRepository) LogAccess(ctx context.Context, a *models.DocumentAccess) error {
+	row, err := r.q.CreateDocumentAccess(ctx, database.CreateDocumentAccessParams{
+		DocumentID: int32(a.DocumentID),
+		UserID:     a.UserID,
+		IpAddress:  a.IPAddress,
+		UserAgent:  a.UserAgent,
+		Download:   a.Download,
+	})
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	*a = documentAccessFromRow(row)
+	return nil
+}
+
+func (r *PostgresDocumentRepository) ListAccessesByLease(ctx context.Context, leaseID int64) ([]models.DocumentAccess, error) {
+	rows, err := r.q.ListDocumentAccessesByLease(ctx, int32(leaseID))
+	if err != nil {
+		return nil, err
+	}
+	accesses := make([]models.DocumentAccess, len(rows))
+	for i, row := range rows {
+		accesses[i] = documentAccessFromRow(row)
+	}
+	return accesses, nil
+}
+
+func documentParams(d *models.Document) database.CreateDocumentParams {
+	return database.CreateDocumentParams{
+		LeaseID:      int32(d.LeaseID),
+		Kind:         database.DocumentKind(d.Kind),
+		Title:        d.Title,
+		Filename:     d.Filename,
+		ContentType:  d.ContentType,
+		SizeBytes:    d.SizeBytes,
+		StorageKey:   d.StorageKey,
+		UploadedBy:   d.UploadedBy,
+		Template:     d.Template,
+		TemplateData: d.TemplateData,
+	}
+}
+
+func documentFromRow(row database.Document) models.Document {
+	return models.Document{
+		ID:               int64(row.ID),
+		LeaseID:          int64(row.LeaseID),
+		Kind:             models.DocumentKind(row.Kind),
+		Title:            row.Title,
+		Filename:         row.Filename,
+		ContentType:      row.ContentType,
+		SizeBytes:        row.SizeBytes,
+		StorageKey:       row.StorageKey,
+		UploadedBy:       row.UploadedBy,
+		Template:         row.Template,
+		TemplateData:     row.TemplateData,
+		SignedDocumentID: int4ToInt64(row.SignedDocumentID),
+		CreatedAt:        row.CreatedAt.Time,
+	}
+}
+
+func documentAccessFromRow(row database.DocumentAccess) models.DocumentAccess {
+	return models.DocumentAccess{
+		ID:         int64(row.ID),
+		DocumentID: int64(row.DocumentID),
+		UserID:     row.UserID,
+		IPAddress:  row.IpAddress,
+		UserAgent:  row.UserAgent,
+		Download:   row.Download,
+		AccessedAt: row.AccessedAt.Time,
+	}
+}
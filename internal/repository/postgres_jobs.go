@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresJobRepository stores job runs in job_runs and locks jobs with
+// Postgres advisory locks, so only one server runs a job at a time
+type PostgresJobRepository struct {
+	db *database.DB
+	q  *database.Queries
+}
+
+// NewPostgresJobRepository creates a JobRepository backed by db
+func NewPostgresJobRepository(db *database.DB) *PostgresJobRepository {
+	return &PostgresJobRepository{db: db, q: database.New(db.Pool)}
+}
+
+// TryLock takes a session-level advisory lock on a connection set aside
+// for the job until it's released. If the server dies mid-run, Postgres
+// drops the lock along with the connection.
+func (r *PostgresJobRepository) TryLock(ctx context.Context, name string) (func(), error) {
+	conn, err := r.db.Pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key := jobLockKey(name)
+	q := database.New(conn)
+	locked, err := q.TryAdvisoryLock(ctx, key)
+	if err != nil || !locked {
+		conn.Release()
+		if err == nil {
+			err = ErrLocked
+		}
+		return nil, err
+	}
+
+	return func() {
+		// The job's context may be done by now, so unlock with a fresh one
+		if _, err := q.AdvisoryUnlock(context.Background(), key); err != nil {
+			// Closing the connection ends the session and its locks
+			log.Printf("unlock job %s: %v", name, err)
+			conn.Conn().Close(context.Background())
+		}
+		conn.Release()
+	}, nil
+}
+
+func (r *PostgresJobRepository) StartRun(ctx context.Context, run *models.JobRun) error {
+	row, err := r.q.CreateJobRun(ctx, database.CreateJobRunParams{
+		Job:          run.Job,
+		Trigger:      string(run.Trigger),
+		ScheduledFor: timeToTimestamp(run.ScheduledFor),
+	})
+	if isUniqueViolation(err) {
+		return ErrDuplicateRun
+	}
+	if err != nil {
+		return err
+	}
+	*run = jobRunFromRow(row)
+	return nil
+}
+
+func (r *PostgresJobRepository) FinishRun(ctx context.Context, id int64, status models.JobRunStatus, summary, errMsg string) (*models.JobRun, error) {
+	row, err := r.q.FinishJobRun(ctx, database.FinishJobRunParams{
+		ID:      int32(id),
+		Status:  database.JobRunStatus(status),
+		Summary: summary,
+		Error:   errMsg,
+	})
+	if err != nil {
+		return nil, notFound(err)
+	}
+	run := jobRunFromRow(row)
+	return &run, nil
+}
+
+func (r *PostgresJobRepository) ListRuns(ctx context.Context, job string, limit int) ([]models.JobRun, error) {
+	rows, err := r.q.ListJobRuns(ctx, database.ListJobRunsParams{JobFilter: job, MaxRows: int32(limit)})
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]models.JobRun, len(rows))
+	for i, row := range rows {
+		runs[i] = jobRunFromRow(row)
+	}
+	return runs, nil
+}
+
+// jobLockKey turns a job name into an advisory lock key
+func jobLockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("russ-rentals/job/" + name))
+	return int64(h.Sum64())
+}
+
+func jobRunFromRow(row database.JobRun) models.JobRun {
+	return models.JobRun{
+		ID:           int64(row.ID),
+		Job:          row.Job,
+		Trigger:      models.JobTrigger(row.Trigger),
+		ScheduledFor: row.ScheduledFor.Time,
+		Status:       models.JobRunStatus(row.Status),
+		Summary:      row.Summary,
+		Error:        row.Error,
+		StartedAt:    row.StartedAt.Time,
+		FinishedAt:   timestampToTime(row.FinishedAt),
+	}
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresLateFeeRepository stores late fee policies in late_fee_policies
+// and the properties using them in property_late_fee_policies
+type PostgresLateFeeRepository struct {
+	db *database.DB
+	q  *database.Queries
+}
+
+// NewPostgresLateFeeRepository creates a LateFeeRepository backed by db
+func NewPostgresLateFeeRepository(db *database.DB) *PostgresLateFeeRepository {
+	return &PostgresLateFeeRepository{db: db, q: database.New(db.Pool)}
+}
+
+func (r *PostgresLateFeeRepository) Create(ctx context.Context, p *models.LateFeePolicy) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := database.New(tx)
+	row, err := q.CreateLateFeePolicy(ctx, database.CreateLateFeePolicyParams(lateFeePolicyParams(p)))
+	if err != nil {
+		return err
+	}
+	if err := setPolicyProperties(ctx, q, row.ID, p.PropertyIDs); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	*p = lateFeePolicyFromRow(row, p.PropertyIDs)
+	return nil
+}
+
+func (r *PostgresLateFeeRepository) Get(ctx context.Context, id int64) (*models.LateFeePolicy, error) {
+	row, err := r.q.GetLateFeePolicy(ctx, int32(id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	policies, err := r.withProperties(ctx, []database.LateFeePolicy{row})
+	if err != nil {
+		return nil, err
+	}
+	return &policies[0], nil
+}
+
+func (r *PostgresLateFeeRepository) List(ctx context.Context) ([]models.LateFeePolicy, error) {
+	rows, err := r.q.ListLateFeePolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.withProperties(ctx, rows)
+}
+
+func (r *PostgresLateFeeRepository) Update(ctx context.Context, p *models.LateFeePolicy) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := database.New(tx)
+	params := lateFeePolicyParams(p)
+	row, err := q.UpdateLateFeePolicy(ctx, database.UpdateLateFeePolicyParams{
+		Name:       params.Name,
+		GraceDays:  params.GraceDays,
+		Type:       params.Type,
+		FlatCents:  params.FlatCents,
+		PercentBps: params.PercentBps,
+		DailyCents: params.DailyCents,
+		MaxCents:   params.MaxCents,
+		ID:         int32(p.ID),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := setPolicyProperties(ctx, q, row.ID, p.PropertyIDs); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	*p = lateFeePolicyFromRow(row, p.PropertyIDs)
+	return nil
+}
+
+func (r *PostgresLateFeeRepository) Delete(ctx context.Context, id int64) error {
+	n, err := r.q.DeleteLateFeePolicy(ctx, int32(id))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// withProperties converts rows to policies, loading the properties using
+// them in one query
+func (r *PostgresLateFeeRepository) withProperties(ctx context.Context, rows []database.LateFeePolicy) ([]models.LateFeePolicy, error) {
+	links, err := r.q.ListPropertyLateFeePolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	properties := make(map[int32][]int64)
+	for _, l := range links {
+		properties[l.PolicyID] = append(properties[l.PolicyID], int64(l.PropertyID))
+	}
+
+	policies := make([]models.LateFeePolicy, len(rows))
+	for i, row := range rows {
+		policies[i] = lateFeePolicyFromRow(row, properties[row.ID])
+	}
+	return policies, nil
+}
+
+// setPolicyProperties makes policy id the policy of exactly propertyIDs,
+// taking them from any other policy
+func setPolicyProperties(ctx context.Context, q *database.Queries, id int32, propertyIDs []int64) error {
+	if err := q.DeletePropertyLateFeePolicies(ctx, id); err != nil {
+		return err
+	}
+	for _, propertyID := range propertyIDs {
+		err := q.SetPropertyLateFeePolicy(ctx, database.SetPropertyLateFeePolicyParams{
+			PropertyID: int32(propertyID),
+			PolicyID:   id,
+		})
+		if isForeignKeyViolation(err) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func lateFeePolicyParams(p *models.LateFeePolicy) database.CreateLateFeePolicyParams {
+	return database.CreateLateFeePolicyParams{
+		Name:       p.Name,
+		GraceDays:  int32(p.GraceDays),
+		Type:       database.LateFeeType(p.Type),
+		FlatCents:  p.FlatCents,
+		PercentBps: int32(p.PercentBps),
+		DailyCents: p.DailyCents,
+		MaxCents:   p.MaxCents,
+	}
+}
+
+func lateFeePolicyFromRow(row database.LateFeePolicy, propertyIDs []int64) models.LateFeePolicy {
+	return models.LateFeePolicy{
+		ID:          int64(row.ID),
+		Name:        row.Name,
+		GraceDays:   int(row.GraceDays),
+		Type:        models.LateFeeType(row.Type),
+		FlatCents:   row.FlatCents,
+		PercentBps:  int(row.PercentBps),
+		DailyCents:  row.DailyCents,
+		MaxCents:    row.MaxCents,
+		PropertyIDs: propertyIDs,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresLeaseRepository stores leases in leases and their tenants in
+// lease_tenants
+type PostgresLeaseRepository struct {
+	db *database.DB
+	q  *database.Queries
+}
+
+// NewPostgresLeaseRepository creates a LeaseRepository backed by db
+func NewPostgresLeaseRepository(db *database.DB) *PostgresLeaseRepository {
+	return &PostgresLeaseRepository{db: db, q: database.New(db.Pool)}
+}
+
+func (r *PostgresLeaseRepository) Create(ctx context.Context, l *models.Lease) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := database.New(tx)
+	row, err := q.CreateLease(ctx, database.CreateLeaseParams{
+		PropertyID:       int32(l.PropertyID),
+		ApplicationID:    int64ToInt4(l.ApplicationID),
+		Status:           database.LeaseStatus(l.Status),
+		StartDate:        timeToDate(&l.StartDate),
+		EndDate:          timeToDate(&l.EndDate),
+		Term:             l.Term,
+		MonthlyRentCents: l.MonthlyRentCents,
+		DepositCents:     l.DepositCents,
+		PetRentCents:     l.PetRentCents,
+		LateFeePolicyID:  int64ToInt4(l.LateFeePolicyID),
+		Notes:            l.Notes,
+	})
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := createLeaseTenants(ctx, q, row.ID, l.Tenants); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	*l = leaseFromRow(row, l.Tenants)
+	return nil
+}
+
+func (r *PostgresLeaseRepository) Get(ctx context.Context, id int64) (*models.Lease, error) {
+	row, err := r.q.GetLease(ctx, int32(id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	leases, err := r.withTenants(ctx, []database.Lease{row})
+	if err != nil {
+		return nil, err
+	}
+	return &leases[0], nil
+}
+
+func (r *PostgresLeaseRepository) Filter(ctx context.Context, filter LeaseFilter) ([]models.Lease, error) {
+	rows, err := r.q.FilterLeases(ctx, database.FilterLeasesParams{
+		PropertyFilter: int32(filter.PropertyID),
+		StatusFilter:   string(filter.Status),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.withTenants(ctx, rows)
+}
+
+func (r *PostgresLeaseRepository) ListByTenant(ctx context.Context, userID string) ([]models.Lease, error) {
+	rows, err := r.q.ListLeasesByTenant(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return r.withTenants(ctx, rows)
+}
+
+func (r *PostgresLeaseRepository) Update(ctx context.Context, l *models.Lease) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := database.New(tx)
+	row, err := q.UpdateLease(ctx, database.UpdateLeaseParams{
+		ID:               int32(l.ID),
+		PropertyID:       int32(l.PropertyID),
+		ApplicationID:    int64ToInt4(l.ApplicationID),
+		StartDate:        timeToDate(&l.StartDate),
+		EndDate:          timeToDate(&l.EndDate),
+		Term:             l.Term,
+		MonthlyRentCents: l.MonthlyRentCents,
+		DepositCents:     l.DepositCents,
+		PetRentCents:     l.PetRentCents,
+		LateFeePolicyID:  int64ToInt4(l.LateFeePolicyID),
+		Notes:            l.Notes,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return r.missing(ctx, l.ID)
+	}
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := q.DeleteLeaseTenants(ctx, row.ID); err != nil {
+		return err
+	}
+	if err := createLeaseTenants(ctx, q, row.ID, l.Tenants); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	*l = leaseFromRow(row, l.Tenants)
+	return nil
+}
+
+func (r *PostgresLeaseRepository) SetStatus(ctx context.Context, id int64, from, to models.LeaseStatus) (*models.Lease, error) {
+	row, err := r.q.UpdateLeaseStatus(ctx, database.UpdateLeaseStatusParams{
+		ID:         int32(id),
+		FromStatus: database.LeaseStatus(from),
+		Status:     database.LeaseStatus(to),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, r.missing(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	leases, err := r.withTenants(ctx, []database.Lease{row})
+	if err != nil {
+		return nil, err
+	}
+	return &leases[0], nil
+}
+
+func (r *PostgresLeaseRepository) MarkNotified(ctx context.Context, leaseID int64, notice string, day time.Time) (bool, error) {
+	n, err := r.q.CreateLeaseNotice(ctx, database.CreateLeaseNoticeParams{
+		LeaseID: int32(leaseID),
+		Notice:  notice,
+		Day:     timeToDate(&day),
+	})
+	if isForeignKeyViolation(err) {
+		return false, ErrNotFound
+	}
+	return n > 0, err
+}
+
+func (r *PostgresLeaseRepository) ClearNotified(ctx context.Context, leaseID int64, notice string, day time.Time) error {
+	return r.q.DeleteLeaseNotice(ctx, database.DeleteLeaseNoticeParams{
+		LeaseID: int32(leaseID),
+		Notice:  notice,
+		Day:     timeToDate(&day),
+	})
+}
+
+// missing explains why a conditional update of lease id matched no row:
+// either it doesn't exist or its status moved on
+func (r *PostgresLeaseRepository) missing(ctx context.Context, id int64) error {
+	if _, err := r.q.GetLease(ctx, int32(id)); err != nil {
+		return notFound(err)
+	}
+	return ErrStatusChanged
+}
+
+// withTenants converts rows to leases, loading all their tenants in one
+// query
+func (r *PostgresLeaseRepository) withTenants(ctx context.Context, rows []database.Lease) ([]models.Lease, error) {
+	ids := make([]int32, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	tenantRows, err := r.q.ListLeaseTenants(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	tenants := make(map[int32][]models.LeaseTenant)
+	for _, t := range tenantRows {
+		tenants[t.LeaseID] = append(tenants[t.LeaseID], models.LeaseTenant{UserID: t.UserID, Name: t.Name, Email: t.Email})
+	}
+
+	leases := make([]models.Lease, len(rows))
+	for i, row := range rows {
+		leases[i] = leaseFromRow(row, tenants[row.ID])
+	}
+	return leases, nil
+}
+
+func createLeaseTenants(ctx context.Context, q *database.Queries, leaseID int32, tenants []models.LeaseTenant) error {
+	for i, t := range tenants {
+		err := q.CreateLeaseTenant(ctx, database.CreateLeaseTenantParams{
+			LeaseID:  leaseID,
+			UserID:   t.UserID,
+			Name:     t.Name,
+			Email:    t.Email,
+			Position: int32(i),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func leaseFromRow(row database.Lease, tenants []models.LeaseTenant) models.Lease {
+	return models.Lease{
+		ID:               int64(row.ID),
+		PropertyID:       int64(row.PropertyID),
+		ApplicationID:    int4ToInt64(row.ApplicationID),
+		Status:           models.LeaseStatus(row.Status),
+		StartDate:        row.StartDate.Time,
+		EndDate:          row.EndDate.Time,
+		Term:             row.Term,
+		MonthlyRentCents: row.MonthlyRentCents,
+		DepositCents:     row.DepositCents,
+		PetRentCents:     row.PetRentCents,
+		LateFeePolicyID:  int4ToInt64(row.LateFeePolicyID),
+		Notes:            row.Notes,
+		Tenants:          tenants,
+		CreatedAt:        row.CreatedAt.Time,
+		UpdatedAt:        row.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresLedgerRepository stores ledger entries in the ledger_entries
+// table
+type PostgresLedgerRepository struct {
+	db *database.DB
+	q  *database.Queries
+}
+
+// NewPostgresLedgerRepository creates a LedgerRepository backed by db
+func NewPostgresLedgerRepository(db *database.DB) *PostgresLedgerRepository {
+	return &PostgresLedgerRepository{db: db, q: database.New(db.Pool)}
+}
+
+func (r *PostgresLedgerRepository) Create(ctx context.Context, e *models.LedgerEntry) error {
+	row, err := r.q.CreateLedgerEntry(ctx, database.CreateLedgerEntryParams(ledgerEntryParams(e)))
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	*e = ledgerEntryFromRow(row)
+	return nil
+}
+
+func (r *PostgresLedgerRepository) PostScheduled(ctx context.Context, entries []models.LedgerEntry) (int, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	q := database.New(tx)
+
+	posted := 0
+	for i := range entries {
+		// Late fees are unique per day rather than per period
+		var n int64
+		params := ledgerEntryParams(&entries[i])
+		if entries[i].Kind == models.LedgerKindLateFee {
+			n, err = q.PostLateFeeLedgerEntry(ctx, database.PostLateFeeLedgerEntryParams(params))
+		} else {
+			n, err = q.PostScheduledLedgerEntry(ctx, params)
+		}
+		if isForeignKeyViolation(err) {
+			return 0, ErrNotFound
+		}
+		if err != nil {
+			return 0, err
+		}
+		posted += int(n)
+	}
+	return posted, tx.Commit(ctx)
+}
+
+func (r *PostgresLedgerRepository) PostPayment(ctx context.Context, e *models.LedgerEntry) (bool, error) {
+	n, err := r.q.PostPaymentLedgerEntry(ctx, database.PostPaymentLedgerEntryParams(ledgerEntryParams(e)))
+	if isForeignKeyViolation(err) {
+		return false, ErrNotFound
+	}
+	return n > 0, err
+}
+
+func (r *PostgresLedgerRepository) ListByLease(ctx context.Context, leaseID int64) (models.Ledger, error) {
+	rows, err := r.q.ListLedgerEntries(ctx, int32(leaseID))
+	if err != nil {
+		return nil, err
+	}
+	ledger := make(models.Ledger, len(rows))
+	for i, row := range rows {
+		ledger[i] = ledgerEntryFromRow(row)
+	}
+	return ledger, nil
+}
+
+func ledgerEntryParams(e *models.LedgerEntry) database.PostScheduledLedgerEntryParams {
+	return database.PostScheduledLedgerEntryParams{
+		LeaseID:     int32(e.LeaseID),
+		Kind:        database.LedgerKind(e.Kind),
+		Description: e.Description,
+		AmountCents: e.AmountCents,
+		PostedOn:    timeToDate(&e.PostedOn),
+		Period:      timeToDate(e.Period),
+		CreatedBy:   e.CreatedBy,
+		PaymentID:   int64ToInt4(e.PaymentID),
+	}
+}
+
+func ledgerEntryFromRow(row database.LedgerEntry) models.LedgerEntry {
+	return models.LedgerEntry{
+		ID:          int64(row.ID),
+		LeaseID:     int64(row.LeaseID),
+		Kind:        models.LedgerKind(row.Kind),
+		Description: row.Description,
+		AmountCents: row.AmountCents,
+		PostedOn:    row.PostedOn.Time,
+		Period:      dateToTime(row.Period),
+		CreatedBy:   row.CreatedBy,
+		PaymentID:   int4ToInt64(row.PaymentID),
+		CreatedAt:   row.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresMaintenanceRepository stores maintenance requests in
+// maintenance_requests, with their photos and timelines alongside
+type PostgresMaintenanceRepository struct {
+	db *database.DB
+	q  *database.Queries
+}
+
+// NewPostgresMaintenanceRepository creates a MaintenanceRepository backed by
+// db
+func NewPostgresMaintenanceRepository(db *database.DB) *PostgresMaintenanceRepository {
+	return &PostgresMaintenanceRepository{db: db, q: database.New(db.Pool)}
+}
+
+func (r *PostgresMaintenanceRepository) Create(ctx context.Context, req *models.MaintenanceRequest) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := database.New(tx)
+	row, err := q.CreateMaintenanceRequest(ctx, database.CreateMaintenanceRequestParams{
+		LeaseID:        int32(req.LeaseID),
+		PropertyID:     int32(req.PropertyID),
+		TenantID:       req.TenantID,
+		TenantName:     req.TenantName,
+		TenantEmail:    req.TenantEmail,
+		Category:       database.MaintenanceCategory(req.Category),
+		Urgency:        database.MaintenanceUrgency(req.Urgency),
+		Title:          req.Title,
+		Description:    req.Description,
+		EntryPermitted: req.EntryPermitted,
+		EntryNotes:     req.EntryNotes,
+	})
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	photos := make([]models.MaintenancePhoto, len(req.Photos))
+	for i, p := range req.Photos {
+		photoRow, err := q.CreateMaintenancePhoto(ctx, database.CreateMaintenancePhotoParams{
+			RequestID:  row.ID,
+			StorageKey: p.StorageKey,
+		})
+		if err != nil {
+			return err
+		}
+		photos[i] = maintenancePhotoFromRow(photoRow)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	*req = maintenanceRequestFromRow(row, photos)
+	return nil
+}
+
+func (r *PostgresMaintenanceRepository) Get(ctx context.Context, id int64) (*models.MaintenanceRequest, error) {
+	row, err := r.q.GetMaintenanceRequest(ctx, int32(id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	requests, err := r.withPhotos(ctx, []database.MaintenanceRequest{row})
+	if err != nil {
+		return nil, err
+	}
+	return &requests[0], nil
+}
+
+func (r *PostgresMaintenanceRepository) ListByLease(ctx context.Context, leaseID int64) ([]models.MaintenanceRequest, error) {
+	rows, err := r.q.ListMaintenanceRequestsByLease(ctx, int32(leaseID))
+	if err != nil {
+		return nil, err
+	}
+	return r.withPhotos(ctx, rows)
+}
+
+func (r *PostgresMaintenanceRepository) Filter(ctx context.Context, filter MaintenanceFilter) ([]models.MaintenanceRequest, error) {
+	rows, err := r.q.FilterMaintenanceRequests(ctx, database.FilterMaintenanceRequestsParams{
+		PropertyFilter: int32(filter.PropertyID),
+		StatusFilter:   string(filter.Status),
+		UrgencyFilter:  string(filter.Urgency),
+		AssigneeFilter: filter.AssigneeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.withPhotos(ctx, rows)
+}
+
+func (r *PostgresMaintenanceRepository) SetStatus(ctx context.Context, id int64, status models.MaintenanceStatus, actorID string) (*models.MaintenanceRequest, error) {
+	var resolvedAt pgtype.Timestamptz
+	if status == models.MaintenanceStatusResolved {
+		resolvedAt = timeToTimestamp(time.Now())
+	}
+	return r.update(ctx, id, actorID, models.MaintenanceEventStatus, string(status), func(q *database.Queries) (database.MaintenanceRequest, error) {
+		return q.UpdateMaintenanceStatus(ctx, database.UpdateMaintenanceStatusParams{
+			ID:         int32(id),
+			Status:     database.MaintenanceStatus(status),
+			ResolvedAt: resolvedAt,
+		})
+	})
+}
+
+func (r *PostgresMaintenanceRepository) Schedule(ctx context.Context, id int64, at *time.Time, actorID string) (*models.MaintenanceRequest, error) {
+	var scheduledFor pgtype.Timestamptz
+	if at != nil {
+		scheduledFor = timeToTimestamp(*at)
+	}
+	return r.update(ctx, id, actorID, models.MaintenanceEventScheduled, scheduleDetail(at), func(q *database.Queries) (database.MaintenanceRequest, error) {
+		return q.UpdateMaintenanceSchedule(ctx, database.UpdateMaintenanceScheduleParams{
+			ID:           int32(id),
+			ScheduledFor: scheduledFor,
+		})
+	})
+}
+
+func (r *PostgresMaintenanceRepository) Assign(ctx context.Context, id int64, assigneeID, actorID string) (*models.MaintenanceRequest, error) {
+	return r.update(ctx, id, actorID, models.MaintenanceEventAssigned, assigneeID, func(q *database.Queries) (database.MaintenanceRequest, error) {
+		return q.UpdateMaintenanceAssignee(ctx, database.UpdateMaintenanceAssigneeParams{
+			ID:         int32(id),
+			AssigneeID: textOrNull(assigneeID),
+		})
+	})
+}
+
+func (r *PostgresMaintenanceRepository) SetVendor(ctx context.Context, id int64, vendor, actorID string) (*models.MaintenanceRequest, error) {
+	return r.update(ctx, id, actorID, models.MaintenanceEventVendor, vendor, func(q *database.Queries) (database.MaintenanceRequest, error) {
+		return q.UpdateMaintenanceVendor(ctx, database.UpdateMaintenanceVendorParams{
+			ID:     int32(id),
+			Vendor: vendor,
+		})
+	})
+}
+
+// update runs change and records it on the request's timeline in one
+// transaction
+func (r *PostgresMaintenanceRepository) update(ctx context.Context, id int64, actorID string, kind models.MaintenanceEventKind, detail string, change func(*database.Queries) (database.MaintenanceRequest, error)) (*models.MaintenanceRequest, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := database.New(tx)
+	row, err := change(q)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	_, err = q.CreateMaintenanceEvent(ctx, database.CreateMaintenanceEventParams{
+		RequestID: int32(id),
+		ActorID:   actorID,
+		Kind:      database.MaintenanceEventKind(kind),
+		Detail:    detail,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	requests, err := r.withPhotos(ctx, []database.MaintenanceRequest{row})
+	if err != nil {
+		return nil, err
+	}
+	return &requests[0], nil
+}
+
+func (r *PostgresMaintenanceRepository) AddEvent(ctx context.Context, id int64, kind models.MaintenanceEventKind, text, actorID string) (*models.MaintenanceEvent, error) {
+	row, err := r.q.CreateMaintenanceEvent(ctx, database.CreateMaintenanceEventParams{
+		RequestID: int32(id),
+		ActorID:   actorID,
+		Kind:      database.MaintenanceEventKind(kind),
+		Detail:    text,
+	})
+	if isForeignKeyViolation(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	event := maintenanceEventFromRow(row)
+	return &event, nil
+}
+
+func (r *PostgresMaintenanceRepository) Timeline(ctx context.Context, id int64) ([]models.MaintenanceEvent, error) {
+	rows, err := r.q.ListMaintenanceEvents(ctx, int32(id))
+	if err != nil {
+		return nil, err
+	}
+	events := make([]models.MaintenanceEvent, len(rows))
+	for i, row := range rows {
+		events[i] = maintenanceEventFromRow(row)
+	}
+	return events, nil
+}
+
+// withPhotos converts rows to requests, loading all their photos in one
+// query
+func (r *PostgresMaintenanceRepository) withPhotos(ctx context.Context, rows []database.MaintenanceRequest) ([]models.MaintenanceRequest, error) {
+	ids := make([]int32, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	photoRows, err := r.q.ListMaintenancePhotos(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	photos := make(map[int32][]models.MaintenancePhoto)
+	for _, p := range photoRows {
+		photos[p.RequestID] = append(photos[p.RequestID], maintenancePhotoFromRow(p))
+	}
+
+	requests := make([]models.MaintenanceRequest, len(rows))
+	for i, row := range rows {
+		requests[i] = maintenanceRequestFromRow(row, photos[row.ID])
+	}
+	return requests, nil
+}
+
+func maintenanceRequestFromRow(row database.MaintenanceRequest, photos []models.MaintenancePhoto) models.MaintenanceRequest {
+	return models.MaintenanceRequest{
+		ID:             int64(row.ID),
+		LeaseID:        int64(row.LeaseID),
+		PropertyID:     int64(row.PropertyID),
+		TenantID:       row.TenantID,
+		TenantName:     row.TenantName,
+		TenantEmail:    row.TenantEmail,
+		Category:       models.MaintenanceCategory(row.Category),
+		Urgency:        models.MaintenanceUrgency(row.Urgency),
+		Title:          row.Title,
+		Description:    row.Description,
+		EntryPermitted: row.EntryPermitted,
+		EntryNotes:     row.EntryNotes,
+		Status:         models.MaintenanceStatus(row.Status),
+		AssigneeID:     row.AssigneeID.String,
+		Vendor:         row.Vendor,
+		ScheduledFor:   timestampToTime(row.ScheduledFor),
+		ResolvedAt:     timestampToTime(row.ResolvedAt),
+		Photos:         photos,
+		CreatedAt:      row.CreatedAt.Time,
+		UpdatedAt:      row.UpdatedAt.Time,
+	}
+}
+
+func maintenancePhotoFromRow(row database.MaintenancePhoto) models.MaintenancePhoto {
+	return models.MaintenancePhoto{
+		ID:         int64(row.ID),
+		RequestID:  int64(row.RequestID),
+		StorageKey: row.StorageKey,
+		CreatedAt:  row.CreatedAt.Time,
+	}
+}
+
+func maintenanceEventFromRow(row database.MaintenanceEvent) models.MaintenanceEvent {
+	return models.MaintenanceEvent{
+		ID:        int64(row.ID),
+		RequestID: int64(row.RequestID),
+		ActorID:   row.ActorID,
+		Kind:      models.MaintenanceEventKind(row.Kind),
+		Detail:    row.Detail,
+		CreatedAt: row.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresNewsletterRepository stores subscribers in newsletter_subscribers
+type PostgresNewsletterRepository struct {
+	q *database.Queries
+}
+
+// NewPostgresNewsletterRepository creates a NewsletterRepository backed by db
+func NewPostgresNewsletterRepository(db *database.DB) *PostgresNewsletterRepository {
+	return &PostgresNewsletterRepository{q: database.New(db.Pool)}
+}
+
+func (r *PostgresNewsletterRepository) Subscribe(ctx context.Context, email, firstName string) (*models.NewsletterSubscriber, error) {
+	row, err := r.q.CreateNewsletterSubscriber(ctx, database.CreateNewsletterSubscriberParams{
+		Email:     email,
+		FirstName: textOrNull(firstName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sub := subscriberFromRow(row)
+	return &sub, nil
+}
+
+func (r *PostgresNewsletterRepository) Get(ctx context.Context, email string) (*models.NewsletterSubscriber, error) {
+	row, err := r.q.GetNewsletterSubscriber(ctx, email)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	sub := subscriberFromRow(row)
+	return &sub, nil
+}
+
+func (r *PostgresNewsletterRepository) Confirm(ctx context.Context, email string) (*models.NewsletterSubscriber, error) {
+	row, err := r.q.ConfirmNewsletterSubscriber(ctx, email)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	sub := subscriberFromRow(row)
+	return &sub, nil
+}
+
+func (r *PostgresNewsletterRepository) Unsubscribe(ctx context.Context, email string) error {
+	n, err := r.q.UnsubscribeNewsletter(ctx, email)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNewsletterRepository) ListActive(ctx context.Context) ([]models.NewsletterSubscriber, error) {
+	rows, err := r.q.ListActiveSubscribers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subs := make([]models.NewsletterSubscriber, len(rows))
+	for i, row := range rows {
+		subs[i] = subscriberFromRow(row)
+	}
+	return subs, nil
+}
+
+func (r *PostgresNewsletterRepository) CountActive(ctx context.Context) (int64, error) {
+	return r.q.CountActiveSubscribers(ctx)
+}
+
+func subscriberFromRow(row database.NewsletterSubscriber) models.NewsletterSubscriber {
+	return models.NewsletterSubscriber{
+		ID:             int64(row.ID),
+		Email:          row.Email,
+		FirstName:      row.FirstName.String,
+		Status:         models.SubscriberStatus(row.Status),
+		SubscribedAt:   row.SubscribedAt.Time,
+		ConfirmedAt:    timestampToTime(row.ConfirmedAt),
+		UnsubscribedAt: timestampToTime(row.UnsubscribedAt),
+	}
+}
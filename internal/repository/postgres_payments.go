@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresPaymentRepository stores payments in the payments table
+type PostgresPaymentRepository struct {
+	q *database.Queries
+}
+
+// NewPostgresPaymentRepository creates a PaymentRepository backed by db
+func NewPostgresPaymentRepository(db *database.DB) *PostgresPaymentRepository {
+	return &PostgresPaymentRepository{q: database.New(db.Pool)}
+}
+
+func (r *PostgresPaymentRepository) Create(ctx context.Context, p *models.Payment) error {
+	row, err := r.q.CreatePayment(ctx, database.CreatePaymentParams{
+		Gateway:       p.Gateway,
+		IntentID:      p.IntentID,
+		Purpose:       string(p.Purpose),
+		ApplicationID: int64ToInt4(p.ApplicationID),
+		LeaseID:       int64ToInt4(p.LeaseID),
+		PayerID:       p.PayerID,
+		AmountCents:   p.AmountCents,
+		Currency:      p.Currency,
+	})
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	*p = *paymentFromRow(row)
+	return nil
+}
+
+func (r *PostgresPaymentRepository) Get(ctx context.Context, id int64) (*models.Payment, error) {
+	row, err := r.q.GetPayment(ctx, int32(id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return paymentFromRow(row), nil
+}
+
+func (r *PostgresPaymentRepository) GetByIntent(ctx context.Context, intentID string) (*models.Payment, error) {
+	row, err := r.q.GetPaymentByIntent(ctx, intentID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return paymentFromRow(row), nil
+}
+
+func (r *PostgresPaymentRepository) ListByApplication(ctx context.Context, applicationID int64) ([]models.Payment, error) {
+	rows, err := r.q.ListPaymentsByApplication(ctx, int64ToInt4(&applicationID))
+	if err != nil {
+		return nil, err
+	}
+	payments := make([]models.Payment, len(rows))
+	for i, row := range rows {
+		payments[i] = *paymentFromRow(row)
+	}
+	return payments, nil
+}
+
+func (r *PostgresPaymentRepository) ListByLease(ctx context.Context, leaseID int64) ([]models.Payment, error) {
+	rows, err := r.q.ListPaymentsByLease(ctx, int64ToInt4(&leaseID))
+	if err != nil {
+		return nil, err
+	}
+	payments := make([]models.Payment, len(rows))
+	for i, row := range rows {
+		payments[i] = *paymentFromRow(row)
+	}
+	return payments, nil
+}
+
+func (r *PostgresPaymentRepository) SetStatus(ctx context.Context, id int64, status models.PaymentStatus, failureMessage string) (*models.Payment, error) {
+	row, err := r.q.UpdatePaymentStatus(ctx, database.UpdatePaymentStatusParams{
+		ID:             int32(id),
+		Status:         database.PaymentStatus(status),
+		FailureMessage: failureMessage,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Already paid, or missing
+		return r.Get(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return paymentFromRow(row), nil
+}
+
+func (r *PostgresPaymentRepository) SetRefunded(ctx context.Context, id int64, refundedCents int64) (*models.Payment, error) {
+	row, err := r.q.UpdatePaymentRefunded(ctx, database.UpdatePaymentRefundedParams{
+		ID:            int32(id),
+		RefundedCents: refundedCents,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := r.Get(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+	return paymentFromRow(row), nil
+}
+
+func paymentFromRow(row database.Payment) *models.Payment {
+	return &models.Payment{
+		ID:             int64(row.ID),
+		Gateway:        row.Gateway,
+		IntentID:       row.IntentID,
+		Purpose:        models.PaymentPurpose(row.Purpose),
+		ApplicationID:  int4ToInt64(row.ApplicationID),
+		LeaseID:        int4ToInt64(row.LeaseID),
+		PayerID:        row.PayerID,
+		AmountCents:    row.AmountCents,
+		RefundedCents:  row.RefundedCents,
+		Currency:       row.Currency,
+		Status:         models.PaymentStatus(row.Status),
+		FailureMessage: row.FailureMessage,
+		PaidAt:         timestampToTime(row.PaidAt),
+		CreatedAt:      row.CreatedAt.Time,
+		UpdatedAt:      row.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresShowingRepository stores availability in showing_windows and
+// bookings in showings, whose exclusion constraints prevent double-booking
+type PostgresShowingRepository struct {
+	q *database.Queries
+}
+
+// NewPostgresShowingRepository creates a ShowingRepository backed by db
+func NewPostgresShowingRepository(db *database.DB) *PostgresShowingRepository {
+	return &PostgresShowingRepository{q: database.New(db.Pool)}
+}
+
+func (r *PostgresShowingRepository) CreateWindow(ctx context.Context, w *models.ShowingWindow) error {
+	row, err := r.q.CreateShowingWindow(ctx, database.CreateShowingWindowParams{
+		PropertyID:  int32(w.PropertyID),
+		AgentID:     w.AgentID,
+		StartsAt:    timeToTimestamp(w.StartsAt),
+		EndsAt:      timeToTimestamp(w.EndsAt),
+		SlotMinutes: int32(w.SlotMinutes),
+	})
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	*w = windowFromRow(row)
+	return nil
+}
+
+func (r *PostgresShowingRepository) Windows(ctx context.Context, propertyID int64, after time.Time) ([]models.ShowingWindow, error) {
+	rows, err := r.q.ListShowingWindows(ctx, database.ListShowingWindowsParams{
+		PropertyFilter: int32(propertyID),
+		EndsAfter:      timeToTimestamp(after),
+	})
+	if err != nil {
+		return nil, err
+	}
+	windows := make([]models.ShowingWindow, len(rows))
+	for i, row := range rows {
+		windows[i] = windowFromRow(row)
+	}
+	return windows, nil
+}
+
+func (r *PostgresShowingRepository) DeleteWindow(ctx context.Context, id int64) error {
+	return r.q.DeleteShowingWindow(ctx, int32(id))
+}
+
+func (r *PostgresShowingRepository) Book(ctx context.Context, s *models.Showing) error {
+	row, err := r.q.CreateShowing(ctx, database.CreateShowingParams{
+		PropertyID:          int32(s.PropertyID),
+		AgentID:             s.AgentID,
+		ContactSubmissionID: int64ToInt4(s.InquiryID),
+		Name:                s.Name,
+		Email:               s.Email,
+		Phone:               s.Phone,
+		StartsAt:            timeToTimestamp(s.StartsAt),
+		EndsAt:              timeToTimestamp(s.EndsAt),
+	})
+	if isExclusionViolation(err) {
+		return ErrSlotTaken
+	}
+	if err != nil {
+		return err
+	}
+	*s = showingFromRow(row)
+	return nil
+}
+
+func (r *PostgresShowingRepository) Get(ctx context.Context, id int64) (*models.Showing, error) {
+	row, err := r.q.GetShowing(ctx, int32(id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	s := showingFromRow(row)
+	return &s, nil
+}
+
+func (r *PostgresShowingRepository) Upcoming(ctx context.Context, from time.Time) ([]models.Showing, error) {
+	rows, err := r.q.ListShowings(ctx, timeToTimestamp(from))
+	if err != nil {
+		return nil, err
+	}
+	return showingsFromRows(rows), nil
+}
+
+func (r *PostgresShowingRepository) Feed(ctx context.Context, propertyID int64, agentID string, since time.Time) ([]models.Showing, error) {
+	rows, err := r.q.ListShowingFeed(ctx, database.ListShowingFeedParams{
+		PropertyFilter: int32(propertyID),
+		AgentFilter:    agentID,
+		Since:          timeToTimestamp(since),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return showingsFromRows(rows), nil
+}
+
+func (r *PostgresShowingRepository) Busy(ctx context.Context, propertyID int64, agentIDs []string, since, until time.Time) ([]models.Showing, error) {
+	rows, err := r.q.ListBusyShowings(ctx, database.ListBusyShowingsParams{
+		PropertyID: int32(propertyID),
+		AgentIds:   nonNil(agentIDs),
+		Until:      timeToTimestamp(until),
+		Since:      timeToTimestamp(since),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return showingsFromRows(rows), nil
+}
+
+func (r *PostgresShowingRepository) SetStatus(ctx context.Context, id int64, status models.ShowingStatus) (*models.Showing, error) {
+	row, err := r.q.UpdateShowingStatus(ctx, database.UpdateShowingStatusParams{
+		ID:     int32(id),
+		Status: database.ShowingStatus(status),
+	})
+	if isExclusionViolation(err) {
+		return nil, ErrSlotTaken
+	}
+	if err != nil {
+		return nil, notFound(err)
+	}
+	s := showingFromRow(row)
+	return &s, nil
+}
+
+func windowFromRow(row database.ShowingWindow) models.ShowingWindow {
+	return models.ShowingWindow{
+		ID:          int64(row.ID),
+		PropertyID:  int64(row.PropertyID),
+		AgentID:     row.AgentID,
+		StartsAt:    row.StartsAt.Time,
+		EndsAt:      row.EndsAt.Time,
+		SlotMinutes: int(row.SlotMinutes),
+		CreatedAt:   row.CreatedAt.Time,
+	}
+}
+
+func showingFromRow(row database.Showing) models.Showing {
+	return models.Showing{
+		ID:         int64(row.ID),
+		PropertyID: int64(row.PropertyID),
+		AgentID:    row.AgentID,
+		InquiryID:  int4ToInt64(row.ContactSubmissionID),
+		Name:       row.Name,
+		Email:      row.Email,
+		Phone:      row.Phone,
+		StartsAt:   row.StartsAt.Time,
+		EndsAt:     row.EndsAt.Time,
+		Status:     models.ShowingStatus(row.Status),
+		Sequence:   int(row.Sequence),
+		CreatedAt:  row.CreatedAt.Time,
+		UpdatedAt:  row.UpdatedAt.Time,
+	}
+}
+
+func showingsFromRows(rows []database.Showing) []models.Showing {
+	showings := make([]models.Showing, len(rows))
+	for i, row := range rows {
+		showings[i] = showingFromRow(row)
+	}
+	return showings
+}
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresSignatureRepository stores signature requests in the
+// signature_requests table and their audit trail in signature_events.
+// Changes to a document's signing lock its row in documents, so that
+// concurrent signers see each other's signatures.
+type PostgresSignatureRepository struct {
+	db *database.DB
+	q  *database.Queries
+}
+
+// NewPostgresSignatureRepository creates a SignatureRepository backed by db
+func NewPostgresSignatureRepository(db *database.DB) *PostgresSignatureRepository {
+	return &PostgresSignatureRepository{db: db, q: database.New(db.Pool)}
+}
+
+func (r *PostgresSignatureRepository) Request(ctx context.Context, documentID int64, requests []models.SignatureRequest, sent models.SignatureEvent) error {
+	return r.inTx(ctx, documentID, func(q *database.Queries, doc database.Document) error {
+		open, err := q.CountOpenSignatureRequests(ctx, int32(documentID))
+		if err != nil {
+			return err
+		}
+		if doc.SignedDocumentID.Valid || open > 0 {
+			return ErrStatusChanged
+		}
+		for i, req := range requests {
+			row, err := q.CreateSignatureRequest(ctx, database.CreateSignatureRequestParams{
+				DocumentID:     int32(documentID),
+				Position:       int32(req.Position),
+				Name:           req.Name,
+				Role:           req.Role,
+				Email:          req.Email,
+				UserID:         req.UserID,
+				DocumentSha256: req.DocumentSHA256,
+				RequestedBy:    req.RequestedBy,
+			})
+			if err != nil {
+				return err
+			}
+			requests[i] = signatureRequestFromRow(row)
+			e := sent
+			e.RequestID = &requests[i].ID
+			e.Kind = models.SignatureEventSent
+			if _, err := q.CreateSignatureEvent(ctx, signatureEventParams(documentID, e)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *PostgresSignatureRepository) Get(ctx context.Context, id int64) (*models.SignatureRequest, error) {
+	row, err := r.q.GetSignatureRequest(ctx, int32(id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	req := signatureRequestFromRow(row)
+	return &req, nil
+}
+
+func (r *PostgresSignatureRepository) ListByDocument(ctx context.Context, documentID int64) ([]models.SignatureRequest, error) {
+	rows, err := r.q.ListSignatureRequestsByDocument(ctx, int32(documentID))
+	if err != nil {
+		return nil, err
+	}
+	return signatureRequestsFromRows(rows), nil
+}
+
+func (r *PostgresSignatureRepository) ListByLease(ctx context.Context, leaseID int64) ([]models.SignatureRequest, error) {
+	rows, err := r.q.ListSignatureRequestsByLease(ctx, int32(leaseID))
+	if err != nil {
+		return nil, err
+	}
+	return signatureRequestsFromRows(rows), nil
+}
+
+func (r *PostgresSignatureRepository) Sign(ctx context.Context, sig *models.SignatureRequest) (bool, error) {
+	req, err := r.Get(ctx, sig.ID)
+	if err != nil {
+		return false, err
+	}
+	complete := false
+	err = r.inTx(ctx, req.DocumentID, func(q *database.Queries, doc database.Document) error {
+		row, err := q.SignSignatureRequest(ctx, database.SignSignatureRequestParams{
+			ID:        int32(sig.ID),
+			Method:    string(sig.Method),
+			TypedName: sig.TypedName,
+			Drawing:   sig.Drawing,
+			IpAddress: sig.IPAddress,
+			UserAgent: sig.UserAgent,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrStatusChanged
+		}
+		if err != nil {
+			return err
+		}
+		*sig = signatureRequestFromRow(row)
+		_, err = q.CreateSignatureEvent(ctx, signatureEventParams(sig.DocumentID, models.SignatureEvent{
+			RequestID: &sig.ID,
+			Kind:      models.SignatureEventSigned,
+			ActorID:   sig.UserID,
+			IPAddress: sig.IPAddress,
+			UserAgent: sig.UserAgent,
+		}))
+		if err != nil {
+			return err
+		}
+		pending, err := q.CountPendingSignatureRequests(ctx, int32(sig.DocumentID))
+		complete = pending == 0
+		return err
+	})
+	return complete, err
+}
+
+func (r *PostgresSignatureRepository) Void(ctx context.Context, documentID int64, voided models.SignatureEvent) error {
+	return r.inTx(ctx, documentID, func(q *database.Queries, doc database.Document) error {
+		if doc.SignedDocumentID.Valid {
+			return ErrStatusChanged
+		}
+		rows, err := q.VoidSignatureRequests(ctx, int32(documentID))
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return ErrStatusChanged
+		}
+		for _, row := range rows {
+			id := int64(row.ID)
+			e := voided
+			e.RequestID = &id
+			e.Kind = models.SignatureEventVoided
+			if _, err := q.CreateSignatureEvent(ctx, signatureEventParams(documentID, e)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *PostgresSignatureRepository) Complete(ctx context.Context, documentID int64, signed *models.Document) error {
+	return r.inTx(ctx, documentID, func(q *database.Queries, doc database.Document) error {
+		open, err := q.CountOpenSignatureRequests(ctx, int32(documentID))
+		if err != nil {
+			return err
+		}
+		pending, err := q.CountPendingSignatureRequests(ctx, int32(documentID))
+		if err != nil {
+			return err
+		}
+		if doc.SignedDocumentID.Valid || open == 0 || pending > 0 {
+			return ErrStatusChanged
+		}
+		row, err := q.CreateDocument(ctx, documentParams(signed))
+		if err != nil {
+			return err
+		}
+		_, err = q.SetSignedDocument(ctx, database.SetSignedDocumentParams{
+			ID:               int32(documentID),
+			SignedDocumentID: pgtype.Int4{Int32: row.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateSignatureEvent(ctx, signatureEventParams(documentID, models.SignatureEvent{
+			Kind:    models.SignatureEventCompleted,
+			ActorID: signed.UploadedBy,
+		}))
+		if err != nil {
+			return err
+		}
+		*signed = documentFromRow(row)
+		return nil
+	})
+}
+
+func (r *PostgresSignatureRepository) LogEvent(ctx context.Context, e *models.SignatureEvent) error {
+	row, err := r.q.CreateSignatureEvent(ctx, signatureEventParams(e.DocumentID, *e))
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	*e = signatureEventFromRow(row)
+	return nil
+}
+
+func (r *PostgresSignatureRepository) EventsByDocument(ctx context.Context, documentID int64) ([]models.SignatureEvent, error) {
+	rows, err := r.q.ListSignatureEventsByDocument(ctx, int32(documentID))
+	if err != nil {
+		return nil, err
+	}
+	return signatureEventsFromRows(rows), nil
+}
+
+func (r *PostgresSignatureRepository) EventsByLease(ctx context.Context, leaseID int64) ([]models.SignatureEvent, error) {
+	rows, err := r.q.ListSignatureEventsByLease(ctx, int32(leaseID))
+	if err != nil {
+		return nil, err
+	}
+	return signatureEventsFromRows(rows), nil
+}
+
+// inTx runs change in a transaction holding the lock on document
+// documentID's row
+func (r *PostgresSignatureRepository) inTx(ctx context.Context, documentID int64, change func(*database.Queries, database.Document) error) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := database.New(tx)
+	doc, err := q.LockDocument(ctx, int32(documentID))
+	if err != nil {
+		return notFound(err)
+	}
+	if err := change(q, doc); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func signatureEventParams(documentID int64, e models.SignatureEvent) database.CreateSignatureEventParams {
+	return database.CreateSignatureEventParams{
+		DocumentID: int32(documentID),
+		RequestID:  int64ToInt4(e.RequestID),
+		Kind:       database.SignatureEventKind(e.Kind),
+		ActorID:    e.ActorID,
+		IpAddress:  e.IPAddress,
+		UserAgent:  e.UserAgent,
+	}
+}
+
+func signatureRequestsFromRows(rows []database.SignatureRequest) []models.SignatureRequest {
+	requests := make([]models.SignatureRequest, len(rows))
+	for i, row := range rows {
+		requests[i] = signatureRequestFromRow(row)
+	}
+	return requests
+}
+
+func signatureRequestFromRow(row database.SignatureRequest) models.SignatureRequest {
+	return models.SignatureRequest{
+		ID:             int64(row.ID),
+		DocumentID:     int64(row.DocumentID),
+		Position:       int(row.Position),
+		Name:           row.Name,
+		Role:           row.Role,
+		Email:          row.Email,
+		UserID:         row.UserID,
+		Status:         models.SignatureStatus(row.Status),
+		DocumentSHA256: row.DocumentSha256,
+		Method:         models.SignatureMethod(row.Method),
+		TypedName:      row.TypedName,
+		Drawing:        row.Drawing,
+		IPAddress:      row.IpAddress,
+		UserAgent:      row.UserAgent,
+		SignedAt:       timestampToTime(row.SignedAt),
+		RequestedBy:    row.RequestedBy,
+		CreatedAt:      row.CreatedAt.Time,
+	}
+}
+
+func signatureEventsFromRows(rows []database.SignatureEvent) []models.SignatureEvent {
+	events := make([]models.SignatureEvent, len(rows))
+	for i, row := range rows {
+		events[i] = signatureEventFromRow(row)
+	}
+	return events
+}
+
+func signatureEventFromRow(row database.SignatureEvent) models.SignatureEvent {
+	return models.SignatureEvent{
+		ID:         int64(row.ID),
+		DocumentID: int64(row.DocumentID),
+		RequestID:  int4ToInt64(row.RequestID),
+		Kind:       models.SignatureEventKind(row.Kind),
+		ActorID:    row.ActorID,
+		IPAddress:  row.IpAddress,
+		UserAgent:  row.UserAgent,
+		CreatedAt:  row.CreatedAt.Time,
+	}
+}
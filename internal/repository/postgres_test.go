@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/migrate"
+	"russ-rentals/migrations"
+)
+
+// postgresTestStore connects to the database in TEST_DATABASE_URL and
+// migrates it, skipping the test when it is unset
+func postgresTestStore(t *testing.T) *Store {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	db, err := database.Connect(ctx, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(db.Close)
+	all, err := migrate.Load(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrate.New(db, all).Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return NewPostgresStore(db)
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresUserRepository stores role assignments in the users table
+type PostgresUserRepository struct {
+	q *database.Queries
+}
+
+// NewPostgresUserRepository creates a UserRepository backed by db
+func NewPostgresUserRepository(db *database.DB) *PostgresUserRepository {
+	return &PostgresUserRepository{q: database.New(db.Pool)}
+}
+
+func (r *PostgresUserRepository) GetByClerkID(ctx context.Context, clerkUserID string) (*models.User, error) {
+	row, err := r.q.GetUserByClerkID(ctx, clerkUserID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	user := userFromRow(row)
+	return &user, nil
+}
+
+func (r *PostgresUserRepository) List(ctx context.Context) ([]models.User, error) {
+	rows, err := r.q.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]models.User, len(rows))
+	for i, row := range rows {
+		users[i] = userFromRow(row)
+	}
+	return users, nil
+}
+
+func (r *PostgresUserRepository) SetRole(ctx context.Context, clerkUserID, email string, role models.Role) (*models.User, error) {
+	row, err := r.q.UpsertUserRole(ctx, database.UpsertUserRoleParams{
+		ClerkUserID: clerkUserID,
+		Email:       textOrNull(email),
+		Role:        database.UserRole(role),
+	})
+	if err != nil {
+		return nil, err
+	}
+	user := userFromRow(row)
+	return &user, nil
+}
+
+func (r *PostgresUserRepository) Delete(ctx context.Context, clerkUserID string) error {
+	return r.q.DeleteUser(ctx, clerkUserID)
+}
+
+func (r *PostgresUserRepository) CalendarKey(ctx context.Context, clerkUserID string) (string, error) {
+	key, err := r.q.GetCalendarKey(ctx, clerkUserID)
+	if err != nil {
+		return "", notFound(err)
+	}
+	return key, nil
+}
+
+func (r *PostgresUserRepository) EnsureCalendarKey(ctx context.Context, clerkUserID, key string) (string, error) {
+	return r.q.EnsureCalendarKey(ctx, database.EnsureCalendarKeyParams{UserID: clerkUserID, Key: key})
+}
+
+func (r *PostgresUserRepository) SetCalendarKey(ctx context.Context, clerkUserID, key string) error {
+	return r.q.SetCalendarKey(ctx, database.SetCalendarKeyParams{UserID: clerkUserID, Key: key})
+}
+
+func userFromRow(row database.User) models.User {
+	return models.User{
+		ID:          int64(row.ID),
+		ClerkUserID: row.ClerkUserID,
+		Email:       row.Email.String,
+		Role:        models.Role(row.Role),
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+}
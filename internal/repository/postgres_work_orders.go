@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// PostgresVendorRepository stores the vendor directory in the vendors table
+type PostgresVendorRepository struct {
+	q *database.Queries
+}
+
+// NewPostgresVendorRepository creates a VendorRepository backed by db
+func NewPostgresVendorRepository(db *database.DB) *PostgresVendorRepository {
+	return &PostgresVendorRepository{q: database.New(db.Pool)}
+}
+
+func (r *PostgresVendorRepository) Create(ctx context.Context, v *models.Vendor) error {
+	row, err := r.q.CreateVendor(ctx, database.CreateVendorParams{
+		Name:            v.Name,
+		Trades:          tradesToStrings(v.Trades),
+		ContactName:     v.ContactName,
+		Email:           v.Email,
+		Phone:           v.Phone,
+		HourlyRateCents: v.HourlyRateCents,
+		CalloutFeeCents: v.CalloutFeeCents,
+		Notes:           v.Notes,
+		Active:          v.Active,
+	})
+	if err != nil {
+		return err
+	}
+	*v = vendorFromRow(row)
+	return nil
+}
+
+func (r *PostgresVendorRepository) Get(ctx context.Context, id int64) (*models.Vendor, error) {
+	row, err := r.q.GetVendor(ctx, int32(id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	v := vendorFromRow(row)
+	return &v, nil
+}
+
+func (r *PostgresVendorRepository) List(ctx context.Context) ([]models.Vendor, error) {
+	rows, err := r.q.ListVendors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vendors := make([]models.Vendor, len(rows))
+	for i, row := range rows {
+		vendors[i] = vendorFromRow(row)
+	}
+	return vendors, nil
+}
+
+func (r *PostgresVendorRepository) Update(ctx context.Context, v *models.Vendor) error {
+	row, err := r.q.UpdateVendor(ctx, database.UpdateVendorParams{
+		ID:              int32(v.ID),
+		Name:            v.Name,
+		Trades:          tradesToStrings(v.Trades),
+		ContactName:     v.ContactName,
+		Email:           v.Email,
+		Phone:           v.Phone,
+		HourlyRateCents: v.HourlyRateCents,
+		CalloutFeeCents: v.CalloutFeeCents,
+		Notes:           v.Notes,
+		Active:          v.Active,
+	})
+	if err != nil {
+		return notFound(err)
+	}
+	*v = vendorFromRow(row)
+	return nil
+}
+
+func tradesToStrings(trades []models.MaintenanceCategory) []string {
+	out := make([]string, len(trades))
+	for i, t := range trades {
+		out[i] = string(t)
+	}
+	return out
+}
+
+func vendorFromRow(row database.Vendor) models.Vendor {
+	trades := make([]models.MaintenanceCategory, len(row.Trades))
+	for i, t := range row.Trades {
+		trades[i] = models.MaintenanceCategory(t)
+	}
+	return models.Vendor{
+		ID:              int64(row.ID),
+		Name:            row.Name,
+		Trades:          trades,
+		ContactName:     row.ContactName,
+		Email:           row.Email,
+		Phone:           row.Phone,
+		HourlyRateCents: row.HourlyRateCents,
+		CalloutFeeCents: row.CalloutFeeCents,
+		Notes:           row.Notes,
+		Active:          row.Active,
+		CreatedAt:       row.CreatedAt.Time,
+		UpdatedAt:       row.UpdatedAt.Time,
+	}
+}
+
+// PostgresWorkOrderRepository stores work orders in the work_orders table
+type PostgresWorkOrderRepository struct {
+	db *database.DB
+	q  *database.Queries
+}
+
+// NewPostgresWorkOrderRepository creates a WorkOrderRepository backed by db
+func NewPostgresWorkOrderRepository(db *database.DB) *PostgresWorkOrderRepository {
+	return &PostgresWorkOrderRepository{db: db, q: database.New(db.Pool)}
+}
+
+func (r *PostgresWorkOrderRepository) Create(ctx context.Context, o *models.WorkOrder) error {
+	row, err := r.q.CreateWorkOrder(ctx, database.CreateWorkOrderParams{
+		RequestID:     int32(o.RequestID),
+		PropertyID:    int32(o.PropertyID),
+		VendorID:      int32(o.VendorID),
+		Scope:         o.Scope,
+		ScheduledOn:   timeToDate(o.ScheduledOn),
+		EstimateCents: o.EstimateCents,
+		BillTo:        database.WorkOrderBillTo(o.BillTo),
+		CreatedBy:     o.CreatedBy,
+	})
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	*o = workOrderFromRow(row)
+	return nil
+}
+
+func (r *PostgresWorkOrderRepository) Get(ctx context.Context, id int64) (*models.WorkOrder, error) {
+	row, err := r.q.GetWorkOrder(ctx, int32(id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	o := workOrderFromRow(row)
+	return &o, nil
+}
+
+func (r *PostgresWorkOrderRepository) ListByRequest(ctx context.Context, requestID int64) ([]models.WorkOrder, error) {
+	rows, err := r.q.ListWorkOrdersByRequest(ctx, int32(requestID))
+	if err != nil {
+		return nil, err
+	}
+	return workOrdersFromRows(rows), nil
+}
+
+func (r *PostgresWorkOrderRepository) Filter(ctx context.Context, filter WorkOrderFilter) ([]models.WorkOrder, error) {
+	rows, err := r.q.FilterWorkOrders(ctx, database.FilterWorkOrdersParams{
+		PropertyFilter: int32(filter.PropertyID),
+		VendorFilter:   int32(filter.VendorID),
+		StatusFilter:   string(filter.Status),
+		BillToFilter:   string(filter.BillTo),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return workOrdersFromRows(rows), nil
+}
+
+func (r *PostgresWorkOrderRepository) Update(ctx context.Context, o *models.WorkOrder) error {
+	row, err := r.q.UpdateWorkOrder(ctx, database.UpdateWorkOrderParams{
+		ID:            int32(o.ID),
+		Scope:         o.Scope,
+		ScheduledOn:   timeToDate(o.ScheduledOn),
+		EstimateCents: o.EstimateCents,
+		BillTo:        database.WorkOrderBillTo(o.BillTo),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return r.missing(ctx, o.ID)
+	}
+	if err != nil {
+		return err
+	}
+	*o = workOrderFromRow(row)
+	return nil
+}
+
+func (r *PostgresWorkOrderRepository) Complete(ctx context.Context, id int64, notes string) (*models.WorkOrder, error) {
+	return r.setStatus(ctx, id, models.WorkOrderStatusCompleted, notes, time.Now())
+}
+
+func (r *PostgresWorkOrderRepository) Cancel(ctx context.Context, id int64) (*models.WorkOrder, error) {
+	return r.setStatus(ctx, id, models.WorkOrderStatusCancelled, "", time.Time{})
+}
+
+func (r *PostgresWorkOrderRepository) setStatus(ctx context.Context, id int64, status models.WorkOrderStatus, notes string, completedAt time.Time) (*models.WorkOrder, error) {
+	row, err := r.q.SetWorkOrderStatus(ctx, database.SetWorkOrderStatusParams{
+		Status:          database.WorkOrderStatus(status),
+		CompletionNotes: notes,
+		CompletedAt:     timeToTimestamp(completedAt),
+		ID:              int32(id),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, r.missing(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	o := workOrderFromRow(row)
+	return &o, nil
+}
+
+func (r *PostgresWorkOrderRepository) RecordInvoice(ctx context.Context, o *models.WorkOrder) error {
+	row, err := r.q.RecordWorkOrderInvoice(ctx, database.RecordWorkOrderInvoiceParams{
+		ID:            int32(o.ID),
+		InvoiceNumber: o.InvoiceNumber,
+		InvoiceCents:  o.InvoiceCents,
+		InvoicedOn:    timeToDate(o.InvoicedOn),
+		BillTo:        database.WorkOrderBillTo(o.BillTo),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return r.missing(ctx, o.ID)
+	}
+	if err != nil {
+		return err
+	}
+	*o = workOrderFromRow(row)
+	return nil
+}
+
+func (r *PostgresWorkOrderRepository) ChargeTenant(ctx context.Context, id int64, e *models.LedgerEntry) (*models.WorkOrder, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := database.New(tx)
+	entry, err := q.CreateLedgerEntry(ctx, database.CreateLedgerEntryParams(ledgerEntryParams(e)))
+	if isForeignKeyViolation(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	row, err := q.SetWorkOrderLedgerEntry(ctx, database.SetWorkOrderLedgerEntryParams{
+		ID:            int32(id),
+		LedgerEntryID: pgtype.Int4{Int32: entry.ID, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, r.missing(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	*e = ledgerEntryFromRow(entry)
+	o := workOrderFromRow(row)
+	return &o, nil
+}
+
+// missing explains why a conditional update of work order id matched no
+// row: either it doesn't exist or its status moved on
+func (r *PostgresWorkOrderRepository) missing(ctx context.Context, id int64) error {
+	if _, err := r.q.GetWorkOrder(ctx, int32(id)); err != nil {
+		return notFound(err)
+	}
+	return ErrStatusChanged
+}
+
+func workOrdersFromRows(rows []database.WorkOrder) []models.WorkOrder {
+	orders := make([]models.WorkOrder, len(rows))
+	for i, row := range rows {
+		orders[i] = workOrderFromRow(row)
+	}
+	return orders
+}
+
+func workOrderFromRow(row database.WorkOrder) models.WorkOrder {
+	return models.WorkOrder{
+		ID:              int64(row.ID),
+		RequestID:       int64(row.RequestID),
+		PropertyID:      int64(row.PropertyID),
+		VendorID:        int64(row.VendorID),
+		Status:          models.WorkOrderStatus(row.Status),
+		Scope:           row.Scope,
+		ScheduledOn:     dateToTime(row.ScheduledOn),
+		EstimateCents:   row.EstimateCents,
+		BillTo:          models.WorkOrderBillTo(row.BillTo),
+		CompletionNotes: row.CompletionNotes,
+		CompletedAt:     timestampToTime(row.CompletedAt),
+		InvoiceNumber:   row.InvoiceNumber,
+		InvoiceCents:    row.InvoiceCents,
+		InvoicedOn:      dateToTime(row.InvoicedOn),
+		LedgerEntryID:   int4ToInt64(row.LedgerEntryID),
+		CreatedBy:       row.CreatedBy,
+		CreatedAt:       row.CreatedAt.Time,
+		UpdatedAt:       row.UpdatedAt.Time,
+	}
+}
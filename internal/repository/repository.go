@@ -0,0 +1,507 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// ErrNotFound is returned when a requested record does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrSlugTaken is returned when a property is created with a slug that is
+// already in use
+var ErrSlugTaken = errors.New("slug already in use")
+
+// ErrSlotTaken is returned when a showing would overlap another active
+// showing of the same property or agent
+var ErrSlotTaken = errors.New("showing slot already booked")
+
+// ErrStatusChanged is returned when a record's status changed after it was
+// read, so the requested update no longer applies
+var ErrStatusChanged = errors.New("status changed")
+
+// ErrInUse is returned when a record can't be deleted because other
+// records still refer to it
+var ErrInUse = errors.New("still in use")
+
+// ErrLocked is returned when another process holds a lock
+var ErrLocked = errors.New("locked by another process")
+
+// ErrDuplicateRun is returned when a scheduled job already ran for the
+// same scheduled time
+var ErrDuplicateRun = errors.New("job already ran for that time")
+
+// Storage drivers accepted by Open
+const (
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+)
+
+// PropertyFilter narrows a property listing. Zero values mean "no filter".
+type PropertyFilter struct {
+	Type        string
+	MinPrice    int
+	MaxPrice    int
+	MinBedrooms int
+}
+
+// InquiryFilter narrows the inquiry inbox. Zero values mean "no filter".
+// ReceivedFrom is inclusive and ReceivedBefore exclusive.
+type InquiryFilter struct {
+	PropertyID     int64
+	Type           models.InquiryType
+	Status         models.InquiryStatus
+	AssigneeID     string
+	ReceivedFrom   time.Time
+	ReceivedBefore time.Time
+}
+
+// ApplicationFilter narrows the staff application list. Zero values mean
+// "no filter".
+type ApplicationFilter struct {
+	PropertyID int64
+	Status     models.ApplicationStatus
+}
+
+// MaintenanceFilter narrows the staff maintenance queue. Zero values mean
+// "no filter".
+type MaintenanceFilter struct {
+	PropertyID int64
+	Status     models.MaintenanceStatus
+	Urgency    models.MaintenanceUrgency
+	AssigneeID string
+}
+
+// WorkOrderFilter narrows the staff work order list. Zero values mean "no
+// filter".
+type WorkOrderFilter struct {
+	PropertyID int64
+	VendorID   int64
+	Status     models.WorkOrderStatus
+	BillTo     models.WorkOrderBillTo
+}
+
+// LeaseFilter narrows the staff lease list. Zero values mean "no filter".
+type LeaseFilter struct {
+	PropertyID int64
+	Status     models.LeaseStatus
+}
+
+// PropertyRepository manages property listings and their images
+type PropertyRepository interface {
+	List(ctx context.Context) ([]models.Property, error)
+	ListAvailable(ctx context.Context) ([]models.Property, error)
+	Filter(ctx context.Context, filter PropertyFilter) ([]models.Property, error)
+	Featured(ctx context.Context, limit int) ([]models.Property, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Property, error)
+	GetByID(ctx context.Context, id int64) (*models.Property, error)
+	Images(ctx context.Context, propertyID int64) ([]models.PropertyImage, error)
+	ImagesByRoom(ctx context.Context, propertyID int64, room models.RoomType) ([]models.PropertyImage, error)
+	// Create inserts p and fills in its ID and timestamps. Images are not
+	// saved.
+	Create(ctx context.Context, p *models.Property) error
+	// Update saves every listing field of p except its slug and images
+	Update(ctx context.Context, p *models.Property) error
+	// Delete removes a property along with its images and showings. It
+	// returns ErrInUse if the property has applications or leases.
+	Delete(ctx context.Context, id int64) error
+	// AddImage inserts img and fills in its ID and CreatedAt
+	AddImage(ctx context.Context, img *models.PropertyImage) error
+	// UpdateImages saves the caption, room and display order of each of
+	// images in a single transaction. images must be exactly the
+	// property's photos, each once; otherwise, as when another admin added
+	// or removed one meanwhile, it returns ErrNotFound and saves nothing.
+	UpdateImages(ctx context.Context, propertyID int64, images []models.PropertyImage) error
+}
+
+// ContactRepository stores contact form submissions
+type ContactRepository interface {
+	// Create inserts sub and fills in its ID and CreatedAt
+	Create(ctx context.Context, sub *models.ContactSubmission) error
+	Get(ctx context.Context, id int64) (*models.ContactSubmission, error)
+	List(ctx context.Context) ([]models.ContactSubmission, error)
+	ListByProperty(ctx context.Context, propertyID int64) ([]models.ContactSubmission, error)
+	ListByEmail(ctx context.Context, email string) ([]models.ContactSubmission, error)
+	Delete(ctx context.Context, id int64) error
+	// Filter lists the submissions matching filter, newest first
+	Filter(ctx context.Context, filter InquiryFilter) ([]models.ContactSubmission, error)
+	// SetStatus changes a submission's status and records the change on its
+	// timeline, in one transaction
+	SetStatus(ctx context.Context, id int64, status models.InquiryStatus, actorID string) (*models.ContactSubmission, error)
+	// Assign hands a submission to assigneeID, or unassigns it when
+	// assigneeID is blank, and records the change on its timeline
+	Assign(ctx context.Context, id int64, assigneeID, actorID string) (*models.ContactSubmission, error)
+	// AddNote adds an internal note to a submission's timeline
+	AddNote(ctx context.Context, id int64, note, actorID string) (*models.InquiryEvent, error)
+	// Timeline lists a submission's events, oldest first
+	Timeline(ctx context.Context, id int64) ([]models.InquiryEvent, error)
+}
+
+// NewsletterRepository stores newsletter subscribers
+type NewsletterRepository interface {
+	// Subscribe adds email as pending confirmation. Active subscribers stay
+	// active; unsubscribed ones go back to pending.
+	Subscribe(ctx context.Context, email, firstName string) (*models.NewsletterSubscriber, error)
+	Get(ctx context.Context, email string) (*models.NewsletterSubscriber, error)
+	// Confirm activates a pending subscriber. It returns ErrNotFound if email
+	// is not pending.
+	Confirm(ctx context.Context, email string) (*models.NewsletterSubscriber, error)
+	Unsubscribe(ctx context.Context, email string) error
+	ListActive(ctx context.Context) ([]models.NewsletterSubscriber, error)
+	CountActive(ctx context.Context) (int64, error)
+}
+
+// UserRepository stores local role assignments for Clerk users
+type UserRepository interface {
+	GetByClerkID(ctx context.Context, clerkUserID string) (*models.User, error)
+	List(ctx context.Context) ([]models.User, error)
+	// SetRole creates or updates the user's record. A blank email keeps the
+	// one already stored.
+	SetRole(ctx context.Context, clerkUserID, email string, role models.Role) (*models.User, error)
+	// Delete removes the user's record and calendar key
+	Delete(ctx context.Context, clerkUserID string) error
+	// CalendarKey returns the key the user's calendar feed links are
+	// signed with, or ErrNotFound if they have none
+	CalendarKey(ctx context.Context, clerkUserID string) (string, error)
+	// EnsureCalendarKey returns the user's calendar key. If they have none
+	// yet, key is stored as theirs.
+	EnsureCalendarKey(ctx context.Context, clerkUserID, key string) (string, error)
+	// SetCalendarKey replaces the user's calendar key, revoking the feed
+	// links signed with the old one
+	SetCalendarKey(ctx context.Context, clerkUserID, key string) error
+}
+
+// ShowingRepository stores showing availability and bookings
+type ShowingRepository interface {
+	// CreateWindow inserts w and fills in its ID and CreatedAt
+	CreateWindow(ctx context.Context, w *models.ShowingWindow) error
+	// Windows lists the windows ending after after, earliest first. A zero
+	// propertyID lists every property's windows.
+	Windows(ctx context.Context, propertyID int64, after time.Time) ([]models.ShowingWindow, error)
+	DeleteWindow(ctx context.Context, id int64) error
+	// Book inserts s as pending and fills in its ID, Status and timestamps.
+	// It returns ErrSlotTaken if s overlaps an active showing of the same
+	// property or agent.
+	Book(ctx context.Context, s *models.Showing) error
+	Get(ctx context.Context, id int64) (*models.Showing, error)
+	// Upcoming lists the showings starting at or after from, earliest first
+	Upcoming(ctx context.Context, from time.Time) ([]models.Showing, error)
+	// Feed lists the showings starting at or after since, cancelled ones
+	// included, earliest first. A zero propertyID or empty agentID matches
+	// any.
+	Feed(ctx context.Context, propertyID int64, agentID string, since time.Time) ([]models.Showing, error)
+	// Busy lists the active showings of propertyID or any of agentIDs that
+	// overlap [since, until)
+	Busy(ctx context.Context, propertyID int64, agentIDs []string, since, until time.Time) ([]models.Showing, error)
+	SetStatus(ctx context.Context, id int64, status models.ShowingStatus) (*models.Showing, error)
+}
+
+// ApplicationRepository stores rental applications and their status history
+type ApplicationRepository interface {
+	// StartDraft returns applicantID's draft for propertyID, creating one
+	// with data if they have none
+	StartDraft(ctx context.Context, propertyID int64, applicantID string, data models.ApplicationData) (*models.Application, error)
+	Get(ctx context.Context, id int64) (*models.Application, error)
+	// ListByApplicant lists applicantID's applications, drafts included,
+	// most recently updated first
+	ListByApplicant(ctx context.Context, applicantID string) ([]models.Application, error)
+	// Filter lists submitted applications, most recently submitted first.
+	// Drafts are never listed.
+	Filter(ctx context.Context, filter ApplicationFilter) ([]models.Application, error)
+	// SaveDraft replaces a draft's answers. It returns ErrStatusChanged if
+	// the application is no longer a draft.
+	SaveDraft(ctx context.Context, id int64, data models.ApplicationData) (*models.Application, error)
+	// SetStatus moves an application from status from to status to and
+	// records the change on its timeline. It returns ErrStatusChanged if the
+	// application's status is no longer from.
+	SetStatus(ctx context.Context, id int64, from, to models.ApplicationStatus, actorID, note string) (*models.Application, error)
+	Timeline(ctx context.Context, id int64) ([]models.ApplicationEvent, error)
+}
+
+// PaymentRepository records payments taken through the payment gateway
+type PaymentRepository interface {
+	// Create inserts p as pending and fills in its ID, Status and timestamps
+	Create(ctx context.Context, p *models.Payment) error
+	Get(ctx context.Context, id int64) (*models.Payment, error)
+	GetByIntent(ctx context.Context, intentID string) (*models.Payment, error)
+	// ListByApplication lists an application's payments, newest first
+	ListByApplication(ctx context.Context, applicationID int64) ([]models.Payment, error)
+	// ListByLease lists the payments made against a lease, newest first
+	ListByLease(ctx context.Context, leaseID int64) ([]models.Payment, error)
+	// SetStatus records the outcome of an attempt to pay. Payments that
+	// already went through are returned unchanged.
+	SetStatus(ctx context.Context, id int64, status models.PaymentStatus, failureMessage string) (*models.Payment, error)
+	// SetRefunded records the total refunded from a paid payment, marking
+	// it refunded once all of it is. The total never goes down, so
+	// repeated or out-of-order notices are harmless. Payments that weren't
+	// paid give ErrStatusChanged.
+	SetRefunded(ctx context.Context, id int64, refundedCents int64) (*models.Payment, error)
+}
+
+// LeaseRepository stores leases and their tenants
+type LeaseRepository interface {
+	// Create inserts l with its tenants and fills in its ID and timestamps
+	Create(ctx context.Context, l *models.Lease) error
+	Get(ctx context.Context, id int64) (*models.Lease, error)
+	// Filter lists the leases matching filter, latest start first
+	Filter(ctx context.Context, filter LeaseFilter) ([]models.Lease, error)
+	// ListByTenant lists the leases userID is a tenant on, drafts included,
+	// latest start first
+	ListByTenant(ctx context.Context, userID string) ([]models.Lease, error)
+	// Update saves a draft's terms and replaces its tenants. It returns
+	// ErrStatusChanged if the lease is no longer a draft.
+	Update(ctx context.Context, l *models.Lease) error
+	// SetStatus moves a lease from status from to status to. It returns
+	// ErrStatusChanged if the lease's status is no longer from.
+	SetStatus(ctx context.Context, id int64, from, to models.LeaseStatus) (*models.Lease, error)
+	// MarkNotified records that the notice named notice about day was sent
+	// to a lease's tenants. It reports false if it already was.
+	MarkNotified(ctx context.Context, leaseID int64, notice string, day time.Time) (bool, error)
+	// ClearNotified undoes MarkNotified, for a notice that failed to send
+	ClearNotified(ctx context.Context, leaseID int64, notice string, day time.Time) error
+}
+
+// LedgerRepository stores lease ledgers
+type LedgerRepository interface {
+	// Create posts e and fills in its ID and CreatedAt. It returns
+	// ErrNotFound if the lease doesn't exist.
+	Create(ctx context.Context, e *models.LedgerEntry) error
+	// PostScheduled posts the entries whose lease, kind and period aren't
+	// on the ledger yet, and returns how many it posted. Late fees are
+	// matched on the day they're posted as well.
+	PostScheduled(ctx context.Context, entries []models.LedgerEntry) (int, error)
+	// PostPayment posts e, which records the gateway payment e.PaymentID,
+	// unless that payment is on the ledger already. It reports whether it
+	// posted e.
+	PostPayment(ctx context.Context, e *models.LedgerEntry) (bool, error)
+	// ListByLease lists a lease's entries, oldest first
+	ListByLease(ctx context.Context, leaseID int64) (models.Ledger, error)
+}
+
+// LateFeeRepository stores late fee policies and the properties that use
+// them. A property uses at most one policy.
+type LateFeeRepository interface {
+	// Create inserts p, fills in its ID and timestamps, and makes it the
+	// policy of p.PropertyIDs
+	Create(ctx context.Context, p *models.LateFeePolicy) error
+	Get(ctx context.Context, id int64) (*models.LateFeePolicy, error)
+	// List lists every policy by name
+	List(ctx context.Context) ([]models.LateFeePolicy, error)
+	// Update saves p and makes it the policy of exactly p.PropertyIDs
+	Update(ctx context.Context, p *models.LateFeePolicy) error
+	// Delete removes a policy. Properties and leases using it are left
+	// without one.
+	Delete(ctx context.Context, id int64) error
+}
+
+// AutopayRepository stores autopay enrollments, at most one per lease
+type AutopayRepository interface {
+	// Enroll saves a, replacing any enrollment its lease already has, and
+	// fills in its ID and timestamps. Attempts, RetryOn and LastError
+	// start over.
+	Enroll(ctx context.Context, a *models.Autopay) error
+	GetByLease(ctx context.Context, leaseID int64) (*models.Autopay, error)
+	// List lists every enrollment
+	List(ctx context.Context) ([]models.Autopay, error)
+	// SaveAttempt saves the outcome of a's latest charge: its Period,
+	// Attempts, RetryOn and LastError
+	SaveAttempt(ctx context.Context, a *models.Autopay) error
+	// Cancel removes a lease's enrollment
+	Cancel(ctx context.Context, leaseID int64) error
+}
+
+// MaintenanceRepository stores maintenance requests, their photos and
+// their timelines
+type MaintenanceRepository interface {
+	// Create inserts r as open with its photos, and fills in their IDs and
+	// timestamps. It returns ErrNotFound if the lease doesn't exist.
+	Create(ctx context.Context, r *models.MaintenanceRequest) error
+	Get(ctx context.Context, id int64) (*models.MaintenanceRequest, error)
+	// ListByLease lists a lease's requests, newest first
+	ListByLease(ctx context.Context, leaseID int64) ([]models.MaintenanceRequest, error)
+	// Filter lists the requests matching filter, newest first
+	Filter(ctx context.Context, filter MaintenanceFilter) ([]models.MaintenanceRequest, error)
+	// SetStatus changes a request's status, noting when it was resolved, and
+	// records the change on its timeline, in one transaction
+	SetStatus(ctx context.Context, id int64, status models.MaintenanceStatus, actorID string) (*models.MaintenanceRequest, error)
+	// Schedule sets when a request's visit is, or clears it when at is nil,
+	// and records the change on its timeline
+	Schedule(ctx context.Context, id int64, at *time.Time, actorID string) (*models.MaintenanceRequest, error)
+	// Assign hands a request to assigneeID, or unassigns it when assigneeID
+	// is blank, and records the change on its timeline
+	Assign(ctx context.Context, id int64, assigneeID, actorID string) (*models.MaintenanceRequest, error)
+	// SetVendor records who is doing the work and notes the change on the
+	// request's timeline
+	SetVendor(ctx context.Context, id int64, vendor, actorID string) (*models.MaintenanceRequest, error)
+	// AddEvent adds a message to the tenant or an internal note to a
+	// request's timeline
+	AddEvent(ctx context.Context, id int64, kind models.MaintenanceEventKind, text, actorID string) (*models.MaintenanceEvent, error)
+	// Timeline lists a request's events, oldest first
+	Timeline(ctx context.Context, id int64) ([]models.MaintenanceEvent, error)
+}
+
+// JobRepository records background job runs and keeps jobs from running
+// on more than one server at once
+type JobRepository interface {
+	// TryLock takes the lock for the job named name across every server
+	// sharing the store. It returns a func that releases it, or ErrLocked if
+	// another process holds it.
+	TryLock(ctx context.Context, name string) (release func(), err error)
+	// StartRun records run as running and fills in its ID, Status and
+	// StartedAt. Scheduled runs give ErrDuplicateRun if the job already ran
+	// for run.ScheduledFor.
+	StartRun(ctx context.Context, run *models.JobRun) error
+	// FinishRun records how a run ended
+	FinishRun(ctx context.Context, id int64, status models.JobRunStatus, summary, errMsg string) (*models.JobRun, error)
+	// ListRuns lists the latest limit runs of the job named job, or of every
+	// job if job is blank, newest first
+	ListRuns(ctx context.Context, job string, limit int) ([]models.JobRun, error)
+}
+
+// VendorRepository stores the vendor directory
+type VendorRepository interface {
+	// Create inserts v and fills in its ID and timestamps
+	Create(ctx context.Context, v *models.Vendor) error
+	Get(ctx context.Context, id int64) (*models.Vendor, error)
+	// List lists every vendor by name, inactive ones included
+	List(ctx context.Context) ([]models.Vendor, error)
+	Update(ctx context.Context, v *models.Vendor) error
+}
+
+// WorkOrderRepository stores the work orders issued for maintenance
+// requests
+type WorkOrderRepository interface {
+	// Create inserts o as open and fills in its ID and timestamps. It
+	// returns ErrNotFound if the request or vendor doesn't exist.
+	Create(ctx context.Context, o *models.WorkOrder) error
+	Get(ctx context.Context, id int64) (*models.WorkOrder, error)
+	// ListByRequest lists a request's work orders, oldest first
+	ListByRequest(ctx context.Context, requestID int64) ([]models.WorkOrder, error)
+	// Filter lists the work orders matching filter, newest first
+	Filter(ctx context.Context, filter WorkOrderFilter) ([]models.WorkOrder, error)
+	// Update saves an open order's scope, schedule, estimate and who it's
+	// billed to. It returns ErrStatusChanged if the order isn't open.
+	Update(ctx context.Context, o *models.WorkOrder) error
+	// Complete marks an open order completed with the vendor's notes, and
+	// Cancel marks it cancelled. Both return ErrStatusChanged if the order
+	// isn't open.
+	Complete(ctx context.Context, id int64, notes string) (*models.WorkOrder, error)
+	Cancel(ctx context.Context, id int64) (*models.WorkOrder, error)
+	// RecordInvoice saves the vendor's invoice for a completed order and
+	// who it's billed to. It returns ErrStatusChanged if the order isn't
+	// completed or was already charged to the tenant.
+	RecordInvoice(ctx context.Context, o *models.WorkOrder) error
+	// ChargeTenant posts e to the tenant's ledger and links it to the
+	// order, in one transaction. It returns ErrStatusChanged unless the
+	// order is models.WorkOrder.Chargeable.
+	ChargeTenant(ctx context.Context, id int64, e *models.LedgerEntry) (*models.WorkOrder, error)
+}
+
+// DocumentRepository stores the files in each lease's document vault and
+// the log of who opened them
+type DocumentRepository interface {
+	// Create inserts d and fills in its ID and CreatedAt. It returns
+	// ErrNotFound if the lease doesn't exist.
+	Create(ctx context.Context, d *models.Document) error
+	Get(ctx context.Context, id int64) (*models.Document, error)
+	// ListByLease lists a lease's documents, newest first
+	ListByLease(ctx context.Context, leaseID int64) ([]models.Document, error)
+	// LogAccess records that a document was served and fills in a's ID and
+	// AccessedAt
+	LogAccess(ctx context.Context, a *models.DocumentAccess) error
+	// ListAccessesByLease lists every access to a lease's documents, newest
+	// first
+	ListAccessesByLease(ctx context.Context, leaseID int64) ([]models.DocumentAccess, error)
+}
+
+// SignatureRepository stores the requests for parties to sign generated
+// documents, and each document's signing audit trail
+type SignatureRepository interface {
+	// Request inserts requests as pending, fills in their IDs, statuses and
+	// CreatedAt, and records sending each one as sent says, in one
+	// transaction. Every request must be for documentID. It returns
+	// ErrStatusChanged if the document already has requests that weren't
+	// voided, or a signed copy, and ErrNotFound if it doesn't exist.
+	Request(ctx context.Context, documentID int64, requests []models.SignatureRequest, sent models.SignatureEvent) error
+	Get(ctx context.Context, id int64) (*models.SignatureRequest, error)
+	// ListByDocument lists a document's requests, voided ones included,
+	// oldest first
+	ListByDocument(ctx context.Context, documentID int64) ([]models.SignatureRequest, error)
+	// ListByLease lists the requests for every document of a lease, voided
+	// ones included, oldest first
+	ListByLease(ctx context.Context, leaseID int64) ([]models.SignatureRequest, error)
+	// Sign saves r's signature, marks it signed and fills in SignedAt, and
+	// records the signing, in one transaction. It reports whether every
+	// current request for the document is now signed, which is true for
+	// exactly one signer. It returns ErrStatusChanged if r isn't pending.
+	Sign(ctx context.Context, r *models.SignatureRequest) (complete bool, err error)
+	// Void withdraws a document's current requests, signed or not, and
+	// records it as voided says. It returns ErrStatusChanged if the
+	// document has no current requests or already has a signed copy.
+	Void(ctx context.Context, documentID int64, voided models.SignatureEvent) error
+	// Complete inserts signed as the signed copy of a document, links the
+	// document to it and records the completion, in one transaction. It
+	// returns ErrStatusChanged unless every current request for the
+	// document is signed and it has no signed copy yet.
+	Complete(ctx context.Context, documentID int64, signed *models.Document) error
+	// LogEvent adds e to its document's audit trail and fills in its ID
+	// and CreatedAt
+	LogEvent(ctx context.Context, e *models.SignatureEvent) error
+	// EventsByDocument lists a document's audit trail, oldest first
+	EventsByDocument(ctx context.Context, documentID int64) ([]models.SignatureEvent, error)
+	// EventsByLease lists the audit trails of a lease's documents, newest
+	// first
+	EventsByLease(ctx context.Context, leaseID int64) ([]models.SignatureEvent, error)
+}
+
+// Store groups the repositories for one storage backend
+type Store struct {
+	Properties   PropertyRepository
+	Contacts     ContactRepository
+	Newsletter   NewsletterRepository
+	Users        UserRepository
+	Showings     ShowingRepository
+	Applications ApplicationRepository
+	Payments     PaymentRepository
+	Leases       LeaseRepository
+	Ledger       LedgerRepository
+	LateFees     LateFeeRepository
+	Autopay      AutopayRepository
+	Maintenance  MaintenanceRepository
+	Vendors      VendorRepository
+	WorkOrders   WorkOrderRepository
+	Documents    DocumentRepository
+	Signatures   SignatureRepository
+	Jobs         JobRepository
+
+	db *database.DB
+}
+
+// Open creates a Store for the given driver. The postgres driver connects to
+// databaseURL; the memory driver starts from the seed properties.
+func Open(ctx context.Context, driver, databaseURL string, seed []models.Property) (*Store, error) {
+	switch driver {
+	case DriverPostgres:
+		db, err := database.Connect(ctx, databaseURL)
+		if err != nil {
+			return nil, err
+		}
+		return NewPostgresStore(db), nil
+	case DriverMemory:
+		return NewMemoryStore(seed), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", driver)
+	}
+}
+
+// Close releases the underlying database connection, if any
+func (s *Store) Close() {
+	if s.db != nil {
+		s.db.Close()
+	}
+}
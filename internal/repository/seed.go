@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"russ-rentals/internal/database"
+	"russ-rentals/internal/models"
+)
+
+// SeedResult counts what Seed did to the properties table
+type SeedResult struct {
+	Created int
+	Updated int
+	Images  int
+}
+
+// Seed upserts properties into Postgres by slug and replaces each one's
+// images, all in a single transaction. Running it again with the same data
+// leaves the database unchanged apart from updated_at and image IDs.
+func Seed(ctx context.Context, db *database.DB, properties []models.Property) (SeedResult, error) {
+	var result SeedResult
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := database.New(tx)
+	for _, p := range properties {
+		params := createPropertyParams(p)
+
+		row, err := q.GetPropertyBySlug(ctx, p.Slug)
+		switch err = notFound(err); {
+		case errors.Is(err, ErrNotFound):
+			row, err = q.CreateProperty(ctx, params)
+			result.Created++
+		case err == nil:
+			row, err = q.UpdateProperty(ctx, updatePropertyParams(row.ID, params))
+			result.Updated++
+		}
+		if err != nil {
+			return SeedResult{}, fmt.Errorf("seed %s: %w", p.Slug, err)
+		}
+
+		if err := q.DeleteImagesByPropertyID(ctx, row.ID); err != nil {
+			return SeedResult{}, fmt.Errorf("seed %s images: %w", p.Slug, err)
+		}
+		for i, img := range p.Images {
+			order := img.DisplayOrder
+			if order == 0 {
+				order = i
+			}
+			_, err := q.CreateImage(ctx, database.CreateImageParams{
+				PropertyID:   row.ID,
+				Url:          img.URL,
+				Caption:      img.Caption,
+				Room:         database.RoomType(img.Room),
+				DisplayOrder: int32(order),
+			})
+			if err != nil {
+				return SeedResult{}, fmt.Errorf("seed %s images: %w", p.Slug, err)
+			}
+			result.Images++
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return SeedResult{}, err
+	}
+	return result, nil
+}
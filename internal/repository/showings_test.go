@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+func TestMemoryShowingBookConflicts(t *testing.T) {
+	store := NewMemoryStore([]models.Property{{ID: 1, Title: "Maple House"}, {ID: 2, Title: "Oak Flat"}})
+	testShowingBookConflicts(t, store, 1, 2)
+}
+
+// TestPostgresShowingBookConflicts runs against the database in
+// TEST_DATABASE_URL. It is skipped when unset.
+func TestPostgresShowingBookConflicts(t *testing.T) {
+	ctx := context.Background()
+	store := postgresTestStore(t)
+	var ids [2]int64
+	for i := range ids {
+		p := models.Property{
+			Slug:  fmt.Sprintf("book-conflicts-%d-%d", time.Now().UnixNano(), i),
+			Title: "Booking test",
+			Type:  models.PropertyTypeHouse,
+		}
+		if err := store.Properties.Create(ctx, &p); err != nil {
+			t.Fatal(err)
+		}
+		// Deleting the property deletes its showings with it
+		t.Cleanup(func() { store.Properties.Delete(ctx, p.ID) })
+		ids[i] = p.ID
+	}
+	testShowingBookConflicts(t, store, ids[0], ids[1])
+}
+
+// testShowingBookConflicts books showings of property and other in turn,
+// checking each store refuses the same double-bookings
+func testShowingBookConflicts(t *testing.T, store *Store, property, other int64) {
+	ctx := context.Background()
+	at := func(hour, min int) time.Time { return time.Date(2030, time.March, 2, hour, min, 0, 0, time.UTC) }
+	// Agent IDs are unique to the run, as agents' showings conflict across
+	// properties
+	agent := func(name string) string { return fmt.Sprintf("%s-%d", name, time.Now().UnixNano()) }
+	ann, bob, cat := agent("ann"), agent("bob"), agent("cat")
+
+	first := models.Showing{PropertyID: property, AgentID: ann, Name: "Vic", Email: "vic@example.com", StartsAt: at(9, 0), EndsAt: at(9, 30)}
+	if err := store.Showings.Book(ctx, &first); err != nil {
+		t.Fatalf("Book() first showing: %v", err)
+	}
+	if first.ID == 0 || first.Status != models.ShowingStatusPending {
+		t.Fatalf("Book() = ID %d status %q, want an ID and pending", first.ID, first.Status)
+	}
+
+	tests := []struct {
+		name     string
+		property int64
+		agent    string
+		start    time.Time
+		end      time.Time
+		want     error
+	}{
+		{"same property, overlapping", property, bob, at(9, 15), at(9, 45), ErrSlotTaken},
+		{"same property, inside", property, bob, at(9, 10), at(9, 20), ErrSlotTaken},
+		{"same agent at another property", other, ann, at(9, 0), at(9, 30), ErrSlotTaken},
+		{"another agent at another property", other, bob, at(9, 0), at(9, 30), nil},
+		{"same property, back to back", property, cat, at(9, 30), at(10, 0), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := models.Showing{PropertyID: tt.property, AgentID: tt.agent, Name: "Vic", Email: "vic@example.com", StartsAt: tt.start, EndsAt: tt.end}
+			if err := store.Showings.Book(ctx, &s); !errors.Is(err, tt.want) {
+				t.Errorf("Book() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := store.Showings.SetStatus(ctx, first.ID, models.ShowingStatusCancelled); err != nil {
+		t.Fatal(err)
+	}
+	again := models.Showing{PropertyID: property, AgentID: cat, Name: "Wren", Email: "wren@example.com", StartsAt: at(9, 0), EndsAt: at(9, 30)}
+	if err := store.Showings.Book(ctx, &again); err != nil {
+		t.Errorf("Book() after cancelling = %v, want the slot free", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Package schedule works out which showing slots are still open from staff
+// availability windows and existing bookings.
+package schedule
+
+import (
+	"sort"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+// Slot is one bookable showing time
+type Slot struct {
+	Start    time.Time
+	End      time.Time
+	WindowID int64
+	AgentID  string
+}
+
+// Day groups the open slots that start on one calendar day
+type Day struct {
+	Date  time.Time
+	Slots []Slot
+}
+
+// OpenSlots splits windows into slots and drops those that start before
+// notBefore or overlap an active showing for the same property or agent.
+// busy must include the showings of every agent in windows. Slots are
+// returned in start order; where windows overlap, slots from windows earlier
+// in the list win.
+func OpenSlots(propertyID int64, windows []models.ShowingWindow, busy []models.Showing, notBefore time.Time) []Slot {
+	var slots []Slot
+	for _, w := range windows {
+		if w.PropertyID != propertyID || w.SlotMinutes <= 0 {
+			continue
+		}
+		for start := w.StartsAt; !start.Add(w.SlotLength()).After(w.EndsAt); start = start.Add(w.SlotLength()) {
+			end := start.Add(w.SlotLength())
+			if start.Before(notBefore) || overlapsSlot(slots, start, end) || isBusy(busy, propertyID, w.AgentID, start, end) {
+				continue
+			}
+			slots = append(slots, Slot{Start: start, End: end, WindowID: w.ID, AgentID: w.AgentID})
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].Start.Before(slots[j].Start) })
+	return slots
+}
+
+// Find returns the open slot starting at start, if there is one
+func Find(slots []Slot, start time.Time) (Slot, bool) {
+	for _, s := range slots {
+		if s.Start.Equal(start) {
+			return s, true
+		}
+	}
+	return Slot{}, false
+}
+
+// ByDay groups slots by their start date in loc
+func ByDay(slots []Slot, loc *time.Location) []Day {
+	var days []Day
+	for _, s := range slots {
+		start := s.Start.In(loc)
+		date := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			days = append(days, Day{Date: date})
+		}
+		days[len(days)-1].Slots = append(days[len(days)-1].Slots, s)
+	}
+	return days
+}
+
+func isBusy(busy []models.Showing, propertyID int64, agentID string, start, end time.Time) bool {
+	for _, s := range busy {
+		if s.Active() && (s.PropertyID == propertyID || s.AgentID == agentID) && s.Overlaps(start, end) {
+			return true
+		}
+	}
+	return false
+}
+
+func overlapsSlot(slots []Slot, start, end time.Time) bool {
+	for _, s := range slots {
+		if s.Start.Before(end) && start.Before(s.End) {
+			return true
+		}
+	}
+	return false
+}
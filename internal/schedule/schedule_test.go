@@ -0,0 +1,122 @@
+package schedule
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"russ-rentals/internal/models"
+)
+
+func TestOpenSlots(t *testing.T) {
+	at := func(hour, min int) time.Time { return time.Date(2026, time.March, 2, hour, min, 0, 0, time.UTC) }
+	window := func(id int64, agent string, from, to time.Time, minutes int) models.ShowingWindow {
+		return models.ShowingWindow{ID: id, PropertyID: 1, AgentID: agent, StartsAt: from, EndsAt: to, SlotMinutes: minutes}
+	}
+	showing := func(property int64, agent string, from, to time.Time, status models.ShowingStatus) models.Showing {
+		return models.Showing{PropertyID: property, AgentID: agent, StartsAt: from, EndsAt: to, Status: status}
+	}
+	morning := window(1, "ann", at(9, 0), at(11, 0), 30)
+
+	tests := []struct {
+		name      string
+		windows   []models.ShowingWindow
+		busy      []models.Showing
+		notBefore time.Time
+		want      []time.Time
+	}{
+		{
+			name:    "window split into slots",
+			windows: []models.ShowingWindow{morning},
+			want:    []time.Time{at(9, 0), at(9, 30), at(10, 0), at(10, 30)},
+		},
+		{
+			name:    "time left at the end of a window too short for a slot",
+			windows: []models.ShowingWindow{window(1, "ann", at(9, 0), at(10, 20), 30)},
+			want:    []time.Time{at(9, 0), at(9, 30)},
+		},
+		{
+			name:      "slots starting before notBefore dropped",
+			windows:   []models.ShowingWindow{morning},
+			notBefore: at(9, 45),
+			want:      []time.Time{at(10, 0), at(10, 30)},
+		},
+		{
+			name:    "booked slot dropped",
+			windows: []models.ShowingWindow{morning},
+			busy:    []models.Showing{showing(1, "bob", at(9, 30), at(10, 0), models.ShowingStatusPending)},
+			want:    []time.Time{at(9, 0), at(10, 0), at(10, 30)},
+		},
+		{
+			name:    "showing across two slots blocks both",
+			windows: []models.ShowingWindow{morning},
+			busy:    []models.Showing{showing(1, "bob", at(9, 15), at(9, 45), models.ShowingStatusConfirmed)},
+			want:    []time.Time{at(10, 0), at(10, 30)},
+		},
+		{
+			name:    "showing ending as a slot starts leaves it open",
+			windows: []models.ShowingWindow{morning},
+			busy:    []models.Showing{showing(1, "bob", at(8, 30), at(9, 0), models.ShowingStatusConfirmed)},
+			want:    []time.Time{at(9, 0), at(9, 30), at(10, 0), at(10, 30)},
+		},
+		{
+			name:    "agent busy at another property",
+			windows: []models.ShowingWindow{morning},
+			busy:    []models.Showing{showing(2, "ann", at(10, 0), at(11, 0), models.ShowingStatusPending)},
+			want:    []time.Time{at(9, 0), at(9, 30)},
+		},
+		{
+			name:    "another agent busy at another property",
+			windows: []models.ShowingWindow{morning},
+			busy:    []models.Showing{showing(2, "bob", at(10, 0), at(11, 0), models.ShowingStatusPending)},
+			want:    []time.Time{at(9, 0), at(9, 30), at(10, 0), at(10, 30)},
+		},
+		{
+			name:    "cancelled showing frees its slot",
+			windows: []models.ShowingWindow{morning},
+			busy:    []models.Showing{showing(1, "ann", at(9, 0), at(9, 30), models.ShowingStatusCancelled)},
+			want:    []time.Time{at(9, 0), at(9, 30), at(10, 0), at(10, 30)},
+		},
+		{
+			name: "overlapping windows offer each time once",
+			windows: []models.ShowingWindow{
+				window(1, "ann", at(9, 0), at(10, 0), 30),
+				window(2, "bob", at(9, 30), at(10, 30), 30),
+			},
+			want: []time.Time{at(9, 0), at(9, 30), at(10, 0)},
+		},
+		{
+			name: "other properties' windows and empty slot lengths ignored",
+			windows: []models.ShowingWindow{
+				{ID: 1, PropertyID: 2, AgentID: "ann", StartsAt: at(9, 0), EndsAt: at(10, 0), SlotMinutes: 30},
+				window(2, "ann", at(9, 0), at(10, 0), 0),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []time.Time
+			for _, s := range OpenSlots(1, tt.windows, tt.busy, tt.notBefore) {
+				got = append(got, s.Start)
+			}
+			if !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Errorf("OpenSlots() starts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenSlotsEarlierWindowWins(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2026, time.March, 2, hour, 0, 0, 0, time.UTC) }
+	windows := []models.ShowingWindow{
+		{ID: 7, PropertyID: 1, AgentID: "bob", StartsAt: at(10), EndsAt: at(11), SlotMinutes: 60},
+		{ID: 3, PropertyID: 1, AgentID: "ann", StartsAt: at(9), EndsAt: at(11), SlotMinutes: 60},
+	}
+	want := []Slot{
+		{Start: at(9), End: at(10), WindowID: 3, AgentID: "ann"},
+		{Start: at(10), End: at(11), WindowID: 7, AgentID: "bob"},
+	}
+	if got := OpenSlots(1, windows, nil, time.Time{}); !slices.Equal(got, want) {
+		t.Errorf("OpenSlots() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Drivers accepted by New
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// ErrNotFound is returned by Open when no object exists under a key
+var ErrNotFound = errors.New("object not found")
+
+// ErrUnavailable is returned by every operation of Unavailable
+var ErrUnavailable = errors.New("storage not configured")
+
+// Storage saves uploaded files under slash-separated keys such as
+// "properties/12/ab34cd-full.jpg"
+type Storage interface {
+	// Put stores r under key, replacing any existing object
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Open returns the contents stored under key. The caller closes it.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL is where browsers can fetch key
+	URL(key string) string
+}
+
+// Options configures the storage drivers
+type Options struct {
+	// Dir is where the local driver writes files
+	Dir string
+	// PublicURL is the URL prefix objects are served from. The local driver
+	// defaults to /uploads; the s3 driver to the bucket's own URL.
+	PublicURL string
+	// Endpoint is the S3-compatible API origin, e.g.
+	// https://s3.us-east-1.amazonaws.com or http://localhost:9000
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// New creates a Storage for the named driver
+func New(driver string, opts Options) (Storage, error) {
+	switch driver {
+	case DriverLocal:
+		return NewLocalStorage(opts.Dir, opts.PublicURL)
+	case DriverS3:
+		if opts.Endpoint == "" || opts.Bucket == "" {
+			return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for the s3 storage driver")
+		}
+		return NewS3Storage(opts)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", driver)
+	}
+}
+
+// cleanKey rejects keys that are empty or would escape the storage root
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != key || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return cleaned, nil
+}
+
+// Unavailable stands in for a Storage on deployments that can't keep
+// files, such as serverless functions without a bucket configured. Every
+// operation fails with ErrUnavailable, so that only the features storing
+// files stop working.
+type Unavailable struct{}
+
+func (Unavailable) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	return ErrUnavailable
+}
+
+func (Unavailable) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return nil, ErrUnavailable
+}
+
+func (Unavailable) Delete(ctx context.Context, key string) error {
+	return ErrUnavailable
+}
+
+func (Unavailable) URL(key string) string {
+	return ""
+}
@@ -0,0 +1,100 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSigner returns a Signer whose clock reads *now
+func testSigner(secret string, now *time.Time) *Signer {
+	s := NewSigner(secret)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestSignerRoundTrip(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	s := testSigner("secret", &now)
+	for _, subject := range []string{"42", "user_1:key:7", ""} {
+		got, err := s.Verify(s.Sign("newsletter", subject, time.Hour), "newsletter")
+		if err != nil || got != subject {
+			t.Errorf("Verify(Sign(%q)) = %q, %v, want the subject back", subject, got, err)
+		}
+	}
+}
+
+func TestSignerRejects(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	s := testSigner("secret", &now)
+	valid := s.Sign("confirm", "42", time.Hour)
+	payload, mac, _ := strings.Cut(valid, ".")
+
+	// Flipping a character of the MAC's first byte keeps it valid base64
+	tamperedMAC := []byte(mac)
+	if tamperedMAC[0] == 'A' {
+		tamperedMAC[0] = 'B'
+	} else {
+		tamperedMAC[0] = 'A'
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		purpose string
+	}{
+		{"wrong purpose", valid, "cancel"},
+		{"tampered signature", payload + "." + string(tamperedMAC), "confirm"},
+		{"tampered subject", encode([]byte("confirm\n43\n"+strings.Split(mustDecode(t, payload), "\n")[2])) + "." + mac, "confirm"},
+		{"other key", testSigner("other", &now).Sign("confirm", "42", time.Hour), "confirm"},
+		{"missing signature", payload, "confirm"},
+		{"not base64", "!!!." + mac, "confirm"},
+		{"empty", "", "confirm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := s.Verify(tt.token, tt.purpose); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Verify() = %q, %v, want ErrInvalid", got, err)
+			}
+		})
+	}
+}
+
+func TestSignerExpiry(t *testing.T) {
+	issued := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	now := issued
+	s := testSigner("secret", &now)
+	expiring := s.Sign("showing", "42", time.Hour)
+	forever := s.Sign("calendar", "42", 0)
+
+	tests := []struct {
+		name    string
+		at      time.Time
+		token   string
+		purpose string
+		want    error
+	}{
+		{"before expiry", issued.Add(59 * time.Minute), expiring, "showing", nil},
+		{"at expiry", issued.Add(time.Hour), expiring, "showing", nil},
+		{"after expiry", issued.Add(time.Hour + time.Second), expiring, "showing", ErrExpired},
+		{"zero ttl years later", issued.AddDate(10, 0, 0), forever, "calendar", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = tt.at
+			if _, err := s.Verify(tt.token, tt.purpose); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func mustDecode(t *testing.T, s string) string {
+	t.Helper()
+	b, err := decode(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
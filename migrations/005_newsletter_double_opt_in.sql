@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TYPE subscriber_status AS ENUM ('pending', 'active', 'unsubscribed');
+
+ALTER TABLE newsletter_subscribers
+    ADD COLUMN status subscriber_status NOT NULL DEFAULT 'pending',
+    ADD COLUMN confirmed_at TIMESTAMPTZ;
+
+-- Existing rows signed up before confirmation was required
+UPDATE newsletter_subscribers
+SET status = CASE WHEN unsubscribed_at IS NULL THEN 'active'::subscriber_status ELSE 'unsubscribed'::subscriber_status END,
+    confirmed_at = CASE WHEN unsubscribed_at IS NULL THEN subscribed_at END;
+
+CREATE INDEX idx_newsletter_status ON newsletter_subscribers(status);
+
+-- +goose Down
+ALTER TABLE newsletter_subscribers
+    DROP COLUMN IF EXISTS confirmed_at,
+    DROP COLUMN IF EXISTS status;
+DROP TYPE IF EXISTS subscriber_status;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TYPE user_role AS ENUM ('admin', 'staff', 'landlord', 'tenant');
+
+-- Local role assignments for Clerk users whose session token carries no role
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    clerk_user_id VARCHAR(255) UNIQUE NOT NULL,
+    email VARCHAR(255),
+    role user_role NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_users_role ON users(role);
+
+-- +goose Down
+DROP TABLE IF EXISTS users;
+DROP TYPE IF EXISTS user_role;
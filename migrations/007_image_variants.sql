@@ -0,0 +1,13 @@
+-- +goose Up
+-- Uploaded photos keep resized copies next to the full-size url. Seeded and
+-- external images leave these NULL and use url everywhere.
+ALTER TABLE property_images
+    ADD COLUMN thumbnail_url TEXT,
+    ADD COLUMN card_url TEXT,
+    ADD COLUMN storage_key TEXT;
+
+-- +goose Down
+ALTER TABLE property_images
+    DROP COLUMN IF EXISTS storage_key,
+    DROP COLUMN IF EXISTS card_url,
+    DROP COLUMN IF EXISTS thumbnail_url;
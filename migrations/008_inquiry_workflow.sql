@@ -0,0 +1,36 @@
+-- +goose Up
+CREATE TYPE inquiry_status AS ENUM ('new', 'contacted', 'scheduled', 'closed');
+CREATE TYPE inquiry_event_kind AS ENUM ('status', 'assigned', 'note');
+
+-- assignee_id is the Clerk user ID of the staff member handling the inquiry
+ALTER TABLE contact_submissions
+    ADD COLUMN status inquiry_status NOT NULL DEFAULT 'new',
+    ADD COLUMN assignee_id VARCHAR(255),
+    ADD COLUMN updated_at TIMESTAMPTZ DEFAULT NOW();
+
+UPDATE contact_submissions SET updated_at = created_at;
+
+CREATE INDEX idx_contacts_status ON contact_submissions(status);
+CREATE INDEX idx_contacts_assignee ON contact_submissions(assignee_id);
+
+-- Timeline of staff activity on an inquiry. detail holds the new status, the
+-- new assignee ID (empty when unassigned) or the note text, depending on kind.
+CREATE TABLE inquiry_events (
+    id SERIAL PRIMARY KEY,
+    submission_id INTEGER NOT NULL REFERENCES contact_submissions(id) ON DELETE CASCADE,
+    actor_id VARCHAR(255) NOT NULL,
+    kind inquiry_event_kind NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_inquiry_events_submission ON inquiry_events(submission_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS inquiry_events;
+ALTER TABLE contact_submissions
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS assignee_id,
+    DROP COLUMN IF EXISTS status;
+DROP TYPE IF EXISTS inquiry_event_kind;
+DROP TYPE IF EXISTS inquiry_status;
@@ -0,0 +1,51 @@
+-- +goose Up
+-- btree_gist lets the exclusion constraints below mix = and && operators
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TYPE showing_status AS ENUM ('pending', 'confirmed', 'cancelled');
+
+-- Times when staff can show a property, split into slots of slot_minutes.
+-- agent_id is the Clerk user ID of the staff member giving the showings.
+CREATE TABLE showing_windows (
+    id SERIAL PRIMARY KEY,
+    property_id INTEGER NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    agent_id VARCHAR(255) NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    slot_minutes INTEGER NOT NULL DEFAULT 30,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    CHECK (ends_at > starts_at),
+    CHECK (slot_minutes > 0)
+);
+
+CREATE INDEX idx_showing_windows_property ON showing_windows(property_id, ends_at);
+
+CREATE TABLE showings (
+    id SERIAL PRIMARY KEY,
+    property_id INTEGER NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    agent_id VARCHAR(255) NOT NULL,
+    contact_submission_id INTEGER REFERENCES contact_submissions(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    phone VARCHAR(50) NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    status showing_status NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CHECK (ends_at > starts_at),
+    -- Neither a property nor an agent can have two showings at once
+    CONSTRAINT showings_property_overlap EXCLUDE USING gist (
+        property_id WITH =, tstzrange(starts_at, ends_at) WITH &&
+    ) WHERE (status <> 'cancelled'),
+    CONSTRAINT showings_agent_overlap EXCLUDE USING gist (
+        agent_id WITH =, tstzrange(starts_at, ends_at) WITH &&
+    ) WHERE (status <> 'cancelled')
+);
+
+CREATE INDEX idx_showings_starts ON showings(starts_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS showings;
+DROP TABLE IF EXISTS showing_windows;
+DROP TYPE IF EXISTS showing_status;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- sequence is the iCalendar SEQUENCE of a showing's event. It goes up on
+-- every change so calendar clients replace their copy.
+ALTER TABLE showings ADD COLUMN sequence INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_showings_agent ON showings(agent_id, starts_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_showings_agent;
+ALTER TABLE showings DROP COLUMN IF EXISTS sequence;
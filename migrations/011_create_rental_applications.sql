@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TYPE application_status AS ENUM ('draft', 'submitted', 'under_review', 'approved', 'denied', 'withdrawn');
+
+-- applicant_id is the Clerk user ID of the applicant. data holds their
+-- answers as JSON so a draft can be saved part-way through the form.
+CREATE TABLE rental_applications (
+    id SERIAL PRIMARY KEY,
+    property_id INTEGER NOT NULL REFERENCES properties(id) ON DELETE RESTRICT,
+    applicant_id VARCHAR(255) NOT NULL,
+    status application_status NOT NULL DEFAULT 'draft',
+    data JSONB NOT NULL DEFAULT '{}',
+    submitted_at TIMESTAMPTZ,
+    decided_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- An applicant has at most one draft per property
+CREATE UNIQUE INDEX idx_applications_draft ON rental_applications(applicant_id, property_id) WHERE status = 'draft';
+CREATE INDEX idx_applications_applicant ON rental_applications(applicant_id);
+CREATE INDEX idx_applications_status ON rental_applications(status);
+
+-- History of status changes. actor_id is the applicant or staff member.
+CREATE TABLE application_events (
+    id SERIAL PRIMARY KEY,
+    application_id INTEGER NOT NULL REFERENCES rental_applications(id) ON DELETE CASCADE,
+    actor_id VARCHAR(255) NOT NULL,
+    status application_status NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_application_events_application ON application_events(application_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS application_events;
+DROP TABLE IF EXISTS rental_applications;
+DROP TYPE IF EXISTS application_status;
@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TYPE payment_status AS ENUM ('pending', 'succeeded', 'failed', 'refunded');
+
+-- Payments collected through the payment gateway. intent_id is the
+-- gateway's ID for the payment; amounts are in cents. payer_id is the
+-- Clerk user ID of whoever paid.
+CREATE TABLE payments (
+    id SERIAL PRIMARY KEY,
+    gateway VARCHAR(50) NOT NULL,
+    intent_id VARCHAR(255) NOT NULL UNIQUE,
+    purpose VARCHAR(50) NOT NULL,
+    application_id INTEGER REFERENCES rental_applications(id) ON DELETE SET NULL,
+    payer_id VARCHAR(255) NOT NULL,
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    refunded_cents BIGINT NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    status payment_status NOT NULL DEFAULT 'pending',
+    failure_message TEXT NOT NULL DEFAULT '',
+    paid_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_payments_application ON payments(application_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS payments;
+DROP TYPE IF EXISTS payment_status;
@@ -0,0 +1,43 @@
+-- +goose Up
+CREATE TYPE lease_status AS ENUM ('draft', 'pending_signature', 'active', 'ended', 'terminated');
+
+-- Amounts are in cents. application_id is the application the lease came
+-- from, if any. A property with leases can't be deleted: its leases hold
+-- the ledger, payments and signed documents.
+CREATE TABLE leases (
+    id SERIAL PRIMARY KEY,
+    property_id INTEGER NOT NULL REFERENCES properties(id) ON DELETE RESTRICT,
+    application_id INTEGER REFERENCES rental_applications(id) ON DELETE SET NULL,
+    status lease_status NOT NULL DEFAULT 'draft',
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    term VARCHAR(100) NOT NULL DEFAULT '',
+    monthly_rent_cents BIGINT NOT NULL CHECK (monthly_rent_cents > 0),
+    deposit_cents BIGINT NOT NULL DEFAULT 0 CHECK (deposit_cents >= 0),
+    pet_rent_cents BIGINT NOT NULL DEFAULT 0 CHECK (pet_rent_cents >= 0),
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    CHECK (end_date > start_date)
+);
+
+CREATE INDEX idx_leases_property ON leases(property_id, start_date);
+CREATE INDEX idx_leases_status ON leases(status);
+
+-- The people on a lease. user_id is their Clerk user ID, which is how a
+-- tenant finds their lease after signing in.
+CREATE TABLE lease_tenants (
+    lease_id INTEGER NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (lease_id, user_id)
+);
+
+CREATE INDEX idx_lease_tenants_user ON lease_tenants(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS lease_tenants;
+DROP TABLE IF EXISTS leases;
+DROP TYPE IF EXISTS lease_status;
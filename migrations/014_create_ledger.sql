@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TYPE ledger_kind AS ENUM ('rent', 'pet_rent', 'charge', 'credit', 'payment');
+
+-- A lease's ledger. Amounts are in cents and always positive; rent, pet
+-- rent and charges add to the balance, credits and payments take from it.
+-- Entries are never edited: mistakes are corrected with a credit or charge.
+-- period is the first of the month a scheduled charge covers, and only one
+-- such charge of each kind is posted per month.
+CREATE TABLE ledger_entries (
+    id SERIAL PRIMARY KEY,
+    lease_id INTEGER NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    kind ledger_kind NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    posted_on DATE NOT NULL,
+    period DATE,
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_ledger_entries_lease ON ledger_entries(lease_id, posted_on);
+CREATE UNIQUE INDEX idx_ledger_entries_period ON ledger_entries(lease_id, kind, period) WHERE period IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS ledger_entries;
+DROP TYPE IF EXISTS ledger_kind;
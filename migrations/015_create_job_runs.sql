@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TYPE job_run_status AS ENUM ('running', 'succeeded', 'failed');
+
+-- One row per run of a background job. A scheduled job runs at most once
+-- per scheduled time, however many servers are running the scheduler.
+CREATE TABLE job_runs (
+    id SERIAL PRIMARY KEY,
+    job VARCHAR(100) NOT NULL,
+    trigger VARCHAR(20) NOT NULL,
+    scheduled_for TIMESTAMPTZ NOT NULL,
+    status job_run_status NOT NULL DEFAULT 'running',
+    summary TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_job_runs_job ON job_runs(job, started_at DESC);
+CREATE UNIQUE INDEX idx_job_runs_slot ON job_runs(job, scheduled_for) WHERE trigger = 'schedule';
+
+-- Notices sent to a lease's tenants, so jobs send each one once. day is
+-- the date the notice is about, e.g. the rent due date.
+CREATE TABLE lease_notices (
+    lease_id INTEGER NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    notice VARCHAR(50) NOT NULL,
+    day DATE NOT NULL,
+    sent_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (lease_id, notice, day)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS lease_notices;
+DROP TABLE IF EXISTS job_runs;
+DROP TYPE IF EXISTS job_run_status;
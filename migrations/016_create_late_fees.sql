@@ -0,0 +1,50 @@
+-- Adding an enum value can't share a transaction with statements that use
+-- it, so this migration runs without one.
+-- +goose NO TRANSACTION
+
+-- +goose Up
+ALTER TYPE ledger_kind ADD VALUE IF NOT EXISTS 'late_fee' AFTER 'pet_rent';
+
+CREATE TYPE late_fee_type AS ENUM ('flat', 'percent');
+
+-- Late fee policies. Amounts are in cents and percent_bps in basis points
+-- of the month's rent; max_cents = 0 means no cap beyond the state's.
+CREATE TABLE late_fee_policies (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    grace_days INTEGER NOT NULL CHECK (grace_days >= 0),
+    type late_fee_type NOT NULL,
+    flat_cents BIGINT NOT NULL DEFAULT 0 CHECK (flat_cents >= 0),
+    percent_bps INTEGER NOT NULL DEFAULT 0 CHECK (percent_bps >= 0),
+    daily_cents BIGINT NOT NULL DEFAULT 0 CHECK (daily_cents >= 0),
+    max_cents BIGINT NOT NULL DEFAULT 0 CHECK (max_cents >= 0),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Each property has at most one default policy
+CREATE TABLE property_late_fee_policies (
+    property_id INTEGER PRIMARY KEY REFERENCES properties(id) ON DELETE CASCADE,
+    policy_id INTEGER NOT NULL REFERENCES late_fee_policies(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_property_late_fee_policies_policy ON property_late_fee_policies(policy_id);
+
+ALTER TABLE leases ADD COLUMN late_fee_policy_id INTEGER REFERENCES late_fee_policies(id) ON DELETE SET NULL;
+
+-- Late fees on a month's rent build up day by day, so they're unique per
+-- day rather than per month like rent
+DROP INDEX idx_ledger_entries_period;
+CREATE UNIQUE INDEX idx_ledger_entries_period ON ledger_entries(lease_id, kind, period) WHERE period IS NOT NULL AND kind <> 'late_fee';
+CREATE UNIQUE INDEX idx_ledger_entries_late_fee ON ledger_entries(lease_id, period, posted_on) WHERE kind = 'late_fee';
+
+-- +goose Down
+-- Postgres can't drop an enum value, so ledger_kind keeps 'late_fee'
+DELETE FROM ledger_entries WHERE kind = 'late_fee';
+DROP INDEX IF EXISTS idx_ledger_entries_late_fee;
+DROP INDEX IF EXISTS idx_ledger_entries_period;
+CREATE UNIQUE INDEX idx_ledger_entries_period ON ledger_entries(lease_id, kind, period) WHERE period IS NOT NULL;
+ALTER TABLE leases DROP COLUMN IF EXISTS late_fee_policy_id;
+DROP TABLE IF EXISTS property_late_fee_policies;
+DROP TABLE IF EXISTS late_fee_policies;
+DROP TYPE IF EXISTS late_fee_type;
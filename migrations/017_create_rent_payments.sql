@@ -0,0 +1,37 @@
+-- +goose Up
+-- Rent payments are payments made against a lease
+ALTER TABLE payments ADD COLUMN lease_id INTEGER REFERENCES leases(id) ON DELETE SET NULL;
+CREATE INDEX idx_payments_lease ON payments(lease_id);
+
+-- Payments taken through the gateway are posted to the ledger at most once
+ALTER TABLE ledger_entries ADD COLUMN payment_id INTEGER REFERENCES payments(id) ON DELETE SET NULL;
+CREATE UNIQUE INDEX idx_ledger_entries_payment ON ledger_entries(payment_id) WHERE payment_id IS NOT NULL;
+
+-- A lease's autopay: the card a tenant saved with the payment gateway and
+-- the day of the month its balance is charged. period is the first of the
+-- month last charged, attempts how many charges failed that month and
+-- retry_on when the next retry is due, if any.
+CREATE TABLE autopay_enrollments (
+    id SERIAL PRIMARY KEY,
+    lease_id INTEGER NOT NULL UNIQUE REFERENCES leases(id) ON DELETE CASCADE,
+    payer_id VARCHAR(255) NOT NULL,
+    payer_email VARCHAR(255) NOT NULL,
+    gateway VARCHAR(50) NOT NULL,
+    customer_id VARCHAR(255) NOT NULL,
+    method_id VARCHAR(255) NOT NULL,
+    method_label VARCHAR(100) NOT NULL,
+    charge_day INTEGER NOT NULL CHECK (charge_day BETWEEN 1 AND 28),
+    period DATE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    retry_on DATE,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS autopay_enrollments;
+DROP INDEX IF EXISTS idx_ledger_entries_payment;
+ALTER TABLE ledger_entries DROP COLUMN IF EXISTS payment_id;
+DROP INDEX IF EXISTS idx_payments_lease;
+ALTER TABLE payments DROP COLUMN IF EXISTS lease_id;
@@ -0,0 +1,74 @@
+-- +goose Up
+CREATE TYPE maintenance_category AS ENUM (
+    'plumbing', 'electrical', 'heating_cooling', 'appliance',
+    'pests', 'doors_locks', 'exterior', 'other'
+);
+CREATE TYPE maintenance_urgency AS ENUM ('routine', 'urgent', 'emergency');
+CREATE TYPE maintenance_status AS ENUM ('open', 'scheduled', 'in_progress', 'resolved');
+CREATE TYPE maintenance_event_kind AS ENUM ('status', 'scheduled', 'assigned', 'vendor', 'message', 'note');
+
+-- Maintenance requests tenants submit against their lease. tenant_id is the
+-- submitter's Clerk user ID and assignee_id that of the staff member
+-- handling the request. entry_permitted is whether staff and vendors may
+-- let themselves in when no one is home.
+CREATE TABLE maintenance_requests (
+    id SERIAL PRIMARY KEY,
+    lease_id INTEGER NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    property_id INTEGER NOT NULL REFERENCES properties(id) ON DELETE RESTRICT,
+    tenant_id VARCHAR(255) NOT NULL,
+    tenant_name VARCHAR(255) NOT NULL,
+    tenant_email VARCHAR(255) NOT NULL,
+    category maintenance_category NOT NULL,
+    urgency maintenance_urgency NOT NULL,
+    title VARCHAR(150) NOT NULL,
+    description TEXT NOT NULL,
+    entry_permitted BOOLEAN NOT NULL DEFAULT false,
+    entry_notes TEXT NOT NULL DEFAULT '',
+    status maintenance_status NOT NULL DEFAULT 'open',
+    assignee_id VARCHAR(255),
+    vendor VARCHAR(255) NOT NULL DEFAULT '',
+    scheduled_for TIMESTAMPTZ,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_maintenance_requests_lease ON maintenance_requests(lease_id, created_at);
+CREATE INDEX idx_maintenance_requests_property ON maintenance_requests(property_id);
+CREATE INDEX idx_maintenance_requests_status ON maintenance_requests(status);
+
+-- Photos attached to a request, stored like property photos
+CREATE TABLE maintenance_photos (
+    id SERIAL PRIMARY KEY,
+    request_id INTEGER NOT NULL REFERENCES maintenance_requests(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    thumbnail_url TEXT NOT NULL,
+    storage_key TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_maintenance_photos_request ON maintenance_photos(request_id);
+
+-- Timeline of a request. detail holds the new status, the visit time
+-- (RFC 3339, empty when unscheduled), the new assignee ID, the vendor or
+-- the message text, depending on kind. Notes are internal to staff;
+-- everything else is shown to the tenant.
+CREATE TABLE maintenance_events (
+    id SERIAL PRIMARY KEY,
+    request_id INTEGER NOT NULL REFERENCES maintenance_requests(id) ON DELETE CASCADE,
+    actor_id VARCHAR(255) NOT NULL,
+    kind maintenance_event_kind NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_maintenance_events_request ON maintenance_events(request_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS maintenance_events;
+DROP TABLE IF EXISTS maintenance_photos;
+DROP TABLE IF EXISTS maintenance_requests;
+DROP TYPE IF EXISTS maintenance_event_kind;
+DROP TYPE IF EXISTS maintenance_status;
+DROP TYPE IF EXISTS maintenance_urgency;
+DROP TYPE IF EXISTS maintenance_category;
@@ -0,0 +1,55 @@
+-- +goose Up
+-- Contractors staff send work orders to. trades holds the maintenance
+-- categories they cover; rates are in cents, 0 when not agreed.
+CREATE TABLE vendors (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(150) NOT NULL,
+    trades TEXT[] NOT NULL DEFAULT '{}',
+    contact_name VARCHAR(255) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    hourly_rate_cents BIGINT NOT NULL DEFAULT 0 CHECK (hourly_rate_cents >= 0),
+    callout_fee_cents BIGINT NOT NULL DEFAULT 0 CHECK (callout_fee_cents >= 0),
+    notes TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE TYPE work_order_status AS ENUM ('open', 'completed', 'cancelled');
+CREATE TYPE work_order_bill_to AS ENUM ('owner', 'tenant');
+
+-- Work orders issued to vendors for a maintenance request. Amounts are in
+-- cents; invoice_cents = 0 means no invoice yet. bill_to is who the cost is
+-- charged back to, and ledger_entry_id the charge posted to the tenant's
+-- ledger once it is.
+CREATE TABLE work_orders (
+    id SERIAL PRIMARY KEY,
+    request_id INTEGER NOT NULL REFERENCES maintenance_requests(id) ON DELETE CASCADE,
+    property_id INTEGER NOT NULL REFERENCES properties(id) ON DELETE RESTRICT,
+    vendor_id INTEGER NOT NULL REFERENCES vendors(id),
+    status work_order_status NOT NULL DEFAULT 'open',
+    scope TEXT NOT NULL,
+    scheduled_on DATE,
+    estimate_cents BIGINT NOT NULL DEFAULT 0 CHECK (estimate_cents >= 0),
+    bill_to work_order_bill_to NOT NULL DEFAULT 'owner',
+    completion_notes TEXT NOT NULL DEFAULT '',
+    completed_at TIMESTAMPTZ,
+    invoice_number VARCHAR(100) NOT NULL DEFAULT '',
+    invoice_cents BIGINT NOT NULL DEFAULT 0 CHECK (invoice_cents >= 0),
+    invoiced_on DATE,
+    ledger_entry_id INTEGER REFERENCES ledger_entries(id) ON DELETE SET NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_work_orders_request ON work_orders(request_id);
+CREATE INDEX idx_work_orders_property ON work_orders(property_id, created_at);
+CREATE INDEX idx_work_orders_vendor ON work_orders(vendor_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS work_orders;
+DROP TYPE IF EXISTS work_order_bill_to;
+DROP TYPE IF EXISTS work_order_status;
+DROP TABLE IF EXISTS vendors;
@@ -0,0 +1,39 @@
+-- +goose Up
+CREATE TYPE document_kind AS ENUM ('lease', 'addendum', 'notice', 'other');
+
+-- Files kept for a lease in the document vault. The file itself is in
+-- private storage under storage_key and is only served through short-lived
+-- signed links.
+CREATE TABLE documents (
+    id SERIAL PRIMARY KEY,
+    lease_id INTEGER NOT NULL REFERENCES leases(id) ON DELETE CASCADE,
+    kind document_kind NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key TEXT NOT NULL,
+    uploaded_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_documents_lease ON documents(lease_id, created_at);
+
+-- Every time a document is served: who opened it, from where, and whether
+-- it was downloaded rather than viewed in the browser
+CREATE TABLE document_accesses (
+    id SERIAL PRIMARY KEY,
+    document_id INTEGER NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    download BOOLEAN NOT NULL DEFAULT false,
+    accessed_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_document_accesses_document ON document_accesses(document_id, accessed_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS document_accesses;
+DROP TABLE IF EXISTS documents;
+DROP TYPE IF EXISTS document_kind;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Generated documents remember the template they came from and the data
+-- they were rendered with, so the same file can be rendered again. Both
+-- are empty for uploaded files.
+ALTER TABLE documents
+    ADD COLUMN template VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN template_data JSONB;
+
+-- +goose Down
+ALTER TABLE documents
+    DROP COLUMN IF EXISTS template_data,
+    DROP COLUMN IF EXISTS template;
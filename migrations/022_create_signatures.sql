@@ -0,0 +1,56 @@
+-- +goose Up
+CREATE TYPE signature_status AS ENUM ('pending', 'signed', 'void');
+CREATE TYPE signature_event_kind AS ENUM ('sent', 'viewed', 'signed', 'voided', 'completed');
+
+-- Once everyone has signed a generated document, it points to the signed
+-- copy stamped with their signatures
+ALTER TABLE documents
+    ADD COLUMN signed_document_id INTEGER REFERENCES documents(id) ON DELETE SET NULL;
+
+-- One request per party asked to sign a document. position is the party's
+-- place among the document's signature blocks. document_sha256 is the hash
+-- of the file as sent, which the signer is shown and the signed copy's
+-- certificate records. Signatures are typed names or drawn PNG images.
+CREATE TABLE signature_requests (
+    id SERIAL PRIMARY KEY,
+    document_id INTEGER NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    user_id VARCHAR(255) NOT NULL DEFAULT '',
+    status signature_status NOT NULL DEFAULT 'pending',
+    document_sha256 CHAR(64) NOT NULL,
+    method VARCHAR(10) NOT NULL DEFAULT '',
+    typed_name VARCHAR(255) NOT NULL DEFAULT '',
+    drawing BYTEA,
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    signed_at TIMESTAMPTZ,
+    requested_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_signature_requests_document ON signature_requests(document_id, position);
+
+-- The audit trail of a document's signing: when each party was sent it,
+-- opened it and signed it, and from where
+CREATE TABLE signature_events (
+    id SERIAL PRIMARY KEY,
+    document_id INTEGER NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    request_id INTEGER REFERENCES signature_requests(id) ON DELETE CASCADE,
+    kind signature_event_kind NOT NULL,
+    actor_id VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_signature_events_document ON signature_events(document_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS signature_events;
+DROP TABLE IF EXISTS signature_requests;
+ALTER TABLE documents DROP COLUMN IF EXISTS signed_document_id;
+DROP TYPE IF EXISTS signature_event_kind;
+DROP TYPE IF EXISTS signature_status;
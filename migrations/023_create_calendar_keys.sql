@@ -0,0 +1,11 @@
+-- +goose Up
+-- Staff calendar feed links are signed with their user's calendar key.
+-- Replacing the key revokes every link made with the old one.
+CREATE TABLE calendar_keys (
+    user_id VARCHAR(255) PRIMARY KEY,
+    key VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS calendar_keys;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Maintenance photos moved to private document storage and are served
+-- through short-lived signed links, so they no longer have public URLs.
+ALTER TABLE maintenance_photos
+    DROP COLUMN IF EXISTS url,
+    DROP COLUMN IF EXISTS thumbnail_url;
+
+-- +goose Down
+ALTER TABLE maintenance_photos
+    ADD COLUMN url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN thumbnail_url TEXT NOT NULL DEFAULT '';
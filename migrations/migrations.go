@@ -0,0 +1,8 @@
+// Package migrations embeds the goose-formatted SQL migrations so the server
+// binary can apply them without external tooling.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
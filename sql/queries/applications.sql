@@ -0,0 +1,48 @@
+-- name: StartApplicationDraft :one
+INSERT INTO rental_applications (property_id, applicant_id, data)
+VALUES ($1, $2, $3)
+ON CONFLICT (applicant_id, property_id) WHERE status = 'draft'
+DO UPDATE SET updated_at = rental_applications.updated_at
+RETURNING *;
+
+-- name: GetApplication :one
+SELECT * FROM rental_applications WHERE id = $1;
+
+-- name: ListApplicationsByApplicant :many
+SELECT * FROM rental_applications
+WHERE applicant_id = $1
+ORDER BY updated_at DESC;
+
+-- name: FilterApplications :many
+SELECT * FROM rental_applications
+WHERE
+    status <> 'draft'
+    AND (CASE WHEN @property_filter::int = 0 THEN true ELSE property_id = @property_filter END)
+    AND (CASE WHEN @status_filter::text = '' THEN true ELSE status::text = @status_filter END)
+ORDER BY submitted_at DESC;
+
+-- name: UpdateApplicationData :one
+UPDATE rental_applications
+SET data = $2, updated_at = NOW()
+WHERE id = $1 AND status = 'draft'
+RETURNING *;
+
+-- name: UpdateApplicationStatus :one
+UPDATE rental_applications
+SET
+    status = @status,
+    submitted_at = CASE WHEN @status::application_status = 'submitted' THEN NOW() ELSE submitted_at END,
+    decided_at = CASE WHEN @status::application_status IN ('approved', 'denied') THEN NOW() ELSE decided_at END,
+    updated_at = NOW()
+WHERE id = @id AND status = @from_status
+RETURNING *;
+
+-- name: CreateApplicationEvent :one
+INSERT INTO application_events (application_id, actor_id, status, note)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListApplicationEvents :many
+SELECT * FROM application_events
+WHERE application_id = $1
+ORDER BY created_at ASC, id ASC;
@@ -0,0 +1,33 @@
+-- name: UpsertAutopay :one
+INSERT INTO autopay_enrollments (lease_id, payer_id, payer_email, gateway, customer_id, method_id, method_label, charge_day, period)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (lease_id) DO UPDATE
+SET
+    payer_id = EXCLUDED.payer_id,
+    payer_email = EXCLUDED.payer_email,
+    gateway = EXCLUDED.gateway,
+    customer_id = EXCLUDED.customer_id,
+    method_id = EXCLUDED.method_id,
+    method_label = EXCLUDED.method_label,
+    charge_day = EXCLUDED.charge_day,
+    period = EXCLUDED.period,
+    attempts = 0,
+    retry_on = NULL,
+    last_error = '',
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetAutopayByLease :one
+SELECT * FROM autopay_enrollments WHERE lease_id = $1;
+
+-- name: ListAutopay :many
+SELECT * FROM autopay_enrollments ORDER BY id;
+
+-- name: UpdateAutopayAttempt :one
+UPDATE autopay_enrollments
+SET period = $2, attempts = $3, retry_on = $4, last_error = $5, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteAutopay :execrows
+DELETE FROM autopay_enrollments WHERE lease_id = $1;
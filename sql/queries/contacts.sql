@@ -29,19 +29,64 @@ INSERT INTO newsletter_subscribers (email, first_name)
 VALUES ($1, $2)
 ON CONFLICT (email) DO UPDATE SET
     first_name = EXCLUDED.first_name,
+    status = CASE
+        WHEN newsletter_subscribers.status = 'active' THEN 'active'::subscriber_status
+        ELSE 'pending'::subscriber_status
+    END,
     unsubscribed_at = NULL
 RETURNING *;
 
 -- name: GetNewsletterSubscriber :one
 SELECT * FROM newsletter_subscribers WHERE email = $1;
 
--- name: UnsubscribeNewsletter :exec
-UPDATE newsletter_subscribers SET unsubscribed_at = NOW() WHERE email = $1;
+-- name: ConfirmNewsletterSubscriber :one
+UPDATE newsletter_subscribers
+SET status = 'active', confirmed_at = NOW()
+WHERE email = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: UnsubscribeNewsletter :execrows
+UPDATE newsletter_subscribers
+SET status = 'unsubscribed', unsubscribed_at = NOW()
+WHERE email = $1;
 
 -- name: ListActiveSubscribers :many
 SELECT * FROM newsletter_subscribers
-WHERE unsubscribed_at IS NULL
+WHERE status = 'active'
 ORDER BY subscribed_at DESC;
 
 -- name: CountActiveSubscribers :one
-SELECT COUNT(*) FROM newsletter_subscribers WHERE unsubscribed_at IS NULL;
+SELECT COUNT(*) FROM newsletter_subscribers WHERE status = 'active';
+
+-- name: FilterContactSubmissions :many
+SELECT * FROM contact_submissions
+WHERE
+    (CASE WHEN @property_filter::int = 0 THEN true ELSE property_id = @property_filter END)
+    AND (CASE WHEN @type_filter::text = '' THEN true ELSE inquiry_type::text = @type_filter END)
+    AND (CASE WHEN @status_filter::text = '' THEN true ELSE status::text = @status_filter END)
+    AND (CASE WHEN @assignee_filter::text = '' THEN true ELSE assignee_id = @assignee_filter END)
+    AND (CASE WHEN sqlc.narg(received_from)::timestamptz IS NULL THEN true ELSE created_at >= sqlc.narg(received_from) END)
+    AND (CASE WHEN sqlc.narg(received_before)::timestamptz IS NULL THEN true ELSE created_at < sqlc.narg(received_before) END)
+ORDER BY created_at DESC;
+
+-- name: UpdateContactSubmissionStatus :one
+UPDATE contact_submissions
+SET status = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateContactSubmissionAssignee :one
+UPDATE contact_submissions
+SET assignee_id = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateInquiryEvent :one
+INSERT INTO inquiry_events (submission_id, actor_id, kind, detail)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListInquiryEvents :many
+SELECT * FROM inquiry_events
+WHERE submission_id = $1
+ORDER BY created_at ASC, id ASC;
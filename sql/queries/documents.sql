@@ -0,0 +1,34 @@
+-- name: CreateDocument :one
+INSERT INTO documents (
+    lease_id, kind, title, filename, content_type, size_bytes, storage_key,
+    uploaded_by, template, template_data
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetDocument :one
+SELECT * FROM documents WHERE id = $1;
+
+-- name: ListDocumentsByLease :many
+SELECT * FROM documents
+WHERE lease_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: CreateDocumentAccess :one
+INSERT INTO document_accesses (document_id, user_id, ip_address, user_agent, download)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListDocumentAccessesByLease :many
+SELECT a.* FROM document_accesses a
+JOIN documents d ON d.id = a.document_id
+WHERE d.lease_id = $1
+ORDER BY a.accessed_at DESC, a.id DESC;
+
+-- name: LockDocument :one
+SELECT * FROM documents WHERE id = $1 FOR UPDATE;
+
+-- name: SetSignedDocument :one
+UPDATE documents
+SET signed_document_id = $2
+WHERE id = $1 AND signed_document_id IS NULL
+RETURNING *;
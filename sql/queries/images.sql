@@ -3,14 +3,20 @@ SELECT * FROM property_images
 WHERE property_id = $1
 ORDER BY display_order ASC;
 
+-- name: LockImagesByPropertyID :many
+SELECT * FROM property_images
+WHERE property_id = $1
+ORDER BY display_order ASC
+FOR UPDATE;
+
 -- name: GetImagesByPropertyIDAndRoom :many
 SELECT * FROM property_images
 WHERE property_id = $1 AND room = $2
 ORDER BY display_order ASC;
 
 -- name: CreateImage :one
-INSERT INTO property_images (property_id, url, caption, room, display_order)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO property_images (property_id, url, caption, room, display_order, thumbnail_url, card_url, storage_key)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
 -- name: UpdateImage :one
@@ -35,3 +41,8 @@ LIMIT 1;
 
 -- name: CountImagesByPropertyID :one
 SELECT COUNT(*) FROM property_images WHERE property_id = $1;
+
+-- name: GetImagesByPropertyIDs :many
+SELECT * FROM property_images
+WHERE property_id = ANY(@property_ids::int[])
+ORDER BY property_id, display_order ASC;
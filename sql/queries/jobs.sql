@@ -0,0 +1,22 @@
+-- name: CreateJobRun :one
+INSERT INTO job_runs (job, trigger, scheduled_for)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: FinishJobRun :one
+UPDATE job_runs
+SET status = @status, summary = @summary, error = @error, finished_at = NOW()
+WHERE id = @id
+RETURNING *;
+
+-- name: ListJobRuns :many
+SELECT * FROM job_runs
+WHERE (CASE WHEN @job_filter::text = '' THEN true ELSE job = @job_filter END)
+ORDER BY started_at DESC, id DESC
+LIMIT @max_rows;
+
+-- name: TryAdvisoryLock :one
+SELECT pg_try_advisory_lock(@key::bigint);
+
+-- name: AdvisoryUnlock :one
+SELECT pg_advisory_unlock(@key::bigint);
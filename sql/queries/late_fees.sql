@@ -0,0 +1,38 @@
+-- name: CreateLateFeePolicy :one
+INSERT INTO late_fee_policies (name, grace_days, type, flat_cents, percent_bps, daily_cents, max_cents)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetLateFeePolicy :one
+SELECT * FROM late_fee_policies WHERE id = $1;
+
+-- name: ListLateFeePolicies :many
+SELECT * FROM late_fee_policies ORDER BY name, id;
+
+-- name: UpdateLateFeePolicy :one
+UPDATE late_fee_policies
+SET
+    name = @name,
+    grace_days = @grace_days,
+    type = @type,
+    flat_cents = @flat_cents,
+    percent_bps = @percent_bps,
+    daily_cents = @daily_cents,
+    max_cents = @max_cents,
+    updated_at = NOW()
+WHERE id = @id
+RETURNING *;
+
+-- name: DeleteLateFeePolicy :execrows
+DELETE FROM late_fee_policies WHERE id = $1;
+
+-- name: ListPropertyLateFeePolicies :many
+SELECT * FROM property_late_fee_policies ORDER BY property_id;
+
+-- name: DeletePropertyLateFeePolicies :exec
+DELETE FROM property_late_fee_policies WHERE policy_id = $1;
+
+-- name: SetPropertyLateFeePolicy :exec
+INSERT INTO property_late_fee_policies (property_id, policy_id)
+VALUES ($1, $2)
+ON CONFLICT (property_id) DO UPDATE SET policy_id = EXCLUDED.policy_id;
@@ -0,0 +1,66 @@
+-- name: CreateLease :one
+INSERT INTO leases (
+    property_id, application_id, status, start_date, end_date, term,
+    monthly_rent_cents, deposit_cents, pet_rent_cents, late_fee_policy_id, notes
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: GetLease :one
+SELECT * FROM leases WHERE id = $1;
+
+-- name: FilterLeases :many
+SELECT * FROM leases
+WHERE
+    (CASE WHEN @property_filter::int = 0 THEN true ELSE property_id = @property_filter END)
+    AND (CASE WHEN @status_filter::text = '' THEN true ELSE status::text = @status_filter END)
+ORDER BY start_date DESC, id DESC;
+
+-- name: ListLeasesByTenant :many
+SELECT leases.* FROM leases
+JOIN lease_tenants ON lease_tenants.lease_id = leases.id
+WHERE lease_tenants.user_id = $1
+ORDER BY leases.start_date DESC, leases.id DESC;
+
+-- name: UpdateLease :one
+UPDATE leases
+SET
+    property_id = @property_id,
+    application_id = @application_id,
+    start_date = @start_date,
+    end_date = @end_date,
+    term = @term,
+    monthly_rent_cents = @monthly_rent_cents,
+    deposit_cents = @deposit_cents,
+    pet_rent_cents = @pet_rent_cents,
+    late_fee_policy_id = @late_fee_policy_id,
+    notes = @notes,
+    updated_at = NOW()
+WHERE id = @id AND status = 'draft'
+RETURNING *;
+
+-- name: UpdateLeaseStatus :one
+UPDATE leases
+SET status = @status, updated_at = NOW()
+WHERE id = @id AND status = @from_status
+RETURNING *;
+
+-- name: CreateLeaseTenant :exec
+INSERT INTO lease_tenants (lease_id, user_id, name, email, position)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: DeleteLeaseTenants :exec
+DELETE FROM lease_tenants WHERE lease_id = $1;
+
+-- name: ListLeaseTenants :many
+SELECT * FROM lease_tenants
+WHERE lease_id = ANY(@lease_ids::int[])
+ORDER BY lease_id, position;
+
+-- name: CreateLeaseNotice :execrows
+INSERT INTO lease_notices (lease_id, notice, day)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteLeaseNotice :exec
+DELETE FROM lease_notices
+WHERE lease_id = $1 AND notice = $2 AND day = $3;
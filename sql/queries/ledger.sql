@@ -0,0 +1,24 @@
+-- name: CreateLedgerEntry :one
+INSERT INTO ledger_entries (lease_id, kind, description, amount_cents, posted_on, period, created_by, payment_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: PostScheduledLedgerEntry :execrows
+INSERT INTO ledger_entries (lease_id, kind, description, amount_cents, posted_on, period, created_by, payment_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (lease_id, kind, period) WHERE period IS NOT NULL AND kind <> 'late_fee' DO NOTHING;
+
+-- name: PostLateFeeLedgerEntry :execrows
+INSERT INTO ledger_entries (lease_id, kind, description, amount_cents, posted_on, period, created_by, payment_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (lease_id, period, posted_on) WHERE kind = 'late_fee' DO NOTHING;
+
+-- name: PostPaymentLedgerEntry :execrows
+INSERT INTO ledger_entries (lease_id, kind, description, amount_cents, posted_on, period, created_by, payment_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (payment_id) WHERE payment_id IS NOT NULL DO NOTHING;
+
+-- name: ListLedgerEntries :many
+SELECT * FROM ledger_entries
+WHERE lease_id = $1
+ORDER BY posted_on, id;
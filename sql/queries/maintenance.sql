@@ -0,0 +1,67 @@
+-- name: CreateMaintenanceRequest :one
+INSERT INTO maintenance_requests (
+    lease_id, property_id, tenant_id, tenant_name, tenant_email, category,
+    urgency, title, description, entry_permitted, entry_notes
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: GetMaintenanceRequest :one
+SELECT * FROM maintenance_requests WHERE id = $1;
+
+-- name: ListMaintenanceRequestsByLease :many
+SELECT * FROM maintenance_requests
+WHERE lease_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: FilterMaintenanceRequests :many
+SELECT * FROM maintenance_requests
+WHERE
+    (CASE WHEN @property_filter::int = 0 THEN true ELSE property_id = @property_filter END)
+    AND (CASE WHEN @status_filter::text = '' THEN true ELSE status::text = @status_filter END)
+    AND (CASE WHEN @urgency_filter::text = '' THEN true ELSE urgency::text = @urgency_filter END)
+    AND (CASE WHEN @assignee_filter::text = '' THEN true ELSE assignee_id = @assignee_filter END)
+ORDER BY created_at DESC, id DESC;
+
+-- name: UpdateMaintenanceStatus :one
+UPDATE maintenance_requests
+SET status = $2, resolved_at = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateMaintenanceSchedule :one
+UPDATE maintenance_requests
+SET scheduled_for = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateMaintenanceAssignee :one
+UPDATE maintenance_requests
+SET assignee_id = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateMaintenanceVendor :one
+UPDATE maintenance_requests
+SET vendor = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateMaintenancePhoto :one
+INSERT INTO maintenance_photos (request_id, storage_key)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: ListMaintenancePhotos :many
+SELECT * FROM maintenance_photos
+WHERE request_id = ANY(@request_ids::int[])
+ORDER BY request_id, id;
+
+-- name: CreateMaintenanceEvent :one
+INSERT INTO maintenance_events (request_id, actor_id, kind, detail)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListMaintenanceEvents :many
+SELECT * FROM maintenance_events
+WHERE request_id = $1
+ORDER BY created_at ASC, id ASC;
@@ -0,0 +1,39 @@
+-- name: CreatePayment :one
+INSERT INTO payments (gateway, intent_id, purpose, application_id, lease_id, payer_id, amount_cents, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetPayment :one
+SELECT * FROM payments WHERE id = $1;
+
+-- name: GetPaymentByIntent :one
+SELECT * FROM payments WHERE intent_id = $1;
+
+-- name: ListPaymentsByApplication :many
+SELECT * FROM payments
+WHERE application_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListPaymentsByLease :many
+SELECT * FROM payments
+WHERE lease_id = $1
+ORDER BY created_at DESC;
+
+-- name: UpdatePaymentStatus :one
+UPDATE payments
+SET
+    status = @status,
+    failure_message = @failure_message,
+    paid_at = CASE WHEN @status = 'succeeded' THEN NOW() ELSE paid_at END,
+    updated_at = NOW()
+WHERE id = @id AND status IN ('pending', 'failed')
+RETURNING *;
+
+-- name: UpdatePaymentRefunded :one
+UPDATE payments
+SET
+    refunded_cents = GREATEST(refunded_cents, @refunded_cents),
+    status = CASE WHEN GREATEST(refunded_cents, @refunded_cents) >= amount_cents THEN 'refunded' ELSE status END,
+    updated_at = NOW()
+WHERE id = @id AND status IN ('succeeded', 'refunded')
+RETURNING *;
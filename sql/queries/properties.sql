@@ -4,6 +4,9 @@ SELECT * FROM properties WHERE slug = $1;
 -- name: GetPropertyByID :one
 SELECT * FROM properties WHERE id = $1;
 
+-- name: LockProperty :one
+SELECT * FROM properties WHERE id = $1 FOR UPDATE;
+
 -- name: ListProperties :many
 SELECT * FROM properties ORDER BY created_at DESC;
 
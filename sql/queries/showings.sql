@@ -0,0 +1,49 @@
+-- name: CreateShowingWindow :one
+INSERT INTO showing_windows (property_id, agent_id, starts_at, ends_at, slot_minutes)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListShowingWindows :many
+SELECT * FROM showing_windows
+WHERE
+    (CASE WHEN @property_filter::int = 0 THEN true ELSE property_id = @property_filter END)
+    AND ends_at > @ends_after
+ORDER BY starts_at ASC;
+
+-- name: DeleteShowingWindow :exec
+DELETE FROM showing_windows WHERE id = $1;
+
+-- name: CreateShowing :one
+INSERT INTO showings (
+    property_id, agent_id, contact_submission_id, name, email, phone, starts_at, ends_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetShowing :one
+SELECT * FROM showings WHERE id = $1;
+
+-- name: ListShowings :many
+SELECT * FROM showings
+WHERE starts_at >= $1
+ORDER BY starts_at ASC;
+
+-- name: ListShowingFeed :many
+SELECT * FROM showings
+WHERE
+    (CASE WHEN @property_filter::int = 0 THEN true ELSE property_id = @property_filter END)
+    AND (CASE WHEN @agent_filter::text = '' THEN true ELSE agent_id = @agent_filter END)
+    AND starts_at >= @since
+ORDER BY starts_at ASC;
+
+-- name: ListBusyShowings :many
+SELECT * FROM showings
+WHERE status <> 'cancelled'
+    AND (property_id = @property_id OR agent_id = ANY(@agent_ids::text[]))
+    AND starts_at < @until AND ends_at > @since
+ORDER BY starts_at ASC;
+
+-- name: UpdateShowingStatus :one
+UPDATE showings
+SET status = $2, sequence = sequence + 1, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,56 @@
+-- name: CreateSignatureRequest :one
+INSERT INTO signature_requests (
+    document_id, position, name, role, email, user_id, document_sha256, requested_by
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetSignatureRequest :one
+SELECT * FROM signature_requests WHERE id = $1;
+
+-- name: ListSignatureRequestsByDocument :many
+SELECT * FROM signature_requests
+WHERE document_id = $1
+ORDER BY id;
+
+-- name: ListSignatureRequestsByLease :many
+SELECT r.* FROM signature_requests r
+JOIN documents d ON d.id = r.document_id
+WHERE d.lease_id = $1
+ORDER BY r.id;
+
+-- name: CountPendingSignatureRequests :one
+SELECT COUNT(*) FROM signature_requests
+WHERE document_id = $1 AND status = 'pending';
+
+-- name: CountOpenSignatureRequests :one
+SELECT COUNT(*) FROM signature_requests
+WHERE document_id = $1 AND status <> 'void';
+
+-- name: SignSignatureRequest :one
+UPDATE signature_requests
+SET status = 'signed', method = $2, typed_name = $3, drawing = $4,
+    ip_address = $5, user_agent = $6, signed_at = NOW()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: VoidSignatureRequests :many
+UPDATE signature_requests
+SET status = 'void'
+WHERE document_id = $1 AND status <> 'void'
+RETURNING *;
+
+-- name: CreateSignatureEvent :one
+INSERT INTO signature_events (document_id, request_id, kind, actor_id, ip_address, user_agent)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListSignatureEventsByDocument :many
+SELECT * FROM signature_events
+WHERE document_id = $1
+ORDER BY created_at, id;
+
+-- name: ListSignatureEventsByLease :many
+SELECT e.* FROM signature_events e
+JOIN documents d ON d.id = e.document_id
+WHERE d.lease_id = $1
+ORDER BY e.created_at DESC, e.id DESC;
@@ -0,0 +1,32 @@
+-- name: GetUserByClerkID :one
+SELECT * FROM users WHERE clerk_user_id = $1;
+
+-- name: ListUsers :many
+SELECT * FROM users ORDER BY created_at DESC;
+
+-- name: UpsertUserRole :one
+INSERT INTO users (clerk_user_id, email, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (clerk_user_id) DO UPDATE SET
+    email = COALESCE(EXCLUDED.email, users.email),
+    role = EXCLUDED.role,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteUser :exec
+WITH keys AS (DELETE FROM calendar_keys WHERE user_id = $1)
+DELETE FROM users WHERE clerk_user_id = $1;
+
+-- name: GetCalendarKey :one
+SELECT key FROM calendar_keys WHERE user_id = $1;
+
+-- name: EnsureCalendarKey :one
+INSERT INTO calendar_keys (user_id, key)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET key = calendar_keys.key
+RETURNING key;
+
+-- name: SetCalendarKey :exec
+INSERT INTO calendar_keys (user_id, key)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET key = EXCLUDED.key, created_at = NOW();
@@ -0,0 +1,68 @@
+-- name: CreateVendor :one
+INSERT INTO vendors (
+    name, trades, contact_name, email, phone, hourly_rate_cents,
+    callout_fee_cents, notes, active
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetVendor :one
+SELECT * FROM vendors WHERE id = $1;
+
+-- name: ListVendors :many
+SELECT * FROM vendors ORDER BY name, id;
+
+-- name: UpdateVendor :one
+UPDATE vendors
+SET name = $2, trades = $3, contact_name = $4, email = $5, phone = $6,
+    hourly_rate_cents = $7, callout_fee_cents = $8, notes = $9, active = $10,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateWorkOrder :one
+INSERT INTO work_orders (
+    request_id, property_id, vendor_id, scope, scheduled_on, estimate_cents,
+    bill_to, created_by
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetWorkOrder :one
+SELECT * FROM work_orders WHERE id = $1;
+
+-- name: ListWorkOrdersByRequest :many
+SELECT * FROM work_orders
+WHERE request_id = $1
+ORDER BY created_at, id;
+
+-- name: FilterWorkOrders :many
+SELECT * FROM work_orders
+WHERE
+    (CASE WHEN @property_filter::int = 0 THEN true ELSE property_id = @property_filter END)
+    AND (CASE WHEN @vendor_filter::int = 0 THEN true ELSE vendor_id = @vendor_filter END)
+    AND (CASE WHEN @status_filter::text = '' THEN true ELSE status::text = @status_filter END)
+    AND (CASE WHEN @bill_to_filter::text = '' THEN true ELSE bill_to::text = @bill_to_filter END)
+ORDER BY created_at DESC, id DESC;
+
+-- name: UpdateWorkOrder :one
+UPDATE work_orders
+SET scope = $2, scheduled_on = $3, estimate_cents = $4, bill_to = $5, updated_at = NOW()
+WHERE id = $1 AND status = 'open'
+RETURNING *;
+
+-- name: SetWorkOrderStatus :one
+UPDATE work_orders
+SET status = @status, completion_notes = @completion_notes, completed_at = @completed_at, updated_at = NOW()
+WHERE id = @id AND status = 'open'
+RETURNING *;
+
+-- name: RecordWorkOrderInvoice :one
+UPDATE work_orders
+SET invoice_number = $2, invoice_cents = $3, invoiced_on = $4, bill_to = $5, updated_at = NOW()
+WHERE id = $1 AND status = 'completed' AND ledger_entry_id IS NULL
+RETURNING *;
+
+-- name: SetWorkOrderLedgerEntry :one
+UPDATE work_orders
+SET ledger_entry_id = $2, updated_at = NOW()
+WHERE id = $1 AND ledger_entry_id IS NULL AND bill_to = 'tenant' AND invoice_cents > 0
+RETURNING *;